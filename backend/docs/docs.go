@@ -15,21 +15,39 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/books": {
+        "/authors": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "books"
+                    "authors"
                 ],
-                "summary": "List all books with pagination and search query params",
+                "summary": "List authors by name with cursor pagination",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "search param to search by title and author",
+                        "description": "search param to search by name and aliases",
                         "name": "search",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "cursor to fetch the page after, taken from next_cursor",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "cursor to fetch the page before, taken from prev_cursor",
+                        "name": "before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "item per page, max 100",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -38,43 +56,50 @@ const docTemplate = `{
                         "schema": {
                             "allOf": [
                                 {
-                                    "$ref": "#/definitions/xhttp.BaseResponse"
+                                    "$ref": "#/definitions/xhttp.BaseListResponse"
                                 },
                                 {
                                     "type": "object",
                                     "properties": {
-                                        " metadata": {
-                                            "$ref": "#/definitions/pagination.Metadata"
-                                        },
                                         "data": {
                                             "type": "array",
                                             "items": {
-                                                "$ref": "#/definitions/model.Book"
+                                                "$ref": "#/definitions/model.Author"
                                             }
+                                        },
+                                        "metadata": {
+                                            "$ref": "#/definitions/pagination.CursorMetadata"
                                         }
                                     }
                                 }
                             ]
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "RFC 8288 links to the next and previous pages"
+                            }
                         }
                     }
                 }
             },
             "post": {
+                "description": "Years are 0 or left out when unknown, negative for BCE. Storing the name of a deleted author brings that author back.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "books"
+                    "authors"
                 ],
-                "summary": "Store new book data, return stored data",
+                "summary": "Store new author data, return stored data",
                 "parameters": [
                     {
-                        "description": "book data",
+                        "description": "author data",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.StoreBookRequest"
+                            "$ref": "#/definitions/model.StoreAuthorRequest"
                         }
                     }
                 ],
@@ -90,7 +115,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/model.Book"
+                                            "$ref": "#/definitions/model.Author"
                                         }
                                     }
                                 }
@@ -100,19 +125,54 @@ const docTemplate = `{
                 }
             }
         },
-        "/books/{id}": {
+        "/authors/duplicates": {
             "get": {
+                "description": "Pairs share a surname and have given names that match or are initials of each other, e.g. \"J. Tolkien\" and \"J.R.R. Tolkien\".",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "books"
+                    "authors"
                 ],
-                "summary": "Get a book data by its ID",
+                "summary": "List pairs of authors that probably name the same person, admin only",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/xhttp.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.AuthorDuplicate"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/authors/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "authors"
+                ],
+                "summary": "Get an author data by its ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "book ID",
+                        "description": "author ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -130,7 +190,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/model.Book"
+                                            "$ref": "#/definitions/model.Author"
                                         }
                                     }
                                 }
@@ -140,28 +200,29 @@ const docTemplate = `{
                 }
             },
             "put": {
+                "description": "Renaming an author rewrites the author field of every book crediting them.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "books"
+                    "authors"
                 ],
-                "summary": "Update book data by ID, return updated data",
+                "summary": "Update author data by ID, return updated data",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "book ID",
+                        "description": "author ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "book data",
+                        "description": "author data",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.UpdateBookRequest"
+                            "$ref": "#/definitions/model.UpdateAuthorRequest"
                         }
                     }
                 ],
@@ -177,7 +238,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/model.Book"
+                                            "$ref": "#/definitions/model.Author"
                                         }
                                     }
                                 }
@@ -187,17 +248,18 @@ const docTemplate = `{
                 }
             },
             "delete": {
+                "description": "Refused while a book that is not deleted still credits the author.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "books"
+                    "authors"
                 ],
-                "summary": "Delete book data by ID",
+                "summary": "Soft delete author data by ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "book ID",
+                        "description": "author ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -225,23 +287,155 @@ const docTemplate = `{
                 }
             }
         },
-        "/url/cleanup": {
+        "/authors/{id}/books": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "authors"
+                ],
+                "summary": "List the books crediting an author, in any role, with the same query params as the book listing",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "author ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "search param to search by title and author",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "search mode, simple (default) or fulltext",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "filter books published in or after this year, 0 being 1 BCE",
+                        "name": "publish_year_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "filter books published in or before this year, 0 being 1 BCE",
+                        "name": "publish_year_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter books that may have been published on or after this EDTF date, like 1949 or 1949-06, instead of publish_year_from",
+                        "name": "published_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter books that may have been published on or before this EDTF date, like 1949 or 1949-06, instead of publish_year_to",
+                        "name": "published_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "include soft deleted books, admin only",
+                        "name": "include_deleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "list the books credited to the author at this RFC3339 timestamp",
+                        "name": "as_of",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma separated sort fields with optional direction, e.g. publish_year:desc",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "cursor to fetch the page after, taken from next_cursor",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "cursor to fetch the page before, taken from prev_cursor",
+                        "name": "before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "item per page, max 100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/xhttp.BaseListResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.Book"
+                                            }
+                                        },
+                                        "metadata": {
+                                            "$ref": "#/definitions/model.BookListMetadata"
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "RFC 8288 links to the next and previous pages"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/authors/{id}/merge": {
             "post": {
+                "description": "Book credits of the merged authors move to the author, their names become aliases and they are deleted. Affected books get their author string rebuilt and a new version, all in one transaction.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "urlCleaner"
+                    "authors"
                 ],
-                "summary": "Clean up URL with either canonical, redirection, or both (all) operations",
+                "summary": "Merge authors into the author by ID, admin only",
                 "parameters": [
                     {
-                        "description": "URL to be clean and the selected operation ('canonical', 'redirection', 'all')",
+                        "type": "integer",
+                        "description": "author ID to merge into",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "authors to merge",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.URLCleanerRequest"
+                            "$ref": "#/definitions/model.MergeAuthorsRequest"
                         }
                     }
                 ],
@@ -249,113 +443,3302 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.URLCleanerResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/xhttp.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.AuthorMerge"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "model.Book": {
-            "type": "object",
-            "properties": {
-                "author": {
-                    "type": "string"
-                },
-                "created_at": {
-                    "type": "string"
-                },
-                "deleted_at": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "integer"
-                },
-                "publish_year": {
-                    "type": "integer"
-                },
-                "title": {
-                    "type": "string"
-                },
-                "updated_at": {
-                    "type": "string"
-                }
-            }
-        },
-        "model.StoreBookRequest": {
-            "type": "object",
-            "properties": {
-                "author": {
-                    "type": "string"
-                },
-                "publish_year": {
-                    "type": "integer"
-                },
-                "title": {
-                    "type": "string"
-                }
-            }
-        },
-        "model.URLCleanerRequest": {
-            "type": "object",
-            "properties": {
-                "operation": {
-                    "type": "string",
-                    "example": "canonical,redirection,all"
-                },
-                "url": {
-                    "type": "string"
-                }
-            }
-        },
-        "model.URLCleanerResponse": {
-            "type": "object",
-            "properties": {
-                "processed_url": {
-                    "type": "string"
-                }
-            }
-        },
-        "model.UpdateBookRequest": {
-            "type": "object",
-            "properties": {
-                "author": {
-                    "type": "string"
-                },
-                "publish_year": {
-                    "type": "integer"
-                },
-                "title": {
-                    "type": "string"
-                }
-            }
         },
-        "pagination.Metadata": {
+        "/books": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "books"
+                ],
+                "summary": "List books with cursor pagination and search query params",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "search param to search by title and author",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "search mode, simple (default, substring match) or fulltext (ranked, supports quoted phrases and -negation)",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter by author name",
+                        "name": "author",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "filter by genre ID, genres below it included",
+                        "name": "genre",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter by tag name",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter by outside identifier as scheme:value, e.g. oclc:12345",
+                        "name": "identifier",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter books with an edition in this format (hardcover, paperback, ebook, audiobook, large_print)",
+                        "name": "edition_format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter books with an edition in this BCP 47 language, e.g. en or pt-BR",
+                        "name": "edition_language",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "list the editions of every book along",
+                        "name": "include_editions",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "filter books published in or after this year, 0 being 1 BCE",
+                        "name": "publish_year_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "filter books published in or before this year, 0 being 1 BCE",
+                        "name": "publish_year_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter books that may have been published on or after this EDTF date, like 1949 or 1949-06, instead of publish_year_from",
+                        "name": "published_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter books that may have been published on or before this EDTF date, like 1949 or 1949-06, instead of publish_year_to",
+                        "name": "published_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter books created after this RFC3339 timestamp",
+                        "name": "created_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma separated book IDs",
+                        "name": "ids",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "include soft deleted books, admin only",
+                        "name": "include_deleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "list the catalog as it was at this RFC3339 timestamp, including books deleted since",
+                        "name": "as_of",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma separated sort fields with optional direction, e.g. publish_year:desc,title (title, author, publish_year, created_at, updated_at)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "cursor to fetch the page after, taken from next_cursor",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "cursor to fetch the page before, taken from prev_cursor",
+                        "name": "before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "item per page, max 100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/xhttp.BaseListResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.Book"
+                                            }
+                                        },
+                                        "metadata": {
+                                            "$ref": "#/definitions/model.BookListMetadata"
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "RFC 8288 links to the next and previous pages"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "books"
+                ],
+                "summary": "Store new book data, return stored data",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "store the book even when it probably is already stored",
+                        "name": "force",
+                        "in": "query"
+                    },
+                    {
+                        "description": "book data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.StoreBookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/xhttp.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Book"
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "stored book version"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "book probably is already stored, matches best first",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/xhttp.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.BookMatch"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/books/batch": {
+            "post": {
+                "description": "Every operation is validated before anything is written and the first failing operation rolls the whole batch back. Update and delete need the book version, like If-Match on the single book endpoints. Restore operations are admin only. A create of a book that probably is already stored fails with the matches, unless force is set.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "books"
+                ],
+                "summary": "Apply many create, update, delete and restore operations in a single transaction",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "create the books even when they probably are already stored",
+                        "name": "force",
+                        "in": "query"
+                    },
+                    {
+                        "description": "batch operations, applied in order",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.BookBatchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/xhttp.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.BookBatchResult"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "invalid operation, nothing applied",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/xhttp.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.BookBatchResult"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "restore operation sent without the admin token",
+                        "schema": {
+                            "$ref": "#/definitions/xhttp.BaseResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "create of a book that probably is already stored, nothing applied",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/xhttp.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.BookBatchResult"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "412": {
+                        "description": "stale version, batch rolled back",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/xhttp.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.BookBatchResult"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/books/duplicates": {
+            "get": {
+                "description": "Pairs share an author or have a similar title and score at least 0.75, weighing title similarity at 0.6, author similarity at 0.3 and publish year at 0.1. Best pairs first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "books"
+                ],
+                "summary": "List pairs of live books that are probably the same book, admin only",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/xhttp.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.BookDuplicate"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/books/export": {
+            "get": {
+                "produces": [
+                    "text/csv",
+                    "application/x-ndjson",
+                    "application/json"
+                ],
+                "tags": [
+                    "books"
+                ],
+                "summary": "Stream every book matching the search query params as a file",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "export format, csv (default), ndjson or json",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "search param to search by title and author",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "search mode, simple (default) or fulltext",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter by author name",
+                        "name": "author",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "filter by genre ID, genres below it included",
+                        "name": "genre",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter by tag name",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter by outside identifier as scheme:value, e.g. oclc:12345",
+                        "name": "identifier",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter books with an edition in this format (hardcover, paperback, ebook, audiobook, large_print)",
+                        "name": "edition_format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter books with an edition in this BCP 47 language, e.g. en or pt-BR",
+                        "name": "edition_language",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "list the editions of every book along",
+                        "name": "include_editions",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "filter books published in or after this year, 0 being 1 BCE",
+                        "name": "publish_year_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "filter books published in or before this year, 0 being 1 BCE",
+                        "name": "publish_year_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter books that may have been published on or after this EDTF date, like 1949 or 1949-06, instead of publish_year_from",
+                        "name": "published_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter books that may have been published on or before this EDTF date, like 1949 or 1949-06, instead of publish_year_to",
+                        "name": "published_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter books created after this RFC3339 timestamp",
+                        "name": "created_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma separated book IDs",
+                        "name": "ids",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "include soft deleted books, admin only",
+                        "name": "include_deleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "export the catalog as it was at this RFC3339 timestamp",
+                        "name": "as_of",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma separated sort fields with optional direction",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "books export",
+                        "schema": {
+                            "type": "file"
+                        },
+                        "headers": {
+                            "Content-Disposition": {
+                                "type": "string",
+                                "description": "attachment file name"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/books/import": {
+            "post": {
+                "description": "CSV needs a header with title, author and publish_year columns, optional published and isbn columns, several authors separated by \" \u0026 \", NDJSON takes one book object per line with author or authors. The upload is either the raw request body or the \"file\" field of a multipart form.",
+                "consumes": [
+                    "text/csv",
+                    "application/x-ndjson",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "books"
+                ],
+                "summary": "Bulk import books from a CSV or NDJSON upload, return a per row report",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "only validate the upload, store nothing",
+                        "name": "dry_run",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "import the rows that probably are already stored books too",
+                        "name": "force",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "all_or_nothing (default) stores nothing when any row is rejected, best_effort stores every accepted row",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "file",
+                        "description": "CSV or NDJSON file, when uploading as multipart form",
+                        "name": "file",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/xhttp.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.BookImportReport"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "415": {
+                        "description": "unsupported upload content type",
+                        "schema": {
+                            "$ref": "#/definitions/xhttp.BaseResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "all or nothing import aborted by rejected rows",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/xhttp.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.BookImportReport"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/books/isbn/{isbn}": {
+            "get": {
+                "description": "Takes an ISBN-10 or ISBN-13, with or without hyphens, and finds the book holding it or the book of the edition holding it. Soft deleted books are not found.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "books"
+                ],
+                "summary": "Get a book data by its ISBN",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ISBN-10 or ISBN-13",
+                        "name": "isbn",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/xhttp.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Book"
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "current book version"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/books/suggest": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "books"
+                ],
+                "summary": "Autocomplete book titles or authors by prefix",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "prefix typed by the user",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "field to complete, title (default) or author",
+                        "name": "field",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "max suggestions, default 5, max 20",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/xhttp.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.BookSuggestion"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/books/trash": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "books"
+                ],
+                "summary": "List soft deleted books, most recently deleted first, admin only",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "search param to search by title and author",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "list books that were soft deleted at this RFC3339 timestamp",
+                        "name": "as_of",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma separated sort fields with optional direction, defaults to deleted_at:desc",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "cursor to fetch the page after, taken from next_cursor",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "cursor to fetch the page before, taken from prev_cursor",
+                        "name": "before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "item per page, max 100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/xhttp.BaseListResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.Book"
+                                            }
+                                        },
+                                        "metadata": {
+                                            "$ref": "#/definitions/model.BookListMetadata"
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "RFC 8288 links to the next and previous pages"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/books/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "books"
+                ],
+                "summary": "Get a book data by its ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "book ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "get the book as it was at this RFC3339 timestamp",
+                        "name": "as_of",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from a previous response, answered with 304 when unchanged",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/xhttp.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Book"
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "book version and a hash of the book as served, or those at as_of"
+                            }
+                        }
+                    },
+                    "302": {
+                        "description": "book was merged, Location points to the book it was merged into",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/xhttp.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.BookRef"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    }
+                }
+            },
+            "put": {
+                "description": "Without authors, an unchanged author string keeps the stored credits, editors and translators included. Without published, an unchanged publish year keeps the stored date, month, day and qualifiers included.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "books"
+                ],
+                "summary": "Update book data by ID, return updated data",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "book ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the book version being updated",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "book data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.UpdateBookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/xhttp.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Book"
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "updated book version"
+                            }
+                        }
+                    },
+                    "412": {
+                        "description": "book has been modified since the given ETag",
+                        "schema": {
+                            "$ref": "#/definitions/xhttp.BaseResponse"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match header is missing",
+                        "schema": {
+                            "$ref": "#/definitions/xhttp.BaseResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "books"
+                ],
+                "summary": "Delete book data by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "book ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the book version being deleted",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/xhttp.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "message": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "412": {
+                        "description": "book has been modified since the given ETag",
+                        "schema": {
+                            "$ref": "#/definitions/xhttp.BaseResponse"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match header is missing",
+                        "schema": {
+                            "$ref": "#/definitions/xhttp.BaseResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Accepts a JSON Merge Patch (application/merge-patch+json) or a JSON Patch (application/json-patch+json) over title, author, publish_year, published and isbn",
+                "consumes": [
+                    "application/merge-patch+json",
+                    "application/json-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "books"
+                ],
+                "summary": "Partially update book data by ID, return updated data",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "book ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the book version being patched",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "merge patch object or json patch operations array",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/xhttp.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Book"
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "patched book version"
+                            }
+                        }
+                    },
+                    "412": {
+                        "description": "book has been modified since the given ETag, or a json patch test operation failed",
+                        "schema": {
+                            "$ref": "#/definitions/xhttp.BaseResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "unsupported patch content type",
+                        "schema": {
+                            "$ref": "#/definitions/xhttp.BaseResponse"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match header is missing",
+                        "schema": {
+                            "$ref": "#/definitions/xhttp.BaseResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/books/{id}/editions": {
+            "get": {
+                "description": "The book is the work, its editions are the forms it was published in, in the order they were added.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "editions"
+                ],
+                "summary": "List the editions of a book by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "book ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter by format (hardcover, paperback, ebook, audiobook, large_print)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter by BCP 47 language, e.g. en or pt-BR",
+                        "name": "language",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/xhttp.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.Edition"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Format is required. An ISBN belongs to one work, so one already on another edition or on another live book answers 409. Editions are not part of the book version, so this neither bumps the version nor adds a revision.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "editions"
+                ],
+                "summary": "Add an edition to a book by ID, return stored data",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "book ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "edition data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.StoreEditionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/xhttp.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Edition"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/books/{id}/editions/{edition_id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "editions"
+                ],
+                "summary": "Get an edition of a book by their IDs",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "book ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "edition ID",
+                        "name": "edition_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/xhttp.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Edition"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Every field is replaced, leaving one out clears it. Format is required.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "editions"
+                ],
+                "summary": "Update an edition of a book by their IDs, return updated data",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "book ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "edition ID",
+                        "name": "edition_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "edition data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.UpdateEditionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/xhttp.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Edition"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "editions"
+                ],
+                "summary": "Delete an edition of a book by their IDs",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "book ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "edition ID",
+                        "name": "edition_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/xhttp.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "message": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/books/{id}/genres": {
+            "put": {
+                "description": "Genres are not part of the book version, so this neither bumps the version nor adds a revision. An empty genre_ids clears them.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "genres"
+                ],
+                "summary": "Replace the genres of a book by ID, return them",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "book ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "genres of the book",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.SetBookGenresRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/xhttp.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.BookGenre"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/books/{id}/history": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "books"
+                ],
+                "summary": "Get every revision of a book, oldest first, with field level diffs",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "book ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/xhttp.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.BookRevision"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/books/{id}/history/{rev}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "books"
+                ],
+                "summary": "Get a single revision of a book with its before and after snapshots",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "book ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "revision, the book version the change produced",
+                        "name": "rev",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/xhttp.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.BookRevision"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/books/{id}/identifiers": {
+            "put": {
+                "description": "Schemes are oclc, lccn, openlibrary and goodreads, values are checked against the scheme format and normalized. Identifiers are not part of the book version, so this neither bumps the version nor adds a revision. An empty list clears them.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "books"
+                ],
+                "summary": "Replace the outside identifiers of a book by ID, return them",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "book ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "identifiers of the book",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.SetBookIdentifiersRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/xhttp.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.BookIdentifier"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/books/{id}/merge": {
+            "post": {
+                "description": "The merged books are soft deleted pointing to the book, which takes over their editions, identifiers, genres and tags, and their ISBN when it has none. With as_editions each merged book becomes an edition of the book instead, keeping its ISBN and publication date. Reading a merged book redirects to the book. All in one transaction.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "books"
+                ],
+                "summary": "Merge books into the book by ID, admin only",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "book ID to merge into",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "books to merge",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.MergeBooksRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/xhttp.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.BookMerge"
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "book version"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/books/{id}/restore": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "books"
+                ],
+                "summary": "Restore a soft deleted book by ID, return restored data, admin only",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "book ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/xhttp.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Book"
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "restored book version"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/books/{id}/revert": {
+            "post": {
+                "description": "The revert is recorded as a new revision. Soft deleted books have to be restored first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "books"
+                ],
+                "summary": "Revert title, author and publish year of a book to a snapshot, return reverted data",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "book ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "snapshot to revert to, from GET /books/{id}/snapshots",
+                        "name": "snapshot",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "current book ETag",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/xhttp.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Book"
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "reverted book version"
+                            }
+                        }
+                    },
+                    "412": {
+                        "description": "If-Match does not match the current version",
+                        "schema": {
+                            "$ref": "#/definitions/xhttp.BaseResponse"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match header is missing",
+                        "schema": {
+                            "$ref": "#/definitions/xhttp.BaseResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/books/{id}/snapshots": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "books"
+                ],
+                "summary": "Get the book as it was after each of its revisions, oldest first, to pick a revert target",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "book ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/xhttp.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.BookSnapshot"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/books/{id}/tags": {
+            "put": {
+                "description": "Unknown tags are added. Tags are not part of the book version, so this neither bumps the version nor adds a revision. An empty list clears them.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Replace the tags of a book by ID, return them",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "book ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "tags of the book",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.SetBookTagsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/xhttp.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "type": "string"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/genres": {
+            "get": {
+                "description": "Genres come ordered by path, every genre right after its parent. Book counts include the genres below.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "genres"
+                ],
+                "summary": "List the whole genre tree",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/xhttp.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.Genre"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "A genre without parent_id is added at the top of the tree. Names are unique among the genres under the same parent.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "genres"
+                ],
+                "summary": "Store new genre data, return stored data",
+                "parameters": [
+                    {
+                        "description": "genre data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.StoreGenreRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/xhttp.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Genre"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/genres/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "genres"
+                ],
+                "summary": "Get a genre data by its ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "genre ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/xhttp.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Genre"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Changing parent_id moves the genre with everything below it. A genre can not be moved below itself, leaving parent_id out moves it to the top.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "genres"
+                ],
+                "summary": "Update genre data by ID, return updated data",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "genre ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "genre data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.UpdateGenreRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/xhttp.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Genre"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "The genre is taken off its books. Refused while other genres are below it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "genres"
+                ],
+                "summary": "Delete genre data by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "genre ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/xhttp.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "message": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/tags": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "List tags, most used first",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "only tags starting with it",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "max tags, default 20, max 100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/xhttp.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.Tag"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Names are lower cased with single spaces.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Store new tag data, return stored data",
+                "parameters": [
+                    {
+                        "description": "tag data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.StoreTagRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/xhttp.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Tag"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/tags/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Get a tag data by its ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "tag ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/xhttp.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Tag"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "The new name shows on every book carrying the tag.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Update tag data by ID, return updated data",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "tag ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "tag data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.UpdateTagRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/xhttp.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Tag"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "The tag is taken off its books.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Delete tag data by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "tag ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/xhttp.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "message": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/url/cleanup": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "urlCleaner"
+                ],
+                "summary": "Clean up URL with either canonical, redirection, or both (all) operations",
+                "parameters": [
+                    {
+                        "description": "URL to be clean and the selected operation ('canonical', 'redirection', 'all')",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.URLCleanerRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.URLCleanerResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "model.Author": {
+            "type": "object",
+            "properties": {
+                "aliases": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Eric Arthur Blair"
+                    ]
+                },
+                "biography": {
+                    "type": "string",
+                    "example": "English novelist, essayist and critic."
+                },
+                "birth_year": {
+                    "type": "integer",
+                    "example": 1903
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "death_year": {
+                    "type": "integer",
+                    "example": 1950
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "name": {
+                    "type": "string",
+                    "example": "George Orwell"
+                },
+                "nationality": {
+                    "type": "string",
+                    "example": "British"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.AuthorDuplicate": {
+            "type": "object",
+            "properties": {
+                "authors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.AuthorRef"
+                    }
+                }
+            }
+        },
+        "model.AuthorMerge": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "$ref": "#/definitions/model.Author"
+                },
+                "book_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        8,
+                        10
+                    ]
+                },
+                "merged_author_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        7,
+                        9
+                    ]
+                }
+            }
+        },
+        "model.AuthorRef": {
+            "type": "object",
+            "properties": {
+                "books": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "name": {
+                    "type": "string",
+                    "example": "J. Tolkien"
+                }
+            }
+        },
+        "model.Book": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "description": "Author is the display string of Authors, the names credited as author\njoined by \" \u0026 \", kept for clients of the single author field",
+                    "type": "string",
+                    "example": "Terry Pratchett \u0026 Neil Gaiman"
+                },
+                "authors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.BookAuthor"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "editions": {
+                    "description": "only filled on single book reads and on listings asking for them",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Edition"
+                    }
+                },
+                "genres": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.BookGenre"
+                    }
+                },
+                "highlight": {
+                    "$ref": "#/definitions/model.BookHighlight"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "identifiers": {
+                    "description": "only filled on reads, identifiers, genres and tags are set through\ntheir own endpoints and are not part of the book version",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.BookIdentifier"
+                    }
+                },
+                "isbn": {
+                    "description": "ISBN is stored as ISBN-13, ISBN10 is derived from it when there is one",
+                    "type": "string",
+                    "example": "9780261103573"
+                },
+                "isbn_10": {
+                    "type": "string",
+                    "example": "0261103571"
+                },
+                "publish_year": {
+                    "type": "integer"
+                },
+                "published": {
+                    "description": "Published is the EDTF publication date, PublishYear is its year, 0\nbeing 1 BCE",
+                    "type": "string",
+                    "example": "1949-06-08"
+                },
+                "rank": {
+                    "description": "only filled on full-text search",
+                    "type": "number"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "description": "bumped on every write, the version part of the book ETag",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.BookAuthor": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Neil Gaiman"
+                },
+                "role": {
+                    "type": "string",
+                    "example": "author"
+                }
+            }
+        },
+        "model.BookBatchOperation": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "type": "string"
+                },
+                "authors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.BookAuthor"
+                    }
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 11
+                },
+                "isbn": {
+                    "type": "string"
+                },
+                "op": {
+                    "type": "string",
+                    "example": "update"
+                },
+                "publish_year": {
+                    "type": "integer"
+                },
+                "published": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "model.BookBatchRequest": {
+            "type": "object",
+            "properties": {
+                "operations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.BookBatchOperation"
+                    }
+                }
+            }
+        },
+        "model.BookBatchResult": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/model.Book"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 11
+                },
+                "index": {
+                    "type": "integer"
+                },
+                "matches": {
+                    "description": "stored books a create probably is, best first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.BookMatch"
+                    }
+                },
+                "op": {
+                    "type": "string",
+                    "example": "update"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "applied"
+                }
+            }
+        },
+        "model.BookDuplicate": {
+            "type": "object",
+            "properties": {
+                "books": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.BookRef"
+                    }
+                },
+                "score": {
+                    "type": "number",
+                    "example": 0.93
+                }
+            }
+        },
+        "model.BookFieldChange": {
+            "type": "object",
+            "properties": {
+                "after": {},
+                "before": {},
+                "field": {
+                    "type": "string",
+                    "example": "title"
+                }
+            }
+        },
+        "model.BookGenre": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "name": {
+                    "type": "string",
+                    "example": "High Fantasy"
+                }
+            }
+        },
+        "model.BookHighlight": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "The \u003cmark\u003eLord\u003c/mark\u003e of the \u003cmark\u003eRings\u003c/mark\u003e"
+                }
+            }
+        },
+        "model.BookIdentifier": {
+            "type": "object",
+            "properties": {
+                "scheme": {
+                    "type": "string",
+                    "example": "oclc"
+                },
+                "value": {
+                    "type": "string",
+                    "example": "12345"
+                }
+            }
+        },
+        "model.BookImportReport": {
+            "type": "object",
+            "properties": {
+                "accepted": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "imported": {
+                    "description": "number of books actually stored, zero on dry run or aborted import",
+                    "type": "integer",
+                    "example": 0
+                },
+                "mode": {
+                    "type": "string",
+                    "example": "all_or_nothing"
+                },
+                "rejected": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.BookImportRow"
+                    }
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "model.BookImportRow": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "publish year field is empty or less than equal 0"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "line": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "matches": {
+                    "description": "stored books the row probably is, best first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.BookMatch"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "example": "rejected"
+                }
+            }
+        },
+        "model.BookListMetadata": {
+            "type": "object",
+            "properties": {
+                "did_you_mean": {
+                    "type": "string",
+                    "example": "J.R.R. Tolkien"
+                },
+                "limit": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "prev_cursor": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.BookMatch": {
+            "type": "object",
+            "properties": {
+                "book": {
+                    "$ref": "#/definitions/model.BookRef"
+                },
+                "score": {
+                    "type": "number",
+                    "example": 0.93
+                }
+            }
+        },
+        "model.BookMerge": {
+            "type": "object",
+            "properties": {
+                "book": {
+                    "$ref": "#/definitions/model.Book"
+                },
+                "merged_book_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        12
+                    ]
+                }
+            }
+        },
+        "model.BookRef": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "type": "string",
+                    "example": "J.R.R. Tolkien"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 8
+                },
+                "publish_year": {
+                    "type": "integer",
+                    "example": 1937
+                },
+                "title": {
+                    "type": "string",
+                    "example": "The Hobbit"
+                }
+            }
+        },
+        "model.BookRevision": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "type": "string",
+                    "example": "admin"
+                },
+                "after": {
+                    "$ref": "#/definitions/model.Book"
+                },
+                "before": {
+                    "$ref": "#/definitions/model.Book"
+                },
+                "book_id": {
+                    "type": "integer",
+                    "example": 11
+                },
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.BookFieldChange"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "operation": {
+                    "type": "string",
+                    "example": "update"
+                },
+                "request_id": {
+                    "type": "string"
+                },
+                "reverted_to": {
+                    "description": "RevertedTo is the snapshot a revert restored the book fields from",
+                    "type": "integer",
+                    "example": 1
+                },
+                "revision": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "model.BookSnapshot": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "type": "string",
+                    "example": "admin"
+                },
+                "book": {
+                    "$ref": "#/definitions/model.Book"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "operation": {
+                    "type": "string",
+                    "example": "update"
+                },
+                "snapshot": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "model.BookSuggestion": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "value": {
+                    "type": "string",
+                    "example": "J.R.R. Tolkien"
+                }
+            }
+        },
+        "model.Edition": {
+            "type": "object",
+            "properties": {
+                "book_id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "format": {
+                    "type": "string",
+                    "example": "paperback"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "isbn": {
+                    "type": "string",
+                    "example": "9780141439518"
+                },
+                "isbn_10": {
+                    "type": "string",
+                    "example": "0141439513"
+                },
+                "language": {
+                    "type": "string",
+                    "example": "en"
+                },
+                "page_count": {
+                    "type": "integer",
+                    "example": 480
+                },
+                "published": {
+                    "type": "string",
+                    "example": "2002-12-31"
+                },
+                "publisher": {
+                    "type": "string",
+                    "example": "Penguin Classics"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Genre": {
             "type": "object",
             "properties": {
-                "current_page": {
+                "books": {
                     "type": "integer",
-                    "example": 1
+                    "example": 2
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string"
                 },
-                "first_page": {
+                "id": {
                     "type": "integer",
-                    "example": 1
+                    "example": 3
+                },
+                "name": {
+                    "type": "string",
+                    "example": "High Fantasy"
                 },
-                "last_page": {
+                "parent_id": {
                     "type": "integer",
-                    "example": 1
+                    "example": 2
+                },
+                "path": {
+                    "type": "string",
+                    "example": "Fiction \u003e Fantasy \u003e High Fantasy"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.MergeAuthorsRequest": {
+            "type": "object",
+            "properties": {
+                "author_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        7,
+                        9
+                    ]
+                }
+            }
+        },
+        "model.MergeBooksRequest": {
+            "type": "object",
+            "properties": {
+                "as_editions": {
+                    "type": "boolean"
+                },
+                "book_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        12
+                    ]
+                }
+            }
+        },
+        "model.SetBookGenresRequest": {
+            "type": "object",
+            "properties": {
+                "genre_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        3,
+                        7
+                    ]
+                }
+            }
+        },
+        "model.SetBookIdentifiersRequest": {
+            "type": "object",
+            "properties": {
+                "identifiers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.BookIdentifier"
+                    }
+                }
+            }
+        },
+        "model.SetBookTagsRequest": {
+            "type": "object",
+            "properties": {
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "quest",
+                        "war"
+                    ]
+                }
+            }
+        },
+        "model.StoreAuthorRequest": {
+            "type": "object",
+            "properties": {
+                "aliases": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "biography": {
+                    "type": "string"
+                },
+                "birth_year": {
+                    "type": "integer"
+                },
+                "death_year": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "nationality": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.StoreBookRequest": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "type": "string"
+                },
+                "authors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.BookAuthor"
+                    }
+                },
+                "isbn": {
+                    "type": "string",
+                    "example": "0-261-10357-1"
+                },
+                "publish_year": {
+                    "type": "integer"
+                },
+                "published": {
+                    "type": "string",
+                    "example": "1949-06-08"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.StoreEditionRequest": {
+            "type": "object",
+            "properties": {
+                "format": {
+                    "type": "string",
+                    "example": "paperback"
+                },
+                "isbn": {
+                    "type": "string",
+                    "example": "0-14-143951-3"
+                },
+                "language": {
+                    "type": "string",
+                    "example": "en"
+                },
+                "page_count": {
+                    "type": "integer",
+                    "example": 480
+                },
+                "published": {
+                    "type": "string",
+                    "example": "2002-12-31"
+                },
+                "publisher": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.StoreGenreRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.StoreTagRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Tag": {
+            "type": "object",
+            "properties": {
+                "books": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "name": {
+                    "type": "string",
+                    "example": "coming of age"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.URLCleanerRequest": {
+            "type": "object",
+            "properties": {
+                "operation": {
+                    "type": "string",
+                    "example": "canonical,redirection,all"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.URLCleanerResponse": {
+            "type": "object",
+            "properties": {
+                "processed_url": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.UpdateAuthorRequest": {
+            "type": "object",
+            "properties": {
+                "aliases": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "biography": {
+                    "type": "string"
+                },
+                "birth_year": {
+                    "type": "integer"
+                },
+                "death_year": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "nationality": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.UpdateBookRequest": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "type": "string"
+                },
+                "authors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.BookAuthor"
+                    }
+                },
+                "isbn": {
+                    "type": "string",
+                    "example": "0-261-10357-1"
+                },
+                "publish_year": {
+                    "type": "integer"
+                },
+                "published": {
+                    "type": "string",
+                    "example": "1949-06-08"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.UpdateEditionRequest": {
+            "type": "object",
+            "properties": {
+                "format": {
+                    "type": "string",
+                    "example": "paperback"
+                },
+                "isbn": {
+                    "type": "string",
+                    "example": "0-14-143951-3"
                 },
-                "page_size": {
+                "language": {
+                    "type": "string",
+                    "example": "en"
+                },
+                "page_count": {
                     "type": "integer",
-                    "example": 50
+                    "example": 480
+                },
+                "published": {
+                    "type": "string",
+                    "example": "2002-12-31"
+                },
+                "publisher": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.UpdateGenreRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
                 },
-                "total_records": {
+                "parent_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.UpdateTagRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "pagination.CursorMetadata": {
+            "type": "object",
+            "properties": {
+                "limit": {
                     "type": "integer",
-                    "example": 1
+                    "example": 20
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "prev_cursor": {
+                    "type": "string"
                 }
             }
         },
+        "xhttp.BaseListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "error": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "metadata": {}
+            }
+        },
         "xhttp.BaseResponse": {
             "type": "object",
             "properties": {
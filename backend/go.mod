@@ -13,6 +13,7 @@ require (
 	github.com/swaggo/http-swagger/v2 v2.0.2
 	github.com/swaggo/swag v1.16.6
 	go.uber.org/mock v0.5.2
+	golang.org/x/text v0.21.0
 )
 
 require (
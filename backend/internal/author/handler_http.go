@@ -0,0 +1,365 @@
+package author
+
+import (
+	"byfood-app/internal/core"
+	"byfood-app/internal/model"
+	"byfood-app/internal/pkg/pagination"
+	"byfood-app/internal/pkg/xerrors"
+	"byfood-app/internal/pkg/xhttp"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type AuthorHandler struct {
+	deps  *core.Dependency
+	logic LogicInterface
+}
+
+func NewHTTPHandler(deps *core.Dependency, logic LogicInterface) *AuthorHandler {
+	return &AuthorHandler{
+		deps:  deps,
+		logic: logic,
+	}
+}
+
+// GetAuthors godoc
+// @Summary List authors by name with cursor pagination
+// @Tags authors
+// @Produce json
+// @Param search query string false "search param to search by name and aliases"
+// @Param after query string false "cursor to fetch the page after, taken from next_cursor"
+// @Param before query string false "cursor to fetch the page before, taken from prev_cursor"
+// @Param limit query integer false "item per page, max 100"
+// @Success 200 {object} xhttp.BaseListResponse{data=[]model.Author,metadata=pagination.CursorMetadata}
+// @Header 200 {string} Link "RFC 8288 links to the next and previous pages"
+// @Router /authors [get]
+func (h *AuthorHandler) GetAuthors(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	page, err := pagination.ParseCursorRequest(r, []byte(h.deps.CursorSecret))
+	if err != nil {
+		h.deps.Logger.WarnContext(ctx, "failed to parse pagination params", slog.Any("error", err))
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to parse pagination params",
+		}, http.StatusBadRequest)
+		return
+	}
+
+	data, meta, err := h.logic.GetAuthors(ctx, model.AuthorSearchParams{
+		Search: r.URL.Query().Get("search"),
+	}, page)
+	if err != nil && !errors.Is(err, xerrors.ErrDataNotFound) {
+		h.deps.Logger.ErrorContext(ctx, "failed to get author(s)", slog.Any("error", err))
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to get author(s)",
+		}, xerrors.ParseErrorTypeToCodeInt(err))
+		return
+	}
+
+	err = meta.Encode([]byte(h.deps.CursorSecret))
+	if err != nil {
+		h.deps.Logger.ErrorContext(ctx, "failed to encode pagination cursor", slog.Any("error", err))
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to get author(s)",
+		}, http.StatusInternalServerError)
+		return
+	}
+
+	if links := meta.Links(r.URL); links != "" {
+		w.Header().Set("Link", links)
+	}
+
+	xhttp.SendJSONResponse(w, xhttp.BaseListResponse{
+		Message:  "authors fetched",
+		Data:     data,
+		Metadata: meta,
+	}, http.StatusOK)
+}
+
+// GetAuthor godoc
+// @Summary Get an author data by its ID
+// @Tags authors
+// @Produce json
+// @Param id path integer true "author ID"
+// @Success 200 {object} xhttp.BaseResponse{data=model.Author}
+// @Router /authors/{id} [get]
+func (h *AuthorHandler) GetAuthorByID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// get and validate id param
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   xerrors.ErrInvalidID.Error(),
+			Message: xerrors.ErrInvalidID.Error(),
+		}, http.StatusBadRequest)
+		return
+	}
+	idParam, err := strconv.Atoi(id)
+	if err != nil {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to parse id parameter",
+		}, http.StatusBadRequest)
+		return
+	}
+
+	data, err := h.logic.GetAuthorByID(ctx, int64(idParam))
+	if err != nil {
+		h.deps.Logger.ErrorContext(ctx, "failed to get author data by id", slog.Any("error", err))
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to get author data",
+		}, xerrors.ParseErrorTypeToCodeInt(err))
+		return
+	}
+
+	xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+		Data:    data,
+		Message: "author data fetched",
+	}, http.StatusOK)
+}
+
+// StoreAuthor godoc
+// @Summary Store new author data, return stored data
+// @Description Years are 0 or left out when unknown, negative for BCE. Storing the name of a deleted author brings that author back.
+// @Tags authors
+// @Produce json
+// @Param data body model.StoreAuthorRequest true "author data"
+// @Success 200 {object} xhttp.BaseResponse{data=model.Author}
+// @Router /authors [post]
+func (h *AuthorHandler) StoreAuthor(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// parse request body
+	var payload model.StoreAuthorRequest
+	err := xhttp.BindJSONRequest(r, &payload)
+	if err != nil {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to parse request body",
+		}, http.StatusBadRequest)
+		return
+	}
+
+	data, err := h.logic.StoreAuthor(ctx, model.Author{
+		Name:        payload.Name,
+		Biography:   payload.Biography,
+		BirthYear:   payload.BirthYear,
+		DeathYear:   payload.DeathYear,
+		Nationality: payload.Nationality,
+		Aliases:     payload.Aliases,
+	})
+	if err != nil {
+		h.deps.Logger.ErrorContext(ctx, "failed to store author data", slog.Any("error", err))
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to store author data",
+		}, xerrors.ParseErrorTypeToCodeInt(err))
+		return
+	}
+
+	xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+		Data:    data,
+		Message: "author data stored",
+	}, http.StatusOK)
+}
+
+// UpdateAuthor godoc
+// @Summary Update author data by ID, return updated data
+// @Description Renaming an author rewrites the author field of every book crediting them.
+// @Tags authors
+// @Produce json
+// @Param id path integer true "author ID"
+// @Param data body model.UpdateAuthorRequest true "author data"
+// @Success 200 {object} xhttp.BaseResponse{data=model.Author}
+// @Router /authors/{id} [put]
+func (h *AuthorHandler) UpdateAuthor(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// get and validate id param
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   xerrors.ErrInvalidID.Error(),
+			Message: xerrors.ErrInvalidID.Error(),
+		}, http.StatusBadRequest)
+		return
+	}
+	idParam, err := strconv.Atoi(id)
+	if err != nil {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to parse id parameter",
+		}, http.StatusBadRequest)
+		return
+	}
+
+	// parse request body
+	var payload model.UpdateAuthorRequest
+	err = xhttp.BindJSONRequest(r, &payload)
+	if err != nil {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to parse request body",
+		}, http.StatusBadRequest)
+		return
+	}
+
+	data, err := h.logic.UpdateAuthor(ctx, model.Author{
+		ID:          int64(idParam),
+		Name:        payload.Name,
+		Biography:   payload.Biography,
+		BirthYear:   payload.BirthYear,
+		DeathYear:   payload.DeathYear,
+		Nationality: payload.Nationality,
+		Aliases:     payload.Aliases,
+	})
+	if err != nil {
+		h.deps.Logger.ErrorContext(ctx, "failed to update author data", slog.Any("error", err))
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to update author data",
+		}, xerrors.ParseErrorTypeToCodeInt(err))
+		return
+	}
+
+	xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+		Data:    data,
+		Message: "author data updated",
+	}, http.StatusOK)
+}
+
+// DeleteAuthor godoc
+// @Summary Soft delete author data by ID
+// @Description Refused while a book that is not deleted still credits the author.
+// @Tags authors
+// @Produce json
+// @Param id path integer true "author ID"
+// @Success 200 {object} xhttp.BaseResponse{message=string}
+// @Router /authors/{id} [delete]
+func (h *AuthorHandler) DeleteAuthor(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// get and validate id param
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   xerrors.ErrInvalidID.Error(),
+			Message: xerrors.ErrInvalidID.Error(),
+		}, http.StatusBadRequest)
+		return
+	}
+	idParam, err := strconv.Atoi(id)
+	if err != nil {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to parse id parameter",
+		}, http.StatusBadRequest)
+		return
+	}
+
+	err = h.logic.DeleteAuthor(ctx, int64(idParam))
+	if err != nil {
+		h.deps.Logger.ErrorContext(ctx, "failed to delete author data", slog.Any("error", err))
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to delete author data",
+		}, xerrors.ParseErrorTypeToCodeInt(err))
+		return
+	}
+
+	xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+		Message: "author data deleted",
+	}, http.StatusOK)
+}
+
+// GetAuthorDuplicates godoc
+// @Summary List pairs of authors that probably name the same person, admin only
+// @Description Pairs share a surname and have given names that match or are initials of each other, e.g. "J. Tolkien" and "J.R.R. Tolkien".
+// @Tags authors
+// @Produce json
+// @Success 200 {object} xhttp.BaseResponse{data=[]model.AuthorDuplicate}
+// @Router /authors/duplicates [get]
+func (h *AuthorHandler) GetAuthorDuplicates(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	data, err := h.logic.GetAuthorDuplicates(ctx)
+	if err != nil {
+		h.deps.Logger.ErrorContext(ctx, "failed to get author duplicates", slog.Any("error", err))
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to get author duplicates",
+		}, xerrors.ParseErrorTypeToCodeInt(err))
+		return
+	}
+
+	xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+		Data:    data,
+		Message: "author duplicates fetched",
+	}, http.StatusOK)
+}
+
+// MergeAuthors godoc
+// @Summary Merge authors into the author by ID, admin only
+// @Description Book credits of the merged authors move to the author, their names become aliases and they are deleted. Affected books get their author string rebuilt and a new version, all in one transaction.
+// @Tags authors
+// @Produce json
+// @Param id path integer true "author ID to merge into"
+// @Param data body model.MergeAuthorsRequest true "authors to merge"
+// @Success 200 {object} xhttp.BaseResponse{data=model.AuthorMerge}
+// @Router /authors/{id}/merge [post]
+func (h *AuthorHandler) MergeAuthors(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// get and validate id param
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   xerrors.ErrInvalidID.Error(),
+			Message: xerrors.ErrInvalidID.Error(),
+		}, http.StatusBadRequest)
+		return
+	}
+	idParam, err := strconv.Atoi(id)
+	if err != nil {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to parse id parameter",
+		}, http.StatusBadRequest)
+		return
+	}
+
+	// parse request body
+	var payload model.MergeAuthorsRequest
+	err = xhttp.BindJSONRequest(r, &payload)
+	if err != nil {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to parse request body",
+		}, http.StatusBadRequest)
+		return
+	}
+
+	data, err := h.logic.MergeAuthors(ctx, int64(idParam), payload.AuthorIDs)
+	if err != nil {
+		h.deps.Logger.ErrorContext(ctx, "failed to merge authors", slog.Any("error", err))
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to merge authors",
+		}, xerrors.ParseErrorTypeToCodeInt(err))
+		return
+	}
+
+	xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+		Data:    data,
+		Message: "authors merged",
+	}, http.StatusOK)
+}
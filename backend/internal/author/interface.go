@@ -0,0 +1,29 @@
+package author
+
+import (
+	"byfood-app/internal/model"
+	"byfood-app/internal/pkg/pagination"
+	"context"
+)
+
+//go:generate go run go.uber.org/mock/mockgen@latest -source=interface.go -destination=mock_interface.go -package=author
+type RepositoryInterface interface {
+	GetAuthors(ctx context.Context, params model.AuthorSearchParams, page pagination.CursorPage) ([]model.Author, pagination.CursorMetadata, error)
+	GetAuthorByID(ctx context.Context, id int64) (model.Author, error)
+	StoreAuthor(ctx context.Context, data model.Author) (model.Author, error)
+	UpdateAuthor(ctx context.Context, data model.Author) (model.Author, error)
+	DeleteAuthor(ctx context.Context, id int64) error
+	GetAuthorDuplicates(ctx context.Context) ([]model.AuthorDuplicate, error)
+	MergeAuthors(ctx context.Context, id int64, sourceIDs []int64) (model.AuthorMerge, error)
+}
+
+//go:generate go run go.uber.org/mock/mockgen@latest -source=interface.go -destination=mock_interface.go -package=author
+type LogicInterface interface {
+	GetAuthors(ctx context.Context, params model.AuthorSearchParams, page pagination.CursorPage) ([]model.Author, pagination.CursorMetadata, error)
+	GetAuthorByID(ctx context.Context, id int64) (model.Author, error)
+	StoreAuthor(ctx context.Context, data model.Author) (model.Author, error)
+	UpdateAuthor(ctx context.Context, data model.Author) (model.Author, error)
+	DeleteAuthor(ctx context.Context, id int64) error
+	GetAuthorDuplicates(ctx context.Context) ([]model.AuthorDuplicate, error)
+	MergeAuthors(ctx context.Context, id int64, sourceIDs []int64) (model.AuthorMerge, error)
+}
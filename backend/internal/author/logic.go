@@ -0,0 +1,220 @@
+package author
+
+import (
+	"byfood-app/internal/core"
+	"byfood-app/internal/model"
+	"byfood-app/internal/pkg/authorname"
+	"byfood-app/internal/pkg/pagination"
+	"byfood-app/internal/pkg/xerrors"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+)
+
+// nameSeparator joins the names in the author string of a book, so no name
+// may contain it.
+const nameSeparator = " & "
+
+const maxAuthorAliases = 50
+
+// maxMergedAuthors caps the authors folded into another in one merge.
+const maxMergedAuthors = 50
+
+type AuthorLogic struct {
+	deps *core.Dependency
+	repo RepositoryInterface
+}
+
+func NewAuthorLogic(deps *core.Dependency, repo RepositoryInterface) *AuthorLogic {
+	return &AuthorLogic{
+		deps: deps,
+		repo: repo,
+	}
+}
+
+func (logic *AuthorLogic) GetAuthors(ctx context.Context, params model.AuthorSearchParams, page pagination.CursorPage) ([]model.Author, pagination.CursorMetadata, error) {
+	params.Search = strings.TrimSpace(params.Search)
+
+	data, meta, err := logic.repo.GetAuthors(ctx, params, page)
+	if err != nil {
+		if !errors.Is(err, xerrors.ErrDataNotFound) {
+			logic.deps.Logger.ErrorContext(ctx, "failed to get authors", slog.Any("error", err))
+		}
+		return []model.Author{}, meta, err
+	}
+
+	return data, meta, nil
+}
+
+func (logic *AuthorLogic) GetAuthorByID(ctx context.Context, id int64) (model.Author, error) {
+	if id <= 0 {
+		return model.Author{}, xerrors.NewClientError(xerrors.ErrInvalidID)
+	}
+
+	data, err := logic.repo.GetAuthorByID(ctx, id)
+	if err != nil {
+		logic.deps.Logger.ErrorContext(ctx, "failed to get author by id", slog.Any("error", err))
+		return model.Author{}, err
+	}
+
+	return data, nil
+}
+
+func (logic *AuthorLogic) StoreAuthor(ctx context.Context, data model.Author) (model.Author, error) {
+	data = normalizeAuthor(data)
+	err := validateAuthor(data)
+	if err != nil {
+		return model.Author{}, err
+	}
+
+	result, err := logic.repo.StoreAuthor(ctx, data)
+	if err != nil {
+		logic.deps.Logger.ErrorContext(ctx, "failed to store author data", slog.Any("error", err))
+		return model.Author{}, err
+	}
+
+	return result, nil
+}
+
+func (logic *AuthorLogic) UpdateAuthor(ctx context.Context, data model.Author) (model.Author, error) {
+	if data.ID <= 0 {
+		return model.Author{}, xerrors.NewClientError(xerrors.ErrInvalidID)
+	}
+
+	data = normalizeAuthor(data)
+	err := validateAuthor(data)
+	if err != nil {
+		return model.Author{}, err
+	}
+
+	result, err := logic.repo.UpdateAuthor(ctx, data)
+	if err != nil {
+		logic.deps.Logger.ErrorContext(ctx, "failed to update author data", slog.Any("error", err))
+		return model.Author{}, err
+	}
+
+	return result, nil
+}
+
+func (logic *AuthorLogic) DeleteAuthor(ctx context.Context, id int64) error {
+	if id <= 0 {
+		return xerrors.NewClientError(xerrors.ErrInvalidID)
+	}
+
+	err := logic.repo.DeleteAuthor(ctx, id)
+	if err != nil {
+		logic.deps.Logger.ErrorContext(ctx, "failed to delete author data", slog.Any("error", err))
+		return err
+	}
+
+	return nil
+}
+
+// GetAuthorDuplicates narrows the authors sharing a surname down to the
+// pairs whose given names match or are initials of each other.
+func (logic *AuthorLogic) GetAuthorDuplicates(ctx context.Context) ([]model.AuthorDuplicate, error) {
+	candidates, err := logic.repo.GetAuthorDuplicates(ctx)
+	if err != nil {
+		logic.deps.Logger.ErrorContext(ctx, "failed to get author duplicates", slog.Any("error", err))
+		return nil, err
+	}
+
+	result := []model.AuthorDuplicate{}
+	for _, candidate := range candidates {
+		if authorname.ProbableDuplicate(authorname.Key(candidate.Authors[0].Name), authorname.Key(candidate.Authors[1].Name)) {
+			result = append(result, candidate)
+		}
+	}
+
+	return result, nil
+}
+
+func (logic *AuthorLogic) MergeAuthors(ctx context.Context, id int64, sourceIDs []int64) (model.AuthorMerge, error) {
+	if id <= 0 {
+		return model.AuthorMerge{}, xerrors.NewClientError(xerrors.ErrInvalidID)
+	}
+
+	sourceIDs = slices.Clone(sourceIDs)
+	slices.Sort(sourceIDs)
+	sourceIDs = slices.Compact(sourceIDs)
+
+	switch {
+	case len(sourceIDs) == 0:
+		return model.AuthorMerge{}, xerrors.NewClientError(errors.New("author_ids is empty"))
+	case len(sourceIDs) > maxMergedAuthors:
+		return model.AuthorMerge{}, xerrors.NewClientError(fmt.Errorf("at most %d authors can be merged at once", maxMergedAuthors))
+	case sourceIDs[0] <= 0:
+		return model.AuthorMerge{}, xerrors.NewClientError(xerrors.ErrInvalidID)
+	case slices.Contains(sourceIDs, id):
+		return model.AuthorMerge{}, xerrors.NewClientError(errors.New("an author can not be merged into itself"))
+	}
+
+	result, err := logic.repo.MergeAuthors(ctx, id, sourceIDs)
+	if err != nil {
+		logic.deps.Logger.ErrorContext(ctx, "failed to merge authors", slog.Any("error", err))
+		return model.AuthorMerge{}, err
+	}
+
+	return result, nil
+}
+
+// normalizeAuthor trims the text fields and normalizes the name and aliases
+// the way book credits are, then sorts the aliases dropping empty ones and
+// those matching the name or an earlier alias by name key.
+func normalizeAuthor(data model.Author) model.Author {
+	data.Name = authorname.Normalize(data.Name)
+	data.Biography = strings.TrimSpace(data.Biography)
+	data.Nationality = strings.TrimSpace(data.Nationality)
+
+	aliases := make([]string, 0, len(data.Aliases))
+	for _, alias := range data.Aliases {
+		alias = authorname.Normalize(alias)
+		if alias != "" {
+			aliases = append(aliases, alias)
+		}
+	}
+	slices.Sort(aliases)
+
+	keys := []string{authorname.Key(data.Name)}
+	data.Aliases = slices.DeleteFunc(aliases, func(alias string) bool {
+		key := authorname.Key(alias)
+		if slices.Contains(keys, key) {
+			return true
+		}
+		keys = append(keys, key)
+		return false
+	})
+
+	return data
+}
+
+func validateAuthor(data model.Author) error {
+	currentYear := int64(time.Now().Year())
+
+	switch {
+	case data.Name == "":
+		return xerrors.NewClientError(fmt.Errorf("name field is empty"))
+	case strings.Contains(data.Name, nameSeparator):
+		return xerrors.NewClientError(fmt.Errorf("name can not contain %q", nameSeparator))
+	case data.BirthYear != nil && *data.BirthYear > currentYear:
+		return xerrors.NewClientError(fmt.Errorf("birth year can not be in the future"))
+	case data.DeathYear != nil && *data.DeathYear > currentYear:
+		return xerrors.NewClientError(fmt.Errorf("death year can not be in the future"))
+	case data.BirthYear != nil && data.DeathYear != nil && *data.DeathYear < *data.BirthYear:
+		return xerrors.NewClientError(fmt.Errorf("death year can not be before birth year"))
+	case len(data.Aliases) > maxAuthorAliases:
+		return xerrors.NewClientError(fmt.Errorf("an author can have at most %d aliases", maxAuthorAliases))
+	}
+
+	for _, alias := range data.Aliases {
+		if strings.Contains(alias, nameSeparator) {
+			return xerrors.NewClientError(fmt.Errorf("alias %q can not contain %q", alias, nameSeparator))
+		}
+	}
+
+	return nil
+}
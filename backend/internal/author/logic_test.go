@@ -0,0 +1,330 @@
+package author
+
+import (
+	"byfood-app/internal/core"
+	"byfood-app/internal/model"
+	"byfood-app/internal/pkg/xerrors"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+)
+
+type testSuite struct {
+	Ctrl           *gomock.Controller
+	MockAuthorRepo *MockRepositoryInterface
+}
+
+func setupTestSuite(t *testing.T) *testSuite {
+	ctrl := gomock.NewController(t)
+	return &testSuite{
+		Ctrl:           ctrl,
+		MockAuthorRepo: NewMockRepositoryInterface(ctrl),
+	}
+}
+
+// year returns a pointer to y, for the known years of an author
+func year(y int64) *int64 {
+	return &y
+}
+
+func TestAuthorLogic_StoreAuthor(t *testing.T) {
+	type fields struct {
+		deps *core.Dependency
+		repo RepositoryInterface
+	}
+	type args struct {
+		ctx  context.Context
+		data model.Author
+	}
+
+	ts := setupTestSuite(t)
+	mockFields := fields{
+		deps: &core.Dependency{
+			Logger: slog.Default(),
+		},
+		repo: ts.MockAuthorRepo,
+	}
+
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		want     model.Author
+		wantCode int
+		mockFunc func()
+	}{
+		{
+			name:   "success store author with trimmed aliases",
+			fields: mockFields,
+			args: args{
+				ctx: context.Background(),
+				data: model.Author{
+					Name:        " George Orwell ",
+					BirthYear:   year(1903),
+					DeathYear:   year(1950),
+					Nationality: "British",
+					Aliases:     []string{"Eric Arthur Blair", " ", "George Orwell", "Eric Arthur Blair "},
+				},
+			},
+			want:     model.Author{ID: 2, Name: "George Orwell"},
+			wantCode: http.StatusOK,
+			mockFunc: func() {
+				ts.MockAuthorRepo.EXPECT().StoreAuthor(gomock.Any(), model.Author{
+					Name:        "George Orwell",
+					BirthYear:   year(1903),
+					DeathYear:   year(1950),
+					Nationality: "British",
+					Aliases:     []string{"Eric Arthur Blair"},
+				}).Return(model.Author{ID: 2, Name: "George Orwell"}, nil)
+			},
+		},
+		{
+			name:   "success store author with normalized name and alias variants",
+			fields: mockFields,
+			args: args{
+				ctx: context.Background(),
+				data: model.Author{
+					Name:    "Tolkien, J. R. R.",
+					Aliases: []string{"JRR Tolkien", "John Ronald Reuel Tolkien", "Tolkien, John Ronald Reuel"},
+				},
+			},
+			want:     model.Author{ID: 8, Name: "J.R.R. Tolkien"},
+			wantCode: http.StatusOK,
+			mockFunc: func() {
+				ts.MockAuthorRepo.EXPECT().StoreAuthor(gomock.Any(), model.Author{
+					Name:    "J.R.R. Tolkien",
+					Aliases: []string{"John Ronald Reuel Tolkien"},
+				}).Return(model.Author{ID: 8, Name: "J.R.R. Tolkien"}, nil)
+			},
+		},
+		{
+			name:   "success store author born BCE",
+			fields: mockFields,
+			args: args{
+				ctx: context.Background(),
+				data: model.Author{
+					Name:      "Homer",
+					BirthYear: year(-750),
+				},
+			},
+			want:     model.Author{ID: 5, Name: "Homer"},
+			wantCode: http.StatusOK,
+			mockFunc: func() {
+				ts.MockAuthorRepo.EXPECT().StoreAuthor(gomock.Any(), model.Author{
+					Name:      "Homer",
+					BirthYear: year(-750),
+					Aliases:   []string{},
+				}).Return(model.Author{ID: 5, Name: "Homer"}, nil)
+			},
+		},
+		{
+			name:   "failed store author without name",
+			fields: mockFields,
+			args: args{
+				ctx:  context.Background(),
+				data: model.Author{Name: " "},
+			},
+			wantCode: http.StatusBadRequest,
+			mockFunc: func() {},
+		},
+		{
+			name:   "failed store author with name joining two authors",
+			fields: mockFields,
+			args: args{
+				ctx:  context.Background(),
+				data: model.Author{Name: "Terry Pratchett & Neil Gaiman"},
+			},
+			wantCode: http.StatusBadRequest,
+			mockFunc: func() {},
+		},
+		{
+			name:   "failed store author dying before being born",
+			fields: mockFields,
+			args: args{
+				ctx:  context.Background(),
+				data: model.Author{Name: "George Orwell", BirthYear: year(1950), DeathYear: year(1903)},
+			},
+			wantCode: http.StatusBadRequest,
+			mockFunc: func() {},
+		},
+		{
+			name:   "failed store author born in the future",
+			fields: mockFields,
+			args: args{
+				ctx:  context.Background(),
+				data: model.Author{Name: "George Orwell", BirthYear: year(9999)},
+			},
+			wantCode: http.StatusBadRequest,
+			mockFunc: func() {},
+		},
+		{
+			name:   "failed store author with taken name",
+			fields: mockFields,
+			args: args{
+				ctx:  context.Background(),
+				data: model.Author{Name: "George Orwell"},
+			},
+			wantCode: http.StatusBadRequest,
+			mockFunc: func() {
+				ts.MockAuthorRepo.EXPECT().StoreAuthor(gomock.Any(), gomock.Any()).
+					Return(model.Author{}, xerrors.NewClientError(errors.New(`author "George Orwell" already exists`)))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logic := &AuthorLogic{
+				deps: tt.fields.deps,
+				repo: tt.fields.repo,
+			}
+
+			tt.mockFunc()
+
+			got, err := logic.StoreAuthor(tt.args.ctx, tt.args.data)
+			if err != nil && xerrors.ParseErrorTypeToCodeInt(err) != tt.wantCode {
+				t.Errorf("AuthorLogic.StoreAuthor() error = %v, wantCode %v", err, tt.wantCode)
+				return
+			}
+			if (err != nil) != (tt.wantCode != http.StatusOK) {
+				t.Errorf("AuthorLogic.StoreAuthor() error = %v, wantCode %v", err, tt.wantCode)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("AuthorLogic.StoreAuthor() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthorLogic_UpdateAuthor(t *testing.T) {
+	ts := setupTestSuite(t)
+	logic := &AuthorLogic{
+		deps: &core.Dependency{
+			Logger: slog.Default(),
+		},
+		repo: ts.MockAuthorRepo,
+	}
+
+	_, err := logic.UpdateAuthor(context.Background(), model.Author{Name: "George Orwell"})
+	if xerrors.ParseErrorTypeToCodeInt(err) != http.StatusBadRequest {
+		t.Errorf("AuthorLogic.UpdateAuthor() error = %v, want client error for id 0", err)
+	}
+
+	ts.MockAuthorRepo.EXPECT().UpdateAuthor(gomock.Any(), model.Author{
+		ID:      2,
+		Name:    "George Orwell",
+		Aliases: []string{"Eric Arthur Blair", "Eric Blair"},
+	}).Return(model.Author{ID: 2, Name: "George Orwell"}, nil)
+
+	got, err := logic.UpdateAuthor(context.Background(), model.Author{
+		ID:      2,
+		Name:    "George Orwell",
+		Aliases: []string{"Eric Blair", "Eric Arthur Blair"},
+	})
+	if err != nil {
+		t.Fatalf("AuthorLogic.UpdateAuthor() error = %v", err)
+	}
+	if got.ID != 2 {
+		t.Errorf("AuthorLogic.UpdateAuthor() = %+v, want author 2", got)
+	}
+}
+
+func TestAuthorLogic_GetAuthorDuplicates(t *testing.T) {
+	ts := setupTestSuite(t)
+	logic := &AuthorLogic{
+		deps: &core.Dependency{
+			Logger: slog.Default(),
+		},
+		repo: ts.MockAuthorRepo,
+	}
+
+	tolkien := model.AuthorRef{ID: 8, Name: "J.R.R. Tolkien", Books: 2}
+	initial := model.AuthorRef{ID: 12, Name: "J. Tolkien", Books: 1}
+	christopher := model.AuthorRef{ID: 14, Name: "Christopher Tolkien", Books: 1}
+
+	// every pair sharing a surname comes back, only the probable ones stay
+	ts.MockAuthorRepo.EXPECT().GetAuthorDuplicates(gomock.Any()).Return([]model.AuthorDuplicate{
+		{Authors: []model.AuthorRef{tolkien, initial}},
+		{Authors: []model.AuthorRef{tolkien, christopher}},
+		{Authors: []model.AuthorRef{initial, christopher}},
+	}, nil)
+
+	got, err := logic.GetAuthorDuplicates(context.Background())
+	if err != nil {
+		t.Fatalf("AuthorLogic.GetAuthorDuplicates() error = %v", err)
+	}
+
+	want := []model.AuthorDuplicate{{Authors: []model.AuthorRef{tolkien, initial}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("AuthorLogic.GetAuthorDuplicates() = %+v, want %+v", got, want)
+	}
+}
+
+func TestAuthorLogic_MergeAuthors(t *testing.T) {
+	ts := setupTestSuite(t)
+	logic := &AuthorLogic{
+		deps: &core.Dependency{
+			Logger: slog.Default(),
+		},
+		repo: ts.MockAuthorRepo,
+	}
+
+	tests := []struct {
+		name      string
+		id        int64
+		sourceIDs []int64
+		wantCode  int
+		mockFunc  func()
+	}{
+		{
+			name:      "success merge sorted unique sources",
+			id:        8,
+			sourceIDs: []int64{14, 12, 14},
+			wantCode:  http.StatusOK,
+			mockFunc: func() {
+				ts.MockAuthorRepo.EXPECT().MergeAuthors(gomock.Any(), int64(8), []int64{12, 14}).
+					Return(model.AuthorMerge{MergedAuthorIDs: []int64{12, 14}}, nil)
+			},
+		},
+		{
+			name:      "failed merge without sources",
+			id:        8,
+			sourceIDs: []int64{},
+			wantCode:  http.StatusBadRequest,
+			mockFunc:  func() {},
+		},
+		{
+			name:      "failed merge into itself",
+			id:        8,
+			sourceIDs: []int64{8, 12},
+			wantCode:  http.StatusBadRequest,
+			mockFunc:  func() {},
+		},
+		{
+			name:      "failed merge of an invalid id",
+			id:        8,
+			sourceIDs: []int64{0, 12},
+			wantCode:  http.StatusBadRequest,
+			mockFunc:  func() {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc()
+
+			_, err := logic.MergeAuthors(context.Background(), tt.id, tt.sourceIDs)
+			if err != nil && xerrors.ParseErrorTypeToCodeInt(err) != tt.wantCode {
+				t.Errorf("AuthorLogic.MergeAuthors() error = %v, wantCode %v", err, tt.wantCode)
+				return
+			}
+			if (err != nil) != (tt.wantCode != http.StatusOK) {
+				t.Errorf("AuthorLogic.MergeAuthors() error = %v, wantCode %v", err, tt.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,277 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: interface.go
+//
+// Generated by this command:
+//
+//	mockgen -source=interface.go -destination=mock_interface.go -package=author
+//
+
+// Package author is a generated GoMock package.
+package author
+
+import (
+	model "byfood-app/internal/model"
+	pagination "byfood-app/internal/pkg/pagination"
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRepositoryInterface is a mock of RepositoryInterface interface.
+type MockRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockRepositoryInterfaceMockRecorder is the mock recorder for MockRepositoryInterface.
+type MockRepositoryInterfaceMockRecorder struct {
+	mock *MockRepositoryInterface
+}
+
+// NewMockRepositoryInterface creates a new mock instance.
+func NewMockRepositoryInterface(ctrl *gomock.Controller) *MockRepositoryInterface {
+	mock := &MockRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepositoryInterface) EXPECT() *MockRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// DeleteAuthor mocks base method.
+func (m *MockRepositoryInterface) DeleteAuthor(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAuthor", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAuthor indicates an expected call of DeleteAuthor.
+func (mr *MockRepositoryInterfaceMockRecorder) DeleteAuthor(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAuthor", reflect.TypeOf((*MockRepositoryInterface)(nil).DeleteAuthor), ctx, id)
+}
+
+// GetAuthorByID mocks base method.
+func (m *MockRepositoryInterface) GetAuthorByID(ctx context.Context, id int64) (model.Author, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuthorByID", ctx, id)
+	ret0, _ := ret[0].(model.Author)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuthorByID indicates an expected call of GetAuthorByID.
+func (mr *MockRepositoryInterfaceMockRecorder) GetAuthorByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthorByID", reflect.TypeOf((*MockRepositoryInterface)(nil).GetAuthorByID), ctx, id)
+}
+
+// GetAuthorDuplicates mocks base method.
+func (m *MockRepositoryInterface) GetAuthorDuplicates(ctx context.Context) ([]model.AuthorDuplicate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuthorDuplicates", ctx)
+	ret0, _ := ret[0].([]model.AuthorDuplicate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuthorDuplicates indicates an expected call of GetAuthorDuplicates.
+func (mr *MockRepositoryInterfaceMockRecorder) GetAuthorDuplicates(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthorDuplicates", reflect.TypeOf((*MockRepositoryInterface)(nil).GetAuthorDuplicates), ctx)
+}
+
+// GetAuthors mocks base method.
+func (m *MockRepositoryInterface) GetAuthors(ctx context.Context, params model.AuthorSearchParams, page pagination.CursorPage) ([]model.Author, pagination.CursorMetadata, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuthors", ctx, params, page)
+	ret0, _ := ret[0].([]model.Author)
+	ret1, _ := ret[1].(pagination.CursorMetadata)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAuthors indicates an expected call of GetAuthors.
+func (mr *MockRepositoryInterfaceMockRecorder) GetAuthors(ctx, params, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthors", reflect.TypeOf((*MockRepositoryInterface)(nil).GetAuthors), ctx, params, page)
+}
+
+// MergeAuthors mocks base method.
+func (m *MockRepositoryInterface) MergeAuthors(ctx context.Context, id int64, sourceIDs []int64) (model.AuthorMerge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergeAuthors", ctx, id, sourceIDs)
+	ret0, _ := ret[0].(model.AuthorMerge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MergeAuthors indicates an expected call of MergeAuthors.
+func (mr *MockRepositoryInterfaceMockRecorder) MergeAuthors(ctx, id, sourceIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeAuthors", reflect.TypeOf((*MockRepositoryInterface)(nil).MergeAuthors), ctx, id, sourceIDs)
+}
+
+// StoreAuthor mocks base method.
+func (m *MockRepositoryInterface) StoreAuthor(ctx context.Context, data model.Author) (model.Author, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StoreAuthor", ctx, data)
+	ret0, _ := ret[0].(model.Author)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StoreAuthor indicates an expected call of StoreAuthor.
+func (mr *MockRepositoryInterfaceMockRecorder) StoreAuthor(ctx, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreAuthor", reflect.TypeOf((*MockRepositoryInterface)(nil).StoreAuthor), ctx, data)
+}
+
+// UpdateAuthor mocks base method.
+func (m *MockRepositoryInterface) UpdateAuthor(ctx context.Context, data model.Author) (model.Author, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAuthor", ctx, data)
+	ret0, _ := ret[0].(model.Author)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAuthor indicates an expected call of UpdateAuthor.
+func (mr *MockRepositoryInterfaceMockRecorder) UpdateAuthor(ctx, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAuthor", reflect.TypeOf((*MockRepositoryInterface)(nil).UpdateAuthor), ctx, data)
+}
+
+// MockLogicInterface is a mock of LogicInterface interface.
+type MockLogicInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockLogicInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockLogicInterfaceMockRecorder is the mock recorder for MockLogicInterface.
+type MockLogicInterfaceMockRecorder struct {
+	mock *MockLogicInterface
+}
+
+// NewMockLogicInterface creates a new mock instance.
+func NewMockLogicInterface(ctrl *gomock.Controller) *MockLogicInterface {
+	mock := &MockLogicInterface{ctrl: ctrl}
+	mock.recorder = &MockLogicInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLogicInterface) EXPECT() *MockLogicInterfaceMockRecorder {
+	return m.recorder
+}
+
+// DeleteAuthor mocks base method.
+func (m *MockLogicInterface) DeleteAuthor(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAuthor", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAuthor indicates an expected call of DeleteAuthor.
+func (mr *MockLogicInterfaceMockRecorder) DeleteAuthor(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAuthor", reflect.TypeOf((*MockLogicInterface)(nil).DeleteAuthor), ctx, id)
+}
+
+// GetAuthorByID mocks base method.
+func (m *MockLogicInterface) GetAuthorByID(ctx context.Context, id int64) (model.Author, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuthorByID", ctx, id)
+	ret0, _ := ret[0].(model.Author)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuthorByID indicates an expected call of GetAuthorByID.
+func (mr *MockLogicInterfaceMockRecorder) GetAuthorByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthorByID", reflect.TypeOf((*MockLogicInterface)(nil).GetAuthorByID), ctx, id)
+}
+
+// GetAuthorDuplicates mocks base method.
+func (m *MockLogicInterface) GetAuthorDuplicates(ctx context.Context) ([]model.AuthorDuplicate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuthorDuplicates", ctx)
+	ret0, _ := ret[0].([]model.AuthorDuplicate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuthorDuplicates indicates an expected call of GetAuthorDuplicates.
+func (mr *MockLogicInterfaceMockRecorder) GetAuthorDuplicates(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthorDuplicates", reflect.TypeOf((*MockLogicInterface)(nil).GetAuthorDuplicates), ctx)
+}
+
+// GetAuthors mocks base method.
+func (m *MockLogicInterface) GetAuthors(ctx context.Context, params model.AuthorSearchParams, page pagination.CursorPage) ([]model.Author, pagination.CursorMetadata, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuthors", ctx, params, page)
+	ret0, _ := ret[0].([]model.Author)
+	ret1, _ := ret[1].(pagination.CursorMetadata)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAuthors indicates an expected call of GetAuthors.
+func (mr *MockLogicInterfaceMockRecorder) GetAuthors(ctx, params, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthors", reflect.TypeOf((*MockLogicInterface)(nil).GetAuthors), ctx, params, page)
+}
+
+// MergeAuthors mocks base method.
+func (m *MockLogicInterface) MergeAuthors(ctx context.Context, id int64, sourceIDs []int64) (model.AuthorMerge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergeAuthors", ctx, id, sourceIDs)
+	ret0, _ := ret[0].(model.AuthorMerge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MergeAuthors indicates an expected call of MergeAuthors.
+func (mr *MockLogicInterfaceMockRecorder) MergeAuthors(ctx, id, sourceIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeAuthors", reflect.TypeOf((*MockLogicInterface)(nil).MergeAuthors), ctx, id, sourceIDs)
+}
+
+// StoreAuthor mocks base method.
+func (m *MockLogicInterface) StoreAuthor(ctx context.Context, data model.Author) (model.Author, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StoreAuthor", ctx, data)
+	ret0, _ := ret[0].(model.Author)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StoreAuthor indicates an expected call of StoreAuthor.
+func (mr *MockLogicInterfaceMockRecorder) StoreAuthor(ctx, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreAuthor", reflect.TypeOf((*MockLogicInterface)(nil).StoreAuthor), ctx, data)
+}
+
+// UpdateAuthor mocks base method.
+func (m *MockLogicInterface) UpdateAuthor(ctx context.Context, data model.Author) (model.Author, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAuthor", ctx, data)
+	ret0, _ := ret[0].(model.Author)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAuthor indicates an expected call of UpdateAuthor.
+func (mr *MockLogicInterfaceMockRecorder) UpdateAuthor(ctx, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAuthor", reflect.TypeOf((*MockLogicInterface)(nil).UpdateAuthor), ctx, data)
+}
@@ -0,0 +1,649 @@
+package author
+
+import (
+	"byfood-app/internal/core"
+	"byfood-app/internal/model"
+	"byfood-app/internal/pkg/authorname"
+	"byfood-app/internal/pkg/pagination"
+	"byfood-app/internal/pkg/xauth"
+	"byfood-app/internal/pkg/xerrors"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/huandu/go-sqlbuilder"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// authorSortSignature is the only ordering of author listings, by name
+// with the id as tie breaker.
+const authorSortSignature = "name:asc,id:asc"
+
+// authorAliasesColumn selects the aliases of an author in name order.
+const authorAliasesColumn = "ARRAY(SELECT aa.name FROM library.author_aliases aa WHERE aa.author_id = authors.id ORDER BY aa.name) AS aliases"
+
+type AuthorRepo struct {
+	deps *core.Dependency
+}
+
+func NewSQLRepo(deps *core.Dependency) *AuthorRepo {
+	return &AuthorRepo{
+		deps: deps,
+	}
+}
+
+func (repo *AuthorRepo) GetAuthors(ctx context.Context, params model.AuthorSearchParams, page pagination.CursorPage) ([]model.Author, pagination.CursorMetadata, error) {
+	meta := pagination.CursorMetadata{Limit: page.Limit}
+
+	// base query
+	q := sqlbuilder.NewSelectBuilder()
+	q.Select("id", "name", "biography", "birth_year", "death_year", "nationality", authorAliasesColumn, "created_at", "updated_at")
+	q.From("library.authors")
+	q.Where(q.IsNull("deleted_at"))
+
+	if params.Search != "" {
+		pattern := "%" + params.Search + "%"
+		q.Where(
+			q.Or(
+				q.ILike("name", pattern),
+				"EXISTS (SELECT 1 FROM library.author_aliases aa WHERE aa.author_id = authors.id AND aa.name ILIKE "+q.Var(pattern)+")",
+			),
+		)
+	}
+
+	// keyset condition, going backward means
+	// reading the previous page in reverse order
+	backward := page.Before != nil
+	cursor := page.After
+	if backward {
+		cursor = page.Before
+	}
+
+	if cursor != nil {
+		if cursor.Sort != authorSortSignature || len(cursor.Values) != 1 {
+			return nil, meta, xerrors.NewClientError(pagination.ErrInvalidCursor)
+		}
+
+		name := cursor.Values[0]
+		if backward {
+			q.Where(q.Or(q.LessThan("name", name), q.And(q.Equal("name", name), q.LessThan("id", cursor.ID))))
+			q.OrderBy("name DESC", "id DESC")
+		} else {
+			q.Where(q.Or(q.GreaterThan("name", name), q.And(q.Equal("name", name), q.GreaterThan("id", cursor.ID))))
+		}
+	}
+	if !backward {
+		q.OrderBy("name ASC", "id ASC")
+	}
+
+	// fetch one extra row to know whether there is another page
+	q.Limit(page.Limit + 1)
+
+	// build and exec query
+	query, args := q.BuildWithFlavor(sqlbuilder.PostgreSQL)
+
+	var temp []model.SQLAuthor
+	err := repo.deps.DB.SelectContext(ctx, &temp, query, args...)
+	if err != nil {
+		return nil, meta, err
+	}
+
+	result := make([]model.Author, 0, len(temp))
+	for _, author := range temp {
+		result = append(result, toAuthor(author))
+	}
+
+	hasMore := len(result) > page.Limit
+	if hasMore {
+		result = result[:page.Limit]
+	}
+
+	if backward {
+		slices.Reverse(result)
+	}
+
+	if len(result) == 0 {
+		return result, meta, xerrors.ErrDataNotFound
+	}
+
+	// build metadata
+	first := authorCursor(result[0])
+	last := authorCursor(result[len(result)-1])
+	switch {
+	case backward:
+		meta.Next = &last
+		if hasMore {
+			meta.Prev = &first
+		}
+	default:
+		if hasMore {
+			meta.Next = &last
+		}
+		if page.After != nil {
+			meta.Prev = &first
+		}
+	}
+
+	return result, meta, nil
+}
+
+func (repo *AuthorRepo) GetAuthorByID(ctx context.Context, id int64) (model.Author, error) {
+	return getAuthorByID(ctx, repo.deps.DB, id)
+}
+
+func getAuthorByID(ctx context.Context, db sqlx.QueryerContext, id int64) (model.Author, error) {
+	var result model.SQLAuthor
+
+	q := `SELECT id, name, biography, birth_year, death_year, nationality, ` + authorAliasesColumn + `, created_at, updated_at FROM library.authors WHERE id = $1 AND deleted_at ISNULL;`
+
+	err := db.QueryRowxContext(ctx, q, id).StructScan(&result)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Author{}, xerrors.NewClientError(xerrors.ErrDataNotFound)
+		}
+
+		return model.Author{}, err
+	}
+
+	return toAuthor(result), nil
+}
+
+// beginTx starts a write transaction tagged the same way as book writes,
+// renaming an author rewrites the author string of its books.
+func (repo *AuthorRepo) beginTx(ctx context.Context) (*sqlx.Tx, error) {
+	tx, err := repo.deps.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	q := `SELECT set_config('byfood.actor', $1, true), set_config('byfood.request_id', $2, true);`
+	_, err = tx.ExecContext(ctx, q, xauth.ActorFromContext(ctx), middleware.GetReqID(ctx))
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// StoreAuthor inserts the author. Taking the name key of a soft deleted
+// author brings that author back with the new data, keeping its book credits.
+func (repo *AuthorRepo) StoreAuthor(ctx context.Context, data model.Author) (model.Author, error) {
+	tx, err := repo.beginTx(ctx)
+	if err != nil {
+		return model.Author{}, err
+	}
+	defer tx.Rollback()
+
+	err = checkNamesFree(ctx, tx, 0, data)
+	if err != nil {
+		return model.Author{}, err
+	}
+
+	var returned model.SQLAuthor
+	q := `
+		INSERT INTO library.authors (name, name_key, biography, birth_year, death_year, nationality) VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (name_key) DO UPDATE
+				SET
+					name = EXCLUDED.name,
+					biography = EXCLUDED.biography,
+					birth_year = EXCLUDED.birth_year,
+					death_year = EXCLUDED.death_year,
+					nationality = EXCLUDED.nationality,
+					updated_at = now(),
+					deleted_at = NULL
+				WHERE
+					library.authors.deleted_at IS NOT NULL
+		RETURNING id, created_at, updated_at;
+	`
+	err = tx.QueryRowxContext(ctx, q, data.Name, authorname.Key(data.Name), data.Biography, nullYear(data.BirthYear), nullYear(data.DeathYear), data.Nationality).
+		Scan(&returned.ID, &returned.CreatedAt, &returned.UpdatedAt)
+	if err != nil {
+		// the name is taken by an author that is not deleted
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Author{}, xerrors.NewClientError(fmt.Errorf("author %q already exists", data.Name))
+		}
+
+		return model.Author{}, err
+	}
+
+	err = writeAuthorAliases(ctx, tx, returned.ID.Int64, data.Aliases)
+	if err != nil {
+		return model.Author{}, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		repo.deps.Logger.ErrorContext(ctx, "failed to commit sql transaction", slog.Any("error", err))
+		return model.Author{}, err
+	}
+
+	data.ID = returned.ID.Int64
+	data.CreatedAt = &returned.CreatedAt.Time
+	data.UpdatedAt = &returned.UpdatedAt.Time
+
+	return data, nil
+}
+
+// UpdateAuthor replaces every field of the author. A rename also rewrites
+// the author string of every book crediting the author.
+func (repo *AuthorRepo) UpdateAuthor(ctx context.Context, data model.Author) (model.Author, error) {
+	tx, err := repo.beginTx(ctx)
+	if err != nil {
+		return model.Author{}, err
+	}
+	defer tx.Rollback()
+
+	var previousName string
+	lockQ := `SELECT name FROM library.authors WHERE id = $1 AND deleted_at ISNULL FOR UPDATE;`
+	err = tx.QueryRowxContext(ctx, lockQ, data.ID).Scan(&previousName)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Author{}, xerrors.NewClientError(xerrors.ErrDataNotFound)
+		}
+
+		return model.Author{}, err
+	}
+
+	err = checkNamesFree(ctx, tx, data.ID, data)
+	if err != nil {
+		return model.Author{}, err
+	}
+
+	var returned model.SQLAuthor
+	q := `
+		UPDATE library.authors
+			SET
+				name = $2,
+				name_key = $3,
+				biography = $4,
+				birth_year = $5,
+				death_year = $6,
+				nationality = $7,
+				updated_at = now()
+			WHERE
+				id = $1
+		RETURNING created_at, updated_at;
+	`
+	err = tx.QueryRowxContext(ctx, q, data.ID, data.Name, authorname.Key(data.Name), data.Biography, nullYear(data.BirthYear), nullYear(data.DeathYear), data.Nationality).
+		Scan(&returned.CreatedAt, &returned.UpdatedAt)
+	if err != nil {
+		if xerrors.IsUniqueViolation(err, "") {
+			return model.Author{}, xerrors.NewClientError(fmt.Errorf("author %q already exists", data.Name))
+		}
+
+		return model.Author{}, err
+	}
+
+	err = writeAuthorAliases(ctx, tx, data.ID, data.Aliases)
+	if err != nil {
+		return model.Author{}, err
+	}
+
+	if data.Name != previousName {
+		bookIDs, err := creditedBookIDs(ctx, tx, []int64{data.ID})
+		if err != nil {
+			return model.Author{}, err
+		}
+
+		err = refreshBookAuthorNames(ctx, tx, bookIDs)
+		if err != nil {
+			return model.Author{}, err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		repo.deps.Logger.ErrorContext(ctx, "failed to commit sql transaction", slog.Any("error", err))
+		return model.Author{}, err
+	}
+
+	data.CreatedAt = &returned.CreatedAt.Time
+	data.UpdatedAt = &returned.UpdatedAt.Time
+
+	return data, nil
+}
+
+// checkNamesFree refuses a name or alias whose key is already the name or an
+// alias of another author that is not deleted, as a book credit could then
+// go to either. authorID is the author being written, 0 for a new one.
+func checkNamesFree(ctx context.Context, tx *sqlx.Tx, authorID int64, data model.Author) error {
+	keys := []string{authorname.Key(data.Name)}
+	for _, alias := range data.Aliases {
+		keys = append(keys, authorname.Key(alias))
+	}
+
+	var taken string
+	q := `
+		SELECT a.name
+			FROM library.authors a
+			WHERE
+				a.id <> $1
+			AND
+				a.deleted_at ISNULL
+			AND (
+				a.name_key = ANY($2)
+				OR
+				EXISTS (SELECT 1 FROM library.author_aliases aa WHERE aa.author_id = a.id AND aa.name_key = ANY($2))
+			)
+		LIMIT 1;
+	`
+	err := tx.QueryRowxContext(ctx, q, authorID, pq.Array(keys)).Scan(&taken)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil
+	case err != nil:
+		return err
+	default:
+		return xerrors.NewClientError(fmt.Errorf("the name or an alias is already used by author %q", taken))
+	}
+}
+
+// writeAuthorAliases replaces the aliases of the author.
+func writeAuthorAliases(ctx context.Context, tx *sqlx.Tx, authorID int64, aliases []string) error {
+	deleteQ := `DELETE FROM library.author_aliases WHERE author_id = $1;`
+	_, err := tx.ExecContext(ctx, deleteQ, authorID)
+	if err != nil {
+		return err
+	}
+
+	if len(aliases) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(aliases))
+	for _, alias := range aliases {
+		keys = append(keys, authorname.Key(alias))
+	}
+
+	insertQ := `INSERT INTO library.author_aliases (author_id, name, name_key) SELECT $1, unnest($2::TEXT[]), unnest($3::TEXT[]);`
+	_, err = tx.ExecContext(ctx, insertQ, authorID, pq.Array(aliases), pq.Array(keys))
+	if err != nil {
+		if xerrors.IsUniqueViolation(err, "") {
+			return xerrors.NewClientError(errors.New("an alias is already used by another author"))
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// creditedBookIDs returns the books crediting any of the authors, in id order.
+func creditedBookIDs(ctx context.Context, tx *sqlx.Tx, authorIDs []int64) ([]int64, error) {
+	result := []int64{}
+
+	q := `SELECT DISTINCT book_id FROM library.book_authors WHERE author_id = ANY($1) ORDER BY book_id;`
+	err := tx.SelectContext(ctx, &result, q, pq.Array(authorIDs))
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// refreshBookAuthorNames rebuilds the author display string of the books,
+// the names credited as author joined by " & " or every name when there are
+// none, as the book package does on write. The books get a new version, so
+// the change to their credits shows up in their history.
+func refreshBookAuthorNames(ctx context.Context, tx *sqlx.Tx, bookIDs []int64) error {
+	if len(bookIDs) == 0 {
+		return nil
+	}
+
+	q := `
+		UPDATE library.books b
+			SET
+				author = d.author,
+				version = b.version + 1,
+				updated_at = now()
+			FROM (
+				SELECT
+					ba.book_id,
+					coalesce(
+						string_agg(a.name, ' & ' ORDER BY ba.position) FILTER (WHERE ba.role = 'author'),
+						string_agg(a.name, ' & ' ORDER BY ba.position)
+					) AS author
+				FROM library.book_authors ba
+				JOIN library.authors a ON a.id = ba.author_id
+				WHERE ba.book_id = ANY($1)
+				GROUP BY ba.book_id
+			) d
+			WHERE
+				b.id = d.book_id;
+	`
+	_, err := tx.ExecContext(ctx, q, pq.Array(bookIDs))
+	return err
+}
+
+// DeleteAuthor soft deletes the author, refused while a book that is not
+// deleted still credits them.
+func (repo *AuthorRepo) DeleteAuthor(ctx context.Context, id int64) error {
+	tx, err := repo.beginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	q := `UPDATE library.authors SET deleted_at = now(), updated_at = now() WHERE id = $1 AND deleted_at ISNULL;`
+	res, err := tx.ExecContext(ctx, q, id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return xerrors.NewClientError(xerrors.ErrDataNotFound)
+	}
+
+	// counted after the update, whose row lock holds off books being credited meanwhile
+	var credits int64
+	countQ := `
+		SELECT COUNT(1)
+		FROM library.book_authors ba
+		JOIN library.books b ON b.id = ba.book_id
+		WHERE ba.author_id = $1 AND b.deleted_at ISNULL;
+	`
+	err = tx.QueryRowxContext(ctx, countQ, id).Scan(&credits)
+	if err != nil {
+		return err
+	}
+	if credits > 0 {
+		return xerrors.NewClientError(fmt.Errorf("author is credited on %d book(s), remove the credits first", credits))
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		repo.deps.Logger.ErrorContext(ctx, "failed to commit sql transaction", slog.Any("error", err))
+		return err
+	}
+
+	return nil
+}
+
+// GetAuthorDuplicates returns every pair of live authors sharing a surname,
+// the last word of their name key, as candidates for probable duplicates.
+func (repo *AuthorRepo) GetAuthorDuplicates(ctx context.Context) ([]model.AuthorDuplicate, error) {
+	q := `
+		SELECT
+			a.id, a.name, (SELECT COUNT(1) FROM library.book_authors ba WHERE ba.author_id = a.id),
+			b.id, b.name, (SELECT COUNT(1) FROM library.book_authors ba WHERE ba.author_id = b.id)
+		FROM library.authors a
+		JOIN library.authors b
+			ON substring(b.name_key from '[^ ]+$') = substring(a.name_key from '[^ ]+$')
+			AND b.id > a.id
+		WHERE
+			a.deleted_at ISNULL
+		AND
+			b.deleted_at ISNULL
+		ORDER BY a.id, b.id;
+	`
+	rows, err := repo.deps.DB.QueryxContext(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []model.AuthorDuplicate
+	for rows.Next() {
+		var a, b model.AuthorRef
+		err := rows.Scan(&a.ID, &a.Name, &a.Books, &b.ID, &b.Name, &b.Books)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, model.AuthorDuplicate{Authors: []model.AuthorRef{a, b}})
+	}
+
+	return result, rows.Err()
+}
+
+// MergeAuthors folds the sources into the target author in one transaction.
+// Their book credits move to the target, dropping a credit when the book
+// already credits the target in the same role, their names and aliases
+// become aliases of the target and the sources are deleted. Every affected
+// book gets its author string rebuilt and a new version.
+func (repo *AuthorRepo) MergeAuthors(ctx context.Context, id int64, sourceIDs []int64) (model.AuthorMerge, error) {
+	tx, err := repo.beginTx(ctx)
+	if err != nil {
+		return model.AuthorMerge{}, err
+	}
+	defer tx.Rollback()
+
+	ids := append([]int64{id}, sourceIDs...)
+
+	// the target has to be live, sources may already be soft deleted
+	lockQ := `SELECT id FROM library.authors WHERE id = ANY($1) AND (id <> $2 OR deleted_at ISNULL) ORDER BY id FOR UPDATE;`
+	// of the credits of the merged authors on a book in one role, the first stays
+	dedupeQ := `
+		DELETE FROM library.book_authors ba
+			USING library.book_authors keep
+			WHERE
+				ba.author_id = ANY($1)
+			AND
+				keep.author_id = ANY($1)
+			AND
+				keep.book_id = ba.book_id
+			AND
+				keep.role = ba.role
+			AND
+				keep.position < ba.position;
+	`
+	creditsQ := `UPDATE library.book_authors SET author_id = $1 WHERE author_id = ANY($2);`
+	aliasesQ := `UPDATE library.author_aliases SET author_id = $1 WHERE author_id = ANY($2);`
+	deleteQ := `
+		WITH merged AS (
+			DELETE FROM library.authors WHERE id = ANY($2) RETURNING name, name_key
+		)
+		INSERT INTO library.author_aliases (author_id, name, name_key)
+			SELECT $1, name, name_key FROM merged
+		ON CONFLICT DO NOTHING;
+	`
+
+	var locked []int64
+	err = tx.SelectContext(ctx, &locked, lockQ, pq.Array(ids), id)
+	if err != nil {
+		return model.AuthorMerge{}, err
+	}
+
+	if !slices.Contains(locked, id) {
+		return model.AuthorMerge{}, xerrors.NewClientError(xerrors.ErrDataNotFound)
+	}
+	for _, sourceID := range sourceIDs {
+		if !slices.Contains(locked, sourceID) {
+			return model.AuthorMerge{}, xerrors.NewClientError(fmt.Errorf("author %d does not exist", sourceID))
+		}
+	}
+
+	result := model.AuthorMerge{MergedAuthorIDs: sourceIDs}
+	result.BookIDs, err = creditedBookIDs(ctx, tx, sourceIDs)
+	if err != nil {
+		return model.AuthorMerge{}, err
+	}
+
+	_, err = tx.ExecContext(ctx, dedupeQ, pq.Array(ids))
+	if err != nil {
+		return model.AuthorMerge{}, err
+	}
+
+	for _, q := range []string{creditsQ, aliasesQ, deleteQ} {
+		_, err = tx.ExecContext(ctx, q, id, pq.Array(sourceIDs))
+		if err != nil {
+			return model.AuthorMerge{}, err
+		}
+	}
+
+	err = refreshBookAuthorNames(ctx, tx, result.BookIDs)
+	if err != nil {
+		return model.AuthorMerge{}, err
+	}
+
+	result.Author, err = getAuthorByID(ctx, tx, id)
+	if err != nil {
+		return model.AuthorMerge{}, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		repo.deps.Logger.ErrorContext(ctx, "failed to commit sql transaction", slog.Any("error", err))
+		return model.AuthorMerge{}, err
+	}
+
+	return result, nil
+}
+
+// nullYear stores a nil year, an unknown one, as NULL.
+func nullYear(year *int64) sql.NullInt64 {
+	if year == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: *year, Valid: true}
+}
+
+func toYear(year sql.NullInt64) *int64 {
+	if !year.Valid {
+		return nil
+	}
+	return &year.Int64
+}
+
+func authorCursor(author model.Author) pagination.Cursor {
+	return pagination.Cursor{
+		Sort:   authorSortSignature,
+		Values: []string{author.Name},
+		ID:     author.ID,
+	}
+}
+
+func toAuthor(temp model.SQLAuthor) model.Author {
+	author := model.Author{
+		ID:          temp.ID.Int64,
+		Name:        temp.Name.String,
+		Biography:   temp.Biography.String,
+		BirthYear:   toYear(temp.BirthYear),
+		DeathYear:   toYear(temp.DeathYear),
+		Nationality: temp.Nationality.String,
+		Aliases:     []string(temp.Aliases),
+		BaseAudit: model.BaseAudit{
+			CreatedAt: &temp.CreatedAt.Time,
+			UpdatedAt: &temp.UpdatedAt.Time,
+		},
+	}
+
+	if author.Aliases == nil {
+		author.Aliases = []string{}
+	}
+
+	if temp.DeletedAt.Valid {
+		author.DeletedAt = &temp.DeletedAt.Time
+	}
+
+	return author
+}
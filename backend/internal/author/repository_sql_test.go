@@ -0,0 +1,595 @@
+package author
+
+import (
+	"byfood-app/internal/core"
+	"byfood-app/internal/model"
+	"byfood-app/internal/pkg/pagination"
+	"byfood-app/internal/pkg/xerrors"
+	"context"
+	"database/sql"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// expectBeginTx expects a write transaction tagged with an empty actor and request id.
+func expectBeginTx(mockDB sqlmock.Sqlmock) {
+	mockDB.ExpectBegin()
+	mockDB.ExpectExec(`(?s)^.*set_config\('byfood.actor', \$1, true\), set_config\('byfood.request_id', \$2, true\).*$`).
+		WithArgs("", "").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+// expectNamesFree expects the name keys to be checked against the other
+// authors, taken is the author already using one of them or empty.
+func expectNamesFree(mockDB sqlmock.Sqlmock, authorID int64, keys []string, taken string) {
+	rows := sqlmock.NewRows([]string{"name"})
+	if taken != "" {
+		rows.AddRow(taken)
+	}
+	mockDB.ExpectQuery(`(?s)^SELECT a.name.*a.id <> \$1.*a.name_key = ANY\(\$2\).*aa.name_key = ANY\(\$2\).*LIMIT 1;$`).
+		WithArgs(authorID, pq.Array(keys)).
+		WillReturnRows(rows)
+}
+
+func TestAuthorRepo_GetAuthors(t *testing.T) {
+	type args struct {
+		ctx    context.Context
+		params model.AuthorSearchParams
+		page   pagination.CursorPage
+	}
+
+	db, mockDB, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dbx := sqlx.NewDb(db, "sqlmock")
+
+	deps := &core.Dependency{
+		Logger: slog.Default(),
+		DB:     dbx,
+	}
+
+	now := time.Now()
+	columns := []string{"id", "name", "biography", "birth_year", "death_year", "nationality", "aliases", "created_at", "updated_at"}
+
+	tests := []struct {
+		name     string
+		args     args
+		want     []model.Author
+		wantMeta pagination.CursorMetadata
+		wantErr  bool
+		mockFunc func()
+	}{
+		{
+			name: "success get first page searching names and aliases",
+			args: args{
+				ctx:    context.Background(),
+				params: model.AuthorSearchParams{Search: "blair"},
+				page:   pagination.CursorPage{Limit: 1},
+			},
+			want: []model.Author{
+				{
+					ID:          2,
+					Name:        "George Orwell",
+					BirthYear:   year(1903),
+					DeathYear:   year(1950),
+					Nationality: "British",
+					Aliases:     []string{"Eric Arthur Blair"},
+					BaseAudit:   model.BaseAudit{CreatedAt: &now, UpdatedAt: &now},
+				},
+			},
+			wantMeta: pagination.CursorMetadata{
+				Limit: 1,
+				Next:  &pagination.Cursor{Sort: "name:asc,id:asc", Values: []string{"George Orwell"}, ID: 2},
+			},
+			mockFunc: func() {
+				rows := sqlmock.NewRows(columns).
+					AddRow(2, "George Orwell", "", 1903, 1950, "British", "{\"Eric Arthur Blair\"}", now, now).
+					AddRow(3, "Jane Austen", "", 1775, 1817, "British", "{}", now, now)
+				mockDB.ExpectQuery(`(?s)^SELECT .* FROM library.authors WHERE deleted_at IS NULL AND \(name ILIKE \$1 OR EXISTS \(SELECT 1 FROM library.author_aliases aa WHERE aa.author_id = authors.id AND aa.name ILIKE \$2\)\) ORDER BY name ASC, id ASC LIMIT \$3$`).
+					WithArgs("%blair%", "%blair%", 2).
+					WillReturnRows(rows)
+			},
+		},
+		{
+			name: "success get previous page with before cursor",
+			args: args{
+				ctx: context.Background(),
+				page: pagination.CursorPage{
+					Before: &pagination.Cursor{Sort: "name:asc,id:asc", Values: []string{"Jane Austen"}, ID: 3},
+					Limit:  1,
+				},
+			},
+			want: []model.Author{
+				{
+					ID:        2,
+					Name:      "George Orwell",
+					Aliases:   []string{},
+					BaseAudit: model.BaseAudit{CreatedAt: &now, UpdatedAt: &now},
+				},
+			},
+			wantMeta: pagination.CursorMetadata{
+				Limit: 1,
+				Next:  &pagination.Cursor{Sort: "name:asc,id:asc", Values: []string{"George Orwell"}, ID: 2},
+			},
+			mockFunc: func() {
+				rows := sqlmock.NewRows(columns).
+					AddRow(2, "George Orwell", "", nil, nil, "", "{}", now, now)
+				mockDB.ExpectQuery(`(?s)^SELECT .* WHERE deleted_at IS NULL AND \(name < \$1 OR \(name = \$2 AND id < \$3\)\) ORDER BY name DESC, id DESC LIMIT \$4$`).
+					WithArgs("Jane Austen", "Jane Austen", int64(3), 2).
+					WillReturnRows(rows)
+			},
+		},
+		{
+			name: "failed get authors with a book cursor",
+			args: args{
+				ctx: context.Background(),
+				page: pagination.CursorPage{
+					After: &pagination.Cursor{Sort: "id:asc", ID: 1},
+					Limit: 10,
+				},
+			},
+			wantMeta: pagination.CursorMetadata{Limit: 10},
+			wantErr:  true,
+			mockFunc: func() {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &AuthorRepo{
+				deps: deps,
+			}
+
+			tt.mockFunc()
+
+			got, gotMeta, err := repo.GetAuthors(tt.args.ctx, tt.args.params, tt.args.page)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("AuthorRepo.GetAuthors() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("AuthorRepo.GetAuthors() got = %+v, want %+v", got, tt.want)
+			}
+			if !reflect.DeepEqual(gotMeta, tt.wantMeta) {
+				t.Errorf("AuthorRepo.GetAuthors() meta = %+v, want %+v", gotMeta, tt.wantMeta)
+			}
+			if err := mockDB.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet sql expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestAuthorRepo_StoreAuthor(t *testing.T) {
+	db, mockDB, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dbx := sqlx.NewDb(db, "sqlmock")
+
+	repo := &AuthorRepo{
+		deps: &core.Dependency{
+			Logger: slog.Default(),
+			DB:     dbx,
+		},
+	}
+
+	now := time.Now()
+	data := model.Author{
+		Name:        "George Orwell",
+		BirthYear:   year(1903),
+		DeathYear:   year(1950),
+		Nationality: "British",
+		Aliases:     []string{"Eric Arthur Blair"},
+	}
+
+	keys := []string{"george orwell", "eric arthur blair"}
+
+	// a new name is inserted along with its aliases, all keyed
+	expectBeginTx(mockDB)
+	expectNamesFree(mockDB, 0, keys, "")
+	mockDB.ExpectQuery(`(?s)^.*INSERT INTO library.authors .*ON CONFLICT \(name_key\) DO UPDATE.*WHERE\s+library.authors.deleted_at IS NOT NULL.*$`).
+		WithArgs("George Orwell", "george orwell", "", sql.NullInt64{Int64: 1903, Valid: true}, sql.NullInt64{Int64: 1950, Valid: true}, "British").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow(2, now, now))
+	mockDB.ExpectExec(`(?s)^DELETE FROM library.author_aliases WHERE author_id = \$1;$`).
+		WithArgs(int64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mockDB.ExpectExec(`(?s)^INSERT INTO library.author_aliases \(author_id, name, name_key\) .*unnest\(\$3::TEXT\[\]\);$`).
+		WithArgs(int64(2), pq.Array([]string{"Eric Arthur Blair"}), pq.Array([]string{"eric arthur blair"})).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mockDB.ExpectCommit()
+
+	got, err := repo.StoreAuthor(context.Background(), data)
+	if err != nil {
+		t.Fatalf("AuthorRepo.StoreAuthor() error = %v", err)
+	}
+	want := data
+	want.ID = 2
+	want.CreatedAt = &now
+	want.UpdatedAt = &now
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("AuthorRepo.StoreAuthor() = %+v, want %+v", got, want)
+	}
+
+	// the alias is the name of another author
+	expectBeginTx(mockDB)
+	expectNamesFree(mockDB, 0, keys, "Eric Blair")
+	mockDB.ExpectRollback()
+
+	_, err = repo.StoreAuthor(context.Background(), data)
+	if xerrors.ParseErrorTypeToCodeInt(err) != http.StatusBadRequest {
+		t.Errorf("AuthorRepo.StoreAuthor() error = %v, want client error for a taken alias", err)
+	}
+
+	// the name was taken by an author that is not deleted in the meantime
+	expectBeginTx(mockDB)
+	expectNamesFree(mockDB, 0, keys, "")
+	mockDB.ExpectQuery(`(?s)^.*INSERT INTO library.authors .*$`).
+		WithArgs("George Orwell", "george orwell", "", sql.NullInt64{Int64: 1903, Valid: true}, sql.NullInt64{Int64: 1950, Valid: true}, "British").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}))
+	mockDB.ExpectRollback()
+
+	_, err = repo.StoreAuthor(context.Background(), data)
+	if xerrors.ParseErrorTypeToCodeInt(err) != http.StatusBadRequest {
+		t.Errorf("AuthorRepo.StoreAuthor() error = %v, want client error for a taken name", err)
+	}
+
+	if err := mockDB.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet sql expectations: %v", err)
+	}
+}
+
+func TestAuthorRepo_UpdateAuthor(t *testing.T) {
+	db, mockDB, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dbx := sqlx.NewDb(db, "sqlmock")
+
+	repo := &AuthorRepo{
+		deps: &core.Dependency{
+			Logger: slog.Default(),
+			DB:     dbx,
+		},
+	}
+
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		data     model.Author
+		wantCode int
+		mockFunc func()
+	}{
+		{
+			name:     "success rename rewrites the books author string",
+			data:     model.Author{ID: 2, Name: "George Orwell"},
+			wantCode: http.StatusOK,
+			mockFunc: func() {
+				expectBeginTx(mockDB)
+				mockDB.ExpectQuery(`(?s)^SELECT name FROM library.authors WHERE id = \$1 AND deleted_at ISNULL FOR UPDATE;$`).
+					WithArgs(int64(2)).
+					WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("George Orwel"))
+				expectNamesFree(mockDB, 2, []string{"george orwell"}, "")
+				mockDB.ExpectQuery(`(?s)^.*UPDATE library.authors.*$`).
+					WithArgs(int64(2), "George Orwell", "george orwell", "", sql.NullInt64{}, sql.NullInt64{}, "").
+					WillReturnRows(sqlmock.NewRows([]string{"created_at", "updated_at"}).AddRow(now, now))
+				mockDB.ExpectExec(`(?s)^DELETE FROM library.author_aliases WHERE author_id = \$1;$`).
+					WithArgs(int64(2)).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mockDB.ExpectQuery(`(?s)^SELECT DISTINCT book_id FROM library.book_authors WHERE author_id = ANY\(\$1\) ORDER BY book_id;$`).
+					WithArgs(pq.Array([]int64{2})).
+					WillReturnRows(sqlmock.NewRows([]string{"book_id"}).AddRow(2).AddRow(5))
+				mockDB.ExpectExec(`(?s)^.*UPDATE library.books b.*string_agg\(a.name, ' & ' ORDER BY ba.position\) FILTER \(WHERE ba.role = 'author'\).*ba.book_id = ANY\(\$1\).*$`).
+					WithArgs(pq.Array([]int64{2, 5})).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mockDB.ExpectCommit()
+			},
+		},
+		{
+			name:     "success update without rename leaves the books alone",
+			data:     model.Author{ID: 2, Name: "George Orwell", Biography: "English novelist."},
+			wantCode: http.StatusOK,
+			mockFunc: func() {
+				expectBeginTx(mockDB)
+				mockDB.ExpectQuery(`(?s)^SELECT name FROM library.authors .*$`).
+					WithArgs(int64(2)).
+					WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("George Orwell"))
+				expectNamesFree(mockDB, 2, []string{"george orwell"}, "")
+				mockDB.ExpectQuery(`(?s)^.*UPDATE library.authors.*$`).
+					WithArgs(int64(2), "George Orwell", "george orwell", "English novelist.", sql.NullInt64{}, sql.NullInt64{}, "").
+					WillReturnRows(sqlmock.NewRows([]string{"created_at", "updated_at"}).AddRow(now, now))
+				mockDB.ExpectExec(`(?s)^DELETE FROM library.author_aliases WHERE author_id = \$1;$`).
+					WithArgs(int64(2)).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mockDB.ExpectCommit()
+			},
+		},
+		{
+			name:     "failed rename to a taken name",
+			data:     model.Author{ID: 2, Name: "Jane Austen"},
+			wantCode: http.StatusBadRequest,
+			mockFunc: func() {
+				expectBeginTx(mockDB)
+				mockDB.ExpectQuery(`(?s)^SELECT name FROM library.authors .*$`).
+					WithArgs(int64(2)).
+					WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("George Orwell"))
+				expectNamesFree(mockDB, 2, []string{"jane austen"}, "Jane Austen")
+				mockDB.ExpectRollback()
+			},
+		},
+		{
+			name:     "failed update deleted author",
+			data:     model.Author{ID: 9, Name: "Jane Austen"},
+			wantCode: http.StatusBadRequest,
+			mockFunc: func() {
+				expectBeginTx(mockDB)
+				mockDB.ExpectQuery(`(?s)^SELECT name FROM library.authors .*$`).
+					WithArgs(int64(9)).
+					WillReturnRows(sqlmock.NewRows([]string{"name"}))
+				mockDB.ExpectRollback()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc()
+
+			_, err := repo.UpdateAuthor(context.Background(), tt.data)
+			if err != nil && xerrors.ParseErrorTypeToCodeInt(err) != tt.wantCode {
+				t.Errorf("AuthorRepo.UpdateAuthor() error = %v, wantCode %v", err, tt.wantCode)
+				return
+			}
+			if (err != nil) != (tt.wantCode != http.StatusOK) {
+				t.Errorf("AuthorRepo.UpdateAuthor() error = %v, wantCode %v", err, tt.wantCode)
+				return
+			}
+			if err := mockDB.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet sql expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestAuthorRepo_DeleteAuthor(t *testing.T) {
+	db, mockDB, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dbx := sqlx.NewDb(db, "sqlmock")
+
+	repo := &AuthorRepo{
+		deps: &core.Dependency{
+			Logger: slog.Default(),
+			DB:     dbx,
+		},
+	}
+
+	tests := []struct {
+		name     string
+		id       int64
+		wantCode int
+		mockFunc func()
+	}{
+		{
+			name:     "success delete uncredited author",
+			id:       5,
+			wantCode: http.StatusOK,
+			mockFunc: func() {
+				expectBeginTx(mockDB)
+				mockDB.ExpectExec(`(?s)^UPDATE library.authors SET deleted_at = now\(\), updated_at = now\(\) WHERE id = \$1 AND deleted_at ISNULL;$`).
+					WithArgs(int64(5)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mockDB.ExpectQuery(`(?s)^.*SELECT COUNT\(1\).*FROM library.book_authors ba.*b.deleted_at ISNULL.*$`).
+					WithArgs(int64(5)).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+				mockDB.ExpectCommit()
+			},
+		},
+		{
+			name:     "failed delete author credited on books",
+			id:       2,
+			wantCode: http.StatusBadRequest,
+			mockFunc: func() {
+				expectBeginTx(mockDB)
+				mockDB.ExpectExec(`(?s)^UPDATE library.authors SET deleted_at.*$`).
+					WithArgs(int64(2)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mockDB.ExpectQuery(`(?s)^.*SELECT COUNT\(1\).*$`).
+					WithArgs(int64(2)).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+				mockDB.ExpectRollback()
+			},
+		},
+		{
+			name:     "failed delete unknown author",
+			id:       99,
+			wantCode: http.StatusBadRequest,
+			mockFunc: func() {
+				expectBeginTx(mockDB)
+				mockDB.ExpectExec(`(?s)^UPDATE library.authors SET deleted_at.*$`).
+					WithArgs(int64(99)).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mockDB.ExpectRollback()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc()
+
+			err := repo.DeleteAuthor(context.Background(), tt.id)
+			if err != nil && xerrors.ParseErrorTypeToCodeInt(err) != tt.wantCode {
+				t.Errorf("AuthorRepo.DeleteAuthor() error = %v, wantCode %v", err, tt.wantCode)
+				return
+			}
+			if (err != nil) != (tt.wantCode != http.StatusOK) {
+				t.Errorf("AuthorRepo.DeleteAuthor() error = %v, wantCode %v", err, tt.wantCode)
+				return
+			}
+			if err := mockDB.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet sql expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestAuthorRepo_GetAuthorDuplicates(t *testing.T) {
+	db, mockDB, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo := &AuthorRepo{
+		deps: &core.Dependency{
+			Logger: slog.Default(),
+			DB:     sqlx.NewDb(db, "sqlmock"),
+		},
+	}
+
+	mockDB.ExpectQuery(`(?s)^SELECT.*FROM library.authors a.*JOIN library.authors b.*substring\(b.name_key from '\[\^ \]\+\$'\) = substring\(a.name_key from '\[\^ \]\+\$'\).*b.id > a.id.*ORDER BY a.id, b.id;$`).
+		WillReturnRows(sqlmock.NewRows([]string{"a_id", "a_name", "a_books", "b_id", "b_name", "b_books"}).
+			AddRow(8, "J.R.R. Tolkien", 2, 12, "J. Tolkien", 1))
+
+	got, err := repo.GetAuthorDuplicates(context.Background())
+	if err != nil {
+		t.Fatalf("AuthorRepo.GetAuthorDuplicates() error = %v", err)
+	}
+
+	want := []model.AuthorDuplicate{{Authors: []model.AuthorRef{
+		{ID: 8, Name: "J.R.R. Tolkien", Books: 2},
+		{ID: 12, Name: "J. Tolkien", Books: 1},
+	}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("AuthorRepo.GetAuthorDuplicates() = %+v, want %+v", got, want)
+	}
+	if err := mockDB.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet sql expectations: %v", err)
+	}
+}
+
+func TestAuthorRepo_MergeAuthors(t *testing.T) {
+	db, mockDB, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo := &AuthorRepo{
+		deps: &core.Dependency{
+			Logger: slog.Default(),
+			DB:     sqlx.NewDb(db, "sqlmock"),
+		},
+	}
+
+	now := time.Now()
+	lockQ := `(?s)^SELECT id FROM library.authors WHERE id = ANY\(\$1\) AND \(id <> \$2 OR deleted_at ISNULL\) ORDER BY id FOR UPDATE;$`
+
+	tests := []struct {
+		name      string
+		id        int64
+		sourceIDs []int64
+		want      model.AuthorMerge
+		wantCode  int
+		mockFunc  func()
+	}{
+		{
+			name:      "success merge moves credits and rewrites the books",
+			id:        8,
+			sourceIDs: []int64{12},
+			want: model.AuthorMerge{
+				Author: model.Author{
+					ID:        8,
+					Name:      "J.R.R. Tolkien",
+					Aliases:   []string{"J. Tolkien"},
+					BaseAudit: model.BaseAudit{CreatedAt: &now, UpdatedAt: &now},
+				},
+				MergedAuthorIDs: []int64{12},
+				BookIDs:         []int64{10},
+			},
+			wantCode: http.StatusOK,
+			mockFunc: func() {
+				expectBeginTx(mockDB)
+				mockDB.ExpectQuery(lockQ).
+					WithArgs(pq.Array([]int64{8, 12}), int64(8)).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(8).AddRow(12))
+				mockDB.ExpectQuery(`(?s)^SELECT DISTINCT book_id FROM library.book_authors.*$`).
+					WithArgs(pq.Array([]int64{12})).
+					WillReturnRows(sqlmock.NewRows([]string{"book_id"}).AddRow(10))
+				mockDB.ExpectExec(`(?s)^.*DELETE FROM library.book_authors ba.*USING library.book_authors keep.*keep.position < ba.position;$`).
+					WithArgs(pq.Array([]int64{8, 12})).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mockDB.ExpectExec(`(?s)^UPDATE library.book_authors SET author_id = \$1 WHERE author_id = ANY\(\$2\);$`).
+					WithArgs(int64(8), pq.Array([]int64{12})).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mockDB.ExpectExec(`(?s)^UPDATE library.author_aliases SET author_id = \$1 WHERE author_id = ANY\(\$2\);$`).
+					WithArgs(int64(8), pq.Array([]int64{12})).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mockDB.ExpectExec(`(?s)^.*DELETE FROM library.authors WHERE id = ANY\(\$2\) RETURNING name, name_key.*INSERT INTO library.author_aliases.*$`).
+					WithArgs(int64(8), pq.Array([]int64{12})).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mockDB.ExpectExec(`(?s)^.*UPDATE library.books b.*$`).
+					WithArgs(pq.Array([]int64{10})).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mockDB.ExpectQuery(`(?s)^SELECT id, name, .* FROM library.authors WHERE id = \$1 AND deleted_at ISNULL;$`).
+					WithArgs(int64(8)).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "biography", "birth_year", "death_year", "nationality", "aliases", "created_at", "updated_at"}).
+						AddRow(8, "J.R.R. Tolkien", "", nil, nil, "", "{\"J. Tolkien\"}", now, now))
+				mockDB.ExpectCommit()
+			},
+		},
+		{
+			name:      "failed merge into a deleted author",
+			id:        8,
+			sourceIDs: []int64{12},
+			wantCode:  http.StatusBadRequest,
+			mockFunc: func() {
+				expectBeginTx(mockDB)
+				mockDB.ExpectQuery(lockQ).
+					WithArgs(pq.Array([]int64{8, 12}), int64(8)).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(12))
+				mockDB.ExpectRollback()
+			},
+		},
+		{
+			name:      "failed merge of an unknown author",
+			id:        8,
+			sourceIDs: []int64{12, 99},
+			wantCode:  http.StatusBadRequest,
+			mockFunc: func() {
+				expectBeginTx(mockDB)
+				mockDB.ExpectQuery(lockQ).
+					WithArgs(pq.Array([]int64{8, 12, 99}), int64(8)).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(8).AddRow(12))
+				mockDB.ExpectRollback()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc()
+
+			got, err := repo.MergeAuthors(context.Background(), tt.id, tt.sourceIDs)
+			if err != nil && xerrors.ParseErrorTypeToCodeInt(err) != tt.wantCode {
+				t.Errorf("AuthorRepo.MergeAuthors() error = %v, wantCode %v", err, tt.wantCode)
+				return
+			}
+			if (err != nil) != (tt.wantCode != http.StatusOK) {
+				t.Errorf("AuthorRepo.MergeAuthors() error = %v, wantCode %v", err, tt.wantCode)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("AuthorRepo.MergeAuthors() = %+v, want %+v", got, tt.want)
+			}
+			if err := mockDB.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet sql expectations: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package book
+
+import (
+	"byfood-app/internal/model"
+	"byfood-app/internal/pkg/authorname"
+	"byfood-app/internal/pkg/xerrors"
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+)
+
+// authorSeparator joins the credited names into the author display string,
+// and splits a bare author string back into authors.
+const authorSeparator = " & "
+
+// maxBookAuthors caps the credits of a single book.
+const maxBookAuthors = 50
+
+const (
+	// minAuthorAge is the youngest an author is taken to publish at
+	minAuthorAge = 5
+	// maxPosthumousYears leaves room for works published long after the
+	// author died, e.g. letters or unearthed manuscripts
+	maxPosthumousYears = 100
+)
+
+var bookAuthorRoles = []string{
+	model.AuthorRoleAuthor,
+	model.AuthorRoleEditor,
+	model.AuthorRoleTranslator,
+	model.AuthorRoleIllustrator,
+}
+
+// normalizeAuthors fills in whichever of Author and Authors the client left
+// out. An authors list wins and rewrites the display string, a bare author
+// string is split into authors. Names are normalized, "Tolkien, J. R. R."
+// becomes "J.R.R. Tolkien", and roles default to author.
+func normalizeAuthors(data model.Book) model.Book {
+	if len(data.Authors) == 0 {
+		if strings.TrimSpace(data.Author) == "" {
+			return data
+		}
+
+		for _, name := range strings.Split(data.Author, authorSeparator) {
+			data.Authors = append(data.Authors, model.BookAuthor{Name: name})
+		}
+	}
+
+	authors := make([]model.BookAuthor, 0, len(data.Authors))
+	for _, author := range data.Authors {
+		author.Name = authorname.Normalize(author.Name)
+		if author.Role == "" {
+			author.Role = model.AuthorRoleAuthor
+		}
+		authors = append(authors, author)
+	}
+
+	data.Authors = authors
+	data.Author = authorDisplayName(authors)
+
+	return data
+}
+
+// keepStoredAuthors keeps the stored credits, editors and translators
+// included, when data sends no authors and the same author string.
+func keepStoredAuthors(current model.Book, data model.Book) model.Book {
+	if len(data.Authors) > 0 || normalizeAuthors(model.Book{Author: data.Author}).Author != current.Author {
+		return data
+	}
+
+	data.Authors = current.Authors
+	return data
+}
+
+// authorDisplayName joins the names credited as author, or every name when
+// the book only credits editors and the like, e.g. an anthology.
+func authorDisplayName(authors []model.BookAuthor) string {
+	var names []string
+	for _, author := range authors {
+		if author.Role == model.AuthorRoleAuthor {
+			names = append(names, author.Name)
+		}
+	}
+
+	if len(names) == 0 {
+		for _, author := range authors {
+			names = append(names, author.Name)
+		}
+	}
+
+	return strings.Join(names, authorSeparator)
+}
+
+func validateAuthors(authors []model.BookAuthor) error {
+	if len(authors) > maxBookAuthors {
+		return xerrors.NewClientError(fmt.Errorf("a book can have at most %d authors", maxBookAuthors))
+	}
+
+	for i, author := range authors {
+		switch {
+		case author.Name == "":
+			return xerrors.NewClientError(fmt.Errorf("author %d name is empty", i+1))
+		case strings.Contains(author.Name, authorSeparator):
+			// it would be read back as two authors from the display string
+			return xerrors.NewClientError(fmt.Errorf("author %d name can not contain %q", i+1, authorSeparator))
+		case !slices.Contains(bookAuthorRoles, author.Role):
+			return xerrors.NewClientError(fmt.Errorf("author %d has unknown role %q", i+1, author.Role))
+		}
+
+		for _, other := range authors[:i] {
+			if authorname.Key(other.Name) == authorname.Key(author.Name) && other.Role == author.Role {
+				return xerrors.NewClientError(fmt.Errorf("author %q is credited twice as %s", author.Name, author.Role))
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateAuthorLifetimes rejects a publish year before the credited authors
+// were old enough to write, or long after they died, when enabled. Only the
+// author role is checked, a translation or illustrated edition can come much
+// later. Names unknown to library.authors and unknown years pass.
+func (logic *BookLogic) validateAuthorLifetimes(ctx context.Context, data model.Book) error {
+	if !logic.checkAuthorLifetimes {
+		return nil
+	}
+
+	var names []string
+	for _, author := range data.Authors {
+		if author.Role == model.AuthorRoleAuthor {
+			names = append(names, author.Name)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	authors, err := logic.repo.GetAuthorsByName(ctx, names)
+	if err != nil {
+		logic.deps.Logger.ErrorContext(ctx, "failed to get authors by name", slog.Any("error", err))
+		return err
+	}
+
+	for _, author := range authors {
+		switch {
+		case author.BirthYear != nil && data.PublishYear < *author.BirthYear+minAuthorAge:
+			return xerrors.NewClientError(fmt.Errorf("publish year %d is implausible for %s, born in %d", data.PublishYear, author.Name, *author.BirthYear))
+		case author.DeathYear != nil && data.PublishYear > *author.DeathYear+maxPosthumousYears:
+			return xerrors.NewClientError(fmt.Errorf("publish year %d is more than %d years after %s died in %d", data.PublishYear, maxPosthumousYears, author.Name, *author.DeathYear))
+		}
+	}
+
+	return nil
+}
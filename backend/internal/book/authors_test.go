@@ -0,0 +1,116 @@
+package book
+
+import (
+	"byfood-app/internal/model"
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeAuthors(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        model.Book
+		wantAuthor  string
+		wantAuthors []model.BookAuthor
+		wantErr     bool
+	}{
+		{
+			name:       "success split author string",
+			data:       model.Book{Author: "Terry Pratchett & Neil Gaiman"},
+			wantAuthor: "Terry Pratchett & Neil Gaiman",
+			wantAuthors: []model.BookAuthor{
+				{Name: "Terry Pratchett", Role: model.AuthorRoleAuthor},
+				{Name: "Neil Gaiman", Role: model.AuthorRoleAuthor},
+			},
+		},
+		{
+			name: "success authors list wins over author string",
+			data: model.Book{
+				Author: "Tolkien",
+				Authors: []model.BookAuthor{
+					{Name: "J.R.R. Tolkien"},
+					{Name: "Christopher Tolkien", Role: model.AuthorRoleEditor},
+				},
+			},
+			wantAuthor: "J.R.R. Tolkien",
+			wantAuthors: []model.BookAuthor{
+				{Name: "J.R.R. Tolkien", Role: model.AuthorRoleAuthor},
+				{Name: "Christopher Tolkien", Role: model.AuthorRoleEditor},
+			},
+		},
+		{
+			name: "success editors only anthology",
+			data: model.Book{
+				Authors: []model.BookAuthor{
+					{Name: "Ellen Datlow", Role: model.AuthorRoleEditor},
+					{Name: "Terri Windling", Role: model.AuthorRoleEditor},
+				},
+			},
+			wantAuthor: "Ellen Datlow & Terri Windling",
+			wantAuthors: []model.BookAuthor{
+				{Name: "Ellen Datlow", Role: model.AuthorRoleEditor},
+				{Name: "Terri Windling", Role: model.AuthorRoleEditor},
+			},
+		},
+		{
+			name: "failed author credited twice in the same role",
+			data: model.Book{
+				Authors: []model.BookAuthor{{Name: "Neil Gaiman"}, {Name: "Neil Gaiman "}},
+			},
+			wantAuthor: "Neil Gaiman & Neil Gaiman",
+			wantAuthors: []model.BookAuthor{
+				{Name: "Neil Gaiman", Role: model.AuthorRoleAuthor},
+				{Name: "Neil Gaiman", Role: model.AuthorRoleAuthor},
+			},
+			wantErr: true,
+		},
+		{
+			name:       "success inverted author string is normalized",
+			data:       model.Book{Author: "Tolkien, J. R. R."},
+			wantAuthor: "J.R.R. Tolkien",
+			wantAuthors: []model.BookAuthor{
+				{Name: "J.R.R. Tolkien", Role: model.AuthorRoleAuthor},
+			},
+		},
+		{
+			name: "failed name variants credited twice in the same role",
+			data: model.Book{
+				Authors: []model.BookAuthor{{Name: "J.R.R. Tolkien"}, {Name: "JRR Tolkien"}},
+			},
+			wantAuthor: "J.R.R. Tolkien & J.R.R. Tolkien",
+			wantAuthors: []model.BookAuthor{
+				{Name: "J.R.R. Tolkien", Role: model.AuthorRoleAuthor},
+				{Name: "J.R.R. Tolkien", Role: model.AuthorRoleAuthor},
+			},
+			wantErr: true,
+		},
+		{
+			name: "failed empty author name",
+			data: model.Book{
+				Authors: []model.BookAuthor{{Name: "Neil Gaiman"}, {Name: " ", Role: model.AuthorRoleTranslator}},
+			},
+			wantAuthor: "Neil Gaiman",
+			wantAuthors: []model.BookAuthor{
+				{Name: "Neil Gaiman", Role: model.AuthorRoleAuthor},
+				{Name: "", Role: model.AuthorRoleTranslator},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := normalizeAuthors(tt.data)
+			if got.Author != tt.wantAuthor {
+				t.Errorf("normalizeAuthors() author = %q, want %q", got.Author, tt.wantAuthor)
+			}
+			if !reflect.DeepEqual(got.Authors, tt.wantAuthors) {
+				t.Errorf("normalizeAuthors() authors = %+v, want %+v", got.Authors, tt.wantAuthors)
+			}
+
+			err := validateAuthors(got.Authors)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateAuthors() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package book
+
+import (
+	"byfood-app/internal/model"
+	"byfood-app/internal/pkg/authorname"
+	"byfood-app/internal/pkg/booktitle"
+	"byfood-app/internal/pkg/xerrors"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"slices"
+	"strings"
+)
+
+// duplicateThreshold is the score from which two books are taken for the
+// same book, a title with a typo by the same author in the same year is above
+// it, another book by the same author is not.
+const duplicateThreshold = 0.75
+
+// maxMergedBooks caps the books folded into another in one merge.
+const maxMergedBooks = 50
+
+// DuplicateBookError refuses a new book that probably is already stored,
+// Matches are the stored books, best first.
+type DuplicateBookError struct {
+	Matches []model.BookMatch
+}
+
+func (e DuplicateBookError) Error() string {
+	return fmt.Sprintf("book %d probably is the same book, store it with force=true if it is not", e.Matches[0].Book.ID)
+}
+
+func (e DuplicateBookError) Unwrap() error {
+	return xerrors.ConflictError{Err: errors.New("book probably is already stored")}
+}
+
+// MergedBookError is the read of a book merged into another, MergedInto is
+// the book it now lives on.
+type MergedBookError struct {
+	ID         int64
+	MergedInto int64
+}
+
+func (e MergedBookError) Error() string {
+	return fmt.Sprintf("book %d was merged into book %d", e.ID, e.MergedInto)
+}
+
+func (e MergedBookError) Unwrap() error {
+	return xerrors.NewClientError(xerrors.ErrDataNotFound)
+}
+
+// checkDuplicates fails with a DuplicateBookError when a stored book scores
+// above duplicateThreshold against data.
+func (logic *BookLogic) checkDuplicates(ctx context.Context, data model.Book) error {
+	candidates, err := logic.repo.GetDuplicateCandidates(ctx, data)
+	if err != nil {
+		logic.deps.Logger.ErrorContext(ctx, "failed to get book duplicate candidates", slog.Any("error", err))
+		return err
+	}
+
+	book := model.BookRef{Title: data.Title, Author: data.Author, PublishYear: data.PublishYear}
+
+	var matches []model.BookMatch
+	for _, candidate := range candidates {
+		score := duplicateScore(book, candidate)
+		if score >= duplicateThreshold {
+			matches = append(matches, model.BookMatch{Book: candidate, Score: score})
+		}
+	}
+	if len(matches) == 0 {
+		return nil
+	}
+
+	slices.SortStableFunc(matches, func(a, b model.BookMatch) int {
+		return cmp.Compare(b.Score, a.Score)
+	})
+
+	return DuplicateBookError{Matches: matches}
+}
+
+// GetBookDuplicates scores the pairs of live books sharing an author or a
+// similar title and returns those above duplicateThreshold, best first.
+func (logic *BookLogic) GetBookDuplicates(ctx context.Context) ([]model.BookDuplicate, error) {
+	candidates, err := logic.repo.GetBookDuplicates(ctx)
+	if err != nil {
+		logic.deps.Logger.ErrorContext(ctx, "failed to get book duplicates", slog.Any("error", err))
+		return nil, err
+	}
+
+	result := []model.BookDuplicate{}
+	for _, candidate := range candidates {
+		candidate.Score = duplicateScore(candidate.Books[0], candidate.Books[1])
+		if candidate.Score >= duplicateThreshold {
+			result = append(result, candidate)
+		}
+	}
+
+	slices.SortStableFunc(result, func(a, b model.BookDuplicate) int {
+		return cmp.Compare(b.Score, a.Score)
+	})
+
+	return result, nil
+}
+
+func (logic *BookLogic) MergeBooks(ctx context.Context, id int64, sourceIDs []int64, asEditions bool) (model.BookMerge, error) {
+	if id <= 0 {
+		return model.BookMerge{}, xerrors.NewClientError(xerrors.ErrInvalidID)
+	}
+
+	sourceIDs = slices.Clone(sourceIDs)
+	slices.Sort(sourceIDs)
+	sourceIDs = slices.Compact(sourceIDs)
+
+	switch {
+	case len(sourceIDs) == 0:
+		return model.BookMerge{}, xerrors.NewClientError(errors.New("book_ids is empty"))
+	case len(sourceIDs) > maxMergedBooks:
+		return model.BookMerge{}, xerrors.NewClientError(fmt.Errorf("at most %d books can be merged at once", maxMergedBooks))
+	case sourceIDs[0] <= 0:
+		return model.BookMerge{}, xerrors.NewClientError(xerrors.ErrInvalidID)
+	case slices.Contains(sourceIDs, id):
+		return model.BookMerge{}, xerrors.NewClientError(errors.New("a book can not be merged into itself"))
+	}
+
+	result, err := logic.repo.MergeBooks(ctx, id, sourceIDs, asEditions)
+	if err != nil {
+		logic.deps.Logger.ErrorContext(ctx, "failed to merge books", slog.Any("error", err))
+		return model.BookMerge{}, err
+	}
+
+	return result, nil
+}
+
+// duplicateScore weighs the title similarity at 0.6, the author similarity
+// at 0.3 and the publish year at 0.1, rounded to two decimals.
+func duplicateScore(a model.BookRef, b model.BookRef) float64 {
+	title := booktitle.Similarity(booktitle.Key(a.Title), booktitle.Key(b.Title))
+	author := authorSimilarity(a.Author, b.Author)
+
+	var year float64
+	switch diff := a.PublishYear - b.PublishYear; {
+	case diff == 0:
+		year = 1
+	case diff == 1 || diff == -1:
+		year = 0.5
+	}
+
+	return math.Round((0.6*title+0.3*author+0.1*year)*100) / 100
+}
+
+// authorSimilarity matches every name of the author string with fewer names
+// to its closest name of the other, an initialed form of a name counting
+// almost as much as the name itself.
+func authorSimilarity(a string, b string) float64 {
+	aKeys, bKeys := authorKeys(a), authorKeys(b)
+	if len(aKeys) == 0 || len(bKeys) == 0 {
+		return 0
+	}
+	if len(aKeys) > len(bKeys) {
+		aKeys, bKeys = bKeys, aKeys
+	}
+
+	var total float64
+	for _, aKey := range aKeys {
+		var best float64
+		for _, bKey := range bKeys {
+			switch {
+			case aKey == bKey:
+				best = 1
+			case authorname.ProbableDuplicate(aKey, bKey):
+				best = max(best, 0.9)
+			default:
+				best = max(best, booktitle.Similarity(aKey, bKey))
+			}
+		}
+		total += best
+	}
+
+	return total / float64(len(aKeys))
+}
+
+func authorKeys(author string) []string {
+	var result []string
+	for _, name := range strings.Split(author, authorSeparator) {
+		if key := authorname.Key(name); key != "" {
+			result = append(result, key)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,196 @@
+package book
+
+import (
+	"byfood-app/internal/core"
+	"byfood-app/internal/model"
+	"byfood-app/internal/pkg/xerrors"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestDuplicateScore(t *testing.T) {
+	pride := model.BookRef{Title: "Pride and Prejudice", Author: "Jane Austen", PublishYear: 1813}
+
+	tests := []struct {
+		name string
+		book model.BookRef
+		want float64
+	}{
+		{
+			name: "same book",
+			book: model.BookRef{Title: "Pride & Prejudice", Author: "Jane Austen", PublishYear: 1813},
+			want: 1,
+		},
+		{
+			name: "title typo",
+			book: model.BookRef{Title: "Pride and Predjudice", Author: "Jane Austen", PublishYear: 1813},
+			want: 0.86,
+		},
+		{
+			name: "initialed author a year off",
+			book: model.BookRef{Title: "Pride and Prejudice", Author: "J. Austen", PublishYear: 1814},
+			want: 0.92,
+		},
+		{
+			name: "another book by the same author",
+			book: model.BookRef{Title: "Emma", Author: "Jane Austen", PublishYear: 1815},
+			want: 0.3,
+		},
+		{
+			name: "same title by another author",
+			book: model.BookRef{Title: "Pride and Prejudice and Zombies", Author: "Seth Grahame-Smith", PublishYear: 2009},
+			want: 0.42,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := duplicateScore(pride, tt.book); got != tt.want {
+				t.Errorf("duplicateScore() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBookLogic_CheckDuplicates(t *testing.T) {
+	ts := setupTestSuite(t)
+	logic := &BookLogic{
+		deps: &core.Dependency{
+			Logger: slog.Default(),
+		},
+		repo: ts.MockBookRepo,
+	}
+
+	typo := model.BookRef{ID: 12, Title: "Pride and Predjudice", Author: "Jane Austen", PublishYear: 1813}
+	same := model.BookRef{ID: 3, Title: "Pride & Prejudice", Author: "Jane Austen", PublishYear: 1813}
+	emma := model.BookRef{ID: 4, Title: "Emma", Author: "Jane Austen", PublishYear: 1815}
+
+	ts.MockBookRepo.EXPECT().GetDuplicateCandidates(gomock.Any(), gomock.Any()).Return([]model.BookRef{typo, emma, same}, nil)
+
+	err := logic.checkDuplicates(context.Background(), model.Book{Title: "Pride and Prejudice", Author: "Jane Austen", PublishYear: 1813})
+
+	var duplicate DuplicateBookError
+	if !errors.As(err, &duplicate) {
+		t.Fatalf("BookLogic.checkDuplicates() error = %v, want DuplicateBookError", err)
+	}
+	if code := xerrors.ParseErrorTypeToCodeInt(err); code != http.StatusConflict {
+		t.Errorf("BookLogic.checkDuplicates() code = %v, want %v", code, http.StatusConflict)
+	}
+
+	want := []model.BookMatch{{Book: same, Score: 1}, {Book: typo, Score: 0.86}}
+	if !reflect.DeepEqual(duplicate.Matches, want) {
+		t.Errorf("BookLogic.checkDuplicates() matches = %+v, want %+v", duplicate.Matches, want)
+	}
+}
+
+func TestBookLogic_GetBookDuplicates(t *testing.T) {
+	ts := setupTestSuite(t)
+	logic := &BookLogic{
+		deps: &core.Dependency{
+			Logger: slog.Default(),
+		},
+		repo: ts.MockBookRepo,
+	}
+
+	pride := model.BookRef{ID: 3, Title: "Pride and Prejudice", Author: "Jane Austen", PublishYear: 1813}
+	emma := model.BookRef{ID: 4, Title: "Emma", Author: "Jane Austen", PublishYear: 1815}
+	typo := model.BookRef{ID: 12, Title: "Pride and Predjudice", Author: "Jane Austen", PublishYear: 1813}
+
+	// every pair sharing an author comes back, only the probable ones stay
+	ts.MockBookRepo.EXPECT().GetBookDuplicates(gomock.Any()).Return([]model.BookDuplicate{
+		{Books: []model.BookRef{pride, emma}},
+		{Books: []model.BookRef{pride, typo}},
+		{Books: []model.BookRef{emma, typo}},
+	}, nil)
+
+	got, err := logic.GetBookDuplicates(context.Background())
+	if err != nil {
+		t.Fatalf("BookLogic.GetBookDuplicates() error = %v", err)
+	}
+
+	want := []model.BookDuplicate{{Books: []model.BookRef{pride, typo}, Score: 0.86}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("BookLogic.GetBookDuplicates() = %+v, want %+v", got, want)
+	}
+}
+
+func TestBookLogic_MergeBooks(t *testing.T) {
+	ts := setupTestSuite(t)
+	logic := &BookLogic{
+		deps: &core.Dependency{
+			Logger: slog.Default(),
+		},
+		repo: ts.MockBookRepo,
+	}
+
+	tests := []struct {
+		name       string
+		id         int64
+		sourceIDs  []int64
+		asEditions bool
+		wantCode   int
+		mockFunc   func()
+	}{
+		{
+			name:      "success merge sorted unique sources",
+			id:        3,
+			sourceIDs: []int64{14, 12, 14},
+			wantCode:  http.StatusOK,
+			mockFunc: func() {
+				ts.MockBookRepo.EXPECT().MergeBooks(gomock.Any(), int64(3), []int64{12, 14}, false).
+					Return(model.BookMerge{MergedBookIDs: []int64{12, 14}}, nil)
+			},
+		},
+		{
+			name:       "success merge sources as editions",
+			id:         3,
+			sourceIDs:  []int64{12},
+			asEditions: true,
+			wantCode:   http.StatusOK,
+			mockFunc: func() {
+				ts.MockBookRepo.EXPECT().MergeBooks(gomock.Any(), int64(3), []int64{12}, true).
+					Return(model.BookMerge{MergedBookIDs: []int64{12}}, nil)
+			},
+		},
+		{
+			name:      "failed merge without sources",
+			id:        3,
+			sourceIDs: []int64{},
+			wantCode:  http.StatusBadRequest,
+			mockFunc:  func() {},
+		},
+		{
+			name:      "failed merge into itself",
+			id:        3,
+			sourceIDs: []int64{3, 12},
+			wantCode:  http.StatusBadRequest,
+			mockFunc:  func() {},
+		},
+		{
+			name:      "failed merge of an invalid id",
+			id:        3,
+			sourceIDs: []int64{-1, 12},
+			wantCode:  http.StatusBadRequest,
+			mockFunc:  func() {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc()
+
+			_, err := logic.MergeBooks(context.Background(), tt.id, tt.sourceIDs, tt.asEditions)
+			if err != nil && xerrors.ParseErrorTypeToCodeInt(err) != tt.wantCode {
+				t.Errorf("BookLogic.MergeBooks() error = %v, wantCode %v", err, tt.wantCode)
+				return
+			}
+			if (err != nil) != (tt.wantCode != http.StatusOK) {
+				t.Errorf("BookLogic.MergeBooks() error = %v, wantCode %v", err, tt.wantCode)
+			}
+		})
+	}
+}
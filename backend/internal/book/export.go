@@ -0,0 +1,155 @@
+package book
+
+import (
+	"byfood-app/internal/model"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+const (
+	exportFormatCSV    = "csv"
+	exportFormatNDJSON = "ndjson"
+	exportFormatJSON   = "json"
+
+	// exportFlushRows is how many rows are buffered before pushing them to the client.
+	exportFlushRows = 500
+)
+
+// bookExporter encodes a stream of books into a single export file.
+type bookExporter interface {
+	ContentType() string
+	Begin() error
+	Write(book model.Book) error
+	// Flush hands whatever the exporter buffered to the underlying writer.
+	Flush() error
+	End() error
+}
+
+func newBookExporter(format string, w io.Writer) (bookExporter, error) {
+	switch format {
+	case exportFormatCSV:
+		return &csvBookExporter{w: csv.NewWriter(w)}, nil
+	case exportFormatNDJSON:
+		return &ndjsonBookExporter{encoder: json.NewEncoder(w)}, nil
+	case exportFormatJSON:
+		return &jsonBookExporter{w: w}, nil
+	default:
+		return nil, fmt.Errorf("unknown export format: %s", format)
+	}
+}
+
+var csvExportHeader = []string{"id", "title", "author", "publish_year", "published", "isbn", "version", "created_at", "updated_at", "deleted_at"}
+
+type csvBookExporter struct {
+	w *csv.Writer
+}
+
+func (e *csvBookExporter) ContentType() string {
+	return "text/csv; charset=utf-8"
+}
+
+func (e *csvBookExporter) Begin() error {
+	return e.w.Write(csvExportHeader)
+}
+
+func (e *csvBookExporter) Write(book model.Book) error {
+	return e.w.Write([]string{
+		strconv.FormatInt(book.ID, 10),
+		book.Title,
+		book.Author,
+		strconv.FormatInt(book.PublishYear, 10),
+		bookPublished(book).String(),
+		book.ISBN,
+		strconv.FormatInt(book.Version, 10),
+		formatExportTime(book.CreatedAt),
+		formatExportTime(book.UpdatedAt),
+		formatExportTime(book.DeletedAt),
+	})
+}
+
+func (e *csvBookExporter) Flush() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func (e *csvBookExporter) End() error {
+	return e.Flush()
+}
+
+func formatExportTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+type ndjsonBookExporter struct {
+	encoder *json.Encoder
+}
+
+func (e *ndjsonBookExporter) ContentType() string {
+	return "application/x-ndjson"
+}
+
+func (e *ndjsonBookExporter) Begin() error {
+	return nil
+}
+
+func (e *ndjsonBookExporter) Write(book model.Book) error {
+	return e.encoder.Encode(book)
+}
+
+func (e *ndjsonBookExporter) Flush() error {
+	return nil
+}
+
+func (e *ndjsonBookExporter) End() error {
+	return nil
+}
+
+// jsonBookExporter writes a single JSON array, one element at a time.
+type jsonBookExporter struct {
+	w       io.Writer
+	written bool
+}
+
+func (e *jsonBookExporter) ContentType() string {
+	return "application/json"
+}
+
+func (e *jsonBookExporter) Begin() error {
+	_, err := io.WriteString(e.w, "[")
+	return err
+}
+
+func (e *jsonBookExporter) Write(book model.Book) error {
+	data, err := json.Marshal(book)
+	if err != nil {
+		return err
+	}
+
+	if e.written {
+		_, err = io.WriteString(e.w, ",")
+		if err != nil {
+			return err
+		}
+	}
+	e.written = true
+
+	_, err = e.w.Write(data)
+	return err
+}
+
+func (e *jsonBookExporter) Flush() error {
+	return nil
+}
+
+func (e *jsonBookExporter) End() error {
+	_, err := io.WriteString(e.w, "]\n")
+	return err
+}
@@ -0,0 +1,75 @@
+package book
+
+import (
+	"byfood-app/internal/model"
+	"byfood-app/internal/pkg/edtf"
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestBookExporter(t *testing.T) {
+	created := time.Date(2025, 8, 10, 16, 24, 56, 0, time.UTC)
+	books := []model.Book{
+		{ID: 1, Title: "One Piece", Author: "Eiichiro Oda", Authors: []model.BookAuthor{{ID: 4, Name: "Eiichiro Oda", Role: model.AuthorRoleAuthor}}, PublishYear: 1997, Published: &edtf.Date{Year: 1997, Month: 7, Day: 22}, ISBN: "9781569319017", ISBN10: "1569319014", Version: 1, BaseAudit: model.BaseAudit{CreatedAt: &created, UpdatedAt: &created}},
+		{ID: 2, Title: "Hello, World", Author: "Anonymous", PublishYear: 2001, Version: 2, BaseAudit: model.BaseAudit{CreatedAt: &created, UpdatedAt: &created}},
+	}
+
+	tests := []struct {
+		name   string
+		format string
+		books  []model.Book
+		want   string
+	}{
+		{
+			name:   "export csv quoting commas",
+			format: exportFormatCSV,
+			books:  books,
+			want: "id,title,author,publish_year,published,isbn,version,created_at,updated_at,deleted_at\n" +
+				"1,One Piece,Eiichiro Oda,1997,1997-07-22,9781569319017,1,2025-08-10T16:24:56Z,2025-08-10T16:24:56Z,\n" +
+				"2,\"Hello, World\",Anonymous,2001,2001,,2,2025-08-10T16:24:56Z,2025-08-10T16:24:56Z,\n",
+		},
+		{
+			name:   "export ndjson",
+			format: exportFormatNDJSON,
+			books:  books[:1],
+			want:   `{"id":1,"title":"One Piece","author":"Eiichiro Oda","authors":[{"id":4,"name":"Eiichiro Oda","role":"author"}],"publish_year":1997,"published":"1997-07-22","isbn":"9781569319017","isbn_10":"1569319014","version":1,"created_at":"2025-08-10T16:24:56Z","updated_at":"2025-08-10T16:24:56Z"}` + "\n",
+		},
+		{
+			name:   "export json array",
+			format: exportFormatJSON,
+			books:  []model.Book{{ID: 1}, {ID: 2}},
+			want:   `[{"id":1,"title":"","author":"","authors":null,"publish_year":0,"version":0},{"id":2,"title":"","author":"","authors":null,"publish_year":0,"version":0}]` + "\n",
+		},
+		{
+			name:   "export empty json array",
+			format: exportFormatJSON,
+			want:   "[]\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			exporter, err := newBookExporter(tt.format, &buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := exporter.Begin(); err != nil {
+				t.Fatal(err)
+			}
+			for _, book := range tt.books {
+				if err := exporter.Write(book); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := exporter.End(); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := buf.String(); got != tt.want {
+				t.Errorf("bookExporter output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
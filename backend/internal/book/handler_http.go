@@ -1,14 +1,29 @@
 package book
 
 import (
+	"bufio"
 	"byfood-app/internal/core"
 	"byfood-app/internal/model"
+	"byfood-app/internal/pkg/edtf"
+	"byfood-app/internal/pkg/identifier"
+	"byfood-app/internal/pkg/jsonpatch"
+	"byfood-app/internal/pkg/langtag"
+	"byfood-app/internal/pkg/pagination"
+	"byfood-app/internal/pkg/xauth"
 	"byfood-app/internal/pkg/xerrors"
 	"byfood-app/internal/pkg/xhttp"
 	"errors"
+	"fmt"
+	"io"
 	"log/slog"
+	"mime"
 	"net/http"
+	"net/url"
+	"path/filepath"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 )
@@ -26,54 +41,311 @@ func NewHTTPHandler(deps *core.Dependency, logic LogicInterface) *BookHandler {
 }
 
 // GetBooks godoc
-// @Summary List all books with pagination and search query params
+// @Summary List books with cursor pagination and search query params
 // @Tags books
 // @Produce json
 // @Param search query string false "search param to search by title and author"
-// @Param page query integer false "page number"
-// @Param size query integer false "item per page"
-// @Success 200 {object} xhttp.BaseResponse{data=[]model.Book, metadata=pagination.Metadata}
+// @Param mode query string false "search mode, simple (default, substring match) or fulltext (ranked, supports quoted phrases and -negation)"
+// @Param author query string false "filter by author name"
+// @Param genre query integer false "filter by genre ID, genres below it included"
+// @Param tag query string false "filter by tag name"
+// @Param identifier query string false "filter by outside identifier as scheme:value, e.g. oclc:12345"
+// @Param edition_format query string false "filter books with an edition in this format (hardcover, paperback, ebook, audiobook, large_print)"
+// @Param edition_language query string false "filter books with an edition in this BCP 47 language, e.g. en or pt-BR"
+// @Param include_editions query boolean false "list the editions of every book along"
+// @Param publish_year_from query integer false "filter books published in or after this year, 0 being 1 BCE"
+// @Param publish_year_to query integer false "filter books published in or before this year, 0 being 1 BCE"
+// @Param published_from query string false "filter books that may have been published on or after this EDTF date, like 1949 or 1949-06, instead of publish_year_from"
+// @Param published_to query string false "filter books that may have been published on or before this EDTF date, like 1949 or 1949-06, instead of publish_year_to"
+// @Param created_after query string false "filter books created after this RFC3339 timestamp"
+// @Param ids query string false "comma separated book IDs"
+// @Param include_deleted query boolean false "include soft deleted books, admin only"
+// @Param as_of query string false "list the catalog as it was at this RFC3339 timestamp, including books deleted since"
+// @Param sort query string false "comma separated sort fields with optional direction, e.g. publish_year:desc,title (title, author, publish_year, created_at, updated_at)"
+// @Param after query string false "cursor to fetch the page after, taken from next_cursor"
+// @Param before query string false "cursor to fetch the page before, taken from prev_cursor"
+// @Param limit query integer false "item per page, max 100"
+// @Success 200 {object} xhttp.BaseListResponse{data=[]model.Book,metadata=model.BookListMetadata}
+// @Header 200 {string} Link "RFC 8288 links to the next and previous pages"
 // @Router /books [get]
 func (h *BookHandler) GetBooks(w http.ResponseWriter, r *http.Request) {
+	params, err := parseBookSearchParams(r.URL.Query())
+	if err != nil {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to parse search params",
+		}, xerrors.ParseErrorTypeToCodeInt(err))
+		return
+	}
+
+	if params.IncludeDeleted && !xauth.IsAdmin(r, h.deps.AdminToken) {
+		err := xerrors.AuthError{Err: xauth.ErrAdminOnly}
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "include_deleted is only available to admin",
+		}, xerrors.ParseErrorTypeToCodeInt(err))
+		return
+	}
+
+	h.sendBookList(w, r, params, "books fetched")
+}
+
+// GetTrashBooks godoc
+// @Summary List soft deleted books, most recently deleted first, admin only
+// @Tags books
+// @Produce json
+// @Param search query string false "search param to search by title and author"
+// @Param as_of query string false "list books that were soft deleted at this RFC3339 timestamp"
+// @Param sort query string false "comma separated sort fields with optional direction, defaults to deleted_at:desc"
+// @Param after query string false "cursor to fetch the page after, taken from next_cursor"
+// @Param before query string false "cursor to fetch the page before, taken from prev_cursor"
+// @Param limit query integer false "item per page, max 100"
+// @Success 200 {object} xhttp.BaseListResponse{data=[]model.Book,metadata=model.BookListMetadata}
+// @Header 200 {string} Link "RFC 8288 links to the next and previous pages"
+// @Router /books/trash [get]
+func (h *BookHandler) GetTrashBooks(w http.ResponseWriter, r *http.Request) {
+	params, err := parseBookSearchParams(r.URL.Query())
+	if err != nil {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to parse search params",
+		}, xerrors.ParseErrorTypeToCodeInt(err))
+		return
+	}
+
+	params.OnlyDeleted = true
+	if len(params.Sort) == 0 {
+		params.Sort = []model.SortParam{{Field: "deleted_at", Desc: true}}
+	}
+
+	h.sendBookList(w, r, params, "deleted books fetched")
+}
+
+// GetAuthorBooks godoc
+// @Summary List the books crediting an author, in any role, with the same query params as the book listing
+// @Tags authors
+// @Produce json
+// @Param id path integer true "author ID"
+// @Param search query string false "search param to search by title and author"
+// @Param mode query string false "search mode, simple (default) or fulltext"
+// @Param publish_year_from query integer false "filter books published in or after this year, 0 being 1 BCE"
+// @Param publish_year_to query integer false "filter books published in or before this year, 0 being 1 BCE"
+// @Param published_from query string false "filter books that may have been published on or after this EDTF date, like 1949 or 1949-06, instead of publish_year_from"
+// @Param published_to query string false "filter books that may have been published on or before this EDTF date, like 1949 or 1949-06, instead of publish_year_to"
+// @Param include_deleted query boolean false "include soft deleted books, admin only"
+// @Param as_of query string false "list the books credited to the author at this RFC3339 timestamp"
+// @Param sort query string false "comma separated sort fields with optional direction, e.g. publish_year:desc"
+// @Param after query string false "cursor to fetch the page after, taken from next_cursor"
+// @Param before query string false "cursor to fetch the page before, taken from prev_cursor"
+// @Param limit query integer false "item per page, max 100"
+// @Success 200 {object} xhttp.BaseListResponse{data=[]model.Book,metadata=model.BookListMetadata}
+// @Header 200 {string} Link "RFC 8288 links to the next and previous pages"
+// @Router /authors/{id}/books [get]
+func (h *BookHandler) GetAuthorBooks(w http.ResponseWriter, r *http.Request) {
+	// get and validate id param
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   xerrors.ErrInvalidID.Error(),
+			Message: xerrors.ErrInvalidID.Error(),
+		}, http.StatusBadRequest)
+		return
+	}
+	idParam, err := strconv.Atoi(id)
+	if err != nil || idParam <= 0 {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   xerrors.ErrInvalidID.Error(),
+			Message: "failed to parse id parameter",
+		}, http.StatusBadRequest)
+		return
+	}
+
+	params, err := parseBookSearchParams(r.URL.Query())
+	if err != nil {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to parse search params",
+		}, xerrors.ParseErrorTypeToCodeInt(err))
+		return
+	}
+
+	if params.IncludeDeleted && !xauth.IsAdmin(r, h.deps.AdminToken) {
+		err := xerrors.AuthError{Err: xauth.ErrAdminOnly}
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "include_deleted is only available to admin",
+		}, xerrors.ParseErrorTypeToCodeInt(err))
+		return
+	}
+
+	params.AuthorID = int64(idParam)
+	h.sendBookList(w, r, params, "author books fetched")
+}
+
+// ExportBooks godoc
+// @Summary Stream every book matching the search query params as a file
+// @Tags books
+// @Produce text/csv,application/x-ndjson,json
+// @Param format query string false "export format, csv (default), ndjson or json"
+// @Param search query string false "search param to search by title and author"
+// @Param mode query string false "search mode, simple (default) or fulltext"
+// @Param author query string false "filter by author name"
+// @Param genre query integer false "filter by genre ID, genres below it included"
+// @Param tag query string false "filter by tag name"
+// @Param identifier query string false "filter by outside identifier as scheme:value, e.g. oclc:12345"
+// @Param edition_format query string false "filter books with an edition in this format (hardcover, paperback, ebook, audiobook, large_print)"
+// @Param edition_language query string false "filter books with an edition in this BCP 47 language, e.g. en or pt-BR"
+// @Param include_editions query boolean false "list the editions of every book along"
+// @Param publish_year_from query integer false "filter books published in or after this year, 0 being 1 BCE"
+// @Param publish_year_to query integer false "filter books published in or before this year, 0 being 1 BCE"
+// @Param published_from query string false "filter books that may have been published on or after this EDTF date, like 1949 or 1949-06, instead of publish_year_from"
+// @Param published_to query string false "filter books that may have been published on or before this EDTF date, like 1949 or 1949-06, instead of publish_year_to"
+// @Param created_after query string false "filter books created after this RFC3339 timestamp"
+// @Param ids query string false "comma separated book IDs"
+// @Param include_deleted query boolean false "include soft deleted books, admin only"
+// @Param as_of query string false "export the catalog as it was at this RFC3339 timestamp"
+// @Param sort query string false "comma separated sort fields with optional direction"
+// @Success 200 {file} file "books export"
+// @Header 200 {string} Content-Disposition "attachment file name"
+// @Router /books/export [get]
+func (h *BookHandler) ExportBooks(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	// these functions works, but
-	// use GetBooksNoPagination for now
-	// cuz it's hard to implement
-	// pagination on the frontend side
-	// skill issue on me :(
-
-	// page, err := pagination.ParsePaginationRequest(r)
-	// if err != nil {
-	// 	h.deps.Logger.WarnContext(ctx, "failed to parse pagination params", slog.Any("error", err))
-	// 	xhttp.SendJSONResponse(w, xhttp.BaseResponse{
-	// 		Error:   err.Error(),
-	// 		Message: "failed to parse pagination params",
-	// 	}, http.StatusBadRequest)
-	// 	return
-	// }
-
-	// data, meta, err := h.logic.GetBooks(ctx, model.BookSearchParams{
-	// 	Search: r.URL.Query().Get("search"),
-	// }, page)
-	// if err != nil && !errors.Is(err, xerrors.ErrDataNotFound) {
-	// 	h.deps.Logger.ErrorContext(ctx, "failed to get book(s)", slog.Any("error", err))
-	// 	xhttp.SendJSONResponse(w, xhttp.BaseResponse{
-	// 		Error:   err.Error(),
-	// 		Message: "failed to get book(s)",
-	// 	}, xerrors.ParseErrorTypeToCodeInt(err))
-	// 	return
-	// }
-
-	// xhttp.SendJSONResponse(w, xhttp.BaseListResponse{
-	// 	Message:  "books fetched",
-	// 	Data:     data,
-	// 	Metadata: meta,
-	// }, http.StatusOK)
-
-	data, err := h.logic.GetBooksNoPagination(ctx, model.BookSearchParams{
-		Search: r.URL.Query().Get("search"),
+	query := r.URL.Query()
+	format := query.Get("format")
+	if format == "" {
+		format = exportFormatCSV
+	}
+	query.Del("format")
+
+	for _, key := range []string{"after", "before", "limit"} {
+		if query.Has(key) {
+			xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+				Error:   fmt.Sprintf("%s params is not supported, export returns every matching book", key),
+				Message: "failed to parse search params",
+			}, http.StatusBadRequest)
+			return
+		}
+	}
+
+	params, err := parseBookSearchParams(query)
+	if err != nil {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to parse search params",
+		}, xerrors.ParseErrorTypeToCodeInt(err))
+		return
+	}
+
+	if params.IncludeDeleted && !xauth.IsAdmin(r, h.deps.AdminToken) {
+		err := xerrors.AuthError{Err: xauth.ErrAdminOnly}
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "include_deleted is only available to admin",
+		}, xerrors.ParseErrorTypeToCodeInt(err))
+		return
+	}
+
+	buffered := bufio.NewWriter(w)
+	exporter, err := newBookExporter(format, buffered)
+	if err != nil {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to parse format parameter",
+		}, http.StatusBadRequest)
+		return
+	}
+
+	controller := http.NewResponseController(w)
+	flush := func() error {
+		err := exporter.Flush()
+		if err != nil {
+			return err
+		}
+
+		err = buffered.Flush()
+		if err != nil {
+			return err
+		}
+
+		err = controller.Flush()
+		if err != nil && !errors.Is(err, http.ErrNotSupported) {
+			return err
+		}
+
+		return nil
+	}
+
+	// the response starts with the first row, so a failing query
+	// can still be answered with a regular error response
+	started := false
+	start := func() error {
+		started = true
+		w.Header().Set("Content-Type", exporter.ContentType())
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="books-%s.%s"`, time.Now().UTC().Format("20060102"), format))
+		w.WriteHeader(http.StatusOK)
+
+		return exporter.Begin()
+	}
+
+	count := 0
+	err = h.logic.ExportBooks(ctx, params, func(book model.Book) error {
+		if !started {
+			err := start()
+			if err != nil {
+				return err
+			}
+		}
+
+		err := exporter.Write(book)
+		if err != nil {
+			return err
+		}
+
+		count++
+		if count%exportFlushRows == 0 {
+			return flush()
+		}
+
+		return nil
 	})
+	if err == nil && !started {
+		err = start()
+	}
+	if err == nil {
+		err = exporter.End()
+	}
+	if err == nil {
+		err = flush()
+	}
+	if err != nil {
+		h.deps.Logger.ErrorContext(ctx, "failed to export books", slog.Any("error", err), slog.Int("exported", count))
+		if !started {
+			xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+				Error:   err.Error(),
+				Message: "failed to export books",
+			}, xerrors.ParseErrorTypeToCodeInt(err))
+		}
+		// otherwise the status is already sent, the client sees a truncated file
+		return
+	}
+}
+
+// sendBookList fetches a page of books and sends it along with its pagination links.
+func (h *BookHandler) sendBookList(w http.ResponseWriter, r *http.Request, params model.BookSearchParams, message string) {
+	ctx := r.Context()
+
+	page, err := pagination.ParseCursorRequest(r, []byte(h.deps.CursorSecret))
+	if err != nil {
+		h.deps.Logger.WarnContext(ctx, "failed to parse pagination params", slog.Any("error", err))
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to parse pagination params",
+		}, http.StatusBadRequest)
+		return
+	}
+
+	data, meta, err := h.logic.GetBooks(ctx, params, page)
 	if err != nil && !errors.Is(err, xerrors.ErrDataNotFound) {
 		h.deps.Logger.ErrorContext(ctx, "failed to get book(s)", slog.Any("error", err))
 		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
@@ -82,9 +354,99 @@ func (h *BookHandler) GetBooks(w http.ResponseWriter, r *http.Request) {
 		}, xerrors.ParseErrorTypeToCodeInt(err))
 		return
 	}
+
+	err = meta.Encode([]byte(h.deps.CursorSecret))
+	if err != nil {
+		h.deps.Logger.ErrorContext(ctx, "failed to encode pagination cursor", slog.Any("error", err))
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to get book(s)",
+		}, http.StatusInternalServerError)
+		return
+	}
+
+	if links := meta.Links(r.URL); links != "" {
+		w.Header().Set("Link", links)
+	}
+
 	xhttp.SendJSONResponse(w, xhttp.BaseListResponse{
-		Message: "books fetched",
+		Message:  message,
+		Data:     data,
+		Metadata: meta,
+	}, http.StatusOK)
+}
+
+// SuggestBooks godoc
+// @Summary Autocomplete book titles or authors by prefix
+// @Tags books
+// @Produce json
+// @Param q query string true "prefix typed by the user"
+// @Param field query string false "field to complete, title (default) or author"
+// @Param limit query integer false "max suggestions, default 5, max 20"
+// @Success 200 {object} xhttp.BaseResponse{data=[]model.BookSuggestion}
+// @Router /books/suggest [get]
+func (h *BookHandler) SuggestBooks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var limit int
+	if val := r.URL.Query().Get("limit"); val != "" {
+		limitInt, err := strconv.Atoi(val)
+		if err != nil {
+			xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+				Error:   err.Error(),
+				Message: "failed to parse limit parameter",
+			}, http.StatusBadRequest)
+			return
+		}
+		limit = limitInt
+	}
+
+	data, err := h.logic.GetBookSuggestions(ctx, model.BookSuggestParams{
+		Query: r.URL.Query().Get("q"),
+		Field: r.URL.Query().Get("field"),
+		Limit: limit,
+	})
+	if err != nil {
+		h.deps.Logger.ErrorContext(ctx, "failed to get book suggestions", slog.Any("error", err))
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to get book suggestions",
+		}, xerrors.ParseErrorTypeToCodeInt(err))
+		return
+	}
+
+	xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+		Data:    data,
+		Message: "book suggestions fetched",
+	}, http.StatusOK)
+}
+
+// GetBookByISBN godoc
+// @Summary Get a book data by its ISBN
+// @Description Takes an ISBN-10 or ISBN-13, with or without hyphens, and finds the book holding it or the book of the edition holding it. Soft deleted books are not found.
+// @Tags books
+// @Produce json
+// @Param isbn path string true "ISBN-10 or ISBN-13"
+// @Success 200 {object} xhttp.BaseResponse{data=model.Book}
+// @Header 200 {string} ETag "current book version"
+// @Router /books/isbn/{isbn} [get]
+func (h *BookHandler) GetBookByISBN(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	data, err := h.logic.GetBookByISBN(ctx, chi.URLParam(r, "isbn"))
+	if err != nil {
+		h.deps.Logger.ErrorContext(ctx, "failed to get book data by isbn", slog.Any("error", err))
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to get book data",
+		}, xerrors.ParseErrorTypeToCodeInt(err))
+		return
+	}
+
+	w.Header().Set("ETag", xhttp.ETag(data.Version, data))
+	xhttp.SendJSONResponse(w, xhttp.BaseResponse{
 		Data:    data,
+		Message: "book data fetched",
 	}, http.StatusOK)
 }
 
@@ -93,7 +455,12 @@ func (h *BookHandler) GetBooks(w http.ResponseWriter, r *http.Request) {
 // @Tags books
 // @Produce json
 // @Param id path integer true "book ID"
+// @Param as_of query string false "get the book as it was at this RFC3339 timestamp"
+// @Param If-None-Match header string false "ETag from a previous response, answered with 304 when unchanged"
 // @Success 200 {object} xhttp.BaseResponse{data=model.Book}
+// @Header 200 {string} ETag "book version and a hash of the book as served, or those at as_of"
+// @Success 304
+// @Success 302 {object} xhttp.BaseResponse{data=model.BookRef} "book was merged, Location points to the book it was merged into"
 // @Router /books/{id} [get]
 func (h *BookHandler) GetBookByID(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -116,7 +483,30 @@ func (h *BookHandler) GetBookByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	data, err := h.logic.GetBookByID(ctx, int64(idParam))
+	var data model.Book
+	if val := r.URL.Query().Get("as_of"); val != "" {
+		asOf, err := time.Parse(time.RFC3339, val)
+		if err != nil {
+			xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+				Error:   err.Error(),
+				Message: "failed to parse as_of parameter",
+			}, http.StatusBadRequest)
+			return
+		}
+
+		data, err = h.logic.GetBookAsOf(ctx, int64(idParam), asOf)
+	} else {
+		data, err = h.logic.GetBookByID(ctx, int64(idParam))
+	}
+	var merged MergedBookError
+	if errors.As(err, &merged) {
+		w.Header().Set("Location", fmt.Sprintf("/books/%d", merged.MergedInto))
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Data:    model.BookRef{ID: merged.MergedInto},
+			Message: merged.Error(),
+		}, http.StatusFound)
+		return
+	}
 	if err != nil {
 		h.deps.Logger.ErrorContext(ctx, "failed to get book data by id", slog.Any("error", err))
 		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
@@ -126,6 +516,13 @@ func (h *BookHandler) GetBookByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	etag := xhttp.ETag(data.Version, data)
+	w.Header().Set("ETag", etag)
+	if xhttp.MatchETag(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
 	xhttp.SendJSONResponse(w, xhttp.BaseResponse{
 		Data:    data,
 		Message: "book data fetched",
@@ -136,12 +533,28 @@ func (h *BookHandler) GetBookByID(w http.ResponseWriter, r *http.Request) {
 // @Summary Store new book data, return stored data
 // @Tags books
 // @Produce json
+// @Param force query boolean false "store the book even when it probably is already stored"
 // @Param data body model.StoreBookRequest true "book data"
 // @Success 200 {object} xhttp.BaseResponse{data=model.Book}
+// @Header 200 {string} ETag "stored book version"
+// @Failure 409 {object} xhttp.BaseResponse{data=[]model.BookMatch} "book probably is already stored, matches best first"
 // @Router /books [post]
 func (h *BookHandler) StoreBook(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
+	var force bool
+	if val := r.URL.Query().Get("force"); val != "" {
+		var err error
+		force, err = strconv.ParseBool(val)
+		if err != nil {
+			xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+				Error:   err.Error(),
+				Message: "failed to parse force parameter",
+			}, http.StatusBadRequest)
+			return
+		}
+	}
+
 	// parse request body
 	var payload model.StoreBookRequest
 	err := xhttp.BindJSONRequest(r, &payload)
@@ -156,8 +569,20 @@ func (h *BookHandler) StoreBook(w http.ResponseWriter, r *http.Request) {
 	data, err := h.logic.StoreBook(ctx, model.Book{
 		Title:       payload.Title,
 		Author:      payload.Author,
+		Authors:     payload.Authors,
 		PublishYear: payload.PublishYear,
-	})
+		Published:   payload.Published,
+		ISBN:        payload.ISBN,
+	}, force)
+	var duplicate DuplicateBookError
+	if errors.As(err, &duplicate) {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Data:    duplicate.Matches,
+			Error:   err.Error(),
+			Message: "book probably is already stored",
+		}, http.StatusConflict)
+		return
+	}
 	if err != nil {
 		h.deps.Logger.ErrorContext(ctx, "failed to store book data", slog.Any("error", err))
 		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
@@ -167,19 +592,112 @@ func (h *BookHandler) StoreBook(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	w.Header().Set("ETag", xhttp.ETag(data.Version, data))
+
 	xhttp.SendJSONResponse(w, xhttp.BaseResponse{
 		Data:    data,
 		Message: "book data stored",
 	}, http.StatusOK)
 }
 
+// ImportBooks godoc
+// @Summary Bulk import books from a CSV or NDJSON upload, return a per row report
+// @Description CSV needs a header with title, author and publish_year columns, optional published and isbn columns, several authors separated by " & ", NDJSON takes one book object per line with author or authors. The upload is either the raw request body or the "file" field of a multipart form.
+// @Tags books
+// @Accept text/csv,application/x-ndjson,multipart/form-data
+// @Produce json
+// @Param dry_run query boolean false "only validate the upload, store nothing"
+// @Param force query boolean false "import the rows that probably are already stored books too"
+// @Param mode query string false "all_or_nothing (default) stores nothing when any row is rejected, best_effort stores every accepted row"
+// @Param file formData file false "CSV or NDJSON file, when uploading as multipart form"
+// @Success 200 {object} xhttp.BaseResponse{data=model.BookImportReport}
+// @Failure 415 {object} xhttp.BaseResponse "unsupported upload content type"
+// @Failure 422 {object} xhttp.BaseResponse{data=model.BookImportReport} "all or nothing import aborted by rejected rows"
+// @Router /books/import [post]
+func (h *BookHandler) ImportBooks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	params := model.BookImportParams{
+		Mode: r.URL.Query().Get("mode"),
+	}
+	if val := r.URL.Query().Get("dry_run"); val != "" {
+		dryRun, err := strconv.ParseBool(val)
+		if err != nil {
+			xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+				Error:   err.Error(),
+				Message: "failed to parse dry_run parameter",
+			}, http.StatusBadRequest)
+			return
+		}
+		params.DryRun = dryRun
+	}
+	if val := r.URL.Query().Get("force"); val != "" {
+		force, err := strconv.ParseBool(val)
+		if err != nil {
+			xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+				Error:   err.Error(),
+				Message: "failed to parse force parameter",
+			}, http.StatusBadRequest)
+			return
+		}
+		params.Force = force
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportBodySize)
+	defer r.Body.Close()
+
+	body, format, err := importUpload(r)
+	if err != nil {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to read upload",
+		}, http.StatusUnsupportedMediaType)
+		return
+	}
+	params.Format = format
+
+	report, err := h.logic.ImportBooks(ctx, params, body)
+	if err != nil {
+		h.deps.Logger.ErrorContext(ctx, "failed to import book data", slog.Any("error", err))
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to import book data",
+		}, xerrors.ParseErrorTypeToCodeInt(err))
+		return
+	}
+
+	switch {
+	case report.DryRun:
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Data:    report,
+			Message: "book import validated",
+		}, http.StatusOK)
+	case report.Mode == model.ImportModeAllOrNothing && report.Rejected > 0:
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Data:    report,
+			Error:   fmt.Sprintf("%d row(s) rejected", report.Rejected),
+			Message: "book import aborted, no book stored",
+		}, http.StatusUnprocessableEntity)
+	default:
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Data:    report,
+			Message: "book data imported",
+		}, http.StatusOK)
+	}
+}
+
 // UpdateBook godoc
 // @Summary Update book data by ID, return updated data
+// @Description Without authors, an unchanged author string keeps the stored credits, editors and translators included. Without published, an unchanged publish year keeps the stored date, month, day and qualifiers included.
 // @Tags books
 // @Produce json
 // @Param id path integer true "book ID"
+// @Param If-Match header string true "ETag of the book version being updated"
 // @Param data body model.UpdateBookRequest true "book data"
 // @Success 200 {object} xhttp.BaseResponse{data=model.Book}
+// @Header 200 {string} ETag "updated book version"
+// @Failure 412 {object} xhttp.BaseResponse "book has been modified since the given ETag"
+// @Failure 428 {object} xhttp.BaseResponse "If-Match header is missing"
 // @Router /books/{id} [put]
 func (h *BookHandler) UpdateBook(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -202,6 +720,15 @@ func (h *BookHandler) UpdateBook(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	version, err := h.ifMatchVersion(r, int64(idParam))
+	if err != nil {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to parse If-Match header",
+		}, xerrors.ParseErrorTypeToCodeInt(err))
+		return
+	}
+
 	// parse request body
 	var payload model.UpdateBookRequest
 	err = xhttp.BindJSONRequest(r, &payload)
@@ -217,7 +744,11 @@ func (h *BookHandler) UpdateBook(w http.ResponseWriter, r *http.Request) {
 		ID:          int64(idParam),
 		Title:       payload.Title,
 		Author:      payload.Author,
+		Authors:     payload.Authors,
 		PublishYear: payload.PublishYear,
+		Published:   payload.Published,
+		ISBN:        payload.ISBN,
+		Version:     version,
 	})
 	if err != nil {
 		h.deps.Logger.ErrorContext(ctx, "failed to update book data", slog.Any("error", err))
@@ -228,6 +759,8 @@ func (h *BookHandler) UpdateBook(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	w.Header().Set("ETag", xhttp.ETag(data.Version, data))
+
 	xhttp.SendJSONResponse(w, xhttp.BaseResponse{
 		Data:    data,
 		Message: "book data updated",
@@ -235,14 +768,22 @@ func (h *BookHandler) UpdateBook(w http.ResponseWriter, r *http.Request) {
 
 }
 
-// DeleteBook godoc
-// @Summary Delete book data by ID
+// PatchBook godoc
+// @Summary Partially update book data by ID, return updated data
+// @Description Accepts a JSON Merge Patch (application/merge-patch+json) or a JSON Patch (application/json-patch+json) over title, author, publish_year, published and isbn
 // @Tags books
+// @Accept application/merge-patch+json,application/json-patch+json
 // @Produce json
 // @Param id path integer true "book ID"
-// @Success 200 {object} xhttp.BaseResponse{message=string}
-// @Router /books/{id} [delete]
-func (h *BookHandler) DeleteBook(w http.ResponseWriter, r *http.Request) {
+// @Param If-Match header string true "ETag of the book version being patched"
+// @Param data body object true "merge patch object or json patch operations array"
+// @Success 200 {object} xhttp.BaseResponse{data=model.Book}
+// @Header 200 {string} ETag "patched book version"
+// @Failure 412 {object} xhttp.BaseResponse "book has been modified since the given ETag, or a json patch test operation failed"
+// @Failure 415 {object} xhttp.BaseResponse "unsupported patch content type"
+// @Failure 428 {object} xhttp.BaseResponse "If-Match header is missing"
+// @Router /books/{id} [patch]
+func (h *BookHandler) PatchBook(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	// get and validate id param
@@ -263,17 +804,854 @@ func (h *BookHandler) DeleteBook(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = h.logic.DeleteBook(ctx, int64(idParam))
+	contentType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || (contentType != jsonpatch.MergePatchType && contentType != jsonpatch.JSONPatchType) {
+		w.Header().Set("Accept-Patch", jsonpatch.MergePatchType+", "+jsonpatch.JSONPatchType)
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   fmt.Sprintf("unsupported patch content type: %s", r.Header.Get("Content-Type")),
+			Message: "failed to parse request body",
+		}, http.StatusUnsupportedMediaType)
+		return
+	}
+
+	version, err := h.ifMatchVersion(r, int64(idParam))
+	if err != nil {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to parse If-Match header",
+		}, xerrors.ParseErrorTypeToCodeInt(err))
+		return
+	}
+
+	// parse request body
+	defer r.Body.Close()
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to parse request body",
+		}, http.StatusBadRequest)
+		return
+	}
+
+	data, err := h.logic.PatchBook(ctx, int64(idParam), version, model.BookPatch{
+		ContentType: contentType,
+		Data:        body,
+	})
 	if err != nil {
-		h.deps.Logger.ErrorContext(ctx, "failed to delete book data", slog.Any("error", err))
+		h.deps.Logger.ErrorContext(ctx, "failed to patch book data", slog.Any("error", err))
 		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
 			Error:   err.Error(),
-			Message: "failed to delete book data",
+			Message: "failed to patch book data",
 		}, xerrors.ParseErrorTypeToCodeInt(err))
 		return
 	}
 
+	w.Header().Set("ETag", xhttp.ETag(data.Version, data))
 	xhttp.SendJSONResponse(w, xhttp.BaseResponse{
-		Message: "book data deleted",
+		Data:    data,
+		Message: "book data patched",
 	}, http.StatusOK)
 }
+
+// DeleteBook godoc
+// @Summary Delete book data by ID
+// @Tags books
+// @Produce json
+// @Param id path integer true "book ID"
+// @Param If-Match header string true "ETag of the book version being deleted"
+// @Success 200 {object} xhttp.BaseResponse{message=string}
+// @Failure 412 {object} xhttp.BaseResponse "book has been modified since the given ETag"
+// @Failure 428 {object} xhttp.BaseResponse "If-Match header is missing"
+// @Router /books/{id} [delete]
+func (h *BookHandler) DeleteBook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// get and validate id param
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   xerrors.ErrInvalidID.Error(),
+			Message: xerrors.ErrInvalidID.Error(),
+		}, http.StatusBadRequest)
+		return
+	}
+	idParam, err := strconv.Atoi(id)
+	if err != nil {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to parse id parameter",
+		}, http.StatusBadRequest)
+		return
+	}
+
+	version, err := h.ifMatchVersion(r, int64(idParam))
+	if err != nil {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to parse If-Match header",
+		}, xerrors.ParseErrorTypeToCodeInt(err))
+		return
+	}
+
+	err = h.logic.DeleteBook(ctx, int64(idParam), version)
+	if err != nil {
+		h.deps.Logger.ErrorContext(ctx, "failed to delete book data", slog.Any("error", err))
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to delete book data",
+		}, xerrors.ParseErrorTypeToCodeInt(err))
+		return
+	}
+
+	xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+		Message: "book data deleted",
+	}, http.StatusOK)
+}
+
+// RestoreBook godoc
+// @Summary Restore a soft deleted book by ID, return restored data, admin only
+// @Tags books
+// @Produce json
+// @Param id path integer true "book ID"
+// @Success 200 {object} xhttp.BaseResponse{data=model.Book}
+// @Header 200 {string} ETag "restored book version"
+// @Router /books/{id}/restore [post]
+func (h *BookHandler) RestoreBook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// get and validate id param
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   xerrors.ErrInvalidID.Error(),
+			Message: xerrors.ErrInvalidID.Error(),
+		}, http.StatusBadRequest)
+		return
+	}
+	idParam, err := strconv.Atoi(id)
+	if err != nil {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to parse id parameter",
+		}, http.StatusBadRequest)
+		return
+	}
+
+	data, err := h.logic.RestoreBook(ctx, int64(idParam))
+	if err != nil {
+		h.deps.Logger.ErrorContext(ctx, "failed to restore book data", slog.Any("error", err))
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to restore book data",
+		}, xerrors.ParseErrorTypeToCodeInt(err))
+		return
+	}
+
+	w.Header().Set("ETag", xhttp.ETag(data.Version, data))
+
+	xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+		Data:    data,
+		Message: "book data restored",
+	}, http.StatusOK)
+}
+
+// SetBookIdentifiers godoc
+// @Summary Replace the outside identifiers of a book by ID, return them
+// @Description Schemes are oclc, lccn, openlibrary and goodreads, values are checked against the scheme format and normalized. Identifiers are not part of the book version, so this neither bumps the version nor adds a revision. An empty list clears them.
+// @Tags books
+// @Accept json
+// @Produce json
+// @Param id path integer true "book ID"
+// @Param data body model.SetBookIdentifiersRequest true "identifiers of the book"
+// @Success 200 {object} xhttp.BaseResponse{data=[]model.BookIdentifier}
+// @Router /books/{id}/identifiers [put]
+func (h *BookHandler) SetBookIdentifiers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// get and validate id param
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   xerrors.ErrInvalidID.Error(),
+			Message: xerrors.ErrInvalidID.Error(),
+		}, http.StatusBadRequest)
+		return
+	}
+	idParam, err := strconv.Atoi(id)
+	if err != nil {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to parse id parameter",
+		}, http.StatusBadRequest)
+		return
+	}
+
+	// parse request body
+	var payload model.SetBookIdentifiersRequest
+	err = xhttp.BindJSONRequest(r, &payload)
+	if err != nil {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to parse request body",
+		}, http.StatusBadRequest)
+		return
+	}
+
+	data, err := h.logic.SetBookIdentifiers(ctx, int64(idParam), payload.Identifiers)
+	if err != nil {
+		h.deps.Logger.ErrorContext(ctx, "failed to set book identifiers", slog.Any("error", err))
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to set book identifiers",
+		}, xerrors.ParseErrorTypeToCodeInt(err))
+		return
+	}
+
+	xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+		Data:    data,
+		Message: "book identifiers updated",
+	}, http.StatusOK)
+}
+
+// GetBookDuplicates godoc
+// @Summary List pairs of live books that are probably the same book, admin only
+// @Description Pairs share an author or have a similar title and score at least 0.75, weighing title similarity at 0.6, author similarity at 0.3 and publish year at 0.1. Best pairs first.
+// @Tags books
+// @Produce json
+// @Success 200 {object} xhttp.BaseResponse{data=[]model.BookDuplicate}
+// @Router /books/duplicates [get]
+func (h *BookHandler) GetBookDuplicates(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	data, err := h.logic.GetBookDuplicates(ctx)
+	if err != nil {
+		h.deps.Logger.ErrorContext(ctx, "failed to get book duplicates", slog.Any("error", err))
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to get book duplicates",
+		}, xerrors.ParseErrorTypeToCodeInt(err))
+		return
+	}
+
+	xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+		Data:    data,
+		Message: "book duplicates fetched",
+	}, http.StatusOK)
+}
+
+// MergeBooks godoc
+// @Summary Merge books into the book by ID, admin only
+// @Description The merged books are soft deleted pointing to the book, which takes over their editions, identifiers, genres and tags, and their ISBN when it has none. With as_editions each merged book becomes an edition of the book instead, keeping its ISBN and publication date. Reading a merged book redirects to the book. All in one transaction.
+// @Tags books
+// @Accept json
+// @Produce json
+// @Param id path integer true "book ID to merge into"
+// @Param data body model.MergeBooksRequest true "books to merge"
+// @Success 200 {object} xhttp.BaseResponse{data=model.BookMerge}
+// @Header 200 {string} ETag "book version"
+// @Router /books/{id}/merge [post]
+func (h *BookHandler) MergeBooks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// get and validate id param
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   xerrors.ErrInvalidID.Error(),
+			Message: xerrors.ErrInvalidID.Error(),
+		}, http.StatusBadRequest)
+		return
+	}
+	idParam, err := strconv.Atoi(id)
+	if err != nil {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to parse id parameter",
+		}, http.StatusBadRequest)
+		return
+	}
+
+	// parse request body
+	var payload model.MergeBooksRequest
+	err = xhttp.BindJSONRequest(r, &payload)
+	if err != nil {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to parse request body",
+		}, http.StatusBadRequest)
+		return
+	}
+
+	data, err := h.logic.MergeBooks(ctx, int64(idParam), payload.BookIDs, payload.AsEditions)
+	if err != nil {
+		h.deps.Logger.ErrorContext(ctx, "failed to merge books", slog.Any("error", err))
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to merge books",
+		}, xerrors.ParseErrorTypeToCodeInt(err))
+		return
+	}
+
+	w.Header().Set("ETag", xhttp.ETag(data.Book.Version, data.Book))
+
+	xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+		Data:    data,
+		Message: "books merged",
+	}, http.StatusOK)
+}
+
+// GetBookHistory godoc
+// @Summary Get every revision of a book, oldest first, with field level diffs
+// @Tags books
+// @Produce json
+// @Param id path integer true "book ID"
+// @Success 200 {object} xhttp.BaseResponse{data=[]model.BookRevision}
+// @Router /books/{id}/history [get]
+func (h *BookHandler) GetBookHistory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// get and validate id param
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   xerrors.ErrInvalidID.Error(),
+			Message: xerrors.ErrInvalidID.Error(),
+		}, http.StatusBadRequest)
+		return
+	}
+	idParam, err := strconv.Atoi(id)
+	if err != nil {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to parse id parameter",
+		}, http.StatusBadRequest)
+		return
+	}
+
+	data, err := h.logic.GetBookHistory(ctx, int64(idParam))
+	if err != nil {
+		h.deps.Logger.ErrorContext(ctx, "failed to get book history", slog.Any("error", err))
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to get book history",
+		}, xerrors.ParseErrorTypeToCodeInt(err))
+		return
+	}
+
+	xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+		Data:    data,
+		Message: "book history retrieved",
+	}, http.StatusOK)
+}
+
+// GetBookRevision godoc
+// @Summary Get a single revision of a book with its before and after snapshots
+// @Tags books
+// @Produce json
+// @Param id path integer true "book ID"
+// @Param rev path integer true "revision, the book version the change produced"
+// @Success 200 {object} xhttp.BaseResponse{data=model.BookRevision}
+// @Router /books/{id}/history/{rev} [get]
+func (h *BookHandler) GetBookRevision(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// get and validate id and rev params
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   xerrors.ErrInvalidID.Error(),
+			Message: xerrors.ErrInvalidID.Error(),
+		}, http.StatusBadRequest)
+		return
+	}
+	idParam, err := strconv.Atoi(id)
+	if err != nil {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to parse id parameter",
+		}, http.StatusBadRequest)
+		return
+	}
+	revParam, err := strconv.Atoi(chi.URLParam(r, "rev"))
+	if err != nil {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to parse rev parameter",
+		}, http.StatusBadRequest)
+		return
+	}
+
+	data, err := h.logic.GetBookRevision(ctx, int64(idParam), int64(revParam))
+	if err != nil {
+		h.deps.Logger.ErrorContext(ctx, "failed to get book revision", slog.Any("error", err))
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to get book revision",
+		}, xerrors.ParseErrorTypeToCodeInt(err))
+		return
+	}
+
+	xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+		Data:    data,
+		Message: "book revision retrieved",
+	}, http.StatusOK)
+}
+
+// GetBookSnapshots godoc
+// @Summary Get the book as it was after each of its revisions, oldest first, to pick a revert target
+// @Tags books
+// @Produce json
+// @Param id path integer true "book ID"
+// @Success 200 {object} xhttp.BaseResponse{data=[]model.BookSnapshot}
+// @Router /books/{id}/snapshots [get]
+func (h *BookHandler) GetBookSnapshots(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// get and validate id param
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   xerrors.ErrInvalidID.Error(),
+			Message: xerrors.ErrInvalidID.Error(),
+		}, http.StatusBadRequest)
+		return
+	}
+	idParam, err := strconv.Atoi(id)
+	if err != nil {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to parse id parameter",
+		}, http.StatusBadRequest)
+		return
+	}
+
+	data, err := h.logic.GetBookSnapshots(ctx, int64(idParam))
+	if err != nil {
+		h.deps.Logger.ErrorContext(ctx, "failed to get book snapshots", slog.Any("error", err))
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to get book snapshots",
+		}, xerrors.ParseErrorTypeToCodeInt(err))
+		return
+	}
+
+	xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+		Data:    data,
+		Message: "book snapshots retrieved",
+	}, http.StatusOK)
+}
+
+// RevertBook godoc
+// @Summary Revert title, author and publish year of a book to a snapshot, return reverted data
+// @Description The revert is recorded as a new revision. Soft deleted books have to be restored first.
+// @Tags books
+// @Produce json
+// @Param id path integer true "book ID"
+// @Param snapshot query integer true "snapshot to revert to, from GET /books/{id}/snapshots"
+// @Param If-Match header string true "current book ETag"
+// @Success 200 {object} xhttp.BaseResponse{data=model.Book}
+// @Header 200 {string} ETag "reverted book version"
+// @Failure 412 {object} xhttp.BaseResponse "If-Match does not match the current version"
+// @Failure 428 {object} xhttp.BaseResponse "If-Match header is missing"
+// @Router /books/{id}/revert [post]
+func (h *BookHandler) RevertBook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// get and validate id param
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   xerrors.ErrInvalidID.Error(),
+			Message: xerrors.ErrInvalidID.Error(),
+		}, http.StatusBadRequest)
+		return
+	}
+	idParam, err := strconv.Atoi(id)
+	if err != nil {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to parse id parameter",
+		}, http.StatusBadRequest)
+		return
+	}
+	snapshot, err := strconv.ParseInt(r.URL.Query().Get("snapshot"), 10, 64)
+	if err != nil {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to parse snapshot parameter",
+		}, http.StatusBadRequest)
+		return
+	}
+
+	version, err := h.ifMatchVersion(r, int64(idParam))
+	if err != nil {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to parse If-Match header",
+		}, xerrors.ParseErrorTypeToCodeInt(err))
+		return
+	}
+
+	data, err := h.logic.RevertBook(ctx, int64(idParam), version, snapshot)
+	if err != nil {
+		h.deps.Logger.ErrorContext(ctx, "failed to revert book data", slog.Any("error", err))
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to revert book data",
+		}, xerrors.ParseErrorTypeToCodeInt(err))
+		return
+	}
+
+	w.Header().Set("ETag", xhttp.ETag(data.Version, data))
+
+	xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+		Data:    data,
+		Message: "book data reverted",
+	}, http.StatusOK)
+}
+
+// maxImportBodySize caps the size of a bulk import upload.
+const maxImportBodySize = 32 << 20
+
+// importFormats maps upload media types to import formats.
+var importFormats = map[string]string{
+	"text/csv":             model.ImportFormatCSV,
+	"application/csv":      model.ImportFormatCSV,
+	"application/x-ndjson": model.ImportFormatNDJSON,
+	"application/ndjson":   model.ImportFormatNDJSON,
+	"application/jsonl":    model.ImportFormatNDJSON,
+}
+
+// importUpload finds the import upload and its format, either in the raw
+// request body or in the "file" field of a multipart form.
+func importUpload(r *http.Request) (io.Reader, string, error) {
+	contentType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid content type: %v", err)
+	}
+
+	if contentType != "multipart/form-data" {
+		format, ok := importFormats[contentType]
+		if !ok {
+			return nil, "", fmt.Errorf("unsupported upload content type: %s", contentType)
+		}
+		return r.Body, format, nil
+	}
+
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, "", err
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, "", fmt.Errorf("multipart form has no file field")
+			}
+			return nil, "", err
+		}
+		if part.FormName() != "file" {
+			continue
+		}
+
+		partType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		if format, ok := importFormats[partType]; ok {
+			return part, format, nil
+		}
+
+		// browsers often send spreadsheets as application/octet-stream
+		switch strings.ToLower(filepath.Ext(part.FileName())) {
+		case ".csv":
+			return part, model.ImportFormatCSV, nil
+		case ".ndjson", ".jsonl":
+			return part, model.ImportFormatNDJSON, nil
+		default:
+			return nil, "", fmt.Errorf("unsupported upload file: %s", part.FileName())
+		}
+	}
+}
+
+// ifMatchVersion reads the book version a write is conditioned on from the
+// If-Match header. A single tag is checked by the write itself, "*" and lists
+// of tags are resolved against the current version first, and the write then
+// still fails if the book changes in between.
+func (h *BookHandler) ifMatchVersion(r *http.Request, id int64) (int64, error) {
+	header := r.Header.Get("If-Match")
+	if header == "" {
+		return 0, xerrors.PreconditionRequiredError{Err: xerrors.ErrMissingIfMatch}
+	}
+
+	versions, wildcard, err := xhttp.ParseIfMatch(header)
+	if err != nil {
+		// tags we never issued can't match the current version
+		return 0, xerrors.PreconditionFailedError{Err: xerrors.ErrVersionMismatch}
+	}
+	if !wildcard && len(versions) == 1 {
+		return versions[0], nil
+	}
+
+	current, err := h.logic.GetBookByID(r.Context(), id)
+	if err != nil {
+		return 0, err
+	}
+	if !wildcard && !slices.Contains(versions, current.Version) {
+		return 0, xerrors.PreconditionFailedError{Err: xerrors.ErrVersionMismatch}
+	}
+
+	return current.Version, nil
+}
+
+// BatchBooks godoc
+// @Summary Apply many create, update, delete and restore operations in a single transaction
+// @Description Every operation is validated before anything is written and the first failing operation rolls the whole batch back. Update and delete need the book version, like If-Match on the single book endpoints. Restore operations are admin only. A create of a book that probably is already stored fails with the matches, unless force is set.
+// @Tags books
+// @Accept json
+// @Produce json
+// @Param force query boolean false "create the books even when they probably are already stored"
+// @Param data body model.BookBatchRequest true "batch operations, applied in order"
+// @Success 200 {object} xhttp.BaseResponse{data=[]model.BookBatchResult}
+// @Failure 400 {object} xhttp.BaseResponse{data=[]model.BookBatchResult} "invalid operation, nothing applied"
+// @Failure 401 {object} xhttp.BaseResponse "restore operation sent without the admin token"
+// @Failure 409 {object} xhttp.BaseResponse{data=[]model.BookBatchResult} "create of a book that probably is already stored, nothing applied"
+// @Failure 412 {object} xhttp.BaseResponse{data=[]model.BookBatchResult} "stale version, batch rolled back"
+// @Router /books/batch [post]
+func (h *BookHandler) BatchBooks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var force bool
+	if val := r.URL.Query().Get("force"); val != "" {
+		var err error
+		force, err = strconv.ParseBool(val)
+		if err != nil {
+			xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+				Error:   err.Error(),
+				Message: "failed to parse force parameter",
+			}, http.StatusBadRequest)
+			return
+		}
+	}
+
+	// parse request body
+	var payload model.BookBatchRequest
+	err := xhttp.BindJSONRequest(r, &payload)
+	if err != nil {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to parse request body",
+		}, http.StatusBadRequest)
+		return
+	}
+
+	if !xauth.IsAdmin(r, h.deps.AdminToken) && slices.ContainsFunc(payload.Operations, func(op model.BookBatchOperation) bool {
+		return op.Op == model.BatchOpRestore
+	}) {
+		err := xerrors.AuthError{Err: xauth.ErrAdminOnly}
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "restore operations are only available to admin",
+		}, xerrors.ParseErrorTypeToCodeInt(err))
+		return
+	}
+
+	data, err := h.logic.BatchBooks(ctx, payload.Operations, force)
+	if err != nil {
+		h.deps.Logger.ErrorContext(ctx, "failed to apply book batch", slog.Any("error", err))
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Data:    data,
+			Error:   err.Error(),
+			Message: "book batch rolled back",
+		}, xerrors.ParseErrorTypeToCodeInt(err))
+		return
+	}
+
+	xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+		Data:    data,
+		Message: "book batch applied",
+	}, http.StatusOK)
+}
+
+// bookListQueryParams whitelists the query params accepted by the books listing.
+var bookListQueryParams = map[string]bool{
+	"search":            true,
+	"mode":              true,
+	"author":            true,
+	"genre":             true,
+	"tag":               true,
+	"identifier":        true,
+	"edition_format":    true,
+	"edition_language":  true,
+	"include_editions":  true,
+	"publish_year_from": true,
+	"publish_year_to":   true,
+	"published_from":    true,
+	"published_to":      true,
+	"created_after":     true,
+	"ids":               true,
+	"include_deleted":   true,
+	"as_of":             true,
+	"sort":              true,
+	"after":             true,
+	"before":            true,
+	"limit":             true,
+}
+
+func parseBookSearchParams(query url.Values) (model.BookSearchParams, error) {
+	var params model.BookSearchParams
+
+	for key := range query {
+		if !bookListQueryParams[key] {
+			return params, xerrors.NewClientError(fmt.Errorf("unknown query param: %s", key))
+		}
+	}
+
+	params.Search = query.Get("search")
+	params.Author = query.Get("author")
+	// tags are stored lower case with single spaces
+	params.Tag = strings.ToLower(strings.Join(strings.Fields(query.Get("tag")), " "))
+
+	switch mode := query.Get("mode"); mode {
+	case "", model.SearchModeSimple:
+		params.SearchMode = model.SearchModeSimple
+	case model.SearchModeFullText:
+		params.SearchMode = model.SearchModeFullText
+	default:
+		return params, xerrors.NewClientError(fmt.Errorf("unknown search mode: %s", mode))
+	}
+
+	if val := query.Get("genre"); val != "" {
+		genreID, err := strconv.ParseInt(val, 10, 64)
+		if err != nil || genreID <= 0 {
+			return params, xerrors.NewClientError(fmt.Errorf("invalid genre params: %s", val))
+		}
+		params.GenreID = genreID
+	}
+
+	if val := query.Get("identifier"); val != "" {
+		scheme, value, err := identifier.Parse(val)
+		if err != nil {
+			return params, xerrors.NewClientError(err)
+		}
+		params.Identifier = model.BookIdentifier{Scheme: scheme, Value: value}
+	}
+
+	if val := query.Get("edition_format"); val != "" {
+		if !slices.Contains(model.EditionFormats, val) {
+			return params, xerrors.NewClientError(fmt.Errorf("unknown edition format: %s", val))
+		}
+		params.EditionFormat = val
+	}
+
+	if val := query.Get("edition_language"); val != "" {
+		language, err := langtag.Normalize(val)
+		if err != nil {
+			return params, xerrors.NewClientError(err)
+		}
+		params.EditionLanguage = language
+	}
+
+	if val := query.Get("include_editions"); val != "" {
+		includeEditions, err := strconv.ParseBool(val)
+		if err != nil {
+			return params, xerrors.NewClientError(fmt.Errorf("failed to parse include_editions params: %v", err))
+		}
+		params.IncludeEditions = includeEditions
+	}
+
+	from, err := parsePublishedParam(query, "publish_year_from", "published_from")
+	if err != nil {
+		return params, err
+	}
+	params.PublishedFrom = from
+
+	to, err := parsePublishedParam(query, "publish_year_to", "published_to")
+	if err != nil {
+		return params, err
+	}
+	params.PublishedTo = to
+
+	if val := query.Get("created_after"); val != "" {
+		createdAfter, err := time.Parse(time.RFC3339, val)
+		if err != nil {
+			return params, xerrors.NewClientError(fmt.Errorf("failed to parse created_after params: %v", err))
+		}
+		params.CreatedAfter = &createdAfter
+	}
+
+	if val := query.Get("ids"); val != "" {
+		for _, rawID := range strings.Split(val, ",") {
+			id, err := strconv.ParseInt(strings.TrimSpace(rawID), 10, 64)
+			if err != nil || id <= 0 {
+				return params, xerrors.NewClientError(fmt.Errorf("invalid id in ids params: %s", rawID))
+			}
+			params.IDs = append(params.IDs, id)
+		}
+	}
+
+	if val := query.Get("include_deleted"); val != "" {
+		includeDeleted, err := strconv.ParseBool(val)
+		if err != nil {
+			return params, xerrors.NewClientError(fmt.Errorf("failed to parse include_deleted params: %v", err))
+		}
+		params.IncludeDeleted = includeDeleted
+	}
+
+	if val := query.Get("as_of"); val != "" {
+		asOf, err := time.Parse(time.RFC3339, val)
+		if err != nil {
+			return params, xerrors.NewClientError(fmt.Errorf("failed to parse as_of params: %v", err))
+		}
+		params.AsOf = &asOf
+	}
+
+	if val := query.Get("sort"); val != "" {
+		seen := map[string]bool{}
+		for _, term := range strings.Split(val, ",") {
+			field, direction, _ := strings.Cut(strings.TrimSpace(term), ":")
+			if _, ok := sortableBookFields[field]; !ok {
+				return params, xerrors.NewClientError(fmt.Errorf("unknown sort field: %s", field))
+			}
+			if seen[field] {
+				return params, xerrors.NewClientError(fmt.Errorf("duplicate sort field: %s", field))
+			}
+			seen[field] = true
+
+			switch strings.ToLower(direction) {
+			case "", "asc":
+				params.Sort = append(params.Sort, model.SortParam{Field: field})
+			case "desc":
+				params.Sort = append(params.Sort, model.SortParam{Field: field, Desc: true})
+			default:
+				return params, xerrors.NewClientError(fmt.Errorf("invalid sort direction: %s", direction))
+			}
+		}
+	}
+
+	return params, nil
+}
+
+// parsePublishedParam reads a publication date bound, given either as an EDTF
+// date or as a year, which is the date known to the year.
+func parsePublishedParam(query url.Values, yearParam string, dateParam string) (*edtf.Date, error) {
+	year, date := query.Get(yearParam), query.Get(dateParam)
+
+	switch {
+	case year != "" && date != "":
+		return nil, xerrors.NewClientError(fmt.Errorf("%s and %s can not be used together", yearParam, dateParam))
+	case year != "":
+		y, err := strconv.ParseInt(year, 10, 64)
+		if err != nil {
+			return nil, xerrors.NewClientError(fmt.Errorf("failed to parse %s params: %v", yearParam, err))
+		}
+		return &edtf.Date{Year: y}, nil
+	case date != "":
+		d, err := edtf.Parse(date)
+		if err != nil {
+			return nil, xerrors.NewClientError(fmt.Errorf("failed to parse %s params: %v", dateParam, err))
+		}
+		return &d, nil
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,184 @@
+package book
+
+import (
+	"byfood-app/internal/model"
+	"byfood-app/internal/pkg/xerrors"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"reflect"
+	"slices"
+)
+
+// bookkeepingFields change on every write, so they are left out of revision diffs.
+var bookkeepingFields = []string{"version", "created_at", "updated_at"}
+
+// GetBookHistory returns every revision of the book, oldest first, each with
+// the fields it changed.
+func (logic *BookLogic) GetBookHistory(ctx context.Context, bookID int64) ([]model.BookRevision, error) {
+	if bookID <= 0 {
+		return nil, xerrors.NewClientError(xerrors.ErrInvalidID)
+	}
+
+	result, err := logic.repo.GetBookRevisions(ctx, bookID)
+	if err != nil {
+		logic.deps.Logger.ErrorContext(ctx, "failed to get book revisions", slog.Any("error", err))
+		return nil, err
+	}
+
+	for i := range result {
+		result[i].Changes, err = diffBooks(result[i].Before, result[i].After)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+func (logic *BookLogic) GetBookRevision(ctx context.Context, bookID int64, revision int64) (model.BookRevision, error) {
+	if bookID <= 0 {
+		return model.BookRevision{}, xerrors.NewClientError(xerrors.ErrInvalidID)
+	}
+	if revision <= 0 {
+		return model.BookRevision{}, xerrors.NewClientError(errors.New("invalid revision"))
+	}
+
+	result, err := logic.repo.GetBookRevision(ctx, bookID, revision)
+	if err != nil {
+		logic.deps.Logger.ErrorContext(ctx, "failed to get book revision", slog.Any("error", err))
+		return model.BookRevision{}, err
+	}
+
+	result.Changes, err = diffBooks(result.Before, result.After)
+	if err != nil {
+		return model.BookRevision{}, err
+	}
+
+	return result, nil
+}
+
+// GetBookSnapshots returns the book as it was after each of its revisions,
+// oldest first. Any of them can be the target of RevertBook.
+func (logic *BookLogic) GetBookSnapshots(ctx context.Context, bookID int64) ([]model.BookSnapshot, error) {
+	if bookID <= 0 {
+		return nil, xerrors.NewClientError(xerrors.ErrInvalidID)
+	}
+
+	revisions, err := logic.repo.GetBookRevisions(ctx, bookID)
+	if err != nil {
+		logic.deps.Logger.ErrorContext(ctx, "failed to get book revisions", slog.Any("error", err))
+		return nil, err
+	}
+
+	result := make([]model.BookSnapshot, 0, len(revisions))
+	for _, revision := range revisions {
+		result = append(result, model.BookSnapshot{
+			Snapshot:  revision.Revision,
+			Operation: revision.Operation,
+			Actor:     revision.Actor,
+			Book:      revision.After,
+			CreatedAt: revision.CreatedAt,
+		})
+	}
+
+	return result, nil
+}
+
+// RevertBook sets title, authors, publication and ISBN back to the snapshot,
+// validated the same way as a full update. The soft delete state is left
+// as it is, a deleted book has to be restored first.
+func (logic *BookLogic) RevertBook(ctx context.Context, id int64, version int64, snapshot int64) (model.Book, error) {
+	switch {
+	case id <= 0:
+		return model.Book{}, xerrors.NewClientError(xerrors.ErrInvalidID)
+	case version <= 0:
+		return model.Book{}, xerrors.PreconditionRequiredError{Err: xerrors.ErrMissingIfMatch}
+	case snapshot <= 0:
+		return model.Book{}, xerrors.NewClientError(errors.New("invalid snapshot"))
+	}
+
+	result, err := logic.repo.RevertBook(ctx, id, version, snapshot, func(current model.Book, target model.Book) (model.Book, error) {
+		current.Title = target.Title
+		current.Author = target.Author
+		current.Authors = target.Authors
+		current.PublishYear = target.PublishYear
+		current.Published = target.Published
+		current.ISBN = target.ISBN
+
+		// snapshots recorded before author lists only have the author string
+		current = normalizeBook(current)
+		err := validateBook(current)
+		if err != nil {
+			return model.Book{}, err
+		}
+		return current, logic.validateAuthorLifetimes(ctx, current)
+	})
+	if err != nil {
+		logic.deps.Logger.ErrorContext(ctx, "failed to revert book data", slog.Any("error", err))
+		return model.Book{}, err
+	}
+
+	return result, nil
+}
+
+// diffBooks lists the fields that differ between two snapshots by their json
+// name, in name order. A nil before means every field was set by the change.
+func diffBooks(before *model.Book, after *model.Book) ([]model.BookFieldChange, error) {
+	beforeFields, err := bookFields(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := bookFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for name := range beforeFields {
+		names = append(names, name)
+	}
+	for name := range afterFields {
+		if _, ok := beforeFields[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	changes := []model.BookFieldChange{}
+	for _, name := range names {
+		if slices.Contains(bookkeepingFields, name) {
+			continue
+		}
+
+		if !reflect.DeepEqual(beforeFields[name], afterFields[name]) {
+			changes = append(changes, model.BookFieldChange{
+				Field:  name,
+				Before: beforeFields[name],
+				After:  afterFields[name],
+			})
+		}
+	}
+
+	return changes, nil
+}
+
+func bookFields(book *model.Book) (map[string]any, error) {
+	fields := map[string]any{}
+	if book == nil {
+		return fields, nil
+	}
+
+	data, err := json.Marshal(book)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(data, &fields)
+	if err != nil {
+		return nil, err
+	}
+
+	return fields, nil
+}
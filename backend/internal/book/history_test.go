@@ -0,0 +1,296 @@
+package book
+
+import (
+	"byfood-app/internal/core"
+	"byfood-app/internal/model"
+	"byfood-app/internal/pkg/edtf"
+	"byfood-app/internal/pkg/xerrors"
+	"context"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestBookLogic_GetBookHistory(t *testing.T) {
+	type fields struct {
+		deps *core.Dependency
+		repo RepositoryInterface
+	}
+	type args struct {
+		ctx    context.Context
+		bookID int64
+	}
+
+	ts := setupTestSuite(t)
+	mockFields := fields{
+		deps: &core.Dependency{
+			Logger: slog.Default(),
+		},
+		repo: ts.MockBookRepo,
+	}
+
+	created := time.Date(2025, 8, 9, 15, 57, 49, 0, time.UTC)
+	updated := created.Add(time.Hour)
+	deleted := updated.Add(time.Hour)
+
+	v1 := &model.Book{ID: 1, Title: "1984", Author: "Orwel", PublishYear: 1949, Version: 1, BaseAudit: model.BaseAudit{CreatedAt: &created, UpdatedAt: &created}}
+	v2 := &model.Book{ID: 1, Title: "1984", Author: "George Orwell", PublishYear: 1949, Version: 2, BaseAudit: model.BaseAudit{CreatedAt: &created, UpdatedAt: &updated}}
+	v3 := &model.Book{ID: 1, Title: "1984", Author: "George Orwell", PublishYear: 1949, Version: 3, BaseAudit: model.BaseAudit{CreatedAt: &created, UpdatedAt: &updated, DeletedAt: &deleted}}
+
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		want     []model.BookRevision
+		wantCode int
+		mockFunc func()
+	}{
+		{
+			name:   "success get history with diffs",
+			fields: mockFields,
+			args: args{
+				ctx:    context.Background(),
+				bookID: 1,
+			},
+			want: []model.BookRevision{
+				{
+					BookID: 1, Revision: 1, Operation: model.RevisionOpCreate, After: v1,
+					Changes: []model.BookFieldChange{
+						{Field: "author", After: "Orwel"},
+						{Field: "id", After: float64(1)},
+						{Field: "publish_year", After: float64(1949)},
+						{Field: "title", After: "1984"},
+					},
+				},
+				{
+					BookID: 1, Revision: 2, Operation: model.RevisionOpUpdate, Actor: "admin", RequestID: "host/abc-000001", Before: v1, After: v2,
+					Changes: []model.BookFieldChange{
+						{Field: "author", Before: "Orwel", After: "George Orwell"},
+					},
+				},
+				{
+					BookID: 1, Revision: 3, Operation: model.RevisionOpDelete, Actor: "anonymous@203.0.113.7", Before: v2, After: v3,
+					Changes: []model.BookFieldChange{
+						{Field: "deleted_at", After: deleted.Format(time.RFC3339)},
+					},
+				},
+			},
+			wantCode: http.StatusOK,
+			mockFunc: func() {
+				ts.MockBookRepo.EXPECT().GetBookRevisions(gomock.Any(), int64(1)).Return([]model.BookRevision{
+					{BookID: 1, Revision: 1, Operation: model.RevisionOpCreate, After: v1},
+					{BookID: 1, Revision: 2, Operation: model.RevisionOpUpdate, Actor: "admin", RequestID: "host/abc-000001", Before: v1, After: v2},
+					{BookID: 1, Revision: 3, Operation: model.RevisionOpDelete, Actor: "anonymous@203.0.113.7", Before: v2, After: v3},
+				}, nil)
+			},
+		},
+		{
+			name:   "failed unknown book",
+			fields: mockFields,
+			args: args{
+				ctx:    context.Background(),
+				bookID: 99,
+			},
+			wantCode: http.StatusBadRequest,
+			mockFunc: func() {
+				ts.MockBookRepo.EXPECT().GetBookRevisions(gomock.Any(), int64(99)).Return(nil, xerrors.NewClientError(xerrors.ErrDataNotFound))
+			},
+		},
+		{
+			name:   "failed invalid id",
+			fields: mockFields,
+			args: args{
+				ctx:    context.Background(),
+				bookID: 0,
+			},
+			wantCode: http.StatusBadRequest,
+			mockFunc: func() {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logic := &BookLogic{
+				deps: tt.fields.deps,
+				repo: tt.fields.repo,
+			}
+
+			tt.mockFunc()
+
+			got, err := logic.GetBookHistory(tt.args.ctx, tt.args.bookID)
+			if err != nil && xerrors.ParseErrorTypeToCodeInt(err) != tt.wantCode {
+				t.Errorf("BookLogic.GetBookHistory() error = %v, wantCode %v", err, tt.wantCode)
+				return
+			}
+			if (err != nil) != (tt.wantCode != http.StatusOK) {
+				t.Errorf("BookLogic.GetBookHistory() error = %v, wantCode %v", err, tt.wantCode)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BookLogic.GetBookHistory() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBookLogic_GetBookRevision(t *testing.T) {
+	ts := setupTestSuite(t)
+	logic := &BookLogic{
+		deps: &core.Dependency{
+			Logger: slog.Default(),
+		},
+		repo: ts.MockBookRepo,
+	}
+
+	before := &model.Book{ID: 1, Title: "1984", Author: "George Orwell", PublishYear: 1948, Version: 2}
+	after := &model.Book{ID: 1, Title: "Nineteen Eighty-Four", Author: "George Orwell", PublishYear: 1949, Version: 3}
+
+	ts.MockBookRepo.EXPECT().GetBookRevision(gomock.Any(), int64(1), int64(3)).Return(model.BookRevision{
+		BookID: 1, Revision: 3, Operation: model.RevisionOpUpdate, Before: before, After: after,
+	}, nil)
+
+	got, err := logic.GetBookRevision(context.Background(), 1, 3)
+	if err != nil {
+		t.Fatalf("BookLogic.GetBookRevision() error = %v", err)
+	}
+
+	want := []model.BookFieldChange{
+		{Field: "publish_year", Before: float64(1948), After: float64(1949)},
+		{Field: "title", Before: "1984", After: "Nineteen Eighty-Four"},
+	}
+	if !reflect.DeepEqual(got.Changes, want) {
+		t.Errorf("BookLogic.GetBookRevision() changes = %+v, want %+v", got.Changes, want)
+	}
+
+	_, err = logic.GetBookRevision(context.Background(), 1, 0)
+	if xerrors.ParseErrorTypeToCodeInt(err) != http.StatusBadRequest {
+		t.Errorf("BookLogic.GetBookRevision() error = %v, want client error for revision 0", err)
+	}
+}
+
+func TestBookLogic_RevertBook(t *testing.T) {
+	type args struct {
+		ctx      context.Context
+		id       int64
+		version  int64
+		snapshot int64
+	}
+
+	ts := setupTestSuite(t)
+	logic := &BookLogic{
+		deps: &core.Dependency{
+			Logger: slog.Default(),
+		},
+		repo: ts.MockBookRepo,
+	}
+
+	current := model.Book{ID: 1, Title: "One Piece (bad edit)", Author: "Oda", PublishYear: 1998, Version: 3}
+
+	tests := []struct {
+		name                 string
+		args                 args
+		checkAuthorLifetimes bool
+		want                 model.Book
+		wantCode             int
+		mockFunc             func()
+	}{
+		{
+			name: "success revert takes the snapshot fields",
+			args: args{
+				ctx:      context.Background(),
+				id:       1,
+				version:  3,
+				snapshot: 1,
+			},
+			want:     model.Book{ID: 1, Title: "One Piece", Author: "Eiichiro Oda", Authors: []model.BookAuthor{{Name: "Eiichiro Oda", Role: model.AuthorRoleAuthor}}, PublishYear: 1997, Published: &edtf.Date{Year: 1997}, Version: 3},
+			wantCode: http.StatusOK,
+			mockFunc: func() {
+				ts.MockBookRepo.EXPECT().RevertBook(gomock.Any(), int64(1), int64(3), int64(1), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, id int64, version int64, snapshot int64, apply func(model.Book, model.Book) (model.Book, error)) (model.Book, error) {
+						return apply(current, model.Book{ID: 1, Title: "One Piece", Author: "Eiichiro Oda", PublishYear: 1997, Version: 1})
+					})
+			},
+		},
+		{
+			name: "failed snapshot does not pass validation",
+			args: args{
+				ctx:      context.Background(),
+				id:       1,
+				version:  3,
+				snapshot: 1,
+			},
+			wantCode: http.StatusBadRequest,
+			mockFunc: func() {
+				ts.MockBookRepo.EXPECT().RevertBook(gomock.Any(), int64(1), int64(3), int64(1), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, id int64, version int64, snapshot int64, apply func(model.Book, model.Book) (model.Book, error)) (model.Book, error) {
+						_, err := apply(current, model.Book{ID: 1, Title: "One Piece", PublishYear: 1997, Version: 1})
+						return model.Book{}, err
+					})
+			},
+		},
+		{
+			name: "failed snapshot published before the author was born",
+			args: args{
+				ctx:      context.Background(),
+				id:       1,
+				version:  3,
+				snapshot: 1,
+			},
+			checkAuthorLifetimes: true,
+			wantCode:             http.StatusBadRequest,
+			mockFunc: func() {
+				ts.MockBookRepo.EXPECT().RevertBook(gomock.Any(), int64(1), int64(3), int64(1), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, id int64, version int64, snapshot int64, apply func(model.Book, model.Book) (model.Book, error)) (model.Book, error) {
+						_, err := apply(current, model.Book{ID: 1, Title: "One Piece", Author: "Eiichiro Oda", PublishYear: 1897, Version: 1})
+						return model.Book{}, err
+					})
+				ts.MockBookRepo.EXPECT().GetAuthorsByName(gomock.Any(), []string{"Eiichiro Oda"}).Return(
+					[]model.Author{{ID: 5, Name: "Eiichiro Oda", BirthYear: year(1975)}},
+					nil,
+				)
+			},
+		},
+		{
+			name: "failed revert without version",
+			args: args{
+				ctx:      context.Background(),
+				id:       1,
+				snapshot: 1,
+			},
+			wantCode: http.StatusPreconditionRequired,
+			mockFunc: func() {},
+		},
+		{
+			name: "failed revert without snapshot",
+			args: args{
+				ctx:     context.Background(),
+				id:      1,
+				version: 3,
+			},
+			wantCode: http.StatusBadRequest,
+			mockFunc: func() {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logic.checkAuthorLifetimes = tt.checkAuthorLifetimes
+			tt.mockFunc()
+
+			got, err := logic.RevertBook(tt.args.ctx, tt.args.id, tt.args.version, tt.args.snapshot)
+			if err != nil && xerrors.ParseErrorTypeToCodeInt(err) != tt.wantCode {
+				t.Errorf("BookLogic.RevertBook() error = %v, wantCode %v", err, tt.wantCode)
+				return
+			}
+			if (err != nil) != (tt.wantCode != http.StatusOK) {
+				t.Errorf("BookLogic.RevertBook() error = %v, wantCode %v", err, tt.wantCode)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BookLogic.RevertBook() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
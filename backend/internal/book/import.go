@@ -0,0 +1,344 @@
+package book
+
+import (
+	"bufio"
+	"byfood-app/internal/model"
+	"byfood-app/internal/pkg/edtf"
+	"byfood-app/internal/pkg/xerrors"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	maxImportRows     = 10000
+	maxImportLineSize = 1 << 20
+)
+
+// importColumns are the CSV header names an import requires, and
+// optionalImportColumns the ones it understands besides.
+var (
+	importColumns         = []string{"title", "author", "publish_year"}
+	optionalImportColumns = []string{"published", "isbn"}
+)
+
+// bookImportRow is a single parsed upload line. Err holds why the line can't
+// be imported, while the reader itself keeps going with the next line.
+type bookImportRow struct {
+	Line int
+	Book model.Book
+	Err  error
+}
+
+// bookImportReader streams rows out of an upload, returning io.EOF once done.
+// Any other error means the upload itself is unreadable.
+type bookImportReader interface {
+	Next() (bookImportRow, error)
+}
+
+func newBookImportReader(format string, body io.Reader) (bookImportReader, error) {
+	switch format {
+	case model.ImportFormatCSV:
+		return newCSVBookReader(body)
+	case model.ImportFormatNDJSON:
+		return newNDJSONBookReader(body), nil
+	default:
+		return nil, fmt.Errorf("unsupported import format: %s", format)
+	}
+}
+
+type csvBookReader struct {
+	reader  *csv.Reader
+	columns map[string]int
+}
+
+func newCSVBookReader(body io.Reader) (*csvBookReader, error) {
+	reader := csv.NewReader(body)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("csv upload is empty")
+		}
+		return nil, fmt.Errorf("failed to read csv header: %v", err)
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		// spreadsheet exports like to start with a byte order mark
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+
+		if _, ok := columns[name]; ok {
+			return nil, fmt.Errorf("duplicate csv column: %s", name)
+		}
+		columns[name] = i
+	}
+
+	for name := range columns {
+		if !slices.Contains(importColumns, name) && !slices.Contains(optionalImportColumns, name) {
+			return nil, fmt.Errorf("unknown csv column: %s", name)
+		}
+	}
+	for _, name := range importColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("missing csv column: %s", name)
+		}
+	}
+
+	reader.FieldsPerRecord = len(header)
+
+	return &csvBookReader{
+		reader:  reader,
+		columns: columns,
+	}, nil
+}
+
+func (c *csvBookReader) Next() (bookImportRow, error) {
+	var row bookImportRow
+
+	record, err := c.reader.Read()
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) && errors.Is(err, csv.ErrFieldCount) {
+			row.Line = parseErr.StartLine
+			row.Err = fmt.Errorf("expected %d fields, got %d", len(c.columns), len(record))
+			return row, nil
+		}
+
+		return row, err
+	}
+
+	row.Line, _ = c.reader.FieldPos(0)
+	row.Book = model.Book{
+		Title:  strings.TrimSpace(record[c.columns["title"]]),
+		Author: strings.TrimSpace(record[c.columns["author"]]),
+	}
+	if i, ok := c.columns["isbn"]; ok {
+		row.Book.ISBN = strings.TrimSpace(record[i])
+	}
+
+	year := strings.TrimSpace(record[c.columns["publish_year"]])
+	if year != "" {
+		row.Book.PublishYear, err = strconv.ParseInt(year, 10, 64)
+		if err != nil {
+			row.Err = fmt.Errorf("invalid publish_year: %q", year)
+		}
+	}
+
+	if i, ok := c.columns["published"]; ok && strings.TrimSpace(record[i]) != "" {
+		published, err := edtf.Parse(record[i])
+		if err != nil {
+			row.Err = fmt.Errorf("invalid published: %q", strings.TrimSpace(record[i]))
+		} else {
+			row.Book.Published = &published
+		}
+	}
+
+	return row, nil
+}
+
+type ndjsonBookReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func newNDJSONBookReader(body io.Reader) *ndjsonBookReader {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxImportLineSize)
+
+	return &ndjsonBookReader{
+		scanner: scanner,
+	}
+}
+
+func (n *ndjsonBookReader) Next() (bookImportRow, error) {
+	for n.scanner.Scan() {
+		n.line++
+
+		line := bytes.TrimSpace(n.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		row := bookImportRow{Line: n.line}
+
+		var payload model.StoreBookRequest
+		decoder := json.NewDecoder(bytes.NewReader(line))
+		decoder.DisallowUnknownFields()
+		err := decoder.Decode(&payload)
+		if err != nil {
+			row.Err = fmt.Errorf("invalid json: %v", err)
+			return row, nil
+		}
+
+		row.Book = model.Book{
+			Title:       strings.TrimSpace(payload.Title),
+			Author:      strings.TrimSpace(payload.Author),
+			Authors:     payload.Authors,
+			PublishYear: payload.PublishYear,
+			Published:   payload.Published,
+			ISBN:        payload.ISBN,
+		}
+
+		return row, nil
+	}
+
+	if err := n.scanner.Err(); err != nil {
+		return bookImportRow{}, err
+	}
+
+	return bookImportRow{}, io.EOF
+}
+
+// ImportBooks validates every row of the upload the same way StoreBook does,
+// rejecting the rows that probably are already stored books unless
+// params.Force is set, and stores the accepted ones in a single transaction. In all or nothing
+// mode a single rejected row stores nothing, best effort stores what it can.
+func (logic *BookLogic) ImportBooks(ctx context.Context, params model.BookImportParams, body io.Reader) (model.BookImportReport, error) {
+	switch params.Mode {
+	case "":
+		params.Mode = model.ImportModeAllOrNothing
+	case model.ImportModeAllOrNothing, model.ImportModeBestEffort:
+	default:
+		return model.BookImportReport{}, xerrors.NewClientError(fmt.Errorf("unknown import mode: %s", params.Mode))
+	}
+
+	reader, err := newBookImportReader(params.Format, body)
+	if err != nil {
+		return model.BookImportReport{}, xerrors.NewClientError(err)
+	}
+
+	report := model.BookImportReport{
+		DryRun: params.DryRun,
+		Mode:   params.Mode,
+		Rows:   []model.BookImportRow{},
+	}
+
+	var (
+		books []model.Book
+		// index of each accepted book in report.Rows
+		rowIndex []int
+		// line of each accepted isbn, an upload can't hold one twice
+		isbnLines = map[string]int{}
+	)
+	for {
+		row, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return model.BookImportReport{}, xerrors.NewClientError(fmt.Errorf("failed to read upload: %v", err))
+		}
+
+		report.Total++
+		if report.Total > maxImportRows {
+			return model.BookImportReport{}, xerrors.NewClientError(fmt.Errorf("upload exceeds %d rows", maxImportRows))
+		}
+
+		if row.Err == nil {
+			row.Book = normalizeBook(row.Book)
+			row.Err = validateBook(row.Book)
+		}
+		if row.Err == nil {
+			row.Err = logic.validateAuthorLifetimes(ctx, row.Book)
+			if row.Err != nil && !errors.As(row.Err, &xerrors.ClientError{}) {
+				return model.BookImportReport{}, row.Err
+			}
+		}
+		if row.Err == nil && row.Book.ISBN != "" {
+			if line, ok := isbnLines[row.Book.ISBN]; ok {
+				row.Err = fmt.Errorf("isbn %s is already on line %d", row.Book.ISBN, line)
+			} else {
+				isbnLines[row.Book.ISBN] = row.Line
+			}
+		}
+		if row.Err != nil {
+			report.Rejected++
+			report.Rows = append(report.Rows, model.BookImportRow{
+				Line:   row.Line,
+				Status: model.ImportRowRejected,
+				Error:  row.Err.Error(),
+			})
+			continue
+		}
+
+		report.Accepted++
+		rowIndex = append(rowIndex, len(report.Rows))
+		report.Rows = append(report.Rows, model.BookImportRow{
+			Line:   row.Line,
+			Status: model.ImportRowAccepted,
+		})
+		books = append(books, row.Book)
+	}
+
+	var storedISBNs []string
+	if len(isbnLines) > 0 {
+		isbns := slices.Sorted(maps.Keys(isbnLines))
+		storedISBNs, err = logic.repo.GetStoredISBNs(ctx, isbns)
+		if err != nil {
+			logic.deps.Logger.ErrorContext(ctx, "failed to get stored isbns", slog.Any("error", err))
+			return model.BookImportReport{}, err
+		}
+	}
+
+	// an isbn already stored or a book that probably is already stored
+	// rejects its row like any other invalid row instead of failing the
+	// whole insert, and shows on a dry run too
+	var keptBooks []model.Book
+	var keptIndex []int
+	for i, book := range books {
+		row := &report.Rows[rowIndex[i]]
+		switch {
+		case book.ISBN != "" && slices.Contains(storedISBNs, book.ISBN):
+			row.Error = fmt.Sprintf("isbn %s is already on a stored book", book.ISBN)
+		case !params.Force:
+			err := logic.checkDuplicates(ctx, book)
+			var duplicate DuplicateBookError
+			if errors.As(err, &duplicate) {
+				row.Error = err.Error()
+				row.Matches = duplicate.Matches
+			} else if err != nil {
+				return model.BookImportReport{}, err
+			}
+		}
+		if row.Error != "" {
+			row.Status = model.ImportRowRejected
+			report.Accepted--
+			report.Rejected++
+			continue
+		}
+		keptBooks = append(keptBooks, book)
+		keptIndex = append(keptIndex, rowIndex[i])
+	}
+	books, rowIndex = keptBooks, keptIndex
+
+	if params.DryRun || len(books) == 0 || (params.Mode == model.ImportModeAllOrNothing && report.Rejected > 0) {
+		return report, nil
+	}
+
+	stored, err := logic.repo.StoreBooks(ctx, books)
+	if err != nil {
+		logic.deps.Logger.ErrorContext(ctx, "failed to import book data", slog.Any("error", err))
+		return model.BookImportReport{}, err
+	}
+
+	for i, book := range stored {
+		report.Rows[rowIndex[i]].ID = book.ID
+	}
+	report.Imported = len(stored)
+
+	return report, nil
+}
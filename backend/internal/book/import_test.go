@@ -0,0 +1,400 @@
+package book
+
+import (
+	"byfood-app/internal/core"
+	"byfood-app/internal/model"
+	"byfood-app/internal/pkg/edtf"
+	"context"
+	"errors"
+	"log/slog"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestBookLogic_ImportBooks(t *testing.T) {
+	type fields struct {
+		deps                 *core.Dependency
+		repo                 RepositoryInterface
+		checkAuthorLifetimes bool
+	}
+	type args struct {
+		ctx    context.Context
+		params model.BookImportParams
+		body   string
+	}
+
+	ts := setupTestSuite(t)
+	mockFields := fields{
+		deps: &core.Dependency{
+			Logger: slog.Default(),
+		},
+		repo: ts.MockBookRepo,
+	}
+	lifetimeFields := mockFields
+	lifetimeFields.checkAuthorLifetimes = true
+
+	csvUpload := "\ufeffTitle,Author,Publish_Year\n" +
+		"One Piece,Eiichiro Oda,1997\n" +
+		"Naruto,,1999\n" +
+		"Bleach,Tite Kubo,2001\n"
+
+	validBooks := []model.Book{
+		{Title: "One Piece", Author: "Eiichiro Oda", Authors: []model.BookAuthor{{Name: "Eiichiro Oda", Role: model.AuthorRoleAuthor}}, PublishYear: 1997, Published: &edtf.Date{Year: 1997}},
+		{Title: "Bleach", Author: "Tite Kubo", Authors: []model.BookAuthor{{Name: "Tite Kubo", Role: model.AuthorRoleAuthor}}, PublishYear: 2001, Published: &edtf.Date{Year: 2001}},
+	}
+
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		want     model.BookImportReport
+		wantErr  bool
+		mockFunc func()
+	}{
+		{
+			name:   "success best effort csv import stores accepted rows",
+			fields: mockFields,
+			args: args{
+				ctx:    context.Background(),
+				params: model.BookImportParams{Format: model.ImportFormatCSV, Mode: model.ImportModeBestEffort},
+				body:   csvUpload,
+			},
+			want: model.BookImportReport{
+				Mode:     model.ImportModeBestEffort,
+				Total:    3,
+				Accepted: 2,
+				Rejected: 1,
+				Imported: 2,
+				Rows: []model.BookImportRow{
+					{Line: 2, Status: model.ImportRowAccepted, ID: 11},
+					{Line: 3, Status: model.ImportRowRejected, Error: "author field is empty"},
+					{Line: 4, Status: model.ImportRowAccepted, ID: 12},
+				},
+			},
+			mockFunc: func() {
+				ts.MockBookRepo.EXPECT().GetDuplicateCandidates(gomock.Any(), gomock.Any()).Return(nil, nil).Times(2)
+				ts.MockBookRepo.EXPECT().StoreBooks(gomock.Any(), validBooks).Return([]model.Book{{ID: 11}, {ID: 12}}, nil)
+			},
+		},
+		{
+			name:   "success all or nothing csv import stores nothing on rejected row",
+			fields: mockFields,
+			args: args{
+				ctx:    context.Background(),
+				params: model.BookImportParams{Format: model.ImportFormatCSV},
+				body:   csvUpload,
+			},
+			want: model.BookImportReport{
+				Mode:     model.ImportModeAllOrNothing,
+				Total:    3,
+				Accepted: 2,
+				Rejected: 1,
+				Rows: []model.BookImportRow{
+					{Line: 2, Status: model.ImportRowAccepted},
+					{Line: 3, Status: model.ImportRowRejected, Error: "author field is empty"},
+					{Line: 4, Status: model.ImportRowAccepted},
+				},
+			},
+			mockFunc: func() {
+				ts.MockBookRepo.EXPECT().GetDuplicateCandidates(gomock.Any(), gomock.Any()).Return(nil, nil).Times(2)
+			},
+		},
+		{
+			name:   "success dry run ndjson import",
+			fields: mockFields,
+			args: args{
+				ctx:    context.Background(),
+				params: model.BookImportParams{Format: model.ImportFormatNDJSON, DryRun: true},
+				body: `{"title":"One Piece","author":"Eiichiro Oda","publish_year":1997}` + "\n\n" +
+					`{"title":"Naruto","author":"Masashi Kishimoto","publish_year":"1999"}` + "\n" +
+					`{"title":"Bleach","author":"Tite Kubo","publish_year":2001,"pages":200}` + "\n",
+			},
+			want: model.BookImportReport{
+				DryRun:   true,
+				Mode:     model.ImportModeAllOrNothing,
+				Total:    3,
+				Accepted: 1,
+				Rejected: 2,
+				Rows: []model.BookImportRow{
+					{Line: 1, Status: model.ImportRowAccepted},
+					{Line: 3, Status: model.ImportRowRejected, Error: "invalid json: json: cannot unmarshal string into Go struct field StoreBookRequest.publish_year of type int64"},
+					{Line: 4, Status: model.ImportRowRejected, Error: `invalid json: json: unknown field "pages"`},
+				},
+			},
+			mockFunc: func() {
+				ts.MockBookRepo.EXPECT().GetDuplicateCandidates(gomock.Any(), gomock.Any()).Return(nil, nil)
+			},
+		},
+		{
+			name:   "success dry run rejects a row published after the author died",
+			fields: lifetimeFields,
+			args: args{
+				ctx:    context.Background(),
+				params: model.BookImportParams{Format: model.ImportFormatNDJSON, DryRun: true},
+				body: `{"title":"Emma","author":"Jane Austen","publish_year":1815}` + "\n" +
+					`{"title":"Sanditon","author":"Jane Austen","publish_year":1925}` + "\n",
+			},
+			want: model.BookImportReport{
+				DryRun:   true,
+				Mode:     model.ImportModeAllOrNothing,
+				Total:    2,
+				Accepted: 1,
+				Rejected: 1,
+				Rows: []model.BookImportRow{
+					{Line: 1, Status: model.ImportRowAccepted},
+					{Line: 2, Status: model.ImportRowRejected, Error: "publish year 1925 is more than 100 years after Jane Austen died in 1817"},
+				},
+			},
+			mockFunc: func() {
+				ts.MockBookRepo.EXPECT().GetAuthorsByName(gomock.Any(), []string{"Jane Austen"}).Return(
+					[]model.Author{{ID: 3, Name: "Jane Austen", BirthYear: year(1775), DeathYear: year(1817)}},
+					nil,
+				).Times(2)
+				ts.MockBookRepo.EXPECT().GetDuplicateCandidates(gomock.Any(), gomock.Any()).Return(nil, nil)
+			},
+		},
+		{
+			name:   "success csv isbn column is normalized and checked",
+			fields: mockFields,
+			args: args{
+				ctx:    context.Background(),
+				params: model.BookImportParams{Format: model.ImportFormatCSV, Mode: model.ImportModeBestEffort},
+				body: "title,author,publish_year,isbn\n" +
+					"One Piece,Eiichiro Oda,1997,1-56931-901-4\n" +
+					"One Piece,Eiichiro Oda,2003,978-1-56931-901-7\n" +
+					"Bleach,Tite Kubo,2001,1421500001\n" +
+					"Naruto,Masashi Kishimoto,1999,\n",
+			},
+			want: model.BookImportReport{
+				Mode:     model.ImportModeBestEffort,
+				Total:    4,
+				Accepted: 2,
+				Rejected: 2,
+				Imported: 2,
+				Rows: []model.BookImportRow{
+					{Line: 2, Status: model.ImportRowAccepted, ID: 11},
+					{Line: 3, Status: model.ImportRowRejected, Error: "isbn 9781569319017 is already on line 2"},
+					{Line: 4, Status: model.ImportRowRejected, Error: "isbn check digit is invalid: 1421500001"},
+					{Line: 5, Status: model.ImportRowAccepted, ID: 12},
+				},
+			},
+			mockFunc: func() {
+				ts.MockBookRepo.EXPECT().GetStoredISBNs(gomock.Any(), []string{"9781569319017"}).Return(nil, nil)
+				ts.MockBookRepo.EXPECT().GetDuplicateCandidates(gomock.Any(), gomock.Any()).Return(nil, nil).Times(2)
+				ts.MockBookRepo.EXPECT().StoreBooks(gomock.Any(), []model.Book{
+					{Title: "One Piece", Author: "Eiichiro Oda", Authors: []model.BookAuthor{{Name: "Eiichiro Oda", Role: model.AuthorRoleAuthor}}, PublishYear: 1997, Published: &edtf.Date{Year: 1997}, ISBN: "9781569319017"},
+					{Title: "Naruto", Author: "Masashi Kishimoto", Authors: []model.BookAuthor{{Name: "Masashi Kishimoto", Role: model.AuthorRoleAuthor}}, PublishYear: 1999, Published: &edtf.Date{Year: 1999}},
+				}).Return([]model.Book{{ID: 11}, {ID: 12}}, nil)
+			},
+		},
+		{
+			name:   "success best effort csv import rejects an isbn already stored",
+			fields: mockFields,
+			args: args{
+				ctx:    context.Background(),
+				params: model.BookImportParams{Format: model.ImportFormatCSV, Mode: model.ImportModeBestEffort},
+				body: "title,author,publish_year,isbn\n" +
+					"One Piece,Eiichiro Oda,1997,1-56931-901-4\n" +
+					"Bleach,Tite Kubo,2001,978-1-4215-0063-8\n",
+			},
+			want: model.BookImportReport{
+				Mode:     model.ImportModeBestEffort,
+				Total:    2,
+				Accepted: 1,
+				Rejected: 1,
+				Imported: 1,
+				Rows: []model.BookImportRow{
+					{Line: 2, Status: model.ImportRowRejected, Error: "isbn 9781569319017 is already on a stored book"},
+					{Line: 3, Status: model.ImportRowAccepted, ID: 12},
+				},
+			},
+			mockFunc: func() {
+				ts.MockBookRepo.EXPECT().GetStoredISBNs(gomock.Any(), []string{"9781421500638", "9781569319017"}).Return([]string{"9781569319017"}, nil)
+				ts.MockBookRepo.EXPECT().GetDuplicateCandidates(gomock.Any(), gomock.Any()).Return(nil, nil)
+				ts.MockBookRepo.EXPECT().StoreBooks(gomock.Any(), []model.Book{
+					{Title: "Bleach", Author: "Tite Kubo", Authors: []model.BookAuthor{{Name: "Tite Kubo", Role: model.AuthorRoleAuthor}}, PublishYear: 2001, Published: &edtf.Date{Year: 2001}, ISBN: "9781421500638"},
+				}).Return([]model.Book{{ID: 12}}, nil)
+			},
+		},
+		{
+			name:   "success dry run rejects an isbn already stored",
+			fields: mockFields,
+			args: args{
+				ctx:    context.Background(),
+				params: model.BookImportParams{Format: model.ImportFormatNDJSON, DryRun: true},
+				body:   `{"title":"One Piece","author":"Eiichiro Oda","publish_year":1997,"isbn":"9781569319017"}` + "\n",
+			},
+			want: model.BookImportReport{
+				DryRun:   true,
+				Mode:     model.ImportModeAllOrNothing,
+				Total:    1,
+				Rejected: 1,
+				Rows: []model.BookImportRow{
+					{Line: 1, Status: model.ImportRowRejected, Error: "isbn 9781569319017 is already on a stored book"},
+				},
+			},
+			mockFunc: func() {
+				ts.MockBookRepo.EXPECT().GetStoredISBNs(gomock.Any(), []string{"9781569319017"}).Return([]string{"9781569319017"}, nil)
+			},
+		},
+		{
+			name:   "success best effort csv import rejects a book probably already stored",
+			fields: mockFields,
+			args: args{
+				ctx:    context.Background(),
+				params: model.BookImportParams{Format: model.ImportFormatCSV, Mode: model.ImportModeBestEffort},
+				body:   "title,author,publish_year\nOne Piece,Eiichiro Oda,1997\nBleach,Tite Kubo,2001\n",
+			},
+			want: model.BookImportReport{
+				Mode:     model.ImportModeBestEffort,
+				Total:    2,
+				Accepted: 1,
+				Rejected: 1,
+				Imported: 1,
+				Rows: []model.BookImportRow{
+					{
+						Line:   2,
+						Status: model.ImportRowRejected,
+						Error:  "book 8 probably is the same book, store it with force=true if it is not",
+						Matches: []model.BookMatch{
+							{Book: model.BookRef{ID: 8, Title: "One Piece", Author: "Eiichiro Oda", PublishYear: 1997}, Score: 1},
+						},
+					},
+					{Line: 3, Status: model.ImportRowAccepted, ID: 12},
+				},
+			},
+			mockFunc: func() {
+				ts.MockBookRepo.EXPECT().GetDuplicateCandidates(gomock.Any(), validBooks[0]).Return([]model.BookRef{
+					{ID: 8, Title: "One Piece", Author: "Eiichiro Oda", PublishYear: 1997},
+				}, nil)
+				ts.MockBookRepo.EXPECT().GetDuplicateCandidates(gomock.Any(), validBooks[1]).Return(nil, nil)
+				ts.MockBookRepo.EXPECT().StoreBooks(gomock.Any(), validBooks[1:]).Return([]model.Book{{ID: 12}}, nil)
+			},
+		},
+		{
+			name:   "success csv import with force stores books probably already stored",
+			fields: mockFields,
+			args: args{
+				ctx:    context.Background(),
+				params: model.BookImportParams{Format: model.ImportFormatCSV, Mode: model.ImportModeBestEffort, Force: true},
+				body:   csvUpload,
+			},
+			want: model.BookImportReport{
+				Mode:     model.ImportModeBestEffort,
+				Total:    3,
+				Accepted: 2,
+				Rejected: 1,
+				Imported: 2,
+				Rows: []model.BookImportRow{
+					{Line: 2, Status: model.ImportRowAccepted, ID: 11},
+					{Line: 3, Status: model.ImportRowRejected, Error: "author field is empty"},
+					{Line: 4, Status: model.ImportRowAccepted, ID: 12},
+				},
+			},
+			mockFunc: func() {
+				ts.MockBookRepo.EXPECT().StoreBooks(gomock.Any(), validBooks).Return([]model.Book{{ID: 11}, {ID: 12}}, nil)
+			},
+		},
+		{
+			name:   "success csv published column wins over publish year",
+			fields: mockFields,
+			args: args{
+				ctx:    context.Background(),
+				params: model.BookImportParams{Format: model.ImportFormatCSV, Mode: model.ImportModeBestEffort},
+				body: "title,author,publish_year,published\n" +
+					"The Iliad,Homer,,-0749~\n" +
+					"1984,George Orwell,1949,1949-06-08\n" +
+					"Emma,Jane Austen,1815,1815-12-32\n",
+			},
+			want: model.BookImportReport{
+				Mode:     model.ImportModeBestEffort,
+				Total:    3,
+				Accepted: 2,
+				Rejected: 1,
+				Imported: 2,
+				Rows: []model.BookImportRow{
+					{Line: 2, Status: model.ImportRowAccepted, ID: 11},
+					{Line: 3, Status: model.ImportRowAccepted, ID: 12},
+					{Line: 4, Status: model.ImportRowRejected, Error: `invalid published: "1815-12-32"`},
+				},
+			},
+			mockFunc: func() {
+				ts.MockBookRepo.EXPECT().GetDuplicateCandidates(gomock.Any(), gomock.Any()).Return(nil, nil).Times(2)
+				ts.MockBookRepo.EXPECT().StoreBooks(gomock.Any(), []model.Book{
+					{Title: "The Iliad", Author: "Homer", Authors: []model.BookAuthor{{Name: "Homer", Role: model.AuthorRoleAuthor}}, PublishYear: -749, Published: &edtf.Date{Year: -749, Approximate: true}},
+					{Title: "1984", Author: "George Orwell", Authors: []model.BookAuthor{{Name: "George Orwell", Role: model.AuthorRoleAuthor}}, PublishYear: 1949, Published: &edtf.Date{Year: 1949, Month: 6, Day: 8}},
+				}).Return([]model.Book{{ID: 11}, {ID: 12}}, nil)
+			},
+		},
+		{
+			name:   "success csv row with wrong field count is rejected",
+			fields: mockFields,
+			args: args{
+				ctx:    context.Background(),
+				params: model.BookImportParams{Format: model.ImportFormatCSV, Mode: model.ImportModeBestEffort, DryRun: true},
+				body:   "title,author,publish_year\nOne Piece,Eiichiro Oda\nBleach,Tite Kubo,twenty\n",
+			},
+			want: model.BookImportReport{
+				DryRun:   true,
+				Mode:     model.ImportModeBestEffort,
+				Total:    2,
+				Rejected: 2,
+				Rows: []model.BookImportRow{
+					{Line: 2, Status: model.ImportRowRejected, Error: "expected 3 fields, got 2"},
+					{Line: 3, Status: model.ImportRowRejected, Error: `invalid publish_year: "twenty"`},
+				},
+			},
+			mockFunc: func() {},
+		},
+		{
+			name:   "failed csv import missing column",
+			fields: mockFields,
+			args: args{
+				ctx:    context.Background(),
+				params: model.BookImportParams{Format: model.ImportFormatCSV},
+				body:   "title,author\nOne Piece,Eiichiro Oda\n",
+			},
+			want:     model.BookImportReport{},
+			wantErr:  true,
+			mockFunc: func() {},
+		},
+		{
+			name:   "failed import storing books",
+			fields: mockFields,
+			args: args{
+				ctx:    context.Background(),
+				params: model.BookImportParams{Format: model.ImportFormatCSV, Mode: model.ImportModeBestEffort},
+				body:   csvUpload,
+			},
+			want:    model.BookImportReport{},
+			wantErr: true,
+			mockFunc: func() {
+				ts.MockBookRepo.EXPECT().GetDuplicateCandidates(gomock.Any(), gomock.Any()).Return(nil, nil).Times(2)
+				ts.MockBookRepo.EXPECT().StoreBooks(gomock.Any(), validBooks).Return(nil, errors.New("connection reset"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logic := &BookLogic{
+				deps:                 tt.fields.deps,
+				repo:                 tt.fields.repo,
+				checkAuthorLifetimes: tt.fields.checkAuthorLifetimes,
+			}
+
+			tt.mockFunc()
+
+			got, err := logic.ImportBooks(tt.args.ctx, tt.args.params, strings.NewReader(tt.args.body))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("BookLogic.ImportBooks() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BookLogic.ImportBooks() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -4,28 +4,65 @@ import (
 	"byfood-app/internal/model"
 	"byfood-app/internal/pkg/pagination"
 	"context"
+	"io"
+	"time"
 )
 
 //go:generate go run go.uber.org/mock/mockgen@latest -source=interface.go -destination=mock_interface.go -package=book
 type RepositoryInterface interface {
-	GetBooks(ctx context.Context, params model.BookSearchParams, page pagination.Page) ([]model.Book, pagination.Metadata, error)
+	GetBooks(ctx context.Context, params model.BookSearchParams, page pagination.CursorPage) ([]model.Book, pagination.CursorMetadata, error)
 	GetBookByID(ctx context.Context, id int64) (model.Book, error)
+	GetBookByISBN(ctx context.Context, isbn string) (model.Book, error)
+	GetBookAsOf(ctx context.Context, id int64, asOf time.Time) (model.Book, error)
 	StoreBook(ctx context.Context, data model.Book) (model.Book, error)
+	StoreBooks(ctx context.Context, data []model.Book) ([]model.Book, error)
 	UpdateBook(ctx context.Context, data model.Book) (model.Book, error)
-	DeleteBook(ctx context.Context, id int64) error
+	PatchBook(ctx context.Context, id int64, version int64, apply func(model.Book) (model.Book, error)) (model.Book, error)
+	RevertBook(ctx context.Context, id int64, version int64, snapshot int64, apply func(current model.Book, snapshot model.Book) (model.Book, error)) (model.Book, error)
+	DeleteBook(ctx context.Context, id int64, version int64) error
+	RestoreBook(ctx context.Context, id int64) (model.Book, error)
+	SetBookIdentifiers(ctx context.Context, bookID int64, identifiers []model.BookIdentifier) ([]model.BookIdentifier, error)
+	GetBookDuplicates(ctx context.Context) ([]model.BookDuplicate, error)
+	MergeBooks(ctx context.Context, id int64, sourceIDs []int64, asEditions bool) (model.BookMerge, error)
+	BatchBooks(ctx context.Context, ops []model.BookBatchOperation) ([]model.BookBatchResult, error)
+	CountPurgeableBooks(ctx context.Context, before time.Time) (int64, error)
+	PurgeDeletedBooks(ctx context.Context, before time.Time, limit int) (int64, error)
+	SuggestSearchTerm(ctx context.Context, search string) (string, error)
+	GetBookSuggestions(ctx context.Context, params model.BookSuggestParams) ([]model.BookSuggestion, error)
+	GetBookRevisions(ctx context.Context, bookID int64) ([]model.BookRevision, error)
+	GetBookRevision(ctx context.Context, bookID int64, revision int64) (model.BookRevision, error)
+	GetAuthorsByName(ctx context.Context, names []string) ([]model.Author, error)
+	GetStoredISBNs(ctx context.Context, isbns []string) ([]string, error)
+	GetDuplicateCandidates(ctx context.Context, data model.Book) ([]model.BookRef, error)
 
 	// special case
 	GetBooksNoPagination(ctx context.Context, params model.BookSearchParams) ([]model.Book, error)
+	StreamBooks(ctx context.Context, params model.BookSearchParams, fn func(model.Book) error) error
 }
 
 //go:generate go run go.uber.org/mock/mockgen@latest -source=interface.go -destination=mock_interface.go -package=book
 type LogicInterface interface {
-	GetBooks(ctx context.Context, params model.BookSearchParams, page pagination.Page) ([]model.Book, pagination.Metadata, error)
+	GetBooks(ctx context.Context, params model.BookSearchParams, page pagination.CursorPage) ([]model.Book, model.BookListMetadata, error)
 	GetBookByID(ctx context.Context, id int64) (model.Book, error)
-	StoreBook(ctx context.Context, data model.Book) (model.Book, error)
+	GetBookByISBN(ctx context.Context, isbn string) (model.Book, error)
+	GetBookAsOf(ctx context.Context, id int64, asOf time.Time) (model.Book, error)
+	StoreBook(ctx context.Context, data model.Book, force bool) (model.Book, error)
+	ImportBooks(ctx context.Context, params model.BookImportParams, body io.Reader) (model.BookImportReport, error)
 	UpdateBook(ctx context.Context, data model.Book) (model.Book, error)
-	DeleteBook(ctx context.Context, id int64) error
+	PatchBook(ctx context.Context, id int64, version int64, patch model.BookPatch) (model.Book, error)
+	RevertBook(ctx context.Context, id int64, version int64, snapshot int64) (model.Book, error)
+	DeleteBook(ctx context.Context, id int64, version int64) error
+	RestoreBook(ctx context.Context, id int64) (model.Book, error)
+	SetBookIdentifiers(ctx context.Context, bookID int64, identifiers []model.BookIdentifier) ([]model.BookIdentifier, error)
+	GetBookDuplicates(ctx context.Context) ([]model.BookDuplicate, error)
+	MergeBooks(ctx context.Context, id int64, sourceIDs []int64, asEditions bool) (model.BookMerge, error)
+	BatchBooks(ctx context.Context, ops []model.BookBatchOperation, force bool) ([]model.BookBatchResult, error)
+	GetBookSuggestions(ctx context.Context, params model.BookSuggestParams) ([]model.BookSuggestion, error)
+	GetBookHistory(ctx context.Context, bookID int64) ([]model.BookRevision, error)
+	GetBookRevision(ctx context.Context, bookID int64, revision int64) (model.BookRevision, error)
+	GetBookSnapshots(ctx context.Context, bookID int64) ([]model.BookSnapshot, error)
 
 	// special case
 	GetBooksNoPagination(ctx context.Context, params model.BookSearchParams) ([]model.Book, error)
+	ExportBooks(ctx context.Context, params model.BookSearchParams, fn func(model.Book) error) error
 }
@@ -3,44 +3,78 @@ package book
 import (
 	"byfood-app/internal/core"
 	"byfood-app/internal/model"
+	"byfood-app/internal/pkg/edtf"
+	"byfood-app/internal/pkg/identifier"
+	"byfood-app/internal/pkg/isbn"
+	"byfood-app/internal/pkg/jsonpatch"
 	"byfood-app/internal/pkg/pagination"
 	"byfood-app/internal/pkg/xerrors"
+	"bytes"
+	"cmp"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+)
+
+const maxBatchOperations = 1000
+
+const maxBookIdentifiers = 50
+
+const (
+	defaultSuggestionLimit = 5
+	maxSuggestionLimit     = 20
+	suggestionTimeout      = 300 * time.Millisecond
 )
 
 type BookLogic struct {
 	deps *core.Dependency
 	repo RepositoryInterface
+
+	// checkAuthorLifetimes enables validateAuthorLifetimes on every write
+	checkAuthorLifetimes bool
 }
 
 func NewBookLogic(deps *core.Dependency, repo RepositoryInterface) *BookLogic {
 	return &BookLogic{
-		deps: deps,
-		repo: repo,
+		deps:                 deps,
+		repo:                 repo,
+		checkAuthorLifetimes: deps.BookCheckAuthorLifetimes,
 	}
 }
 
-func (logic *BookLogic) GetBooks(ctx context.Context, params model.BookSearchParams, page pagination.Page) ([]model.Book, pagination.Metadata, error) {
-	// this function works, but
-	// use GetBooksNoPagination,
-	// cuz it's hard to implement
-	// pagination on the frontend side
-	// skill issue on me :(
+func (logic *BookLogic) GetBooks(ctx context.Context, params model.BookSearchParams, page pagination.CursorPage) ([]model.Book, model.BookListMetadata, error) {
+	err := validateBookSearchParams(params)
+	if err != nil {
+		return []model.Book{}, model.BookListMetadata{}, err
+	}
 
 	data, meta, err := logic.repo.GetBooks(ctx, params, page)
+	result := model.BookListMetadata{CursorMetadata: meta}
 	if err != nil {
 		if errors.Is(err, xerrors.ErrDataNotFound) {
-			return []model.Book{}, meta, err
+			// only suggest on the first page, paging past the end is not a typo
+			if params.Search != "" && page.After == nil && page.Before == nil {
+				suggestion, err := logic.repo.SuggestSearchTerm(ctx, params.Search)
+				if err != nil {
+					logic.deps.Logger.WarnContext(ctx, "failed to suggest search term", slog.Any("error", err))
+				}
+				result.DidYouMean = suggestion
+			}
+
+			return []model.Book{}, result, err
 		}
 
 		logic.deps.Logger.ErrorContext(ctx, "failed to get books", slog.Any("error", err))
-		return []model.Book{}, meta, err
+		return []model.Book{}, result, err
 	}
 
-	return data, meta, nil
+	return data, result, nil
 }
 
 func (logic *BookLogic) GetBookByID(ctx context.Context, id int64) (model.Book, error) {
@@ -57,14 +91,56 @@ func (logic *BookLogic) GetBookByID(ctx context.Context, id int64) (model.Book,
 	return data, nil
 }
 
-func (logic *BookLogic) StoreBook(ctx context.Context, data model.Book) (model.Book, error) {
-	switch {
-	case data.Author == "":
-		return model.Book{}, xerrors.NewClientError(fmt.Errorf("author field is empty"))
-	case data.Title == "":
-		return model.Book{}, xerrors.NewClientError(fmt.Errorf("title field is empty"))
-	case data.PublishYear <= 0:
-		return model.Book{}, xerrors.NewClientError(fmt.Errorf("publish year field is empty or less than equal 0"))
+// GetBookByISBN looks a book up by an ISBN-10 or ISBN-13, hyphens allowed.
+func (logic *BookLogic) GetBookByISBN(ctx context.Context, value string) (model.Book, error) {
+	normalized, err := isbn.Normalize(value)
+	if err != nil {
+		return model.Book{}, xerrors.NewClientError(err)
+	}
+
+	data, err := logic.repo.GetBookByISBN(ctx, normalized)
+	if err != nil {
+		logic.deps.Logger.ErrorContext(ctx, "failed to get book by isbn", slog.Any("error", err))
+		return model.Book{}, err
+	}
+
+	return data, nil
+}
+
+// GetBookAsOf returns the book as it was at asOf, for audits of past catalogs.
+func (logic *BookLogic) GetBookAsOf(ctx context.Context, id int64, asOf time.Time) (model.Book, error) {
+	if id <= 0 {
+		return model.Book{}, xerrors.NewClientError(xerrors.ErrInvalidID)
+	}
+
+	data, err := logic.repo.GetBookAsOf(ctx, id, asOf)
+	if err != nil {
+		logic.deps.Logger.ErrorContext(ctx, "failed to get book by id as of", slog.Any("error", err))
+		return model.Book{}, err
+	}
+
+	return data, nil
+}
+
+// StoreBook refuses a book that probably is already stored with a
+// DuplicateBookError, unless force is set.
+func (logic *BookLogic) StoreBook(ctx context.Context, data model.Book, force bool) (model.Book, error) {
+	data = normalizeBook(data)
+	err := validateBook(data)
+	if err != nil {
+		return model.Book{}, err
+	}
+
+	err = logic.validateAuthorLifetimes(ctx, data)
+	if err != nil {
+		return model.Book{}, err
+	}
+
+	if !force {
+		err = logic.checkDuplicates(ctx, data)
+		if err != nil {
+			return model.Book{}, err
+		}
 	}
 
 	result, err := logic.repo.StoreBook(ctx, data)
@@ -80,12 +156,32 @@ func (logic *BookLogic) UpdateBook(ctx context.Context, data model.Book) (model.
 	switch {
 	case data.ID <= 0:
 		return model.Book{}, xerrors.NewClientError(xerrors.ErrInvalidID)
-	case data.Author == "":
-		return model.Book{}, xerrors.NewClientError(fmt.Errorf("author field is empty"))
-	case data.Title == "":
-		return model.Book{}, xerrors.NewClientError(fmt.Errorf("title field is empty"))
-	case data.PublishYear <= 0:
-		return model.Book{}, xerrors.NewClientError(fmt.Errorf("publish year field is empty or less than equal 0"))
+	case data.Version <= 0:
+		return model.Book{}, xerrors.PreconditionRequiredError{Err: xerrors.ErrMissingIfMatch}
+	}
+
+	// a client of the author string and publish year alone can't send the
+	// credits and full date behind them, the stored book tells them. A stale
+	// read fails on the version anyway.
+	if len(data.Authors) == 0 || data.Published == nil {
+		current, err := logic.repo.GetBookByID(ctx, data.ID)
+		if err != nil {
+			logic.deps.Logger.ErrorContext(ctx, "failed to get book by id", slog.Any("error", err))
+			return model.Book{}, err
+		}
+		data = keepStoredAuthors(current, data)
+		data = keepStoredPublished(current, data)
+	}
+
+	data = normalizeBook(data)
+	err := validateBook(data)
+	if err != nil {
+		return model.Book{}, err
+	}
+
+	err = logic.validateAuthorLifetimes(ctx, data)
+	if err != nil {
+		return model.Book{}, err
 	}
 
 	result, err := logic.repo.UpdateBook(ctx, data)
@@ -97,12 +193,41 @@ func (logic *BookLogic) UpdateBook(ctx context.Context, data model.Book) (model.
 	return result, nil
 }
 
-func (logic *BookLogic) DeleteBook(ctx context.Context, id int64) error {
-	if id <= 0 {
+// PatchBook applies a JSON Merge Patch or JSON Patch to the stored book.
+// Only the fields the patch touches change, and the result is validated
+// the same way as a full update.
+func (logic *BookLogic) PatchBook(ctx context.Context, id int64, version int64, patch model.BookPatch) (model.Book, error) {
+	switch {
+	case id <= 0:
+		return model.Book{}, xerrors.NewClientError(xerrors.ErrInvalidID)
+	case version <= 0:
+		return model.Book{}, xerrors.PreconditionRequiredError{Err: xerrors.ErrMissingIfMatch}
+	}
+
+	result, err := logic.repo.PatchBook(ctx, id, version, func(current model.Book) (model.Book, error) {
+		patched, err := applyBookPatch(current, patch)
+		if err != nil {
+			return model.Book{}, err
+		}
+		return patched, logic.validateAuthorLifetimes(ctx, patched)
+	})
+	if err != nil {
+		logic.deps.Logger.ErrorContext(ctx, "failed to patch book data", slog.Any("error", err))
+		return model.Book{}, err
+	}
+
+	return result, nil
+}
+
+func (logic *BookLogic) DeleteBook(ctx context.Context, id int64, version int64) error {
+	switch {
+	case id <= 0:
 		return xerrors.NewClientError(xerrors.ErrInvalidID)
+	case version <= 0:
+		return xerrors.PreconditionRequiredError{Err: xerrors.ErrMissingIfMatch}
 	}
 
-	err := logic.repo.DeleteBook(ctx, id)
+	err := logic.repo.DeleteBook(ctx, id, version)
 	if err != nil {
 		logic.deps.Logger.ErrorContext(ctx, "failed to delete book data", slog.Any("error", err))
 		return err
@@ -110,7 +235,205 @@ func (logic *BookLogic) DeleteBook(ctx context.Context, id int64) error {
 	return nil
 }
 
+func (logic *BookLogic) RestoreBook(ctx context.Context, id int64) (model.Book, error) {
+	if id <= 0 {
+		return model.Book{}, xerrors.NewClientError(xerrors.ErrInvalidID)
+	}
+
+	result, err := logic.repo.RestoreBook(ctx, id)
+	if err != nil {
+		logic.deps.Logger.ErrorContext(ctx, "failed to restore book data", slog.Any("error", err))
+		return model.Book{}, err
+	}
+
+	return result, nil
+}
+
+// SetBookIdentifiers normalizes the identifiers and replaces those of the
+// book with them, listed by scheme then value.
+func (logic *BookLogic) SetBookIdentifiers(ctx context.Context, bookID int64, identifiers []model.BookIdentifier) ([]model.BookIdentifier, error) {
+	if bookID <= 0 {
+		return nil, xerrors.NewClientError(xerrors.ErrInvalidID)
+	}
+	if len(identifiers) > maxBookIdentifiers {
+		return nil, xerrors.NewClientError(fmt.Errorf("a book can have at most %d identifiers", maxBookIdentifiers))
+	}
+
+	normalized := make([]model.BookIdentifier, len(identifiers))
+	for i, id := range identifiers {
+		scheme, value, err := identifier.Normalize(id.Scheme, id.Value)
+		if err != nil {
+			return nil, xerrors.NewClientError(err)
+		}
+		normalized[i] = model.BookIdentifier{Scheme: scheme, Value: value}
+	}
+	slices.SortFunc(normalized, func(a, b model.BookIdentifier) int {
+		return cmp.Or(strings.Compare(a.Scheme, b.Scheme), strings.Compare(a.Value, b.Value))
+	})
+	normalized = slices.Compact(normalized)
+
+	result, err := logic.repo.SetBookIdentifiers(ctx, bookID, normalized)
+	if err != nil {
+		logic.deps.Logger.ErrorContext(ctx, "failed to set book identifiers", slog.Any("error", err))
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// BatchBooks validates every operation up front, an invalid batch is rejected
+// as a whole without touching the database, then applies them all in a
+// single transaction. A create of a book that probably is already stored
+// fails like StoreBook does, unless force is set.
+func (logic *BookLogic) BatchBooks(ctx context.Context, ops []model.BookBatchOperation, force bool) ([]model.BookBatchResult, error) {
+	switch {
+	case len(ops) == 0:
+		return []model.BookBatchResult{}, xerrors.NewClientError(fmt.Errorf("batch has no operations"))
+	case len(ops) > maxBatchOperations:
+		return []model.BookBatchResult{}, xerrors.NewClientError(fmt.Errorf("batch exceeds %d operations", maxBatchOperations))
+	}
+
+	stored, err := logic.storedBatchBooks(ctx, ops)
+	if err != nil {
+		return []model.BookBatchResult{}, err
+	}
+
+	results := make([]model.BookBatchResult, len(ops))
+	invalid, duplicates := 0, 0
+	for i, op := range ops {
+		results[i] = model.BookBatchResult{
+			Index:  i,
+			Op:     op.Op,
+			ID:     op.ID,
+			Status: model.BatchStatusSkipped,
+		}
+
+		if op.Op == model.BatchOpCreate || op.Op == model.BatchOpUpdate {
+			book := op.Book()
+			if current, ok := stored[op.ID]; ok && op.Op == model.BatchOpUpdate {
+				book = keepStoredPublished(current, keepStoredAuthors(current, book))
+			}
+			book = normalizeBook(book)
+			op.Author, op.Authors, op.ISBN = book.Author, book.Authors, book.ISBN
+			op.PublishYear, op.Published = book.PublishYear, book.Published
+			ops[i] = op
+		}
+
+		err := validateBatchOperation(op)
+		if err == nil && (op.Op == model.BatchOpCreate || op.Op == model.BatchOpUpdate) {
+			err = logic.validateAuthorLifetimes(ctx, op.Book())
+			if err != nil && !errors.As(err, &xerrors.ClientError{}) {
+				return results, err
+			}
+		}
+		if err != nil {
+			results[i].Status = model.BatchStatusFailed
+			results[i].Error = err.Error()
+			invalid++
+			continue
+		}
+
+		if op.Op == model.BatchOpCreate && !force {
+			err = logic.checkDuplicates(ctx, op.Book())
+			var duplicate DuplicateBookError
+			if errors.As(err, &duplicate) {
+				results[i].Status = model.BatchStatusFailed
+				results[i].Error = err.Error()
+				results[i].Matches = duplicate.Matches
+				duplicates++
+				continue
+			}
+			if err != nil {
+				return results, err
+			}
+		}
+	}
+	switch {
+	case invalid > 0:
+		return results, xerrors.NewClientError(fmt.Errorf("%d invalid batch operation(s)", invalid))
+	case duplicates > 0:
+		return results, xerrors.ConflictError{Err: fmt.Errorf("%d batch operation(s) probably create an already stored book, send them with force=true if they do not", duplicates)}
+	}
+
+	results, err = logic.repo.BatchBooks(ctx, ops)
+	if err != nil {
+		logic.deps.Logger.ErrorContext(ctx, "failed to apply book batch", slog.Any("error", err))
+		return results, err
+	}
+
+	return results, nil
+}
+
+// storedBatchBooks reads the stored books of the update operations sending
+// no authors or no publication date, by id, for the credits and the date
+// their clients can't send like UpdateBook does. A book it misses fails its operation in the repository.
+func (logic *BookLogic) storedBatchBooks(ctx context.Context, ops []model.BookBatchOperation) (map[int64]model.Book, error) {
+	var ids []int64
+	for _, op := range ops {
+		if op.Op == model.BatchOpUpdate && op.ID > 0 && (len(op.Authors) == 0 || op.Published == nil) {
+			ids = append(ids, op.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	books, err := logic.repo.GetBooksNoPagination(ctx, model.BookSearchParams{IDs: ids})
+	if err != nil {
+		logic.deps.Logger.ErrorContext(ctx, "failed to get books of batch updates", slog.Any("error", err))
+		return nil, err
+	}
+
+	stored := make(map[int64]model.Book, len(books))
+	for _, book := range books {
+		stored[book.ID] = book
+	}
+
+	return stored, nil
+}
+
+func (logic *BookLogic) GetBookSuggestions(ctx context.Context, params model.BookSuggestParams) ([]model.BookSuggestion, error) {
+	params.Query = strings.TrimSpace(params.Query)
+	if params.Field == "" {
+		params.Field = "title"
+	}
+	if params.Limit == 0 {
+		params.Limit = defaultSuggestionLimit
+	}
+
+	switch {
+	case params.Query == "":
+		return []model.BookSuggestion{}, xerrors.NewClientError(fmt.Errorf("q field is empty"))
+	case suggestableBookFields[params.Field] == "":
+		return []model.BookSuggestion{}, xerrors.NewClientError(fmt.Errorf("field must be either title or author"))
+	case params.Limit < 0 || params.Limit > maxSuggestionLimit:
+		return []model.BookSuggestion{}, xerrors.NewClientError(fmt.Errorf("limit must be between 1 and %d", maxSuggestionLimit))
+	}
+
+	// autocomplete is only useful while the user is typing,
+	// give up rather than hold the request
+	ctx, cancel := context.WithTimeout(ctx, suggestionTimeout)
+	defer cancel()
+
+	data, err := logic.repo.GetBookSuggestions(ctx, params)
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		logic.deps.Logger.WarnContext(ctx, "book suggestions timed out", slog.Any("error", err))
+		return []model.BookSuggestion{}, nil
+	}
+	if err != nil {
+		logic.deps.Logger.ErrorContext(ctx, "failed to get book suggestions", slog.Any("error", err))
+		return []model.BookSuggestion{}, err
+	}
+
+	return data, nil
+}
+
 func (logic *BookLogic) GetBooksNoPagination(ctx context.Context, params model.BookSearchParams) ([]model.Book, error) {
+	err := validateBookSearchParams(params)
+	if err != nil {
+		return []model.Book{}, err
+	}
+
 	data, err := logic.repo.GetBooksNoPagination(ctx, params)
 	if err != nil {
 		logic.deps.Logger.ErrorContext(ctx, "failed to get books", slog.Any("error", err))
@@ -119,3 +442,180 @@ func (logic *BookLogic) GetBooksNoPagination(ctx context.Context, params model.B
 
 	return data, nil
 }
+
+// ExportBooks streams every book matching params to fn, see RepositoryInterface.StreamBooks.
+func (logic *BookLogic) ExportBooks(ctx context.Context, params model.BookSearchParams, fn func(model.Book) error) error {
+	err := validateBookSearchParams(params)
+	if err != nil {
+		return err
+	}
+
+	err = logic.repo.StreamBooks(ctx, params, fn)
+	if err != nil {
+		logic.deps.Logger.ErrorContext(ctx, "failed to export books", slog.Any("error", err))
+		return err
+	}
+
+	return nil
+}
+
+// keepStoredPublished keeps the stored publication date, month, day and
+// qualifiers included, when data sends no date and the same publish year.
+func keepStoredPublished(current model.Book, data model.Book) model.Book {
+	if data.Published != nil || data.PublishYear != current.PublishYear {
+		return data
+	}
+
+	data.Published = current.Published
+	return data
+}
+
+// normalizeBook normalizes the author fields, reads a publish year sent alone
+// as a date known to the year and turns the ISBN into the ISBN-13 it is
+// stored as. An invalid ISBN is left for validateBook to report.
+func normalizeBook(data model.Book) model.Book {
+	data = normalizeAuthors(data)
+
+	// the publication date wins over the year, which is then its year
+	switch {
+	case data.Published != nil:
+		data.PublishYear = data.Published.Year
+	case data.PublishYear != 0:
+		data.Published = &edtf.Date{Year: data.PublishYear}
+	}
+
+	if normalized, err := isbn.Normalize(data.ISBN); err == nil {
+		data.ISBN = normalized
+	}
+	data.ISBN10 = ""
+
+	return data
+}
+
+func validateBook(data model.Book) error {
+	switch {
+	case data.Author == "":
+		return xerrors.NewClientError(fmt.Errorf("author field is empty"))
+	case data.Title == "":
+		return xerrors.NewClientError(fmt.Errorf("title field is empty"))
+	case data.Published == nil && data.PublishYear == 0:
+		return xerrors.NewClientError(fmt.Errorf("publish year field is empty, send published 0000 for 1 BCE"))
+	}
+
+	if err := bookPublished(data).Validate(); err != nil {
+		return xerrors.NewClientError(err)
+	}
+
+	if data.ISBN != "" {
+		if _, err := isbn.Normalize(data.ISBN); err != nil {
+			return xerrors.NewClientError(err)
+		}
+	}
+
+	return validateAuthors(data.Authors)
+}
+
+func validateBatchOperation(op model.BookBatchOperation) error {
+	switch op.Op {
+	case model.BatchOpCreate:
+		if op.ID != 0 || op.Version != 0 {
+			return xerrors.NewClientError(fmt.Errorf("id and version can not be set on create"))
+		}
+		return validateBook(op.Book())
+	case model.BatchOpUpdate:
+		switch {
+		case op.ID <= 0:
+			return xerrors.NewClientError(xerrors.ErrInvalidID)
+		case op.Version <= 0:
+			return xerrors.NewClientError(fmt.Errorf("version is required"))
+		}
+		return validateBook(op.Book())
+	case model.BatchOpDelete:
+		switch {
+		case op.ID <= 0:
+			return xerrors.NewClientError(xerrors.ErrInvalidID)
+		case op.Version <= 0:
+			return xerrors.NewClientError(fmt.Errorf("version is required"))
+		}
+	case model.BatchOpRestore:
+		if op.ID <= 0 {
+			return xerrors.NewClientError(xerrors.ErrInvalidID)
+		}
+	default:
+		return xerrors.NewClientError(fmt.Errorf("unknown batch op: %s", op.Op))
+	}
+
+	return nil
+}
+
+// applyBookPatch patches the editable fields of the book, the same fields a
+// full update accepts, so a patch can't touch id, version or audit columns.
+func applyBookPatch(current model.Book, patch model.BookPatch) (model.Book, error) {
+	doc, err := json.Marshal(model.UpdateBookRequest{
+		Title:       current.Title,
+		Author:      current.Author,
+		Authors:     current.Authors,
+		PublishYear: current.PublishYear,
+		Published:   current.Published,
+		ISBN:        current.ISBN,
+	})
+	if err != nil {
+		return model.Book{}, err
+	}
+
+	var patched []byte
+	switch patch.ContentType {
+	case jsonpatch.MergePatchType:
+		patched, err = jsonpatch.MergePatch(doc, patch.Data)
+	case jsonpatch.JSONPatchType:
+		patched, err = jsonpatch.Apply(doc, patch.Data)
+	default:
+		return model.Book{}, xerrors.NewClientError(fmt.Errorf("unsupported patch content type: %s", patch.ContentType))
+	}
+	if err != nil {
+		if errors.Is(err, jsonpatch.ErrTestFailed) {
+			return model.Book{}, xerrors.PreconditionFailedError{Err: err}
+		}
+		return model.Book{}, xerrors.NewClientError(err)
+	}
+
+	var payload model.UpdateBookRequest
+	decoder := json.NewDecoder(bytes.NewReader(patched))
+	decoder.DisallowUnknownFields()
+	err = decoder.Decode(&payload)
+	if err != nil {
+		return model.Book{}, xerrors.NewClientError(fmt.Errorf("invalid patched book: %v", err))
+	}
+
+	// a patch of the author string alone is read as a new author list
+	if payload.Author != current.Author && reflect.DeepEqual(payload.Authors, current.Authors) {
+		payload.Authors = nil
+	}
+	// and one of the publish year alone as a date known to the year
+	if payload.PublishYear != current.PublishYear && reflect.DeepEqual(payload.Published, current.Published) {
+		payload.Published = nil
+	}
+
+	current.Title = payload.Title
+	current.Author = payload.Author
+	current.Authors = payload.Authors
+	current.PublishYear = payload.PublishYear
+	current.Published = payload.Published
+	current.ISBN = payload.ISBN
+
+	current = normalizeBook(current)
+	return current, validateBook(current)
+}
+
+func validateBookSearchParams(params model.BookSearchParams) error {
+	switch {
+	case params.IncludeDeleted && params.OnlyDeleted:
+		return xerrors.NewClientError(fmt.Errorf("include_deleted can not be used on trash listing"))
+	case !params.OnlyDeleted && slices.ContainsFunc(params.Sort, func(sort model.SortParam) bool { return sort.Field == "deleted_at" }):
+		return xerrors.NewClientError(fmt.Errorf("deleted_at sort is only available on trash listing"))
+	case params.PublishedFrom != nil && params.PublishedTo != nil && edtf.Compare(params.PublishedFrom.Start(), params.PublishedTo.End()) > 0:
+		return xerrors.NewClientError(fmt.Errorf("published_from can not be after published_to"))
+	}
+
+	return nil
+}
@@ -3,9 +3,14 @@ package book
 import (
 	"byfood-app/internal/core"
 	"byfood-app/internal/model"
+	"byfood-app/internal/pkg/edtf"
+	"byfood-app/internal/pkg/jsonpatch"
 	"byfood-app/internal/pkg/pagination"
+	"byfood-app/internal/pkg/xerrors"
 	"context"
+	"errors"
 	"log/slog"
+	"net/http"
 	"reflect"
 	"testing"
 
@@ -25,14 +30,21 @@ func setupTestSuite(t *testing.T) *testSuite {
 	}
 }
 
+// year returns a pointer to y, for the known years of an author
+func year(y int64) *int64 {
+	return &y
+}
+
 func TestBookLogic_StoreBook(t *testing.T) {
 	type fields struct {
-		deps *core.Dependency
-		repo RepositoryInterface
+		deps                 *core.Dependency
+		repo                 RepositoryInterface
+		checkAuthorLifetimes bool
 	}
 	type args struct {
-		ctx  context.Context
-		data model.Book
+		ctx   context.Context
+		data  model.Book
+		force bool
 	}
 
 	ts := setupTestSuite(t)
@@ -42,6 +54,8 @@ func TestBookLogic_StoreBook(t *testing.T) {
 		},
 		repo: ts.MockBookRepo,
 	}
+	lifetimeFields := mockFields
+	lifetimeFields.checkAuthorLifetimes = true
 
 	expectedResult := model.Book{}
 
@@ -67,27 +81,354 @@ func TestBookLogic_StoreBook(t *testing.T) {
 			want:    expectedResult,
 			wantErr: false,
 			mockFunc: func() {
+				ts.MockBookRepo.EXPECT().GetDuplicateCandidates(gomock.Any(), gomock.Any()).Return(nil, nil)
 				ts.MockBookRepo.EXPECT().StoreBook(gomock.Any(), model.Book{
 					Title:       "One Piece",
 					Author:      "Eiichiro Oda",
+					Authors:     []model.BookAuthor{{Name: "Eiichiro Oda", Role: model.AuthorRoleAuthor}},
 					PublishYear: 1997,
+					Published:   &edtf.Date{Year: 1997},
+				}).Return(
+					expectedResult,
+					nil,
+				)
+			},
+		},
+		{
+			name:   "success store book with authors list",
+			fields: mockFields,
+			args: args{
+				ctx: context.Background(),
+				data: model.Book{
+					Title: "Good Omens",
+					Authors: []model.BookAuthor{
+						{Name: " Terry Pratchett "},
+						{Name: "Neil Gaiman", Role: model.AuthorRoleAuthor},
+						{Name: "Paul Kidby", Role: model.AuthorRoleIllustrator},
+					},
+					PublishYear: 1990,
+				},
+			},
+			want:    expectedResult,
+			wantErr: false,
+			mockFunc: func() {
+				ts.MockBookRepo.EXPECT().GetDuplicateCandidates(gomock.Any(), gomock.Any()).Return(nil, nil)
+				ts.MockBookRepo.EXPECT().StoreBook(gomock.Any(), model.Book{
+					Title:  "Good Omens",
+					Author: "Terry Pratchett & Neil Gaiman",
+					Authors: []model.BookAuthor{
+						{Name: "Terry Pratchett", Role: model.AuthorRoleAuthor},
+						{Name: "Neil Gaiman", Role: model.AuthorRoleAuthor},
+						{Name: "Paul Kidby", Role: model.AuthorRoleIllustrator},
+					},
+					PublishYear: 1990,
+					Published:   &edtf.Date{Year: 1990},
+				}).Return(
+					expectedResult,
+					nil,
+				)
+			},
+		},
+		{
+			name:   "success store book with hyphenated isbn 10",
+			fields: mockFields,
+			args: args{
+				ctx: context.Background(),
+				data: model.Book{
+					Title:       "1984",
+					Author:      "George Orwell",
+					PublishYear: 1949,
+					ISBN:        "0-451-52493-4",
+				},
+			},
+			want:    expectedResult,
+			wantErr: false,
+			mockFunc: func() {
+				ts.MockBookRepo.EXPECT().GetDuplicateCandidates(gomock.Any(), gomock.Any()).Return(nil, nil)
+				ts.MockBookRepo.EXPECT().StoreBook(gomock.Any(), model.Book{
+					Title:       "1984",
+					Author:      "George Orwell",
+					Authors:     []model.BookAuthor{{Name: "George Orwell", Role: model.AuthorRoleAuthor}},
+					PublishYear: 1949,
+					Published:   &edtf.Date{Year: 1949},
+					ISBN:        "9780451524935",
+				}).Return(
+					expectedResult,
+					nil,
+				)
+			},
+		},
+		{
+			name:   "failed store book with wrong isbn check digit",
+			fields: mockFields,
+			args: args{
+				ctx: context.Background(),
+				data: model.Book{
+					Title:       "1984",
+					Author:      "George Orwell",
+					PublishYear: 1949,
+					ISBN:        "978-0-451-52493-6",
+				},
+			},
+			want:     model.Book{},
+			wantErr:  true,
+			mockFunc: func() {},
+		},
+		{
+			name:   "success store book approximately published before the common era",
+			fields: mockFields,
+			args: args{
+				ctx: context.Background(),
+				data: model.Book{
+					Title:       "The Iliad",
+					Author:      "Homer",
+					PublishYear: 1598,
+					Published:   &edtf.Date{Year: -749, Approximate: true},
+				},
+			},
+			want:    expectedResult,
+			wantErr: false,
+			mockFunc: func() {
+				ts.MockBookRepo.EXPECT().GetDuplicateCandidates(gomock.Any(), gomock.Any()).Return(nil, nil)
+				ts.MockBookRepo.EXPECT().StoreBook(gomock.Any(), model.Book{
+					Title:       "The Iliad",
+					Author:      "Homer",
+					Authors:     []model.BookAuthor{{Name: "Homer", Role: model.AuthorRoleAuthor}},
+					PublishYear: -749,
+					Published:   &edtf.Date{Year: -749, Approximate: true},
+				}).Return(
+					expectedResult,
+					nil,
+				)
+			},
+		},
+		{
+			name:   "success store book published in 1 BCE",
+			fields: mockFields,
+			args: args{
+				ctx: context.Background(),
+				data: model.Book{
+					Title:     "Ars Amatoria",
+					Author:    "Ovid",
+					Published: &edtf.Date{Year: 0, Uncertain: true},
+				},
+			},
+			want:    expectedResult,
+			wantErr: false,
+			mockFunc: func() {
+				ts.MockBookRepo.EXPECT().GetDuplicateCandidates(gomock.Any(), gomock.Any()).Return(nil, nil)
+				ts.MockBookRepo.EXPECT().StoreBook(gomock.Any(), model.Book{
+					Title:     "Ars Amatoria",
+					Author:    "Ovid",
+					Authors:   []model.BookAuthor{{Name: "Ovid", Role: model.AuthorRoleAuthor}},
+					Published: &edtf.Date{Year: 0, Uncertain: true},
 				}).Return(
 					expectedResult,
 					nil,
 				)
 			},
 		},
+		{
+			name:   "failed store book without publication date",
+			fields: mockFields,
+			args: args{
+				ctx: context.Background(),
+				data: model.Book{
+					Title:  "1984",
+					Author: "George Orwell",
+				},
+			},
+			want:     model.Book{},
+			wantErr:  true,
+			mockFunc: func() {},
+		},
+		{
+			name:   "failed store book published on a day its month does not have",
+			fields: mockFields,
+			args: args{
+				ctx: context.Background(),
+				data: model.Book{
+					Title:     "1984",
+					Author:    "George Orwell",
+					Published: &edtf.Date{Year: 1949, Month: 2, Day: 29},
+				},
+			},
+			want:     model.Book{},
+			wantErr:  true,
+			mockFunc: func() {},
+		},
+		{
+			name:   "failed store book with unknown author role",
+			fields: mockFields,
+			args: args{
+				ctx: context.Background(),
+				data: model.Book{
+					Title:       "Good Omens",
+					Authors:     []model.BookAuthor{{Name: "Terry Pratchett", Role: "ghostwriter"}},
+					PublishYear: 1990,
+				},
+			},
+			want:     model.Book{},
+			wantErr:  true,
+			mockFunc: func() {},
+		},
+		{
+			name:   "failed store book probably already stored",
+			fields: mockFields,
+			args: args{
+				ctx: context.Background(),
+				data: model.Book{
+					Title:       "Pride & Predjudice",
+					Author:      "Jane Austen",
+					PublishYear: 1813,
+				},
+			},
+			want:    model.Book{},
+			wantErr: true,
+			mockFunc: func() {
+				ts.MockBookRepo.EXPECT().GetDuplicateCandidates(gomock.Any(), gomock.Any()).Return([]model.BookRef{
+					{ID: 4, Title: "Emma", Author: "Jane Austen", PublishYear: 1815},
+					{ID: 3, Title: "Pride and Prejudice", Author: "Jane Austen", PublishYear: 1813},
+				}, nil)
+			},
+		},
+		{
+			name:   "success store book probably already stored with force",
+			fields: mockFields,
+			args: args{
+				ctx: context.Background(),
+				data: model.Book{
+					Title:       "Pride and Prejudice",
+					Author:      "Jane Austen",
+					PublishYear: 1813,
+				},
+				force: true,
+			},
+			want:    expectedResult,
+			wantErr: false,
+			mockFunc: func() {
+				ts.MockBookRepo.EXPECT().StoreBook(gomock.Any(), gomock.Any()).Return(expectedResult, nil)
+			},
+		},
+		{
+			name:   "success store book within the author lifetime",
+			fields: lifetimeFields,
+			args: args{
+				ctx: context.Background(),
+				data: model.Book{
+					Title:       "Nineteen Eighty-Four",
+					Author:      "George Orwell",
+					PublishYear: 1949,
+				},
+			},
+			want:    expectedResult,
+			wantErr: false,
+			mockFunc: func() {
+				ts.MockBookRepo.EXPECT().GetAuthorsByName(gomock.Any(), []string{"George Orwell"}).Return(
+					[]model.Author{{ID: 2, Name: "George Orwell", BirthYear: year(1903), DeathYear: year(1950)}},
+					nil,
+				)
+				ts.MockBookRepo.EXPECT().GetDuplicateCandidates(gomock.Any(), gomock.Any()).Return(nil, nil)
+				ts.MockBookRepo.EXPECT().StoreBook(gomock.Any(), gomock.Any()).Return(expectedResult, nil)
+			},
+		},
+		{
+			name:   "success store book only checking names credited as author",
+			fields: lifetimeFields,
+			args: args{
+				ctx: context.Background(),
+				data: model.Book{
+					Title: "Pride and Prejudice",
+					Authors: []model.BookAuthor{
+						{Name: "Jane Austen"},
+						{Name: "Hugh Thomson", Role: model.AuthorRoleIllustrator},
+					},
+					PublishYear: 1894,
+				},
+			},
+			want:    expectedResult,
+			wantErr: false,
+			mockFunc: func() {
+				ts.MockBookRepo.EXPECT().GetAuthorsByName(gomock.Any(), []string{"Jane Austen"}).Return(
+					[]model.Author{{ID: 3, Name: "Jane Austen", BirthYear: year(1775), DeathYear: year(1817)}},
+					nil,
+				)
+				ts.MockBookRepo.EXPECT().GetDuplicateCandidates(gomock.Any(), gomock.Any()).Return(nil, nil)
+				ts.MockBookRepo.EXPECT().StoreBook(gomock.Any(), gomock.Any()).Return(expectedResult, nil)
+			},
+		},
+		{
+			name:   "failed store book published before the author was born",
+			fields: lifetimeFields,
+			args: args{
+				ctx: context.Background(),
+				data: model.Book{
+					Title:       "Nineteen Eighty-Four",
+					Author:      "George Orwell",
+					PublishYear: 1849,
+				},
+			},
+			want:    model.Book{},
+			wantErr: true,
+			mockFunc: func() {
+				ts.MockBookRepo.EXPECT().GetAuthorsByName(gomock.Any(), []string{"George Orwell"}).Return(
+					[]model.Author{{ID: 2, Name: "George Orwell", BirthYear: year(1903), DeathYear: year(1950)}},
+					nil,
+				)
+			},
+		},
+		{
+			name:   "failed store book published before an author born in 1 BCE",
+			fields: lifetimeFields,
+			args: args{
+				ctx: context.Background(),
+				data: model.Book{
+					Title:       "Epistulae",
+					Author:      "Lucius",
+					PublishYear: 2,
+				},
+			},
+			want:    model.Book{},
+			wantErr: true,
+			mockFunc: func() {
+				ts.MockBookRepo.EXPECT().GetAuthorsByName(gomock.Any(), []string{"Lucius"}).Return(
+					[]model.Author{{ID: 6, Name: "Lucius", BirthYear: year(0)}},
+					nil,
+				)
+			},
+		},
+		{
+			name:   "failed store book published long after the author died",
+			fields: lifetimeFields,
+			args: args{
+				ctx: context.Background(),
+				data: model.Book{
+					Title:       "Emma",
+					Author:      "Jane Austen",
+					PublishYear: 1950,
+				},
+			},
+			want:    model.Book{},
+			wantErr: true,
+			mockFunc: func() {
+				ts.MockBookRepo.EXPECT().GetAuthorsByName(gomock.Any(), []string{"Jane Austen"}).Return(
+					[]model.Author{{ID: 3, Name: "Jane Austen", BirthYear: year(1775), DeathYear: year(1817)}},
+					nil,
+				)
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			logic := &BookLogic{
-				deps: tt.fields.deps,
-				repo: tt.fields.repo,
+				deps:                 tt.fields.deps,
+				repo:                 tt.fields.repo,
+				checkAuthorLifetimes: tt.fields.checkAuthorLifetimes,
 			}
 
 			tt.mockFunc()
 
-			got, err := logic.StoreBook(tt.args.ctx, tt.args.data)
+			got, err := logic.StoreBook(tt.args.ctx, tt.args.data, tt.args.force)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("BookLogic.StoreBook() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -107,7 +448,7 @@ func TestBookLogic_GetBooks(t *testing.T) {
 	type args struct {
 		ctx    context.Context
 		params model.BookSearchParams
-		page   pagination.Page
+		page   pagination.CursorPage
 	}
 
 	ts := setupTestSuite(t)
@@ -125,7 +466,7 @@ func TestBookLogic_GetBooks(t *testing.T) {
 		fields   fields
 		args     args
 		want     []model.Book
-		wantMeta pagination.Metadata
+		wantMeta model.BookListMetadata
 		wantErr  bool
 		mockFunc func()
 	}{
@@ -137,20 +478,63 @@ func TestBookLogic_GetBooks(t *testing.T) {
 				params: model.BookSearchParams{
 					Search: "oda",
 				},
-				page: pagination.Page{},
+				page: pagination.CursorPage{Limit: 20},
 			},
 			want:     expectedResult,
-			wantMeta: pagination.Metadata{},
+			wantMeta: model.BookListMetadata{CursorMetadata: pagination.CursorMetadata{Limit: 20}},
 			wantErr:  false,
 			mockFunc: func() {
 				ts.MockBookRepo.EXPECT().GetBooks(gomock.Any(), model.BookSearchParams{
 					Search: "oda",
-				}, pagination.Page{}).Return(
+				}, pagination.CursorPage{Limit: 20}).Return(
 					expectedResult,
-					pagination.Metadata{},
+					pagination.CursorMetadata{Limit: 20},
+					nil,
+				)
+			},
+		},
+		{
+			name:   "suggest search term when search found nothing",
+			fields: mockFields,
+			args: args{
+				ctx: context.Background(),
+				params: model.BookSearchParams{
+					Search: "tolkein",
+				},
+				page: pagination.CursorPage{Limit: 20},
+			},
+			want: expectedResult,
+			wantMeta: model.BookListMetadata{
+				CursorMetadata: pagination.CursorMetadata{Limit: 20},
+				DidYouMean:     "J.R.R. Tolkien",
+			},
+			wantErr: true,
+			mockFunc: func() {
+				ts.MockBookRepo.EXPECT().GetBooks(gomock.Any(), model.BookSearchParams{
+					Search: "tolkein",
+				}, pagination.CursorPage{Limit: 20}).Return(
 					nil,
+					pagination.CursorMetadata{Limit: 20},
+					xerrors.ErrDataNotFound,
 				)
+				ts.MockBookRepo.EXPECT().SuggestSearchTerm(gomock.Any(), "tolkein").Return("J.R.R. Tolkien", nil)
+			},
+		},
+		{
+			name:   "failed get books with inverted publish year range",
+			fields: mockFields,
+			args: args{
+				ctx: context.Background(),
+				params: model.BookSearchParams{
+					PublishedFrom: &edtf.Date{Year: 1950},
+					PublishedTo:   &edtf.Date{Year: 1900},
+				},
+				page: pagination.CursorPage{Limit: 20},
 			},
+			want:     expectedResult,
+			wantMeta: model.BookListMetadata{},
+			wantErr:  true,
+			mockFunc: func() {},
 		},
 	}
 	for _, tt := range tests {
@@ -167,6 +551,9 @@ func TestBookLogic_GetBooks(t *testing.T) {
 				t.Errorf("BookLogic.GetBooks() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
+			if tt.wantErr && !errors.Is(err, xerrors.ErrDataNotFound) {
+				return
+			}
 			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("BookLogic.GetBooks() got = %v, want %v", got, tt.want)
 			}
@@ -240,14 +627,14 @@ func TestBookLogic_GetBookByID(t *testing.T) {
 	}
 }
 
-func TestBookLogic_UpdateBook(t *testing.T) {
+func TestBookLogic_GetBookByISBN(t *testing.T) {
 	type fields struct {
 		deps *core.Dependency
 		repo RepositoryInterface
 	}
 	type args struct {
 		ctx  context.Context
-		data model.Book
+		isbn string
 	}
 
 	ts := setupTestSuite(t)
@@ -258,7 +645,7 @@ func TestBookLogic_UpdateBook(t *testing.T) {
 		repo: ts.MockBookRepo,
 	}
 
-	expectedResult := model.Book{}
+	expectedResult := model.Book{ID: 2, ISBN: "9780451524935", ISBN10: "0451524934"}
 
 	tests := []struct {
 		name     string
@@ -266,33 +653,46 @@ func TestBookLogic_UpdateBook(t *testing.T) {
 		args     args
 		want     model.Book
 		wantErr  bool
+		wantCode int
 		mockFunc func()
 	}{
 		{
-			name:   "success update book data",
+			name:   "success get book data by isbn 13",
 			fields: mockFields,
 			args: args{
-				ctx: context.Background(),
-				data: model.Book{
-					ID:          int64(1),
-					Title:       "One Piece",
-					Author:      "Eiichiro Oda",
-					PublishYear: 1997,
-				},
+				ctx:  context.Background(),
+				isbn: "978-0-451-52493-5",
 			},
-			want:    expectedResult,
-			wantErr: false,
+			want:     expectedResult,
+			wantCode: http.StatusOK,
 			mockFunc: func() {
-				ts.MockBookRepo.EXPECT().UpdateBook(gomock.Any(), model.Book{
-					ID:          int64(1),
-					Title:       "One Piece",
-					Author:      "Eiichiro Oda",
-					PublishYear: 1997,
-				}).Return(
-					expectedResult,
-					nil,
-				)
+				ts.MockBookRepo.EXPECT().GetBookByISBN(gomock.Any(), "9780451524935").Return(expectedResult, nil)
+			},
+		},
+		{
+			name:   "success get book data by isbn 10",
+			fields: mockFields,
+			args: args{
+				ctx:  context.Background(),
+				isbn: "0451524934",
+			},
+			want:     expectedResult,
+			wantCode: http.StatusOK,
+			mockFunc: func() {
+				ts.MockBookRepo.EXPECT().GetBookByISBN(gomock.Any(), "9780451524935").Return(expectedResult, nil)
+			},
+		},
+		{
+			name:   "failed get book data by invalid isbn",
+			fields: mockFields,
+			args: args{
+				ctx:  context.Background(),
+				isbn: "0451524935",
 			},
+			want:     model.Book{},
+			wantErr:  true,
+			wantCode: http.StatusBadRequest,
+			mockFunc: func() {},
 		},
 	}
 	for _, tt := range tests {
@@ -304,19 +704,670 @@ func TestBookLogic_UpdateBook(t *testing.T) {
 
 			tt.mockFunc()
 
-			got, err := logic.UpdateBook(tt.args.ctx, tt.args.data)
+			got, err := logic.GetBookByISBN(tt.args.ctx, tt.args.isbn)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("BookLogic.UpdateBook() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("BookLogic.GetBookByISBN() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
+			if tt.wantErr && xerrors.ParseErrorTypeToCodeInt(err) != tt.wantCode {
+				t.Errorf("BookLogic.GetBookByISBN() error code = %v, want %v", xerrors.ParseErrorTypeToCodeInt(err), tt.wantCode)
+			}
 			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("BookLogic.UpdateBook() = %v, want %v", got, tt.want)
+				t.Errorf("BookLogic.GetBookByISBN() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
 
-func TestBookLogic_DeleteBook(t *testing.T) {
+func TestBookLogic_UpdateBook(t *testing.T) {
+	type fields struct {
+		deps *core.Dependency
+		repo RepositoryInterface
+	}
+	type args struct {
+		ctx  context.Context
+		data model.Book
+	}
+
+	ts := setupTestSuite(t)
+	mockFields := fields{
+		deps: &core.Dependency{
+			Logger: slog.Default(),
+		},
+		repo: ts.MockBookRepo,
+	}
+
+	expectedResult := model.Book{}
+
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		want     model.Book
+		wantErr  bool
+		mockFunc func()
+	}{
+		{
+			name:   "success update book data",
+			fields: mockFields,
+			args: args{
+				ctx: context.Background(),
+				data: model.Book{
+					ID:          int64(1),
+					Title:       "One Piece",
+					Author:      "Eiichiro Oda",
+					PublishYear: 1997,
+					Version:     2,
+				},
+			},
+			want:    expectedResult,
+			wantErr: false,
+			mockFunc: func() {
+				ts.MockBookRepo.EXPECT().GetBookByID(gomock.Any(), int64(1)).Return(model.Book{
+					ID:      int64(1),
+					Author:  "Oda Eiichiro",
+					Authors: []model.BookAuthor{{Name: "Oda Eiichiro", Role: model.AuthorRoleAuthor}},
+					Version: 2,
+				}, nil)
+				ts.MockBookRepo.EXPECT().UpdateBook(gomock.Any(), model.Book{
+					ID:          int64(1),
+					Title:       "One Piece",
+					Author:      "Eiichiro Oda",
+					Authors:     []model.BookAuthor{{Name: "Eiichiro Oda", Role: model.AuthorRoleAuthor}},
+					PublishYear: 1997,
+					Published:   &edtf.Date{Year: 1997},
+					Version:     2,
+				}).Return(
+					expectedResult,
+					nil,
+				)
+			},
+		},
+		{
+			name:   "success update book data keeping the stored credits of the same author string",
+			fields: mockFields,
+			args: args{
+				ctx: context.Background(),
+				data: model.Book{
+					ID:          int64(1),
+					Title:       "The Odyssey",
+					Author:      "Homer",
+					PublishYear: 1996,
+					Version:     3,
+				},
+			},
+			want:    expectedResult,
+			wantErr: false,
+			mockFunc: func() {
+				credits := []model.BookAuthor{
+					{Name: "Homer", Role: model.AuthorRoleAuthor},
+					{Name: "Robert Fagles", Role: model.AuthorRoleTranslator},
+				}
+				ts.MockBookRepo.EXPECT().GetBookByID(gomock.Any(), int64(1)).Return(model.Book{
+					ID:      int64(1),
+					Author:  "Homer",
+					Authors: credits,
+					Version: 3,
+				}, nil)
+				ts.MockBookRepo.EXPECT().UpdateBook(gomock.Any(), model.Book{
+					ID:          int64(1),
+					Title:       "The Odyssey",
+					Author:      "Homer",
+					Authors:     credits,
+					PublishYear: 1996,
+					Published:   &edtf.Date{Year: 1996},
+					Version:     3,
+				}).Return(
+					expectedResult,
+					nil,
+				)
+			},
+		},
+		{
+			name:   "success update book data keeping the stored date of the same publish year",
+			fields: mockFields,
+			args: args{
+				ctx: context.Background(),
+				data: model.Book{
+					ID:          int64(2),
+					Title:       "Nineteen Eighty-Four",
+					Author:      "George Orwell",
+					PublishYear: 1949,
+					Version:     4,
+				},
+			},
+			want:    expectedResult,
+			wantErr: false,
+			mockFunc: func() {
+				credits := []model.BookAuthor{{Name: "George Orwell", Role: model.AuthorRoleAuthor}}
+				ts.MockBookRepo.EXPECT().GetBookByID(gomock.Any(), int64(2)).Return(model.Book{
+					ID:          int64(2),
+					Author:      "George Orwell",
+					Authors:     credits,
+					PublishYear: 1949,
+					Published:   &edtf.Date{Year: 1949, Month: 6, Day: 8},
+					Version:     4,
+				}, nil)
+				ts.MockBookRepo.EXPECT().UpdateBook(gomock.Any(), model.Book{
+					ID:          int64(2),
+					Title:       "Nineteen Eighty-Four",
+					Author:      "George Orwell",
+					Authors:     credits,
+					PublishYear: 1949,
+					Published:   &edtf.Date{Year: 1949, Month: 6, Day: 8},
+					Version:     4,
+				}).Return(
+					expectedResult,
+					nil,
+				)
+			},
+		},
+		{
+			name:   "failed update book data without version",
+			fields: mockFields,
+			args: args{
+				ctx: context.Background(),
+				data: model.Book{
+					ID:          int64(1),
+					Title:       "One Piece",
+					Author:      "Eiichiro Oda",
+					PublishYear: 1997,
+				},
+			},
+			want:     model.Book{},
+			wantErr:  true,
+			mockFunc: func() {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logic := &BookLogic{
+				deps: tt.fields.deps,
+				repo: tt.fields.repo,
+			}
+
+			tt.mockFunc()
+
+			got, err := logic.UpdateBook(tt.args.ctx, tt.args.data)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("BookLogic.UpdateBook() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BookLogic.UpdateBook() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBookLogic_PatchBook(t *testing.T) {
+	type fields struct {
+		deps                 *core.Dependency
+		repo                 RepositoryInterface
+		checkAuthorLifetimes bool
+	}
+	type args struct {
+		ctx     context.Context
+		id      int64
+		version int64
+		patch   model.BookPatch
+	}
+
+	ts := setupTestSuite(t)
+	mockFields := fields{
+		deps: &core.Dependency{
+			Logger: slog.Default(),
+		},
+		repo: ts.MockBookRepo,
+	}
+	lifetimeFields := mockFields
+	lifetimeFields.checkAuthorLifetimes = true
+
+	current := model.Book{
+		ID:          int64(1),
+		Title:       "One Piece",
+		Author:      "Eichiro Oda",
+		PublishYear: 1997,
+		Published:   &edtf.Date{Year: 1997, Month: 7, Day: 22},
+		Version:     2,
+	}
+
+	// the mocked repo applies the patch on the current row and echoes the result back
+	applyOnCurrent := func(_ context.Context, _ int64, _ int64, apply func(model.Book) (model.Book, error)) (model.Book, error) {
+		return apply(current)
+	}
+
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		want     model.Book
+		wantCode int
+		mockFunc func()
+	}{
+		{
+			name:   "success merge patch author only",
+			fields: mockFields,
+			args: args{
+				ctx:     context.Background(),
+				id:      int64(1),
+				version: int64(2),
+				patch: model.BookPatch{
+					ContentType: jsonpatch.MergePatchType,
+					Data:        []byte(`{"author":"Eiichiro Oda"}`),
+				},
+			},
+			want: model.Book{
+				ID:          int64(1),
+				Title:       "One Piece",
+				Author:      "Eiichiro Oda",
+				Authors:     []model.BookAuthor{{Name: "Eiichiro Oda", Role: model.AuthorRoleAuthor}},
+				PublishYear: 1997,
+				Published:   &edtf.Date{Year: 1997, Month: 7, Day: 22},
+				Version:     2,
+			},
+			mockFunc: func() {
+				ts.MockBookRepo.EXPECT().PatchBook(gomock.Any(), int64(1), int64(2), gomock.Any()).DoAndReturn(applyOnCurrent)
+			},
+		},
+		{
+			name:   "failed merge patch publish year before the author was born",
+			fields: lifetimeFields,
+			args: args{
+				ctx:     context.Background(),
+				id:      int64(1),
+				version: int64(2),
+				patch: model.BookPatch{
+					ContentType: jsonpatch.MergePatchType,
+					Data:        []byte(`{"publish_year":1937}`),
+				},
+			},
+			want:     model.Book{},
+			wantCode: http.StatusBadRequest,
+			mockFunc: func() {
+				ts.MockBookRepo.EXPECT().PatchBook(gomock.Any(), int64(1), int64(2), gomock.Any()).DoAndReturn(applyOnCurrent)
+				ts.MockBookRepo.EXPECT().GetAuthorsByName(gomock.Any(), []string{"Eichiro Oda"}).Return(
+					[]model.Author{{ID: 5, Name: "Eiichiro Oda", BirthYear: year(1975)}},
+					nil,
+				)
+			},
+		},
+		{
+			name:   "success json patch with test operation",
+			fields: mockFields,
+			args: args{
+				ctx:     context.Background(),
+				id:      int64(1),
+				version: int64(2),
+				patch: model.BookPatch{
+					ContentType: jsonpatch.JSONPatchType,
+					Data:        []byte(`[{"op":"test","path":"/author","value":"Eichiro Oda"},{"op":"replace","path":"/author","value":"Eiichiro Oda"}]`),
+				},
+			},
+			want: model.Book{
+				ID:          int64(1),
+				Title:       "One Piece",
+				Author:      "Eiichiro Oda",
+				Authors:     []model.BookAuthor{{Name: "Eiichiro Oda", Role: model.AuthorRoleAuthor}},
+				PublishYear: 1997,
+				Published:   &edtf.Date{Year: 1997, Month: 7, Day: 22},
+				Version:     2,
+			},
+			mockFunc: func() {
+				ts.MockBookRepo.EXPECT().PatchBook(gomock.Any(), int64(1), int64(2), gomock.Any()).DoAndReturn(applyOnCurrent)
+			},
+		},
+		{
+			name:   "success merge patch publish year alone drops the month and day",
+			fields: mockFields,
+			args: args{
+				ctx:     context.Background(),
+				id:      int64(1),
+				version: int64(2),
+				patch: model.BookPatch{
+					ContentType: jsonpatch.MergePatchType,
+					Data:        []byte(`{"publish_year":1998}`),
+				},
+			},
+			want: model.Book{
+				ID:          int64(1),
+				Title:       "One Piece",
+				Author:      "Eichiro Oda",
+				Authors:     []model.BookAuthor{{Name: "Eichiro Oda", Role: model.AuthorRoleAuthor}},
+				PublishYear: 1998,
+				Published:   &edtf.Date{Year: 1998},
+				Version:     2,
+			},
+			mockFunc: func() {
+				ts.MockBookRepo.EXPECT().PatchBook(gomock.Any(), int64(1), int64(2), gomock.Any()).DoAndReturn(applyOnCurrent)
+			},
+		},
+		{
+			name:   "success json patch approximate published date",
+			fields: mockFields,
+			args: args{
+				ctx:     context.Background(),
+				id:      int64(1),
+				version: int64(2),
+				patch: model.BookPatch{
+					ContentType: jsonpatch.JSONPatchType,
+					Data:        []byte(`[{"op":"replace","path":"/published","value":"1997-07~"}]`),
+				},
+			},
+			want: model.Book{
+				ID:          int64(1),
+				Title:       "One Piece",
+				Author:      "Eichiro Oda",
+				Authors:     []model.BookAuthor{{Name: "Eichiro Oda", Role: model.AuthorRoleAuthor}},
+				PublishYear: 1997,
+				Published:   &edtf.Date{Year: 1997, Month: 7, Approximate: true},
+				Version:     2,
+			},
+			mockFunc: func() {
+				ts.MockBookRepo.EXPECT().PatchBook(gomock.Any(), int64(1), int64(2), gomock.Any()).DoAndReturn(applyOnCurrent)
+			},
+		},
+		{
+			name:   "failed json patch invalid published date",
+			fields: mockFields,
+			args: args{
+				ctx:     context.Background(),
+				id:      int64(1),
+				version: int64(2),
+				patch: model.BookPatch{
+					ContentType: jsonpatch.JSONPatchType,
+					Data:        []byte(`[{"op":"replace","path":"/published","value":"1997-02-30"}]`),
+				},
+			},
+			want:     model.Book{},
+			wantCode: http.StatusBadRequest,
+			mockFunc: func() {
+				ts.MockBookRepo.EXPECT().PatchBook(gomock.Any(), int64(1), int64(2), gomock.Any()).DoAndReturn(applyOnCurrent)
+			},
+		},
+		{
+			name:   "failed merge patch removing required title",
+			fields: mockFields,
+			args: args{
+				ctx:     context.Background(),
+				id:      int64(1),
+				version: int64(2),
+				patch: model.BookPatch{
+					ContentType: jsonpatch.MergePatchType,
+					Data:        []byte(`{"title":null}`),
+				},
+			},
+			want:     model.Book{},
+			wantCode: http.StatusBadRequest,
+			mockFunc: func() {
+				ts.MockBookRepo.EXPECT().PatchBook(gomock.Any(), int64(1), int64(2), gomock.Any()).DoAndReturn(applyOnCurrent)
+			},
+		},
+		{
+			name:   "failed json patch adding unknown field",
+			fields: mockFields,
+			args: args{
+				ctx:     context.Background(),
+				id:      int64(1),
+				version: int64(2),
+				patch: model.BookPatch{
+					ContentType: jsonpatch.JSONPatchType,
+					Data:        []byte(`[{"op":"add","path":"/id","value":7}]`),
+				},
+			},
+			want:     model.Book{},
+			wantCode: http.StatusBadRequest,
+			mockFunc: func() {
+				ts.MockBookRepo.EXPECT().PatchBook(gomock.Any(), int64(1), int64(2), gomock.Any()).DoAndReturn(applyOnCurrent)
+			},
+		},
+		{
+			name:   "failed json patch test operation",
+			fields: mockFields,
+			args: args{
+				ctx:     context.Background(),
+				id:      int64(1),
+				version: int64(2),
+				patch: model.BookPatch{
+					ContentType: jsonpatch.JSONPatchType,
+					Data:        []byte(`[{"op":"test","path":"/publish_year","value":1999}]`),
+				},
+			},
+			want:     model.Book{},
+			wantCode: http.StatusPreconditionFailed,
+			mockFunc: func() {
+				ts.MockBookRepo.EXPECT().PatchBook(gomock.Any(), int64(1), int64(2), gomock.Any()).DoAndReturn(applyOnCurrent)
+			},
+		},
+		{
+			name:   "failed patch without version",
+			fields: mockFields,
+			args: args{
+				ctx: context.Background(),
+				id:  int64(1),
+				patch: model.BookPatch{
+					ContentType: jsonpatch.MergePatchType,
+					Data:        []byte(`{"author":"Eiichiro Oda"}`),
+				},
+			},
+			want:     model.Book{},
+			wantCode: http.StatusPreconditionRequired,
+			mockFunc: func() {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logic := &BookLogic{
+				deps:                 tt.fields.deps,
+				repo:                 tt.fields.repo,
+				checkAuthorLifetimes: tt.fields.checkAuthorLifetimes,
+			}
+
+			tt.mockFunc()
+
+			got, err := logic.PatchBook(tt.args.ctx, tt.args.id, tt.args.version, tt.args.patch)
+			if (err != nil) != (tt.wantCode != 0) {
+				t.Errorf("BookLogic.PatchBook() error = %v, wantCode %v", err, tt.wantCode)
+				return
+			}
+			if err != nil && xerrors.ParseErrorTypeToCodeInt(err) != tt.wantCode {
+				t.Errorf("BookLogic.PatchBook() error code = %v, want %v", xerrors.ParseErrorTypeToCodeInt(err), tt.wantCode)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BookLogic.PatchBook() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBookLogic_DeleteBook(t *testing.T) {
+	type fields struct {
+		deps *core.Dependency
+		repo RepositoryInterface
+	}
+	type args struct {
+		ctx     context.Context
+		id      int64
+		version int64
+	}
+
+	ts := setupTestSuite(t)
+	mockFields := fields{
+		deps: &core.Dependency{
+			Logger: slog.Default(),
+		},
+		repo: ts.MockBookRepo,
+	}
+
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantErr  bool
+		mockFunc func()
+	}{
+		{
+			name:   "success delete book data",
+			fields: mockFields,
+			args: args{
+				ctx:     context.Background(),
+				id:      int64(1),
+				version: int64(2),
+			},
+			wantErr: false,
+			mockFunc: func() {
+				ts.MockBookRepo.EXPECT().DeleteBook(gomock.Any(), int64(1), int64(2)).Return(nil)
+			},
+		},
+		{
+			name:   "failed delete stale book version",
+			fields: mockFields,
+			args: args{
+				ctx:     context.Background(),
+				id:      int64(1),
+				version: int64(1),
+			},
+			wantErr: true,
+			mockFunc: func() {
+				ts.MockBookRepo.EXPECT().DeleteBook(gomock.Any(), int64(1), int64(1)).Return(xerrors.PreconditionFailedError{Err: xerrors.ErrVersionMismatch})
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logic := &BookLogic{
+				deps: tt.fields.deps,
+				repo: tt.fields.repo,
+			}
+
+			tt.mockFunc()
+
+			if err := logic.DeleteBook(tt.args.ctx, tt.args.id, tt.args.version); (err != nil) != tt.wantErr {
+				t.Errorf("BookLogic.DeleteBook() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBookLogic_GetBookSuggestions(t *testing.T) {
+	type fields struct {
+		deps *core.Dependency
+		repo RepositoryInterface
+	}
+	type args struct {
+		ctx    context.Context
+		params model.BookSuggestParams
+	}
+
+	ts := setupTestSuite(t)
+	mockFields := fields{
+		deps: &core.Dependency{
+			Logger: slog.Default(),
+		},
+		repo: ts.MockBookRepo,
+	}
+
+	expectedResult := []model.BookSuggestion{
+		{Value: "J.R.R. Tolkien", Count: 2},
+	}
+
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		want     []model.BookSuggestion
+		wantErr  bool
+		mockFunc func()
+	}{
+		{
+			name:   "success get author suggestions with default limit",
+			fields: mockFields,
+			args: args{
+				ctx: context.Background(),
+				params: model.BookSuggestParams{
+					Query: " tol ",
+					Field: "author",
+				},
+			},
+			want:    expectedResult,
+			wantErr: false,
+			mockFunc: func() {
+				ts.MockBookRepo.EXPECT().GetBookSuggestions(gomock.Any(), model.BookSuggestParams{
+					Query: "tol",
+					Field: "author",
+					Limit: 5,
+				}).Return(expectedResult, nil)
+			},
+		},
+		{
+			name:   "success get no suggestions when the lookup times out",
+			fields: mockFields,
+			args: args{
+				ctx: context.Background(),
+				params: model.BookSuggestParams{
+					Query: "tol",
+				},
+			},
+			want:    []model.BookSuggestion{},
+			wantErr: false,
+			mockFunc: func() {
+				ts.MockBookRepo.EXPECT().GetBookSuggestions(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, params model.BookSuggestParams) ([]model.BookSuggestion, error) {
+						<-ctx.Done()
+						return nil, errors.New("pq: canceling statement due to user request")
+					})
+			},
+		},
+		{
+			name:   "failed get suggestions with empty query",
+			fields: mockFields,
+			args: args{
+				ctx: context.Background(),
+				params: model.BookSuggestParams{
+					Query: " ",
+				},
+			},
+			want:     []model.BookSuggestion{},
+			wantErr:  true,
+			mockFunc: func() {},
+		},
+		{
+			name:   "failed get suggestions with unknown field",
+			fields: mockFields,
+			args: args{
+				ctx: context.Background(),
+				params: model.BookSuggestParams{
+					Query: "tol",
+					Field: "publish_year",
+				},
+			},
+			want:     []model.BookSuggestion{},
+			wantErr:  true,
+			mockFunc: func() {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logic := &BookLogic{
+				deps: tt.fields.deps,
+				repo: tt.fields.repo,
+			}
+
+			tt.mockFunc()
+
+			got, err := logic.GetBookSuggestions(tt.args.ctx, tt.args.params)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("BookLogic.GetBookSuggestions() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BookLogic.GetBookSuggestions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBookLogic_RestoreBook(t *testing.T) {
 	type fields struct {
 		deps *core.Dependency
 		repo RepositoryInterface
@@ -334,24 +1385,44 @@ func TestBookLogic_DeleteBook(t *testing.T) {
 		repo: ts.MockBookRepo,
 	}
 
+	expectedResult := model.Book{
+		ID:          int64(1),
+		Title:       "One Piece",
+		Author:      "Eiichiro Oda",
+		PublishYear: 1997,
+	}
+
 	tests := []struct {
 		name     string
 		fields   fields
 		args     args
+		want     model.Book
 		wantErr  bool
 		mockFunc func()
 	}{
 		{
-			name:   "success delete book data",
+			name:   "success restore book data",
 			fields: mockFields,
 			args: args{
 				ctx: context.Background(),
 				id:  int64(1),
 			},
+			want:    expectedResult,
 			wantErr: false,
 			mockFunc: func() {
-				ts.MockBookRepo.EXPECT().DeleteBook(gomock.Any(), int64(1)).Return(nil)
+				ts.MockBookRepo.EXPECT().RestoreBook(gomock.Any(), int64(1)).Return(expectedResult, nil)
+			},
+		},
+		{
+			name:   "failed restore book data with invalid id",
+			fields: mockFields,
+			args: args{
+				ctx: context.Background(),
+				id:  int64(0),
 			},
+			want:     model.Book{},
+			wantErr:  true,
+			mockFunc: func() {},
 		},
 	}
 	for _, tt := range tests {
@@ -363,8 +1434,359 @@ func TestBookLogic_DeleteBook(t *testing.T) {
 
 			tt.mockFunc()
 
-			if err := logic.DeleteBook(tt.args.ctx, tt.args.id); (err != nil) != tt.wantErr {
-				t.Errorf("BookLogic.DeleteBook() error = %v, wantErr %v", err, tt.wantErr)
+			got, err := logic.RestoreBook(tt.args.ctx, tt.args.id)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("BookLogic.RestoreBook() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BookLogic.RestoreBook() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBookLogic_SetBookIdentifiers(t *testing.T) {
+	type fields struct {
+		deps *core.Dependency
+		repo RepositoryInterface
+	}
+	type args struct {
+		ctx         context.Context
+		bookID      int64
+		identifiers []model.BookIdentifier
+	}
+
+	ts := setupTestSuite(t)
+	mockFields := fields{
+		deps: &core.Dependency{
+			Logger: slog.Default(),
+		},
+		repo: ts.MockBookRepo,
+	}
+
+	normalized := []model.BookIdentifier{
+		{Scheme: "lccn", Value: "n78890351"},
+		{Scheme: "oclc", Value: "12345"},
+		{Scheme: "openlibrary", Value: "OL27482W"},
+	}
+
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		want     []model.BookIdentifier
+		wantErr  bool
+		mockFunc func()
+	}{
+		{
+			name:   "success set book identifiers normalized and deduplicated",
+			fields: mockFields,
+			args: args{
+				ctx:    context.Background(),
+				bookID: 8,
+				identifiers: []model.BookIdentifier{
+					{Scheme: "openlibrary", Value: "ol27482w"},
+					{Scheme: "OCLC", Value: "ocm00012345"},
+					{Scheme: "lccn", Value: "n78-890351"},
+					{Scheme: "oclc", Value: "12345"},
+				},
+			},
+			want: normalized,
+			mockFunc: func() {
+				ts.MockBookRepo.EXPECT().SetBookIdentifiers(gomock.Any(), int64(8), normalized).Return(normalized, nil)
+			},
+		},
+		{
+			name:   "success clear book identifiers",
+			fields: mockFields,
+			args: args{
+				ctx:         context.Background(),
+				bookID:      8,
+				identifiers: nil,
+			},
+			want: []model.BookIdentifier{},
+			mockFunc: func() {
+				ts.MockBookRepo.EXPECT().SetBookIdentifiers(gomock.Any(), int64(8), []model.BookIdentifier{}).Return([]model.BookIdentifier{}, nil)
+			},
+		},
+		{
+			name:   "failed set book identifiers with invalid format",
+			fields: mockFields,
+			args: args{
+				ctx:         context.Background(),
+				bookID:      8,
+				identifiers: []model.BookIdentifier{{Scheme: "goodreads", Value: "5907.The_Hobbit"}},
+			},
+			want:     nil,
+			wantErr:  true,
+			mockFunc: func() {},
+		},
+		{
+			name:   "failed set book identifiers with unknown scheme",
+			fields: mockFields,
+			args: args{
+				ctx:         context.Background(),
+				bookID:      8,
+				identifiers: []model.BookIdentifier{{Scheme: "asin", Value: "B000FC1PJI"}},
+			},
+			want:     nil,
+			wantErr:  true,
+			mockFunc: func() {},
+		},
+		{
+			name:   "failed set book identifiers with invalid id",
+			fields: mockFields,
+			args: args{
+				ctx:         context.Background(),
+				bookID:      0,
+				identifiers: normalized,
+			},
+			want:     nil,
+			wantErr:  true,
+			mockFunc: func() {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logic := &BookLogic{
+				deps: tt.fields.deps,
+				repo: tt.fields.repo,
+			}
+
+			tt.mockFunc()
+
+			got, err := logic.SetBookIdentifiers(tt.args.ctx, tt.args.bookID, tt.args.identifiers)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("BookLogic.SetBookIdentifiers() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BookLogic.SetBookIdentifiers() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBookLogic_BatchBooks(t *testing.T) {
+	type fields struct {
+		deps                 *core.Dependency
+		repo                 RepositoryInterface
+		checkAuthorLifetimes bool
+	}
+	type args struct {
+		ctx   context.Context
+		ops   []model.BookBatchOperation
+		force bool
+	}
+
+	ts := setupTestSuite(t)
+	mockFields := fields{
+		deps: &core.Dependency{
+			Logger: slog.Default(),
+		},
+		repo: ts.MockBookRepo,
+	}
+	lifetimeFields := mockFields
+	lifetimeFields.checkAuthorLifetimes = true
+
+	validOps := []model.BookBatchOperation{
+		{Op: model.BatchOpCreate, Title: "One Piece", Author: "Eiichiro Oda", PublishYear: 1997},
+		{Op: model.BatchOpDelete, ID: 3, Version: 1},
+	}
+	appliedResults := []model.BookBatchResult{
+		{Index: 0, Op: model.BatchOpCreate, ID: 11, Status: model.BatchStatusApplied},
+		{Index: 1, Op: model.BatchOpDelete, ID: 3, Status: model.BatchStatusApplied},
+	}
+
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		want     []model.BookBatchResult
+		wantErr  bool
+		mockFunc func()
+	}{
+		{
+			name:   "success apply valid batch",
+			fields: mockFields,
+			args: args{
+				ctx: context.Background(),
+				ops: validOps,
+			},
+			want: appliedResults,
+			mockFunc: func() {
+				ts.MockBookRepo.EXPECT().GetDuplicateCandidates(gomock.Any(), gomock.Any()).Return(nil, nil)
+				ts.MockBookRepo.EXPECT().BatchBooks(gomock.Any(), validOps).Return(appliedResults, nil)
+			},
+		},
+		{
+			name:   "failed batch creating a book probably already stored is not applied",
+			fields: mockFields,
+			args: args{
+				ctx: context.Background(),
+				ops: []model.BookBatchOperation{
+					{Op: model.BatchOpCreate, Title: "One Piece", Author: "Eiichiro Oda", PublishYear: 1997},
+					{Op: model.BatchOpDelete, ID: 3, Version: 1},
+				},
+			},
+			want: []model.BookBatchResult{
+				{
+					Index:  0,
+					Op:     model.BatchOpCreate,
+					Status: model.BatchStatusFailed,
+					Error:  "book 8 probably is the same book, store it with force=true if it is not",
+					Matches: []model.BookMatch{
+						{Book: model.BookRef{ID: 8, Title: "One Piece", Author: "Eiichiro Oda", PublishYear: 1997}, Score: 1},
+					},
+				},
+				{Index: 1, Op: model.BatchOpDelete, ID: 3, Status: model.BatchStatusSkipped},
+			},
+			wantErr: true,
+			mockFunc: func() {
+				ts.MockBookRepo.EXPECT().GetDuplicateCandidates(gomock.Any(), gomock.Any()).Return([]model.BookRef{
+					{ID: 8, Title: "One Piece", Author: "Eiichiro Oda", PublishYear: 1997},
+				}, nil)
+			},
+		},
+		{
+			name:   "success batch creating a book probably already stored with force",
+			fields: mockFields,
+			args: args{
+				ctx:   context.Background(),
+				ops:   validOps,
+				force: true,
+			},
+			want: appliedResults,
+			mockFunc: func() {
+				ts.MockBookRepo.EXPECT().BatchBooks(gomock.Any(), validOps).Return(appliedResults, nil)
+			},
+		},
+		{
+			name:   "failed batch with invalid operations is not applied",
+			fields: mockFields,
+			args: args{
+				ctx: context.Background(),
+				ops: []model.BookBatchOperation{
+					{Op: model.BatchOpCreate, Title: "One Piece", Author: "Eiichiro Oda", PublishYear: 1997},
+					{Op: model.BatchOpUpdate, ID: 3, Title: "Emma", Author: "Jane Austen", PublishYear: 1815},
+					{Op: "rename", ID: 4},
+				},
+			},
+			want: []model.BookBatchResult{
+				{Index: 0, Op: model.BatchOpCreate, Status: model.BatchStatusSkipped},
+				{Index: 1, Op: model.BatchOpUpdate, ID: 3, Status: model.BatchStatusFailed, Error: "version is required"},
+				{Index: 2, Op: "rename", ID: 4, Status: model.BatchStatusFailed, Error: "unknown batch op: rename"},
+			},
+			wantErr: true,
+			mockFunc: func() {
+				ts.MockBookRepo.EXPECT().GetBooksNoPagination(gomock.Any(), model.BookSearchParams{IDs: []int64{3}}).Return(nil, nil)
+				ts.MockBookRepo.EXPECT().GetDuplicateCandidates(gomock.Any(), gomock.Any()).Return(nil, nil)
+			},
+		},
+		{
+			name:   "success batch update of the publish year alone keeps the stored date",
+			fields: mockFields,
+			args: args{
+				ctx: context.Background(),
+				ops: []model.BookBatchOperation{
+					{Op: model.BatchOpUpdate, ID: 6, Version: 1, Title: "Ulysses", Author: "James Joyce", PublishYear: 1922},
+				},
+			},
+			want: []model.BookBatchResult{
+				{Index: 0, Op: model.BatchOpUpdate, ID: 6, Status: model.BatchStatusApplied},
+			},
+			mockFunc: func() {
+				published := &edtf.Date{Year: 1922, Month: 2, Day: 2}
+				credits := []model.BookAuthor{{Name: "James Joyce", Role: model.AuthorRoleAuthor}}
+				ts.MockBookRepo.EXPECT().GetBooksNoPagination(gomock.Any(), model.BookSearchParams{IDs: []int64{6}}).Return([]model.Book{
+					{ID: 6, Title: "Ulysses", Author: "James Joyce", Authors: credits, PublishYear: 1922, Published: published, Version: 1},
+				}, nil)
+				ts.MockBookRepo.EXPECT().BatchBooks(gomock.Any(), []model.BookBatchOperation{
+					{Op: model.BatchOpUpdate, ID: 6, Version: 1, Title: "Ulysses", Author: "James Joyce", Authors: credits, PublishYear: 1922, Published: published},
+				}).Return([]model.BookBatchResult{
+					{Index: 0, Op: model.BatchOpUpdate, ID: 6, Status: model.BatchStatusApplied},
+				}, nil)
+			},
+		},
+		{
+			name:   "success batch update of the author string keeps the stored credits",
+			fields: mockFields,
+			args: args{
+				ctx: context.Background(),
+				ops: []model.BookBatchOperation{
+					{Op: model.BatchOpUpdate, ID: 5, Version: 2, Title: "The Sandman", Author: "Neil Gaiman", PublishYear: 1989},
+				},
+			},
+			want: []model.BookBatchResult{
+				{Index: 0, Op: model.BatchOpUpdate, ID: 5, Status: model.BatchStatusApplied},
+			},
+			mockFunc: func() {
+				credits := []model.BookAuthor{
+					{Name: "Neil Gaiman", Role: model.AuthorRoleAuthor},
+					{Name: "Karen Berger", Role: model.AuthorRoleEditor},
+				}
+				ts.MockBookRepo.EXPECT().GetBooksNoPagination(gomock.Any(), model.BookSearchParams{IDs: []int64{5}}).Return([]model.Book{
+					{ID: 5, Title: "The Sandman", Author: "Neil Gaiman", Authors: credits, PublishYear: 1989, Version: 2},
+				}, nil)
+				ts.MockBookRepo.EXPECT().BatchBooks(gomock.Any(), []model.BookBatchOperation{
+					{Op: model.BatchOpUpdate, ID: 5, Version: 2, Title: "The Sandman", Author: "Neil Gaiman", Authors: credits, PublishYear: 1989, Published: &edtf.Date{Year: 1989}},
+				}).Return([]model.BookBatchResult{
+					{Index: 0, Op: model.BatchOpUpdate, ID: 5, Status: model.BatchStatusApplied},
+				}, nil)
+			},
+		},
+		{
+			name:   "failed batch with an implausible publish year is not applied",
+			fields: lifetimeFields,
+			args: args{
+				ctx: context.Background(),
+				ops: []model.BookBatchOperation{
+					{Op: model.BatchOpUpdate, ID: 3, Version: 2, Title: "Emma", Author: "Jane Austen", PublishYear: 1950},
+					{Op: model.BatchOpDelete, ID: 4, Version: 1},
+				},
+			},
+			want: []model.BookBatchResult{
+				{Index: 0, Op: model.BatchOpUpdate, ID: 3, Status: model.BatchStatusFailed, Error: "publish year 1950 is more than 100 years after Jane Austen died in 1817"},
+				{Index: 1, Op: model.BatchOpDelete, ID: 4, Status: model.BatchStatusSkipped},
+			},
+			wantErr: true,
+			mockFunc: func() {
+				ts.MockBookRepo.EXPECT().GetBooksNoPagination(gomock.Any(), model.BookSearchParams{IDs: []int64{3}}).Return(nil, nil)
+				ts.MockBookRepo.EXPECT().GetAuthorsByName(gomock.Any(), []string{"Jane Austen"}).Return(
+					[]model.Author{{ID: 3, Name: "Jane Austen", BirthYear: year(1775), DeathYear: year(1817)}},
+					nil,
+				)
+			},
+		},
+		{
+			name:   "failed empty batch",
+			fields: mockFields,
+			args: args{
+				ctx: context.Background(),
+			},
+			want:     []model.BookBatchResult{},
+			wantErr:  true,
+			mockFunc: func() {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logic := &BookLogic{
+				deps:                 tt.fields.deps,
+				repo:                 tt.fields.repo,
+				checkAuthorLifetimes: tt.fields.checkAuthorLifetimes,
+			}
+
+			tt.mockFunc()
+
+			got, err := logic.BatchBooks(tt.args.ctx, tt.args.ops, tt.args.force)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("BookLogic.BatchBooks() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BookLogic.BatchBooks() = %v, want %v", got, tt.want)
 			}
 		})
 	}
@@ -13,7 +13,9 @@ import (
 	model "byfood-app/internal/model"
 	pagination "byfood-app/internal/pkg/pagination"
 	context "context"
+	io "io"
 	reflect "reflect"
+	time "time"
 
 	gomock "go.uber.org/mock/gomock"
 )
@@ -42,18 +44,78 @@ func (m *MockRepositoryInterface) EXPECT() *MockRepositoryInterfaceMockRecorder
 	return m.recorder
 }
 
+// BatchBooks mocks base method.
+func (m *MockRepositoryInterface) BatchBooks(ctx context.Context, ops []model.BookBatchOperation) ([]model.BookBatchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchBooks", ctx, ops)
+	ret0, _ := ret[0].([]model.BookBatchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchBooks indicates an expected call of BatchBooks.
+func (mr *MockRepositoryInterfaceMockRecorder) BatchBooks(ctx, ops any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchBooks", reflect.TypeOf((*MockRepositoryInterface)(nil).BatchBooks), ctx, ops)
+}
+
+// CountPurgeableBooks mocks base method.
+func (m *MockRepositoryInterface) CountPurgeableBooks(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountPurgeableBooks", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountPurgeableBooks indicates an expected call of CountPurgeableBooks.
+func (mr *MockRepositoryInterfaceMockRecorder) CountPurgeableBooks(ctx, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountPurgeableBooks", reflect.TypeOf((*MockRepositoryInterface)(nil).CountPurgeableBooks), ctx, before)
+}
+
 // DeleteBook mocks base method.
-func (m *MockRepositoryInterface) DeleteBook(ctx context.Context, id int64) error {
+func (m *MockRepositoryInterface) DeleteBook(ctx context.Context, id, version int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteBook", ctx, id)
+	ret := m.ctrl.Call(m, "DeleteBook", ctx, id, version)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteBook indicates an expected call of DeleteBook.
-func (mr *MockRepositoryInterfaceMockRecorder) DeleteBook(ctx, id any) *gomock.Call {
+func (mr *MockRepositoryInterfaceMockRecorder) DeleteBook(ctx, id, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBook", reflect.TypeOf((*MockRepositoryInterface)(nil).DeleteBook), ctx, id, version)
+}
+
+// GetAuthorsByName mocks base method.
+func (m *MockRepositoryInterface) GetAuthorsByName(ctx context.Context, names []string) ([]model.Author, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuthorsByName", ctx, names)
+	ret0, _ := ret[0].([]model.Author)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuthorsByName indicates an expected call of GetAuthorsByName.
+func (mr *MockRepositoryInterfaceMockRecorder) GetAuthorsByName(ctx, names any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBook", reflect.TypeOf((*MockRepositoryInterface)(nil).DeleteBook), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthorsByName", reflect.TypeOf((*MockRepositoryInterface)(nil).GetAuthorsByName), ctx, names)
+}
+
+// GetBookAsOf mocks base method.
+func (m *MockRepositoryInterface) GetBookAsOf(ctx context.Context, id int64, asOf time.Time) (model.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookAsOf", ctx, id, asOf)
+	ret0, _ := ret[0].(model.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookAsOf indicates an expected call of GetBookAsOf.
+func (mr *MockRepositoryInterfaceMockRecorder) GetBookAsOf(ctx, id, asOf any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookAsOf", reflect.TypeOf((*MockRepositoryInterface)(nil).GetBookAsOf), ctx, id, asOf)
 }
 
 // GetBookByID mocks base method.
@@ -71,12 +133,87 @@ func (mr *MockRepositoryInterfaceMockRecorder) GetBookByID(ctx, id any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookByID", reflect.TypeOf((*MockRepositoryInterface)(nil).GetBookByID), ctx, id)
 }
 
+// GetBookByISBN mocks base method.
+func (m *MockRepositoryInterface) GetBookByISBN(ctx context.Context, isbn string) (model.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookByISBN", ctx, isbn)
+	ret0, _ := ret[0].(model.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookByISBN indicates an expected call of GetBookByISBN.
+func (mr *MockRepositoryInterfaceMockRecorder) GetBookByISBN(ctx, isbn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookByISBN", reflect.TypeOf((*MockRepositoryInterface)(nil).GetBookByISBN), ctx, isbn)
+}
+
+// GetBookDuplicates mocks base method.
+func (m *MockRepositoryInterface) GetBookDuplicates(ctx context.Context) ([]model.BookDuplicate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookDuplicates", ctx)
+	ret0, _ := ret[0].([]model.BookDuplicate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookDuplicates indicates an expected call of GetBookDuplicates.
+func (mr *MockRepositoryInterfaceMockRecorder) GetBookDuplicates(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookDuplicates", reflect.TypeOf((*MockRepositoryInterface)(nil).GetBookDuplicates), ctx)
+}
+
+// GetBookRevision mocks base method.
+func (m *MockRepositoryInterface) GetBookRevision(ctx context.Context, bookID, revision int64) (model.BookRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookRevision", ctx, bookID, revision)
+	ret0, _ := ret[0].(model.BookRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookRevision indicates an expected call of GetBookRevision.
+func (mr *MockRepositoryInterfaceMockRecorder) GetBookRevision(ctx, bookID, revision any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookRevision", reflect.TypeOf((*MockRepositoryInterface)(nil).GetBookRevision), ctx, bookID, revision)
+}
+
+// GetBookRevisions mocks base method.
+func (m *MockRepositoryInterface) GetBookRevisions(ctx context.Context, bookID int64) ([]model.BookRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookRevisions", ctx, bookID)
+	ret0, _ := ret[0].([]model.BookRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookRevisions indicates an expected call of GetBookRevisions.
+func (mr *MockRepositoryInterfaceMockRecorder) GetBookRevisions(ctx, bookID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookRevisions", reflect.TypeOf((*MockRepositoryInterface)(nil).GetBookRevisions), ctx, bookID)
+}
+
+// GetBookSuggestions mocks base method.
+func (m *MockRepositoryInterface) GetBookSuggestions(ctx context.Context, params model.BookSuggestParams) ([]model.BookSuggestion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookSuggestions", ctx, params)
+	ret0, _ := ret[0].([]model.BookSuggestion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookSuggestions indicates an expected call of GetBookSuggestions.
+func (mr *MockRepositoryInterfaceMockRecorder) GetBookSuggestions(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookSuggestions", reflect.TypeOf((*MockRepositoryInterface)(nil).GetBookSuggestions), ctx, params)
+}
+
 // GetBooks mocks base method.
-func (m *MockRepositoryInterface) GetBooks(ctx context.Context, params model.BookSearchParams, page pagination.Page) ([]model.Book, pagination.Metadata, error) {
+func (m *MockRepositoryInterface) GetBooks(ctx context.Context, params model.BookSearchParams, page pagination.CursorPage) ([]model.Book, pagination.CursorMetadata, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetBooks", ctx, params, page)
 	ret0, _ := ret[0].([]model.Book)
-	ret1, _ := ret[1].(pagination.Metadata)
+	ret1, _ := ret[1].(pagination.CursorMetadata)
 	ret2, _ := ret[2].(error)
 	return ret0, ret1, ret2
 }
@@ -102,6 +239,126 @@ func (mr *MockRepositoryInterfaceMockRecorder) GetBooksNoPagination(ctx, params
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBooksNoPagination", reflect.TypeOf((*MockRepositoryInterface)(nil).GetBooksNoPagination), ctx, params)
 }
 
+// GetDuplicateCandidates mocks base method.
+func (m *MockRepositoryInterface) GetDuplicateCandidates(ctx context.Context, data model.Book) ([]model.BookRef, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDuplicateCandidates", ctx, data)
+	ret0, _ := ret[0].([]model.BookRef)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDuplicateCandidates indicates an expected call of GetDuplicateCandidates.
+func (mr *MockRepositoryInterfaceMockRecorder) GetDuplicateCandidates(ctx, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDuplicateCandidates", reflect.TypeOf((*MockRepositoryInterface)(nil).GetDuplicateCandidates), ctx, data)
+}
+
+// GetStoredISBNs mocks base method.
+func (m *MockRepositoryInterface) GetStoredISBNs(ctx context.Context, isbns []string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStoredISBNs", ctx, isbns)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStoredISBNs indicates an expected call of GetStoredISBNs.
+func (mr *MockRepositoryInterfaceMockRecorder) GetStoredISBNs(ctx, isbns any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStoredISBNs", reflect.TypeOf((*MockRepositoryInterface)(nil).GetStoredISBNs), ctx, isbns)
+}
+
+// MergeBooks mocks base method.
+func (m *MockRepositoryInterface) MergeBooks(ctx context.Context, id int64, sourceIDs []int64, asEditions bool) (model.BookMerge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergeBooks", ctx, id, sourceIDs, asEditions)
+	ret0, _ := ret[0].(model.BookMerge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MergeBooks indicates an expected call of MergeBooks.
+func (mr *MockRepositoryInterfaceMockRecorder) MergeBooks(ctx, id, sourceIDs, asEditions any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeBooks", reflect.TypeOf((*MockRepositoryInterface)(nil).MergeBooks), ctx, id, sourceIDs, asEditions)
+}
+
+// PatchBook mocks base method.
+func (m *MockRepositoryInterface) PatchBook(ctx context.Context, id, version int64, apply func(model.Book) (model.Book, error)) (model.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchBook", ctx, id, version, apply)
+	ret0, _ := ret[0].(model.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchBook indicates an expected call of PatchBook.
+func (mr *MockRepositoryInterfaceMockRecorder) PatchBook(ctx, id, version, apply any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchBook", reflect.TypeOf((*MockRepositoryInterface)(nil).PatchBook), ctx, id, version, apply)
+}
+
+// PurgeDeletedBooks mocks base method.
+func (m *MockRepositoryInterface) PurgeDeletedBooks(ctx context.Context, before time.Time, limit int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeletedBooks", ctx, before, limit)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeDeletedBooks indicates an expected call of PurgeDeletedBooks.
+func (mr *MockRepositoryInterfaceMockRecorder) PurgeDeletedBooks(ctx, before, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeletedBooks", reflect.TypeOf((*MockRepositoryInterface)(nil).PurgeDeletedBooks), ctx, before, limit)
+}
+
+// RestoreBook mocks base method.
+func (m *MockRepositoryInterface) RestoreBook(ctx context.Context, id int64) (model.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreBook", ctx, id)
+	ret0, _ := ret[0].(model.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreBook indicates an expected call of RestoreBook.
+func (mr *MockRepositoryInterfaceMockRecorder) RestoreBook(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreBook", reflect.TypeOf((*MockRepositoryInterface)(nil).RestoreBook), ctx, id)
+}
+
+// RevertBook mocks base method.
+func (m *MockRepositoryInterface) RevertBook(ctx context.Context, id, version, snapshot int64, apply func(model.Book, model.Book) (model.Book, error)) (model.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevertBook", ctx, id, version, snapshot, apply)
+	ret0, _ := ret[0].(model.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevertBook indicates an expected call of RevertBook.
+func (mr *MockRepositoryInterfaceMockRecorder) RevertBook(ctx, id, version, snapshot, apply any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevertBook", reflect.TypeOf((*MockRepositoryInterface)(nil).RevertBook), ctx, id, version, snapshot, apply)
+}
+
+// SetBookIdentifiers mocks base method.
+func (m *MockRepositoryInterface) SetBookIdentifiers(ctx context.Context, bookID int64, identifiers []model.BookIdentifier) ([]model.BookIdentifier, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetBookIdentifiers", ctx, bookID, identifiers)
+	ret0, _ := ret[0].([]model.BookIdentifier)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetBookIdentifiers indicates an expected call of SetBookIdentifiers.
+func (mr *MockRepositoryInterfaceMockRecorder) SetBookIdentifiers(ctx, bookID, identifiers any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBookIdentifiers", reflect.TypeOf((*MockRepositoryInterface)(nil).SetBookIdentifiers), ctx, bookID, identifiers)
+}
+
 // StoreBook mocks base method.
 func (m *MockRepositoryInterface) StoreBook(ctx context.Context, data model.Book) (model.Book, error) {
 	m.ctrl.T.Helper()
@@ -117,6 +374,50 @@ func (mr *MockRepositoryInterfaceMockRecorder) StoreBook(ctx, data any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreBook", reflect.TypeOf((*MockRepositoryInterface)(nil).StoreBook), ctx, data)
 }
 
+// StoreBooks mocks base method.
+func (m *MockRepositoryInterface) StoreBooks(ctx context.Context, data []model.Book) ([]model.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StoreBooks", ctx, data)
+	ret0, _ := ret[0].([]model.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StoreBooks indicates an expected call of StoreBooks.
+func (mr *MockRepositoryInterfaceMockRecorder) StoreBooks(ctx, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreBooks", reflect.TypeOf((*MockRepositoryInterface)(nil).StoreBooks), ctx, data)
+}
+
+// StreamBooks mocks base method.
+func (m *MockRepositoryInterface) StreamBooks(ctx context.Context, params model.BookSearchParams, fn func(model.Book) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamBooks", ctx, params, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamBooks indicates an expected call of StreamBooks.
+func (mr *MockRepositoryInterfaceMockRecorder) StreamBooks(ctx, params, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamBooks", reflect.TypeOf((*MockRepositoryInterface)(nil).StreamBooks), ctx, params, fn)
+}
+
+// SuggestSearchTerm mocks base method.
+func (m *MockRepositoryInterface) SuggestSearchTerm(ctx context.Context, search string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SuggestSearchTerm", ctx, search)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SuggestSearchTerm indicates an expected call of SuggestSearchTerm.
+func (mr *MockRepositoryInterfaceMockRecorder) SuggestSearchTerm(ctx, search any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SuggestSearchTerm", reflect.TypeOf((*MockRepositoryInterface)(nil).SuggestSearchTerm), ctx, search)
+}
+
 // UpdateBook mocks base method.
 func (m *MockRepositoryInterface) UpdateBook(ctx context.Context, data model.Book) (model.Book, error) {
 	m.ctrl.T.Helper()
@@ -156,18 +457,62 @@ func (m *MockLogicInterface) EXPECT() *MockLogicInterfaceMockRecorder {
 	return m.recorder
 }
 
+// BatchBooks mocks base method.
+func (m *MockLogicInterface) BatchBooks(ctx context.Context, ops []model.BookBatchOperation, force bool) ([]model.BookBatchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchBooks", ctx, ops, force)
+	ret0, _ := ret[0].([]model.BookBatchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchBooks indicates an expected call of BatchBooks.
+func (mr *MockLogicInterfaceMockRecorder) BatchBooks(ctx, ops, force any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchBooks", reflect.TypeOf((*MockLogicInterface)(nil).BatchBooks), ctx, ops, force)
+}
+
 // DeleteBook mocks base method.
-func (m *MockLogicInterface) DeleteBook(ctx context.Context, id int64) error {
+func (m *MockLogicInterface) DeleteBook(ctx context.Context, id, version int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteBook", ctx, id)
+	ret := m.ctrl.Call(m, "DeleteBook", ctx, id, version)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteBook indicates an expected call of DeleteBook.
-func (mr *MockLogicInterfaceMockRecorder) DeleteBook(ctx, id any) *gomock.Call {
+func (mr *MockLogicInterfaceMockRecorder) DeleteBook(ctx, id, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBook", reflect.TypeOf((*MockLogicInterface)(nil).DeleteBook), ctx, id, version)
+}
+
+// ExportBooks mocks base method.
+func (m *MockLogicInterface) ExportBooks(ctx context.Context, params model.BookSearchParams, fn func(model.Book) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportBooks", ctx, params, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportBooks indicates an expected call of ExportBooks.
+func (mr *MockLogicInterfaceMockRecorder) ExportBooks(ctx, params, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportBooks", reflect.TypeOf((*MockLogicInterface)(nil).ExportBooks), ctx, params, fn)
+}
+
+// GetBookAsOf mocks base method.
+func (m *MockLogicInterface) GetBookAsOf(ctx context.Context, id int64, asOf time.Time) (model.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookAsOf", ctx, id, asOf)
+	ret0, _ := ret[0].(model.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookAsOf indicates an expected call of GetBookAsOf.
+func (mr *MockLogicInterfaceMockRecorder) GetBookAsOf(ctx, id, asOf any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBook", reflect.TypeOf((*MockLogicInterface)(nil).DeleteBook), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookAsOf", reflect.TypeOf((*MockLogicInterface)(nil).GetBookAsOf), ctx, id, asOf)
 }
 
 // GetBookByID mocks base method.
@@ -185,12 +530,102 @@ func (mr *MockLogicInterfaceMockRecorder) GetBookByID(ctx, id any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookByID", reflect.TypeOf((*MockLogicInterface)(nil).GetBookByID), ctx, id)
 }
 
+// GetBookByISBN mocks base method.
+func (m *MockLogicInterface) GetBookByISBN(ctx context.Context, isbn string) (model.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookByISBN", ctx, isbn)
+	ret0, _ := ret[0].(model.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookByISBN indicates an expected call of GetBookByISBN.
+func (mr *MockLogicInterfaceMockRecorder) GetBookByISBN(ctx, isbn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookByISBN", reflect.TypeOf((*MockLogicInterface)(nil).GetBookByISBN), ctx, isbn)
+}
+
+// GetBookDuplicates mocks base method.
+func (m *MockLogicInterface) GetBookDuplicates(ctx context.Context) ([]model.BookDuplicate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookDuplicates", ctx)
+	ret0, _ := ret[0].([]model.BookDuplicate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookDuplicates indicates an expected call of GetBookDuplicates.
+func (mr *MockLogicInterfaceMockRecorder) GetBookDuplicates(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookDuplicates", reflect.TypeOf((*MockLogicInterface)(nil).GetBookDuplicates), ctx)
+}
+
+// GetBookHistory mocks base method.
+func (m *MockLogicInterface) GetBookHistory(ctx context.Context, bookID int64) ([]model.BookRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookHistory", ctx, bookID)
+	ret0, _ := ret[0].([]model.BookRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookHistory indicates an expected call of GetBookHistory.
+func (mr *MockLogicInterfaceMockRecorder) GetBookHistory(ctx, bookID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookHistory", reflect.TypeOf((*MockLogicInterface)(nil).GetBookHistory), ctx, bookID)
+}
+
+// GetBookRevision mocks base method.
+func (m *MockLogicInterface) GetBookRevision(ctx context.Context, bookID, revision int64) (model.BookRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookRevision", ctx, bookID, revision)
+	ret0, _ := ret[0].(model.BookRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookRevision indicates an expected call of GetBookRevision.
+func (mr *MockLogicInterfaceMockRecorder) GetBookRevision(ctx, bookID, revision any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookRevision", reflect.TypeOf((*MockLogicInterface)(nil).GetBookRevision), ctx, bookID, revision)
+}
+
+// GetBookSnapshots mocks base method.
+func (m *MockLogicInterface) GetBookSnapshots(ctx context.Context, bookID int64) ([]model.BookSnapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookSnapshots", ctx, bookID)
+	ret0, _ := ret[0].([]model.BookSnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookSnapshots indicates an expected call of GetBookSnapshots.
+func (mr *MockLogicInterfaceMockRecorder) GetBookSnapshots(ctx, bookID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookSnapshots", reflect.TypeOf((*MockLogicInterface)(nil).GetBookSnapshots), ctx, bookID)
+}
+
+// GetBookSuggestions mocks base method.
+func (m *MockLogicInterface) GetBookSuggestions(ctx context.Context, params model.BookSuggestParams) ([]model.BookSuggestion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookSuggestions", ctx, params)
+	ret0, _ := ret[0].([]model.BookSuggestion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookSuggestions indicates an expected call of GetBookSuggestions.
+func (mr *MockLogicInterfaceMockRecorder) GetBookSuggestions(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookSuggestions", reflect.TypeOf((*MockLogicInterface)(nil).GetBookSuggestions), ctx, params)
+}
+
 // GetBooks mocks base method.
-func (m *MockLogicInterface) GetBooks(ctx context.Context, params model.BookSearchParams, page pagination.Page) ([]model.Book, pagination.Metadata, error) {
+func (m *MockLogicInterface) GetBooks(ctx context.Context, params model.BookSearchParams, page pagination.CursorPage) ([]model.Book, model.BookListMetadata, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetBooks", ctx, params, page)
 	ret0, _ := ret[0].([]model.Book)
-	ret1, _ := ret[1].(pagination.Metadata)
+	ret1, _ := ret[1].(model.BookListMetadata)
 	ret2, _ := ret[2].(error)
 	return ret0, ret1, ret2
 }
@@ -201,19 +636,124 @@ func (mr *MockLogicInterfaceMockRecorder) GetBooks(ctx, params, page any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBooks", reflect.TypeOf((*MockLogicInterface)(nil).GetBooks), ctx, params, page)
 }
 
+// GetBooksNoPagination mocks base method.
+func (m *MockLogicInterface) GetBooksNoPagination(ctx context.Context, params model.BookSearchParams) ([]model.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBooksNoPagination", ctx, params)
+	ret0, _ := ret[0].([]model.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBooksNoPagination indicates an expected call of GetBooksNoPagination.
+func (mr *MockLogicInterfaceMockRecorder) GetBooksNoPagination(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBooksNoPagination", reflect.TypeOf((*MockLogicInterface)(nil).GetBooksNoPagination), ctx, params)
+}
+
+// ImportBooks mocks base method.
+func (m *MockLogicInterface) ImportBooks(ctx context.Context, params model.BookImportParams, body io.Reader) (model.BookImportReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportBooks", ctx, params, body)
+	ret0, _ := ret[0].(model.BookImportReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportBooks indicates an expected call of ImportBooks.
+func (mr *MockLogicInterfaceMockRecorder) ImportBooks(ctx, params, body any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportBooks", reflect.TypeOf((*MockLogicInterface)(nil).ImportBooks), ctx, params, body)
+}
+
+// MergeBooks mocks base method.
+func (m *MockLogicInterface) MergeBooks(ctx context.Context, id int64, sourceIDs []int64, asEditions bool) (model.BookMerge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergeBooks", ctx, id, sourceIDs, asEditions)
+	ret0, _ := ret[0].(model.BookMerge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MergeBooks indicates an expected call of MergeBooks.
+func (mr *MockLogicInterfaceMockRecorder) MergeBooks(ctx, id, sourceIDs, asEditions any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeBooks", reflect.TypeOf((*MockLogicInterface)(nil).MergeBooks), ctx, id, sourceIDs, asEditions)
+}
+
+// PatchBook mocks base method.
+func (m *MockLogicInterface) PatchBook(ctx context.Context, id, version int64, patch model.BookPatch) (model.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchBook", ctx, id, version, patch)
+	ret0, _ := ret[0].(model.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchBook indicates an expected call of PatchBook.
+func (mr *MockLogicInterfaceMockRecorder) PatchBook(ctx, id, version, patch any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchBook", reflect.TypeOf((*MockLogicInterface)(nil).PatchBook), ctx, id, version, patch)
+}
+
+// RestoreBook mocks base method.
+func (m *MockLogicInterface) RestoreBook(ctx context.Context, id int64) (model.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreBook", ctx, id)
+	ret0, _ := ret[0].(model.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreBook indicates an expected call of RestoreBook.
+func (mr *MockLogicInterfaceMockRecorder) RestoreBook(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreBook", reflect.TypeOf((*MockLogicInterface)(nil).RestoreBook), ctx, id)
+}
+
+// RevertBook mocks base method.
+func (m *MockLogicInterface) RevertBook(ctx context.Context, id, version, snapshot int64) (model.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevertBook", ctx, id, version, snapshot)
+	ret0, _ := ret[0].(model.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevertBook indicates an expected call of RevertBook.
+func (mr *MockLogicInterfaceMockRecorder) RevertBook(ctx, id, version, snapshot any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevertBook", reflect.TypeOf((*MockLogicInterface)(nil).RevertBook), ctx, id, version, snapshot)
+}
+
+// SetBookIdentifiers mocks base method.
+func (m *MockLogicInterface) SetBookIdentifiers(ctx context.Context, bookID int64, identifiers []model.BookIdentifier) ([]model.BookIdentifier, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetBookIdentifiers", ctx, bookID, identifiers)
+	ret0, _ := ret[0].([]model.BookIdentifier)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetBookIdentifiers indicates an expected call of SetBookIdentifiers.
+func (mr *MockLogicInterfaceMockRecorder) SetBookIdentifiers(ctx, bookID, identifiers any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBookIdentifiers", reflect.TypeOf((*MockLogicInterface)(nil).SetBookIdentifiers), ctx, bookID, identifiers)
+}
+
 // StoreBook mocks base method.
-func (m *MockLogicInterface) StoreBook(ctx context.Context, data model.Book) (model.Book, error) {
+func (m *MockLogicInterface) StoreBook(ctx context.Context, data model.Book, force bool) (model.Book, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "StoreBook", ctx, data)
+	ret := m.ctrl.Call(m, "StoreBook", ctx, data, force)
 	ret0, _ := ret[0].(model.Book)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // StoreBook indicates an expected call of StoreBook.
-func (mr *MockLogicInterfaceMockRecorder) StoreBook(ctx, data any) *gomock.Call {
+func (mr *MockLogicInterfaceMockRecorder) StoreBook(ctx, data, force any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreBook", reflect.TypeOf((*MockLogicInterface)(nil).StoreBook), ctx, data)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreBook", reflect.TypeOf((*MockLogicInterface)(nil).StoreBook), ctx, data, force)
 }
 
 // UpdateBook mocks base method.
@@ -0,0 +1,112 @@
+package book
+
+import (
+	"byfood-app/internal/core"
+	"context"
+	"log/slog"
+	"time"
+)
+
+const (
+	defaultPurgeInterval  = time.Hour
+	defaultPurgeBatchSize = 500
+)
+
+// Purger permanently deletes books that stayed soft deleted
+// longer than the configured retention period.
+type Purger struct {
+	deps *core.Dependency
+	repo RepositoryInterface
+}
+
+func NewPurger(deps *core.Dependency, repo RepositoryInterface) *Purger {
+	return &Purger{
+		deps: deps,
+		repo: repo,
+	}
+}
+
+// Run purges once per interval until ctx is cancelled.
+func (p *Purger) Run(ctx context.Context) {
+	interval := p.deps.BookPurgeInterval
+	if interval <= 0 {
+		interval = defaultPurgeInterval
+	}
+
+	p.deps.Logger.InfoContext(ctx, "book purger started",
+		slog.Duration("purge_after", p.deps.BookPurgeAfter),
+		slog.Duration("interval", interval),
+		slog.Bool("dry_run", p.deps.BookPurgeDryRun),
+	)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		// failures are logged, the next tick retries
+		_, _ = p.Purge(ctx)
+
+		select {
+		case <-ctx.Done():
+			p.deps.Logger.InfoContext(ctx, "book purger stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Purge deletes expired books in batches and logs a summary of the run.
+// It returns how many books were purged, or would be purged on dry run.
+func (p *Purger) Purge(ctx context.Context) (int64, error) {
+	start := time.Now()
+	cutoff := start.Add(-p.deps.BookPurgeAfter)
+
+	if p.deps.BookPurgeDryRun {
+		total, err := p.repo.CountPurgeableBooks(ctx, cutoff)
+		if err != nil {
+			p.deps.Logger.ErrorContext(ctx, "failed to count purgeable books", slog.Any("error", err))
+			return 0, err
+		}
+
+		p.deps.Logger.InfoContext(ctx, "book purge dry run",
+			slog.Int64("purgeable", total),
+			slog.Time("cutoff", cutoff),
+			slog.Duration("duration", time.Since(start)),
+		)
+		return total, nil
+	}
+
+	batchSize := p.deps.BookPurgeBatchSize
+	if batchSize <= 0 {
+		batchSize = defaultPurgeBatchSize
+	}
+
+	var (
+		purged  int64
+		batches int
+	)
+	for ctx.Err() == nil {
+		count, err := p.repo.PurgeDeletedBooks(ctx, cutoff, batchSize)
+		if err != nil {
+			p.deps.Logger.ErrorContext(ctx, "failed to purge deleted books", slog.Any("error", err), slog.Int64("purged", purged))
+			return purged, err
+		}
+
+		purged += count
+		batches++
+
+		// a short batch means nothing is left to purge
+		if count < int64(batchSize) {
+			break
+		}
+	}
+
+	p.deps.Logger.InfoContext(ctx, "book purge finished",
+		slog.Int64("purged", purged),
+		slog.Int("batches", batches),
+		slog.Time("cutoff", cutoff),
+		slog.Duration("duration", time.Since(start)),
+	)
+
+	return purged, nil
+}
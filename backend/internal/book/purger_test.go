@@ -0,0 +1,110 @@
+package book
+
+import (
+	"byfood-app/internal/config"
+	"byfood-app/internal/core"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestPurger_Purge(t *testing.T) {
+	type fields struct {
+		deps *core.Dependency
+		repo RepositoryInterface
+	}
+	type args struct {
+		ctx context.Context
+	}
+
+	ts := setupTestSuite(t)
+	newFields := func(dryRun bool) fields {
+		return fields{
+			deps: &core.Dependency{
+				Logger: slog.Default(),
+				Config: &config.Config{
+					BookPurgeAfter:     90 * 24 * time.Hour,
+					BookPurgeBatchSize: 2,
+					BookPurgeDryRun:    dryRun,
+				},
+			},
+			repo: ts.MockBookRepo,
+		}
+	}
+
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		want     int64
+		wantErr  bool
+		mockFunc func()
+	}{
+		{
+			name:   "success purge in batches until a short batch",
+			fields: newFields(false),
+			args: args{
+				ctx: context.Background(),
+			},
+			want:    5,
+			wantErr: false,
+			mockFunc: func() {
+				gomock.InOrder(
+					ts.MockBookRepo.EXPECT().PurgeDeletedBooks(gomock.Any(), gomock.Any(), 2).Return(int64(2), nil),
+					ts.MockBookRepo.EXPECT().PurgeDeletedBooks(gomock.Any(), gomock.Any(), 2).Return(int64(2), nil),
+					ts.MockBookRepo.EXPECT().PurgeDeletedBooks(gomock.Any(), gomock.Any(), 2).Return(int64(1), nil),
+				)
+			},
+		},
+		{
+			name:   "success dry run only counts purgeable books",
+			fields: newFields(true),
+			args: args{
+				ctx: context.Background(),
+			},
+			want:    7,
+			wantErr: false,
+			mockFunc: func() {
+				ts.MockBookRepo.EXPECT().CountPurgeableBooks(gomock.Any(), gomock.Any()).Return(int64(7), nil)
+			},
+		},
+		{
+			name:   "failed purge stops at the failing batch",
+			fields: newFields(false),
+			args: args{
+				ctx: context.Background(),
+			},
+			want:    2,
+			wantErr: true,
+			mockFunc: func() {
+				gomock.InOrder(
+					ts.MockBookRepo.EXPECT().PurgeDeletedBooks(gomock.Any(), gomock.Any(), 2).Return(int64(2), nil),
+					ts.MockBookRepo.EXPECT().PurgeDeletedBooks(gomock.Any(), gomock.Any(), 2).Return(int64(0), errors.New("connection reset")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Purger{
+				deps: tt.fields.deps,
+				repo: tt.fields.repo,
+			}
+
+			tt.mockFunc()
+
+			got, err := p.Purge(tt.args.ctx)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Purger.Purge() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Purger.Purge() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
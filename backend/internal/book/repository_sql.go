@@ -3,111 +3,252 @@ package book
 import (
 	"byfood-app/internal/core"
 	"byfood-app/internal/model"
+	"byfood-app/internal/pkg/authorname"
+	"byfood-app/internal/pkg/edtf"
+	"byfood-app/internal/pkg/isbn"
 	"byfood-app/internal/pkg/pagination"
+	"byfood-app/internal/pkg/xauth"
 	"byfood-app/internal/pkg/xerrors"
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/go-chi/chi/v5/middleware"
 	"github.com/huandu/go-sqlbuilder"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
+// fuzzySearchThreshold is the pg_trgm word similarity a title or author
+// needs to match a misspelled search, low enough for "Tolkein" to hit "Tolkien".
+const fuzzySearchThreshold = "0.4"
+
+// storeBooksBatchSize caps the rows of a single multi row insert.
+const storeBooksBatchSize = 500
+
+// suggestionThreshold is the minimum word similarity for a "did you mean" suggestion.
+const suggestionThreshold = 0.2
+
+// maxDuplicateCandidates caps the stored books a new book is scored against.
+const maxDuplicateCandidates = 50
+
+// bookAuthorsColumn selects the credit ordered author list of a live book as json.
+const bookAuthorsColumn = "library.book_author_list(id) AS authors"
+
+// bookPublishedColumn selects the publication date written in EDTF.
+const bookPublishedColumn = "library.edtf_date(publish_year, publish_month, publish_day, publish_approximate, publish_uncertain) AS published"
+
+// bookIdentifiersColumn, bookGenresColumn and bookTagsColumn select the
+// identifiers and classification of a book. They are not versioned, past
+// catalogs show the current ones.
+const (
+	bookIdentifiersColumn = "library.book_identifier_list(id) AS identifiers"
+	bookGenresColumn      = "library.book_genre_list(id) AS genres"
+	bookTagsColumn        = "library.book_tag_list(id) AS tags"
+	bookEditionsColumn    = "library.book_edition_list(id) AS editions"
+)
+
+// bookISBNIndex is the unique index keeping an ISBN on one live book.
+const bookISBNIndex = "idx_books_isbn"
+
 type BookRepo struct {
 	deps *core.Dependency
+
+	// fuzzySearch is enabled when pg_trgm and unaccent are installed
+	fuzzySearch bool
 }
 
 func NewSQLRepo(deps *core.Dependency) *BookRepo {
-	return &BookRepo{
+	repo := &BookRepo{
 		deps: deps,
 	}
+	repo.fuzzySearch = repo.isFuzzySearchAvailable(context.Background())
+
+	return repo
 }
 
-func (repo *BookRepo) GetBooks(ctx context.Context, params model.BookSearchParams, page pagination.Page) ([]model.Book, pagination.Metadata, error) {
-	var (
-		result []model.Book
-		meta   pagination.Metadata
-	)
+func (repo *BookRepo) isFuzzySearchAvailable(ctx context.Context) bool {
+	var available bool
 
-	// base query
-	countQ := sqlbuilder.NewSelectBuilder()
-	countQ.Select("COUNT(1)").From("library.books")
+	q := `
+		SELECT
+			EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'pg_trgm')
+		AND
+			to_regprocedure('library.f_unaccent(text)') IS NOT NULL;
+	`
+	err := repo.deps.DB.QueryRowxContext(ctx, q).Scan(&available)
+	if err != nil {
+		repo.deps.Logger.WarnContext(ctx, "failed to check fuzzy search extensions", slog.Any("error", err))
+		return false
+	}
+
+	if !available {
+		repo.deps.Logger.WarnContext(ctx, "pg_trgm or unaccent is unavailable, falling back to ILIKE search")
+	}
+
+	return available
+}
+
+func (repo *BookRepo) GetBooks(ctx context.Context, params model.BookSearchParams, page pagination.CursorPage) ([]model.Book, pagination.CursorMetadata, error) {
+	meta := pagination.CursorMetadata{Limit: page.Limit}
 
+	// base query
 	q := sqlbuilder.NewSelectBuilder()
-	q = q.Select("id", "title", "author", "publish_year", "created_at", "updated_at").From("library.books")
+	q = q.Select("id", "title", "author", "publish_year", bookPublishedColumn, "isbn", "version", "created_at", "updated_at", "deleted_at", booksAuthorsColumn(params), bookIdentifiersColumn, bookGenresColumn, bookTagsColumn)
+	q.From(booksTable(q, params))
 
-	if params.Search != "" {
-		q.Where(
-			q.Or(
-				q.ILike("title", "%"+params.Search+"%"),
-				q.ILike("author", "%"+params.Search+"%"),
-			),
-		)
+	selectBookSearchColumns(q, params)
+	if params.IncludeEditions {
+		q.SelectMore(bookEditionsColumn)
+	}
+	applyBookFilters(q, params, repo.fuzzySearch)
+
+	keys := bookSortKeys(q, params)
+	signature := sortSignature(keys)
+
+	// keyset condition, going backward means
+	// reading the previous page in reverse order
+	backward := page.Before != nil
+	cursor := page.After
+	if backward {
+		cursor = page.Before
 	}
 
-	q.Where(q.IsNull("deleted_at"))
-	q.OrderBy("id")
+	if cursor != nil {
+		if cursor.Sort != signature || len(cursor.Values) != len(keys)-1 {
+			return nil, meta, xerrors.NewClientError(pagination.ErrInvalidCursor)
+		}
+
+		values := make([]any, 0, len(keys))
+		for _, v := range cursor.Values {
+			values = append(values, v)
+		}
+		values = append(values, cursor.ID)
+
+		q.Where(keysetCondition(q, keys, values, backward))
+	}
 
-	// store where clause for metadata query
-	whereClauseNoPage := q.WhereClause
+	q.OrderBy(orderByClause(keys, backward)...)
 
-	// pagination compute
-	page.Compute()
-	q.Limit(page.Limit)
-	q.Offset(page.Offset)
+	// fetch one extra row to know whether there is another page
+	q.Limit(page.Limit + 1)
 
 	// build and exec query
 	query, args := q.BuildWithFlavor(sqlbuilder.PostgreSQL)
-	rows, err := repo.deps.DB.QueryxContext(ctx, query, args...)
+	result, err := repo.queryBooks(ctx, params, query, args)
 	if err != nil {
 		return result, meta, err
 	}
-	defer rows.Close()
 
-	var temp model.SQLBook
-	for rows.Next() {
-		err := rows.StructScan(&temp)
-		if err != nil {
-			repo.deps.Logger.WarnContext(ctx, "failed to scan book data", slog.Any("error", err))
-			continue
-		}
+	hasMore := len(result) > page.Limit
+	if hasMore {
+		result = result[:page.Limit]
+	}
+
+	if backward {
+		slices.Reverse(result)
+	}
 
-		result = append(result, model.Book{
-			ID:          temp.ID.Int64,
-			Title:       temp.Title.String,
-			Author:      temp.Author.String,
-			PublishYear: temp.PublishYear.Int64,
-			BaseAudit: model.BaseAudit{
-				CreatedAt: &temp.CreatedAt.Time,
-				UpdatedAt: &temp.UpdatedAt.Time,
-			},
-		})
+	if len(result) == 0 {
+		return result, meta, xerrors.ErrDataNotFound
 	}
 
 	// build metadata
-	var total int64
-	countQ.WhereClause = whereClauseNoPage
-	query, args = countQ.BuildWithFlavor(sqlbuilder.PostgreSQL)
-	err = repo.deps.DB.QueryRowxContext(ctx, query, args...).Scan(&total)
-	if err != nil {
-		return result, meta, err
+	first := bookCursor(result[0], keys, signature)
+	last := bookCursor(result[len(result)-1], keys, signature)
+	switch {
+	case backward:
+		meta.Next = &last
+		if hasMore {
+			meta.Prev = &first
+		}
+	default:
+		if hasMore {
+			meta.Next = &last
+		}
+		if page.After != nil {
+			meta.Prev = &first
+		}
+	}
+
+	return result, meta, nil
+}
+
+// booksTable is what book listings read from, the live table or the catalog
+// rebuilt from library.book_versions as it was at params.AsOf.
+func booksTable(q *sqlbuilder.SelectBuilder, params model.BookSearchParams) string {
+	if params.AsOf == nil {
+		return "library.books"
 	}
 
-	meta.Compute(total, page.Size, page.Page)
+	return "library.books_as_of(" + q.Var(params.AsOf.UTC()) + ") AS books"
+}
 
-	if total == 0 {
-		return result, meta, xerrors.ErrDataNotFound
+// booksAuthorsColumn selects the author list matching booksTable, past
+// catalogs carry the list each book version was written with.
+func booksAuthorsColumn(params model.BookSearchParams) string {
+	if params.AsOf == nil {
+		return bookAuthorsColumn
 	}
 
-	return result, meta, nil
+	return "authors"
 }
 
-func (repo *BookRepo) GetBookByID(ctx context.Context, id int64) (model.Book, error) {
+// suggestableBookFields whitelists the fields offering autocomplete, mapped to their column.
+var suggestableBookFields = map[string]string{
+	"title":  "title",
+	"author": "author",
+}
+
+// GetBookSuggestions returns distinct values starting with the query,
+// ranked by how many books share them, using the lower(column) prefix indexes.
+func (repo *BookRepo) GetBookSuggestions(ctx context.Context, params model.BookSuggestParams) ([]model.BookSuggestion, error) {
+	var result []model.BookSuggestion
+
+	column, ok := suggestableBookFields[params.Field]
+	if !ok {
+		return result, xerrors.NewClientError(fmt.Errorf("unknown suggestion field: %s", params.Field))
+	}
+
+	q := sqlbuilder.NewSelectBuilder()
+	q.Select(column+" AS value", "COUNT(1) AS count").From("library.books")
+	q.Where(
+		"lower("+column+") LIKE "+q.Var(escapeLikePattern(strings.ToLower(params.Query))+"%"),
+		q.IsNull("deleted_at"),
+	)
+	q.GroupBy(column)
+	q.OrderBy("count DESC", column+" ASC")
+	q.Limit(params.Limit)
+
+	query, args := q.BuildWithFlavor(sqlbuilder.PostgreSQL)
+	err := repo.deps.DB.SelectContext(ctx, &result, query, args...)
+	if err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// escapeLikePattern escapes LIKE wildcards so user input is matched literally.
+func escapeLikePattern(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// GetBookAsOf returns the book as it was at the given moment, unless it was
+// soft deleted or not created yet by then.
+func (repo *BookRepo) GetBookAsOf(ctx context.Context, id int64, asOf time.Time) (model.Book, error) {
 	var result model.SQLBook
 
-	q := `SELECT id, title, author, publish_year, created_at, updated_at FROM library.books WHERE id = $1 AND deleted_at ISNULL;`
+	q := `SELECT id, title, author, authors, publish_year, ` + bookPublishedColumn + `, isbn, version, created_at, updated_at FROM library.books_as_of($2) WHERE id = $1 AND deleted_at ISNULL;`
 
-	err := repo.deps.DB.QueryRowxContext(ctx, q, id).StructScan(&result)
+	err := repo.deps.DB.QueryRowxContext(ctx, q, id, asOf.UTC()).StructScan(&result)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return model.Book{}, xerrors.NewClientError(xerrors.ErrDataNotFound)
@@ -116,168 +257,1609 @@ func (repo *BookRepo) GetBookByID(ctx context.Context, id int64) (model.Book, er
 		return model.Book{}, err
 	}
 
-	return model.Book{
-		ID:          result.ID.Int64,
-		Title:       result.Title.String,
-		Author:      result.Author.String,
-		PublishYear: result.PublishYear.Int64,
-		BaseAudit: model.BaseAudit{
-			CreatedAt: &result.CreatedAt.Time,
-			UpdatedAt: &result.UpdatedAt.Time,
-		},
-	}, nil
+	return toBook(result), nil
 }
 
-func (repo *BookRepo) StoreBook(ctx context.Context, data model.Book) (model.Book, error) {
-	var returned model.SQLBook
-	q := `
-		INSERT INTO library.books (title, author, publish_year) VALUES ($1, $2, $3) RETURNING id, created_at, updated_at
-	`
-	err := repo.deps.DB.QueryRowxContext(ctx, q, data.Title, data.Author, data.PublishYear).
-		Scan(&returned.ID, &returned.CreatedAt, &returned.UpdatedAt)
+// beginTx starts a write transaction tagged with the actor and request id,
+// which the book_revisions trigger records alongside every change.
+func (repo *BookRepo) beginTx(ctx context.Context) (*sqlx.Tx, error) {
+	tx, err := repo.deps.DB.BeginTxx(ctx, nil)
 	if err != nil {
-		return model.Book{}, err
+		return nil, err
 	}
 
-	data.ID = returned.ID.Int64
-	data.CreatedAt = &returned.CreatedAt.Time
-	data.UpdatedAt = &returned.UpdatedAt.Time
+	q := `SELECT set_config('byfood.actor', $1, true), set_config('byfood.request_id', $2, true);`
+	_, err = tx.ExecContext(ctx, q, xauth.ActorFromContext(ctx), middleware.GetReqID(ctx))
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
 
-	return data, nil
+	return tx, nil
 }
 
-func (repo *BookRepo) UpdateBook(ctx context.Context, data model.Book) (model.Book, error) {
-	var returned model.SQLBook
+func (repo *BookRepo) GetBookByID(ctx context.Context, id int64) (model.Book, error) {
+	var result model.SQLBook
 
-	q := `
-		UPDATE library.books
-			SET 
-				title = $1,
-				author = $2,
-				publish_year = $3
-			WHERE
-				id = $4
-			AND
-				deleted_at ISNULL
-		RETURNING updated_at;
-	`
-	tx, err := repo.deps.DB.BeginTxx(ctx, nil)
+	q := `SELECT id, title, author, ` + bookAuthorsColumn + `, publish_year, ` + bookPublishedColumn + `, isbn, ` + bookIdentifiersColumn + `, ` + bookGenresColumn + `, ` + bookTagsColumn + `, ` + bookEditionsColumn + `, version, created_at, updated_at FROM library.books WHERE id = $1 AND deleted_at ISNULL;`
+
+	err := repo.deps.DB.QueryRowxContext(ctx, q, id).StructScan(&result)
 	if err != nil {
-		return data, err
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Book{}, repo.bookNotFoundError(ctx, id)
+		}
+
+		return model.Book{}, err
 	}
-	defer tx.Rollback()
 
-	err = tx.QueryRowxContext(ctx, q, data.Title, data.Author, data.PublishYear, data.ID).Scan(&returned.UpdatedAt)
+	return toBook(result), nil
+}
+
+// bookNotFoundError tells a book merged into another apart from one that
+// does not exist or was deleted.
+func (repo *BookRepo) bookNotFoundError(ctx context.Context, id int64) error {
+	var mergedInto int64
+
+	q := `SELECT merged_into FROM library.books WHERE id = $1 AND merged_into NOTNULL;`
+	err := repo.deps.DB.QueryRowxContext(ctx, q, id).Scan(&mergedInto)
 	if err != nil {
-		// this means no data is updated
-		// which probably caused by invalid id input (i.e. updating deleted entry)
 		if errors.Is(err, sql.ErrNoRows) {
-			return model.Book{}, xerrors.NewClientError(xerrors.ErrInvalidID)
+			return xerrors.NewClientError(xerrors.ErrDataNotFound)
 		}
 
-		return data, err
+		return err
 	}
 
-	err = tx.Commit()
+	return MergedBookError{ID: id, MergedInto: mergedInto}
+}
+
+// GetBookByISBN returns the live book with the given ISBN-13, on the book
+// itself or on one of its editions.
+func (repo *BookRepo) GetBookByISBN(ctx context.Context, isbn string) (model.Book, error) {
+	var result model.SQLBook
+
+	q := `SELECT id, title, author, ` + bookAuthorsColumn + `, publish_year, ` + bookPublishedColumn + `, isbn, ` + bookIdentifiersColumn + `, ` + bookGenresColumn + `, ` + bookTagsColumn + `, ` + bookEditionsColumn + `, version, created_at, updated_at FROM library.books WHERE (isbn = $1 OR id = (SELECT book_id FROM library.book_editions WHERE isbn = $1)) AND deleted_at ISNULL;`
+
+	err := repo.deps.DB.QueryRowxContext(ctx, q, isbn).StructScan(&result)
 	if err != nil {
-		repo.deps.Logger.ErrorContext(ctx, "failed to commit sql transaction", slog.Any("error", err))
-		return data, err
-	}
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Book{}, xerrors.NewClientError(xerrors.ErrDataNotFound)
+		}
 
-	data.UpdatedAt = &returned.UpdatedAt.Time
+		return model.Book{}, err
+	}
 
-	return data, nil
+	return toBook(result), nil
 }
-func (repo *BookRepo) DeleteBook(ctx context.Context, id int64) error {
-	q := `
-		UPDATE library.books
-			SET 
-				deleted_at = now()
-			WHERE
-				id = $1
-			AND
-				deleted_at ISNULL;
-	`
+
+// SetBookIdentifiers replaces the identifiers of a live book. Like genres and
+// tags they are not versioned, so the book version and revisions are left as
+// they are.
+func (repo *BookRepo) SetBookIdentifiers(ctx context.Context, bookID int64, identifiers []model.BookIdentifier) ([]model.BookIdentifier, error) {
 	tx, err := repo.deps.DB.BeginTxx(ctx, nil)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer tx.Rollback()
 
-	res, err := tx.ExecContext(ctx, q, id)
+	bookQ := `SELECT id FROM library.books WHERE id = $1 AND deleted_at ISNULL FOR SHARE;`
+	deleteQ := `DELETE FROM library.book_identifiers WHERE book_id = $1;`
+	insertQ := `INSERT INTO library.book_identifiers (book_id, scheme, value) SELECT $1, i.scheme, i.value FROM unnest($2::TEXT[], $3::TEXT[]) AS i (scheme, value);`
+	listQ := `SELECT library.book_identifier_list($1);`
+
+	err = tx.QueryRowxContext(ctx, bookQ, bookID).Scan(&bookID)
 	if err != nil {
-		return err
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, xerrors.NewClientError(xerrors.ErrDataNotFound)
+		}
+
+		return nil, err
 	}
 
-	rowsCount, err := res.RowsAffected()
+	_, err = tx.ExecContext(ctx, deleteQ, bookID)
 	if err != nil {
-		repo.deps.Logger.WarnContext(ctx, "failed to check affected row", slog.Any("error", err))
-		return err
+		return nil, err
 	}
 
-	if rowsCount < 1 {
-		// this means no data is soft deleted
-		// which probably caused by invalid id input (i.e. deleting deleted entry)
-		return xerrors.NewClientError(xerrors.ErrInvalidID)
+	if len(identifiers) > 0 {
+		schemes := make([]string, len(identifiers))
+		values := make([]string, len(identifiers))
+		for i, identifier := range identifiers {
+			schemes[i] = identifier.Scheme
+			values[i] = identifier.Value
+		}
+
+		_, err = tx.ExecContext(ctx, insertQ, bookID, pq.Array(schemes), pq.Array(values))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var result model.SQLBookIdentifiers
+	err = tx.QueryRowxContext(ctx, listQ, bookID).Scan(&result)
+	if err != nil {
+		return nil, err
 	}
 
 	err = tx.Commit()
 	if err != nil {
 		repo.deps.Logger.ErrorContext(ctx, "failed to commit sql transaction", slog.Any("error", err))
-		return err
+		return nil, err
 	}
 
-	return nil
+	return result, nil
 }
 
-func (repo *BookRepo) GetBooksNoPagination(ctx context.Context, params model.BookSearchParams) ([]model.Book, error) {
-	var result []model.Book
+func (repo *BookRepo) StoreBook(ctx context.Context, data model.Book) (model.Book, error) {
+	tx, err := repo.beginTx(ctx)
+	if err != nil {
+		return model.Book{}, err
+	}
+	defer tx.Rollback()
 
-	// base query
-	countQ := sqlbuilder.NewSelectBuilder()
-	countQ.Select("COUNT(1)").From("library.books")
+	result, err := storeBook(ctx, tx, data)
+	if err != nil {
+		return model.Book{}, err
+	}
 
-	q := sqlbuilder.NewSelectBuilder()
-	q = q.Select("id", "title", "author", "publish_year", "created_at", "updated_at").From("library.books")
+	err = tx.Commit()
+	if err != nil {
+		repo.deps.Logger.ErrorContext(ctx, "failed to commit sql transaction", slog.Any("error", err))
+		return model.Book{}, err
+	}
 
-	if params.Search != "" {
-		q.Where(
-			q.Or(
-				q.ILike("title", "%"+params.Search+"%"),
-				q.ILike("author", "%"+params.Search+"%"),
-			),
-		)
+	return result, nil
+}
+
+func storeBook(ctx context.Context, tx *sqlx.Tx, data model.Book) (model.Book, error) {
+	var returned model.SQLBook
+	q := `
+		INSERT INTO library.books (title, author, publish_year, publish_month, publish_day, publish_approximate, publish_uncertain, isbn)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, version, created_at, updated_at
+	`
+	data, err := resolveBookAuthors(ctx, tx, data)
+	if err != nil {
+		return model.Book{}, err
 	}
 
-	q.Where(q.IsNull("deleted_at"))
-	q.OrderBy("id")
+	published := bookPublished(data)
+	err = tx.QueryRowxContext(ctx, q, data.Title, data.Author, published.Year, nullDatePart(published.Month), nullDatePart(published.Day), published.Approximate, published.Uncertain, nullISBN(data.ISBN)).
+		Scan(&returned.ID, &returned.Version, &returned.CreatedAt, &returned.UpdatedAt)
+	if err != nil {
+		return model.Book{}, isbnConflictError(err, data.ISBN)
+	}
 
-	// build and exec query
-	query, args := q.BuildWithFlavor(sqlbuilder.PostgreSQL)
-	rows, err := repo.deps.DB.QueryxContext(ctx, query, args...)
+	err = writeBookAuthors(ctx, tx, returned.ID.Int64, data.Authors)
 	if err != nil {
-		return result, err
+		return model.Book{}, err
 	}
-	defer rows.Close()
 
-	var temp model.SQLBook
-	for rows.Next() {
-		err := rows.StructScan(&temp)
+	data.ID = returned.ID.Int64
+	data.PublishYear, data.Published = published.Year, &published
+	data.ISBN10 = isbn10(data.ISBN)
+	data.Version = returned.Version.Int64
+	data.CreatedAt = &returned.CreatedAt.Time
+	data.UpdatedAt = &returned.UpdatedAt.Time
+
+	return data, nil
+}
+
+// StoreBooks inserts all books in one transaction, using multi row inserts
+// of storeBooksBatchSize rows, and returns them with their generated columns.
+func (repo *BookRepo) StoreBooks(ctx context.Context, data []model.Book) ([]model.Book, error) {
+	tx, err := repo.beginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	data = slices.Clone(data)
+	for i := range data {
+		data[i], err = resolveBookAuthors(ctx, tx, data[i])
 		if err != nil {
-			repo.deps.Logger.WarnContext(ctx, "failed to scan book data", slog.Any("error", err))
-			continue
+			return nil, err
+		}
+	}
+
+	result := make([]model.Book, 0, len(data))
+	for batch := range slices.Chunk(data, storeBooksBatchSize) {
+		q := sqlbuilder.NewInsertBuilder()
+		q.InsertInto("library.books").Cols("title", "author", "publish_year", "publish_month", "publish_day", "publish_approximate", "publish_uncertain", "isbn")
+		for _, book := range batch {
+			published := bookPublished(book)
+			q.Values(book.Title, book.Author, published.Year, nullDatePart(published.Month), nullDatePart(published.Day), published.Approximate, published.Uncertain, nullISBN(book.ISBN))
+		}
+		q.SQL("RETURNING id, title, author, publish_year, " + bookPublishedColumn + ", isbn, version, created_at, updated_at")
+
+		query, args := q.BuildWithFlavor(sqlbuilder.PostgreSQL)
+		rows, err := tx.QueryxContext(ctx, query, args...)
+		if err != nil {
+			return nil, isbnConflictError(err, "")
+		}
+
+		for rows.Next() {
+			var temp model.SQLBook
+			err := rows.StructScan(&temp)
+			if err != nil {
+				rows.Close()
+				return nil, err
+			}
+			result = append(result, toBook(temp))
+		}
+		rows.Close()
+
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	// rows are returned in insert order, the authors of result[i] are data[i]'s
+	for i := range result {
+		err = writeBookAuthors(ctx, tx, result[i].ID, data[i].Authors)
+		if err != nil {
+			return nil, err
 		}
+		result[i].Authors = data[i].Authors
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		repo.deps.Logger.ErrorContext(ctx, "failed to commit sql transaction", slog.Any("error", err))
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// UpdateBook overwrites the book only when data.Version still matches the
+// stored version, bumping the version on success.
+func (repo *BookRepo) UpdateBook(ctx context.Context, data model.Book) (model.Book, error) {
+	tx, err := repo.beginTx(ctx)
+	if err != nil {
+		return data, err
+	}
+	defer tx.Rollback()
+
+	result, err := updateBook(ctx, tx, data)
+	if err != nil {
+		return result, err
+	}
 
-		result = append(result, model.Book{
-			ID:          temp.ID.Int64,
-			Title:       temp.Title.String,
-			Author:      temp.Author.String,
-			PublishYear: temp.PublishYear.Int64,
-			BaseAudit: model.BaseAudit{
-				CreatedAt: &temp.CreatedAt.Time,
-				UpdatedAt: &temp.UpdatedAt.Time,
-			},
-		})
+	err = tx.Commit()
+	if err != nil {
+		repo.deps.Logger.ErrorContext(ctx, "failed to commit sql transaction", slog.Any("error", err))
+		return data, err
+	}
+
+	return result, nil
+}
+
+func updateBook(ctx context.Context, tx *sqlx.Tx, data model.Book) (model.Book, error) {
+	var returned model.SQLBook
+
+	q := `
+		UPDATE library.books
+			SET
+				title = $1,
+				author = $2,
+				publish_year = $3,
+				publish_month = $4,
+				publish_day = $5,
+				publish_approximate = $6,
+				publish_uncertain = $7,
+				isbn = $8,
+				version = version + 1,
+				updated_at = now()
+			WHERE
+				id = $9
+			AND
+				version = $10
+			AND
+				deleted_at ISNULL
+		RETURNING id, title, author, publish_year, ` + bookPublishedColumn + `, isbn, version, created_at, updated_at;
+	`
+	data, err := resolveBookAuthors(ctx, tx, data)
+	if err != nil {
+		return model.Book{}, err
+	}
+
+	published := bookPublished(data)
+	err = tx.QueryRowxContext(ctx, q, data.Title, data.Author, published.Year, nullDatePart(published.Month), nullDatePart(published.Day), published.Approximate, published.Uncertain, nullISBN(data.ISBN), data.ID, data.Version).StructScan(&returned)
+	if err != nil {
+		// this means no data is updated
+		// which is caused by either a stale version or an invalid id (i.e. updating deleted entry)
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Book{}, conflictError(ctx, tx, data.ID)
+		}
+
+		return data, isbnConflictError(err, data.ISBN)
+	}
+
+	result := toBook(returned)
+	err = writeBookAuthors(ctx, tx, result.ID, data.Authors)
+	if err != nil {
+		return model.Book{}, err
+	}
+	result.Authors = data.Authors
+
+	return result, nil
+}
+
+// PatchBook locks the book row, hands it to apply and stores whatever apply
+// returns, all in one transaction so the patch always sees the latest row.
+func (repo *BookRepo) PatchBook(ctx context.Context, id int64, version int64, apply func(model.Book) (model.Book, error)) (model.Book, error) {
+	tx, err := repo.beginTx(ctx)
+	if err != nil {
+		return model.Book{}, err
+	}
+	defer tx.Rollback()
+
+	result, err := patchBook(ctx, tx, id, version, apply)
+	if err != nil {
+		return model.Book{}, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		repo.deps.Logger.ErrorContext(ctx, "failed to commit sql transaction", slog.Any("error", err))
+		return model.Book{}, err
+	}
+
+	return result, nil
+}
+
+// RevertBook sets the book fields back to how they were at the snapshot,
+// the book version right after one of its revisions. apply gets the current
+// book and the snapshot and returns what to store, which is recorded as a
+// revert revision. Soft deleted books have to be restored before a revert.
+func (repo *BookRepo) RevertBook(ctx context.Context, id int64, version int64, snapshot int64, apply func(current model.Book, snapshot model.Book) (model.Book, error)) (model.Book, error) {
+	var after []byte
+
+	snapshotQ := `SELECT after FROM library.book_revisions WHERE book_id = $1 AND revision = $2;`
+	revertQ := `SELECT set_config('byfood.reverted_to', $1, true);`
+
+	tx, err := repo.beginTx(ctx)
+	if err != nil {
+		return model.Book{}, err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowxContext(ctx, snapshotQ, id, snapshot).Scan(&after)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Book{}, xerrors.NewClientError(fmt.Errorf("book has no snapshot %d", snapshot))
+		}
+
+		return model.Book{}, err
+	}
+
+	var target model.Book
+	err = json.Unmarshal(after, &target)
+	if err != nil {
+		return model.Book{}, fmt.Errorf("invalid book revision snapshot: %v", err)
+	}
+
+	_, err = tx.ExecContext(ctx, revertQ, strconv.FormatInt(snapshot, 10))
+	if err != nil {
+		return model.Book{}, err
+	}
+
+	result, err := patchBook(ctx, tx, id, version, func(current model.Book) (model.Book, error) {
+		return apply(current, target)
+	})
+	if err != nil {
+		return model.Book{}, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		repo.deps.Logger.ErrorContext(ctx, "failed to commit sql transaction", slog.Any("error", err))
+		return model.Book{}, err
+	}
+
+	return result, nil
+}
+
+func patchBook(ctx context.Context, tx *sqlx.Tx, id int64, version int64, apply func(model.Book) (model.Book, error)) (model.Book, error) {
+	var current, returned model.SQLBook
+
+	selectQ := `
+		SELECT id, title, author, ` + bookAuthorsColumn + `, publish_year, ` + bookPublishedColumn + `, isbn, version, created_at, updated_at
+			FROM library.books
+			WHERE
+				id = $1
+			AND
+				deleted_at ISNULL
+		FOR UPDATE;
+	`
+	updateQ := `
+		UPDATE library.books
+			SET
+				title = $1,
+				author = $2,
+				publish_year = $3,
+				publish_month = $4,
+				publish_day = $5,
+				publish_approximate = $6,
+				publish_uncertain = $7,
+				isbn = $8,
+				version = version + 1,
+				updated_at = now()
+			WHERE
+				id = $9
+		RETURNING id, title, author, publish_year, ` + bookPublishedColumn + `, isbn, version, created_at, updated_at;
+	`
+	err := tx.QueryRowxContext(ctx, selectQ, id).StructScan(&current)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Book{}, xerrors.NewClientError(xerrors.ErrInvalidID)
+		}
+
+		return model.Book{}, err
+	}
+
+	if current.Version.Int64 != version {
+		return model.Book{}, xerrors.PreconditionFailedError{Err: xerrors.ErrVersionMismatch}
+	}
+
+	patched, err := apply(toBook(current))
+	if err != nil {
+		return model.Book{}, err
+	}
+
+	patched, err = resolveBookAuthors(ctx, tx, patched)
+	if err != nil {
+		return model.Book{}, err
+	}
+
+	published := bookPublished(patched)
+	err = tx.QueryRowxContext(ctx, updateQ, patched.Title, patched.Author, published.Year, nullDatePart(published.Month), nullDatePart(published.Day), published.Approximate, published.Uncertain, nullISBN(patched.ISBN), id).StructScan(&returned)
+	if err != nil {
+		return model.Book{}, isbnConflictError(err, patched.ISBN)
+	}
+
+	result := toBook(returned)
+	err = writeBookAuthors(ctx, tx, id, patched.Authors)
+	if err != nil {
+		return model.Book{}, err
+	}
+	result.Authors = patched.Authors
+
+	return result, nil
+}
+
+// resolveBookAuthors matches the credited names to library.authors by name
+// key, their own or an alias, and credits the canonical name instead. Unknown
+// names are added and soft deleted authors are brought back. Credits that
+// resolve to an author already credited in the same role are dropped, and the
+// author display string is rebuilt from the canonical names. It has to run
+// before the book row is written, the row is revisioned once per transaction.
+func resolveBookAuthors(ctx context.Context, tx *sqlx.Tx, data model.Book) (model.Book, error) {
+	if len(data.Authors) == 0 {
+		return data, nil
+	}
+
+	q := `
+		WITH found AS (
+			SELECT id FROM library.authors WHERE name_key = $2
+			UNION ALL
+			SELECT author_id FROM library.author_aliases WHERE name_key = $2
+			LIMIT 1
+		), revived AS (
+			UPDATE library.authors SET deleted_at = NULL
+				WHERE id IN (SELECT id FROM found) AND deleted_at IS NOT NULL
+		), created AS (
+			INSERT INTO library.authors (name, name_key)
+				SELECT $1, $2 WHERE NOT EXISTS (SELECT 1 FROM found)
+			RETURNING id, name
+		)
+		SELECT a.id, a.name FROM library.authors a JOIN found f ON f.id = a.id
+		UNION ALL
+		SELECT id, name FROM created;
+	`
+
+	authors := make([]model.BookAuthor, 0, len(data.Authors))
+	for _, author := range data.Authors {
+		err := tx.QueryRowxContext(ctx, q, author.Name, authorname.Key(author.Name)).Scan(&author.ID, &author.Name)
+		if err != nil {
+			return model.Book{}, err
+		}
+
+		if slices.ContainsFunc(authors, func(other model.BookAuthor) bool {
+			return other.ID == author.ID && other.Role == author.Role
+		}) {
+			continue
+		}
+		authors = append(authors, author)
+	}
+
+	data.Authors = authors
+	data.Author = authorDisplayName(authors)
+
+	return data, nil
+}
+
+// writeBookAuthors replaces the credits of the book with authors, in order.
+// The authors have to be resolved to their ids first.
+func writeBookAuthors(ctx context.Context, tx *sqlx.Tx, bookID int64, authors []model.BookAuthor) error {
+	deleteQ := `DELETE FROM library.book_authors WHERE book_id = $1;`
+	insertQ := `INSERT INTO library.book_authors (book_id, author_id, position, role) VALUES ($1, $2, $3, $4);`
+
+	_, err := tx.ExecContext(ctx, deleteQ, bookID)
+	if err != nil {
+		return err
+	}
+
+	for i, author := range authors {
+		_, err := tx.ExecContext(ctx, insertQ, bookID, author.ID, i+1, author.Role)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetStoredISBNs returns which of the given ISBNs a live book or an edition
+// already holds, which a new book can't take.
+func (repo *BookRepo) GetStoredISBNs(ctx context.Context, isbns []string) ([]string, error) {
+	var result []string
+
+	q := `
+		SELECT isbn FROM library.books WHERE isbn = ANY($1) AND deleted_at ISNULL
+		UNION
+		SELECT isbn FROM library.book_editions WHERE isbn = ANY($1)
+		ORDER BY isbn;
+	`
+	err := repo.deps.DB.SelectContext(ctx, &result, q, pq.Array(isbns))
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetAuthorsByName returns the authors the given names would be credited to,
+// matched by name key on their name or an alias. Deleted ones are included
+// as crediting them on a book brings them back.
+func (repo *BookRepo) GetAuthorsByName(ctx context.Context, names []string) ([]model.Author, error) {
+	var temp []model.SQLAuthor
+
+	keys := make([]string, 0, len(names))
+	for _, name := range names {
+		keys = append(keys, authorname.Key(name))
+	}
+
+	q := `
+		SELECT id, name, birth_year, death_year
+			FROM library.authors
+			WHERE
+				name_key = ANY($1)
+			OR
+				id IN (SELECT author_id FROM library.author_aliases WHERE name_key = ANY($1));
+	`
+	err := repo.deps.DB.SelectContext(ctx, &temp, q, pq.Array(keys))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]model.Author, 0, len(temp))
+	for _, author := range temp {
+		data := model.Author{
+			ID:   author.ID.Int64,
+			Name: author.Name.String,
+		}
+		// an unknown year is nil, 0 being 1 BCE
+		if author.BirthYear.Valid {
+			data.BirthYear = &author.BirthYear.Int64
+		}
+		if author.DeathYear.Valid {
+			data.DeathYear = &author.DeathYear.Int64
+		}
+		result = append(result, data)
+	}
+
+	return result, nil
+}
+
+// DeleteBook soft deletes the book only when version still matches the stored version.
+func (repo *BookRepo) DeleteBook(ctx context.Context, id int64, version int64) error {
+	tx, err := repo.beginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = deleteBook(ctx, tx, id, version)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		repo.deps.Logger.ErrorContext(ctx, "failed to commit sql transaction", slog.Any("error", err))
+		return err
+	}
+
+	return nil
+}
+
+func deleteBook(ctx context.Context, tx *sqlx.Tx, id int64, version int64) error {
+	q := `
+		UPDATE library.books
+			SET
+				deleted_at = now(),
+				version = version + 1
+			WHERE
+				id = $1
+			AND
+				version = $2
+			AND
+				deleted_at ISNULL;
+	`
+	res, err := tx.ExecContext(ctx, q, id, version)
+	if err != nil {
+		return err
+	}
+
+	rowsCount, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsCount < 1 {
+		// this means no data is soft deleted
+		// which is caused by either a stale version or an invalid id (i.e. deleting deleted entry)
+		return conflictError(ctx, tx, id)
+	}
+
+	return nil
+}
+
+// isbnConflictError reports a write putting an ISBN already on another live
+// book as a conflict, isbn is empty when the writer doesn't know which one.
+func isbnConflictError(err error, isbn string) error {
+	if !xerrors.IsUniqueViolation(err, bookISBNIndex) {
+		return err
+	}
+
+	if isbn == "" {
+		return xerrors.ConflictError{Err: errors.New("another book already has this isbn")}
+	}
+
+	return xerrors.ConflictError{Err: fmt.Errorf("another book already has isbn %s", isbn)}
+}
+
+// nullISBN stores a book without ISBN as NULL, out of the unique index.
+func nullISBN(isbn string) sql.NullString {
+	return sql.NullString{String: isbn, Valid: isbn != ""}
+}
+
+// isbn10 derives the ISBN-10 of a stored ISBN-13, empty when there is none.
+func isbn10(isbn13 string) string {
+	result, err := isbn.To10(isbn13)
+	if err != nil {
+		return ""
+	}
+
+	return result
+}
+
+// conflictError tells a stale version apart from a missing book
+// once a conditional write matched no rows.
+func conflictError(ctx context.Context, tx *sqlx.Tx, id int64) error {
+	var exists bool
+
+	q := `SELECT EXISTS (SELECT 1 FROM library.books WHERE id = $1 AND deleted_at ISNULL);`
+	err := tx.QueryRowxContext(ctx, q, id).Scan(&exists)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		return xerrors.PreconditionFailedError{Err: xerrors.ErrVersionMismatch}
+	}
+
+	return xerrors.NewClientError(xerrors.ErrInvalidID)
+}
+
+func (repo *BookRepo) RestoreBook(ctx context.Context, id int64) (model.Book, error) {
+	tx, err := repo.beginTx(ctx)
+	if err != nil {
+		return model.Book{}, err
+	}
+	defer tx.Rollback()
+
+	result, err := restoreBook(ctx, tx, id)
+	if err != nil {
+		return model.Book{}, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		repo.deps.Logger.ErrorContext(ctx, "failed to commit sql transaction", slog.Any("error", err))
+		return model.Book{}, err
+	}
+
+	return result, nil
+}
+
+func restoreBook(ctx context.Context, tx *sqlx.Tx, id int64) (model.Book, error) {
+	var returned model.SQLBook
+
+	q := `
+		UPDATE library.books
+			SET
+				deleted_at = NULL,
+				merged_into = NULL,
+				updated_at = now(),
+				version = version + 1
+			WHERE
+				id = $1
+			AND
+				deleted_at NOTNULL
+		RETURNING id, title, author, ` + bookAuthorsColumn + `, publish_year, ` + bookPublishedColumn + `, isbn, version, created_at, updated_at, deleted_at;
+	`
+	err := tx.QueryRowxContext(ctx, q, id).StructScan(&returned)
+	if err != nil {
+		// this means no data is restored
+		// which probably caused by invalid id input (i.e. restoring live entry)
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Book{}, xerrors.NewClientError(xerrors.ErrInvalidID)
+		}
+
+		return model.Book{}, isbnConflictError(err, "")
+	}
+
+	return toBook(returned), nil
+}
+
+// GetDuplicateCandidates returns the live books a new book may duplicate,
+// those crediting one of its authors, by name or alias, and those with a
+// similar title, or the same title but for case without pg_trgm.
+func (repo *BookRepo) GetDuplicateCandidates(ctx context.Context, data model.Book) ([]model.BookRef, error) {
+	result := []model.BookRef{}
+
+	titleCondition := `lower(title) = lower($1)`
+	titleOrder := `lower(title) = lower($1) DESC`
+	if repo.fuzzySearch {
+		titleCondition = `library.f_unaccent(title) % library.f_unaccent($1)`
+		titleOrder = `similarity(library.f_unaccent(title), library.f_unaccent($1)) DESC`
+	}
+
+	keys := make([]string, len(data.Authors))
+	for i, author := range data.Authors {
+		keys[i] = authorname.Key(author.Name)
+	}
+
+	q := `
+		SELECT id, title, author, publish_year
+		FROM library.books
+		WHERE
+			deleted_at ISNULL
+		AND (
+			` + titleCondition + `
+			OR EXISTS (
+				SELECT 1 FROM library.book_authors ba
+				JOIN library.authors a ON a.id = ba.author_id
+				LEFT JOIN library.author_aliases al ON al.author_id = a.id
+				WHERE ba.book_id = books.id AND (a.name_key = ANY($2) OR al.name_key = ANY($2))
+			)
+		)
+		ORDER BY ` + titleOrder + `, id
+		LIMIT $3;
+	`
+	err := repo.deps.DB.SelectContext(ctx, &result, q, data.Title, pq.Array(keys), maxDuplicateCandidates)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetBookDuplicates returns the pairs of live books sharing a credited author
+// or with a similar title, the same way GetDuplicateCandidates finds them.
+func (repo *BookRepo) GetBookDuplicates(ctx context.Context) ([]model.BookDuplicate, error) {
+	titleCondition := `lower(a.title) = lower(b.title)`
+	if repo.fuzzySearch {
+		titleCondition = `library.f_unaccent(a.title) % library.f_unaccent(b.title)`
+	}
+
+	q := `
+		SELECT a.id, a.title, a.author, a.publish_year, b.id, b.title, b.author, b.publish_year
+		FROM library.books a
+		JOIN library.books b
+			ON b.id > a.id
+			AND b.deleted_at ISNULL
+		WHERE
+			a.deleted_at ISNULL
+		AND (
+			` + titleCondition + `
+			OR EXISTS (
+				SELECT 1 FROM library.book_authors x
+				JOIN library.book_authors y ON y.author_id = x.author_id
+				WHERE x.book_id = a.id AND y.book_id = b.id
+			)
+		)
+		ORDER BY a.id, b.id;
+	`
+	rows, err := repo.deps.DB.QueryxContext(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []model.BookDuplicate
+	for rows.Next() {
+		var a, b model.BookRef
+		err := rows.Scan(&a.ID, &a.Title, &a.Author, &a.PublishYear, &b.ID, &b.Title, &b.Author, &b.PublishYear)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, model.BookDuplicate{Books: []model.BookRef{a, b}})
+	}
+
+	return result, rows.Err()
+}
+
+// MergeBooks folds the sources into the target book in one transaction. The
+// sources are soft deleted with merged_into pointing to the target, which
+// takes over their editions, identifiers, genres and tags, and the first of
+// their ISBNs when it has none. With asEditions each source is kept as an
+// edition of the target instead, with its ISBN and publication date. The
+// other fields and author credits of the target stay as they are.
+func (repo *BookRepo) MergeBooks(ctx context.Context, id int64, sourceIDs []int64, asEditions bool) (model.BookMerge, error) {
+	tx, err := repo.beginTx(ctx)
+	if err != nil {
+		return model.BookMerge{}, err
+	}
+	defer tx.Rollback()
+
+	ids := append([]int64{id}, sourceIDs...)
+
+	lockQ := `SELECT id, isbn FROM library.books WHERE id = ANY($1) AND deleted_at ISNULL ORDER BY id FOR UPDATE;`
+	mergeQ := `UPDATE library.books SET deleted_at = now(), merged_into = $1, updated_at = now(), version = version + 1 WHERE id = ANY($2);`
+	// the sources gave their ISBNs up when they were deleted
+	isbnQ := `UPDATE library.books SET isbn = $2, updated_at = now(), version = version + 1 WHERE id = $1;`
+	asEditionsQ := `
+		INSERT INTO library.book_editions (book_id, isbn, published)
+		SELECT $1, isbn, library.edtf_date(publish_year, publish_month, publish_day, publish_approximate, publish_uncertain)
+		FROM library.books
+		WHERE id = ANY($2)
+		ORDER BY id
+		ON CONFLICT (isbn) DO NOTHING;
+	`
+	copyQs := []string{
+		`UPDATE library.book_editions SET book_id = $1, updated_at = now() WHERE book_id = ANY($2);`,
+		`INSERT INTO library.book_identifiers (book_id, scheme, value) SELECT $1, scheme, value FROM library.book_identifiers WHERE book_id = ANY($2) ON CONFLICT DO NOTHING;`,
+		`INSERT INTO library.book_genres (book_id, genre_id) SELECT $1, genre_id FROM library.book_genres WHERE book_id = ANY($2) ON CONFLICT DO NOTHING;`,
+		`INSERT INTO library.book_tags (book_id, tag_id) SELECT $1, tag_id FROM library.book_tags WHERE book_id = ANY($2) ON CONFLICT DO NOTHING;`,
+	}
+	readQ := `SELECT id, title, author, ` + bookAuthorsColumn + `, publish_year, ` + bookPublishedColumn + `, isbn, ` + bookIdentifiersColumn + `, ` + bookGenresColumn + `, ` + bookTagsColumn + `, ` + bookEditionsColumn + `, version, created_at, updated_at FROM library.books WHERE id = $1;`
+
+	var locked []model.SQLBook
+	err = tx.SelectContext(ctx, &locked, lockQ, pq.Array(ids))
+	if err != nil {
+		return model.BookMerge{}, err
+	}
+	if len(locked) != len(ids) {
+		return model.BookMerge{}, xerrors.NewClientError(xerrors.ErrDataNotFound)
+	}
+
+	var targetISBN, sourceISBN string
+	for _, book := range locked {
+		switch {
+		case book.ID.Int64 == id:
+			targetISBN = book.ISBN.String
+		case sourceISBN == "":
+			sourceISBN = book.ISBN.String
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, mergeQ, id, pq.Array(sourceIDs))
+	if err != nil {
+		return model.BookMerge{}, err
+	}
+
+	for _, copyQ := range copyQs {
+		_, err = tx.ExecContext(ctx, copyQ, id, pq.Array(sourceIDs))
+		if err != nil {
+			return model.BookMerge{}, err
+		}
+	}
+
+	switch {
+	case asEditions:
+		_, err = tx.ExecContext(ctx, asEditionsQ, id, pq.Array(sourceIDs))
+		if err != nil {
+			return model.BookMerge{}, err
+		}
+	case targetISBN == "" && sourceISBN != "":
+		_, err = tx.ExecContext(ctx, isbnQ, id, sourceISBN)
+		if err != nil {
+			return model.BookMerge{}, isbnConflictError(err, sourceISBN)
+		}
+	}
+
+	var result model.SQLBook
+	err = tx.QueryRowxContext(ctx, readQ, id).StructScan(&result)
+	if err != nil {
+		return model.BookMerge{}, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		repo.deps.Logger.ErrorContext(ctx, "failed to commit sql transaction", slog.Any("error", err))
+		return model.BookMerge{}, err
+	}
+
+	return model.BookMerge{
+		Book:          toBook(result),
+		MergedBookIDs: sourceIDs,
+	}, nil
+}
+
+// BatchBooks applies every operation in order inside a single transaction.
+// The first failing operation rolls back the whole batch.
+func (repo *BookRepo) BatchBooks(ctx context.Context, ops []model.BookBatchOperation) ([]model.BookBatchResult, error) {
+	results := make([]model.BookBatchResult, len(ops))
+	for i, op := range ops {
+		results[i] = model.BookBatchResult{
+			Index:  i,
+			Op:     op.Op,
+			ID:     op.ID,
+			Status: model.BatchStatusSkipped,
+		}
+	}
+
+	// rollback marks every applied operation as rolled back
+	rollback := func() {
+		for i := range results {
+			if results[i].Status == model.BatchStatusApplied {
+				results[i].Status = model.BatchStatusRolledBack
+				results[i].Data = nil
+			}
+		}
+	}
+
+	tx, err := repo.beginTx(ctx)
+	if err != nil {
+		return results, err
+	}
+	defer tx.Rollback()
+
+	for i, op := range ops {
+		var (
+			book model.Book
+			err  error
+		)
+
+		switch op.Op {
+		case model.BatchOpCreate:
+			book, err = storeBook(ctx, tx, op.Book())
+		case model.BatchOpUpdate:
+			book, err = updateBook(ctx, tx, op.Book())
+		case model.BatchOpDelete:
+			err = deleteBook(ctx, tx, op.ID, op.Version)
+		case model.BatchOpRestore:
+			book, err = restoreBook(ctx, tx, op.ID)
+		default:
+			err = xerrors.NewClientError(fmt.Errorf("unknown batch op: %s", op.Op))
+		}
+		if err != nil {
+			rollback()
+			results[i].Status = model.BatchStatusFailed
+			results[i].Error = err.Error()
+			return results, fmt.Errorf("operation %d (%s): %w", i, op.Op, err)
+		}
+
+		results[i].Status = model.BatchStatusApplied
+		if op.Op != model.BatchOpDelete {
+			results[i].ID = book.ID
+			results[i].Data = &book
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		rollback()
+		repo.deps.Logger.ErrorContext(ctx, "failed to commit sql transaction", slog.Any("error", err))
+		return results, err
+	}
+
+	return results, nil
+}
+
+// CountPurgeableBooks counts books soft deleted before the cutoff. Books
+// merged into another are kept for their redirect and never purged, and so
+// is a deleted book others were merged into, the redirect points to it.
+func (repo *BookRepo) CountPurgeableBooks(ctx context.Context, before time.Time) (int64, error) {
+	var total int64
+
+	q := `SELECT COUNT(1) FROM library.books WHERE deleted_at < $1 AND merged_into ISNULL AND NOT EXISTS (SELECT 1 FROM library.books m WHERE m.merged_into = books.id);`
+	err := repo.deps.DB.QueryRowxContext(ctx, q, before).Scan(&total)
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+// PurgeDeletedBooks permanently deletes up to limit purgeable books soft deleted before the cutoff,
+// see CountPurgeableBooks for the ones kept.
+// Rows locked by another replica's purge are skipped rather than waited on.
+func (repo *BookRepo) PurgeDeletedBooks(ctx context.Context, before time.Time, limit int) (int64, error) {
+	q := `
+		DELETE FROM library.books
+			WHERE id IN (
+				SELECT id FROM library.books
+					WHERE deleted_at < $1 AND merged_into ISNULL
+					AND NOT EXISTS (SELECT 1 FROM library.books m WHERE m.merged_into = books.id)
+					ORDER BY id
+					LIMIT $2
+					FOR UPDATE SKIP LOCKED
+			);
+	`
+	tx, err := repo.deps.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, q, before, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	rowsCount, err := res.RowsAffected()
+	if err != nil {
+		repo.deps.Logger.WarnContext(ctx, "failed to check affected row", slog.Any("error", err))
+		return 0, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		repo.deps.Logger.ErrorContext(ctx, "failed to commit sql transaction", slog.Any("error", err))
+		return 0, err
+	}
+
+	return rowsCount, nil
+}
+
+func (repo *BookRepo) GetBooksNoPagination(ctx context.Context, params model.BookSearchParams) ([]model.Book, error) {
+	query, args := unpaginatedBooksQuery(params, repo.fuzzySearch)
+	result, err := repo.queryBooks(ctx, params, query, args)
+	if err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// StreamBooks runs the same query as GetBooksNoPagination but hands the books
+// to fn one at a time as rows are read, so memory stays flat whatever the
+// table size. An error returned by fn stops the stream.
+func (repo *BookRepo) StreamBooks(ctx context.Context, params model.BookSearchParams, fn func(model.Book) error) error {
+	query, args := unpaginatedBooksQuery(params, repo.fuzzySearch)
+	return repo.eachBook(ctx, params, query, args, fn)
+}
+
+func unpaginatedBooksQuery(params model.BookSearchParams, fuzzy bool) (string, []any) {
+	q := sqlbuilder.NewSelectBuilder()
+	q = q.Select("id", "title", "author", "publish_year", bookPublishedColumn, "isbn", "version", "created_at", "updated_at", "deleted_at", booksAuthorsColumn(params), bookIdentifiersColumn, bookGenresColumn, bookTagsColumn)
+	q.From(booksTable(q, params))
+
+	selectBookSearchColumns(q, params)
+	if params.IncludeEditions {
+		q.SelectMore(bookEditionsColumn)
+	}
+	applyBookFilters(q, params, fuzzy)
+	q.OrderBy(orderByClause(bookSortKeys(q, params), false)...)
+
+	return q.BuildWithFlavor(sqlbuilder.PostgreSQL)
+}
+
+// queryBooks runs a books listing query and scans its rows.
+func (repo *BookRepo) queryBooks(ctx context.Context, params model.BookSearchParams, query string, args []any) ([]model.Book, error) {
+	var result []model.Book
+
+	err := repo.eachBook(ctx, params, query, args, func(book model.Book) error {
+		result = append(result, book)
+		return nil
+	})
+
+	return result, err
+}
+
+// eachBook runs a books query and calls fn for every scanned row.
+// Fuzzy searches run in a read-only transaction so the lowered
+// similarity threshold only applies to them.
+func (repo *BookRepo) eachBook(ctx context.Context, params model.BookSearchParams, query string, args []any, fn func(model.Book) error) error {
+	var queryer sqlx.QueryerContext = repo.deps.DB
+
+	if repo.isFuzzySearch(params) {
+		tx, err := repo.deps.DB.BeginTxx(ctx, &sql.TxOptions{ReadOnly: true})
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		_, err = tx.ExecContext(ctx, `SELECT set_config('pg_trgm.word_similarity_threshold', $1, true);`, fuzzySearchThreshold)
+		if err != nil {
+			return err
+		}
+
+		queryer = tx
+	}
+
+	rows, err := queryer.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var temp model.SQLBook
+		err := rows.StructScan(&temp)
+		if err != nil {
+			repo.deps.Logger.WarnContext(ctx, "failed to scan book data", slog.Any("error", err))
+			continue
+		}
+
+		err = fn(toBook(temp))
+		if err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// SuggestSearchTerm returns the title or author closest to a search
+// that found nothing, or an empty string without fuzzy search support.
+func (repo *BookRepo) SuggestSearchTerm(ctx context.Context, search string) (string, error) {
+	var suggestion string
+
+	if !repo.fuzzySearch || search == "" {
+		return suggestion, nil
+	}
+
+	q := `
+		SELECT term FROM (
+			SELECT title AS term, word_similarity(library.f_unaccent($1), library.f_unaccent(title)) AS score
+				FROM library.books WHERE deleted_at ISNULL
+			UNION ALL
+			SELECT author AS term, word_similarity(library.f_unaccent($1), library.f_unaccent(author)) AS score
+				FROM library.books WHERE deleted_at ISNULL
+		) candidates
+		WHERE score >= $2
+		ORDER BY score DESC, term
+		LIMIT 1;
+	`
+	err := repo.deps.DB.QueryRowxContext(ctx, q, search, suggestionThreshold).Scan(&suggestion)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+
+		return "", err
+	}
+
+	return suggestion, nil
+}
+
+func toBook(temp model.SQLBook) model.Book {
+	book := model.Book{
+		ID:          temp.ID.Int64,
+		Title:       temp.Title.String,
+		Author:      temp.Author.String,
+		Authors:     temp.Authors,
+		PublishYear: temp.PublishYear.Int64,
+		Published:   parsePublished(temp),
+		ISBN:        temp.ISBN.String,
+		ISBN10:      isbn10(temp.ISBN.String),
+		Identifiers: temp.Identifiers,
+		Genres:      temp.Genres,
+		Tags:        temp.Tags,
+		Editions:    temp.Editions,
+		Version:     temp.Version.Int64,
+		Rank:        temp.Rank.Float64,
+		BaseAudit: model.BaseAudit{
+			CreatedAt: &temp.CreatedAt.Time,
+			UpdatedAt: &temp.UpdatedAt.Time,
+		},
+	}
+
+	if temp.DeletedAt.Valid {
+		book.DeletedAt = &temp.DeletedAt.Time
+	}
+
+	for i := range book.Editions {
+		book.Editions[i].ISBN10 = isbn10(book.Editions[i].ISBN)
+	}
+
+	if temp.TitleHighlight.Valid || temp.AuthorHighlight.Valid {
+		book.Highlight = &model.BookHighlight{
+			Title:  temp.TitleHighlight.String,
+			Author: temp.AuthorHighlight.String,
+		}
+	}
+
+	return book
+}
+
+// parsePublished reads the published column, nil when the query did not
+// select it.
+func parsePublished(temp model.SQLBook) *edtf.Date {
+	if !temp.Published.Valid {
+		return nil
+	}
+
+	published, err := edtf.Parse(temp.Published.String)
+	if err != nil {
+		return nil
+	}
+
+	return &published
+}
+
+// bookPublished is the publication date of a book, its year when it has none.
+func bookPublished(book model.Book) edtf.Date {
+	if book.Published != nil {
+		return *book.Published
+	}
+
+	return edtf.Date{Year: book.PublishYear}
+}
+
+// nullDatePart stores an unknown month or day as NULL.
+func nullDatePart(n int) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(n), Valid: n != 0}
+}
+
+// sortKey is a single ORDER BY term of the books listing.
+// Field names the term in cursors, Expr is the SQL expression it orders by.
+type sortKey struct {
+	Field string
+	Expr  string
+	Desc  bool
+}
+
+// sortableBookFields whitelists the fields clients can sort by,
+// mapped to their column.
+var sortableBookFields = map[string]string{
+	"title":        "title",
+	"author":       "author",
+	"publish_year": "publish_year",
+	"created_at":   "created_at",
+	"updated_at":   "updated_at",
+	"deleted_at":   "deleted_at",
+}
+
+// bookSortKeys returns the ordering of the books listing,
+// always ending with id as the unique tie breaker.
+// Full-text searches are ordered by relevance unless a sort is given.
+func bookSortKeys(q *sqlbuilder.SelectBuilder, params model.BookSearchParams) []sortKey {
+	keys := make([]sortKey, 0, len(params.Sort)+1)
+	for _, sort := range params.Sort {
+		column, ok := sortableBookFields[sort.Field]
+		if !ok {
+			continue
+		}
+		keys = append(keys, sortKey{Field: sort.Field, Expr: column, Desc: sort.Desc})
+	}
+
+	if len(keys) == 0 && isFullTextSearch(params) {
+		keys = append(keys, sortKey{Field: "rank", Expr: bookRankExpr(q, params), Desc: true})
+	}
+
+	return append(keys, sortKey{Field: "id", Expr: "id"})
+}
+
+func (repo *BookRepo) isFuzzySearch(params model.BookSearchParams) bool {
+	return repo.fuzzySearch && params.Search != "" && !isFullTextSearch(params)
+}
+
+func isFullTextSearch(params model.BookSearchParams) bool {
+	return params.Search != "" && params.SearchMode == model.SearchModeFullText
+}
+
+// bookTSQueryExpr parses the search input with websearch syntax,
+// so quoted phrases, OR and -negation work as users expect.
+func bookTSQueryExpr(q *sqlbuilder.SelectBuilder, params model.BookSearchParams) string {
+	return "websearch_to_tsquery('english', " + q.Var(params.Search) + ")"
+}
+
+func bookRankExpr(q *sqlbuilder.SelectBuilder, params model.BookSearchParams) string {
+	return "ts_rank(search_vector, " + bookTSQueryExpr(q, params) + ")"
+}
+
+// selectBookSearchColumns adds relevance and highlighted snippets on full-text search.
+func selectBookSearchColumns(q *sqlbuilder.SelectBuilder, params model.BookSearchParams) {
+	if !isFullTextSearch(params) {
+		return
+	}
+
+	headline := func(column string) string {
+		return "ts_headline('english', " + column + ", " + bookTSQueryExpr(q, params) + ", 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true')"
+	}
+
+	q.SelectMore(
+		bookRankExpr(q, params)+" AS rank",
+		headline("title")+" AS title_highlight",
+		headline("author")+" AS author_highlight",
+	)
+}
+
+func applyBookFilters(q *sqlbuilder.SelectBuilder, params model.BookSearchParams, fuzzy bool) {
+	switch {
+	case isFullTextSearch(params):
+		q.Where("search_vector @@ " + bookTSQueryExpr(q, params))
+	case params.Search != "" && fuzzy:
+		// accent insensitive substring match, or a close enough word for typos
+		pattern := "library.f_unaccent(" + q.Var("%"+params.Search+"%") + ")"
+		term := "library.f_unaccent(" + q.Var(params.Search) + ")"
+		q.Where(
+			q.Or(
+				"library.f_unaccent(title) ILIKE "+pattern,
+				"library.f_unaccent(author) ILIKE "+pattern,
+				term+" <% library.f_unaccent(title)",
+				term+" <% library.f_unaccent(author)",
+			),
+		)
+	case params.Search != "":
+		q.Where(
+			q.Or(
+				q.ILike("title", "%"+params.Search+"%"),
+				q.ILike("author", "%"+params.Search+"%"),
+			),
+		)
+	}
+
+	if params.Author != "" {
+		q.Where(q.ILike("author", "%"+params.Author+"%"))
+	}
+
+	if params.AuthorID > 0 {
+		q.Where(bookAuthorCondition(q, params))
+	}
+
+	if params.GenreID > 0 {
+		q.Where("EXISTS (SELECT 1 FROM library.book_genres bg WHERE bg.book_id = books.id AND bg.genre_id IN (SELECT library.genre_subtree(" + q.Var(params.GenreID) + ")))")
+	}
+
+	if params.Tag != "" {
+		q.Where("EXISTS (SELECT 1 FROM library.book_tags bt JOIN library.tags t ON t.id = bt.tag_id WHERE bt.book_id = books.id AND t.name = " + q.Var(params.Tag) + ")")
+	}
+
+	if params.Identifier.Scheme != "" {
+		q.Where("EXISTS (SELECT 1 FROM library.book_identifiers bi WHERE bi.book_id = books.id AND bi.scheme = " + q.Var(params.Identifier.Scheme) + " AND bi.value = " + q.Var(params.Identifier.Value) + ")")
+	}
+
+	if params.EditionFormat != "" {
+		q.Where("EXISTS (SELECT 1 FROM library.book_editions be WHERE be.book_id = books.id AND be.format = " + q.Var(params.EditionFormat) + ")")
+	}
+
+	if params.EditionLanguage != "" {
+		q.Where("EXISTS (SELECT 1 FROM library.book_editions be WHERE be.book_id = books.id AND be.language = " + q.Var(params.EditionLanguage) + ")")
+	}
+
+	// a book matches when it may have been published in the range, so "1949"
+	// is within published_from=1949-06 and "1949-06" within published_to=1949
+	if params.PublishedFrom != nil {
+		from := params.PublishedFrom.Start()
+		q.Where("(publish_year, COALESCE(publish_month, 12), COALESCE(publish_day, 31)) >= (" + q.Var(from.Year) + ", " + q.Var(from.Month) + ", " + q.Var(from.Day) + ")")
+	}
+
+	if params.PublishedTo != nil {
+		to := params.PublishedTo.End()
+		q.Where("(publish_year, COALESCE(publish_month, 1), COALESCE(publish_day, 1)) <= (" + q.Var(to.Year) + ", " + q.Var(to.Month) + ", " + q.Var(to.Day) + ")")
+	}
+
+	if params.CreatedAfter != nil {
+		q.Where(q.GreaterThan("created_at", *params.CreatedAfter))
+	}
+
+	if len(params.IDs) > 0 {
+		ids := make([]any, 0, len(params.IDs))
+		for _, id := range params.IDs {
+			ids = append(ids, id)
+		}
+		q.Where(q.In("id", ids...))
+	}
+
+	switch {
+	case params.OnlyDeleted:
+		q.Where(q.IsNotNull("deleted_at"))
+	case params.IncludeDeleted:
+	default:
+		q.Where(q.IsNull("deleted_at"))
+	}
+}
+
+// bookAuthorCondition matches books crediting params.AuthorID, past catalogs
+// by the author list the book version was written with.
+func bookAuthorCondition(q *sqlbuilder.SelectBuilder, params model.BookSearchParams) string {
+	if params.AsOf != nil {
+		return "authors @> " + q.Var(fmt.Sprintf(`[{"id": %d}]`, params.AuthorID)) + "::jsonb"
+	}
+
+	return "EXISTS (SELECT 1 FROM library.book_authors ba WHERE ba.book_id = books.id AND ba.author_id = " + q.Var(params.AuthorID) + ")"
+}
+
+// sortSignature identifies the ordering a cursor was issued for,
+// so a cursor can not be replayed against a different ordering.
+func sortSignature(keys []sortKey) string {
+	terms := make([]string, 0, len(keys))
+	for _, key := range keys {
+		direction := "asc"
+		if key.Desc {
+			direction = "desc"
+		}
+		terms = append(terms, key.Field+":"+direction)
+	}
+
+	return strings.Join(terms, ",")
+}
+
+func orderByClause(keys []sortKey, backward bool) []string {
+	terms := make([]string, 0, len(keys))
+	for _, key := range keys {
+		// reading backward flips every direction
+		if key.Desc != backward {
+			terms = append(terms, key.Expr+" DESC")
+		} else {
+			terms = append(terms, key.Expr+" ASC")
+		}
+	}
+
+	return terms
+}
+
+// keysetCondition builds the "row comes after the cursor" condition,
+// i.e. (k1 > v1) OR (k1 = v1 AND k2 > v2) OR ..., honoring each key direction.
+func keysetCondition(q *sqlbuilder.SelectBuilder, keys []sortKey, values []any, backward bool) string {
+	ors := make([]string, 0, len(keys))
+	for i, key := range keys {
+		ands := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			ands = append(ands, q.Equal(keys[j].Expr, values[j]))
+		}
+
+		if key.Desc != backward {
+			ands = append(ands, q.LessThan(key.Expr, values[i]))
+		} else {
+			ands = append(ands, q.GreaterThan(key.Expr, values[i]))
+		}
+
+		ors = append(ors, q.And(ands...))
+	}
+
+	return q.Or(ors...)
+}
+
+func bookCursor(book model.Book, keys []sortKey, signature string) pagination.Cursor {
+	cursor := pagination.Cursor{
+		Sort: signature,
+		ID:   book.ID,
+	}
+
+	// the last key is always the id tie breaker
+	for _, key := range keys[:len(keys)-1] {
+		cursor.Values = append(cursor.Values, bookColumnValue(book, key.Field))
+	}
+
+	return cursor
+}
+
+func bookColumnValue(book model.Book, column string) string {
+	switch column {
+	case "id":
+		return strconv.FormatInt(book.ID, 10)
+	case "title":
+		return book.Title
+	case "author":
+		return book.Author
+	case "publish_year":
+		return strconv.FormatInt(book.PublishYear, 10)
+	case "rank":
+		return strconv.FormatFloat(book.Rank, 'g', -1, 32)
+	case "created_at":
+		if book.CreatedAt != nil {
+			return book.CreatedAt.Format(time.RFC3339Nano)
+		}
+	case "updated_at":
+		if book.UpdatedAt != nil {
+			return book.UpdatedAt.Format(time.RFC3339Nano)
+		}
+	case "deleted_at":
+		if book.DeletedAt != nil {
+			return book.DeletedAt.Format(time.RFC3339Nano)
+		}
+	}
+
+	return ""
+}
+
+// GetBookRevisions returns every recorded revision of the book, oldest first.
+// Soft deleted books keep their history until they are purged.
+func (repo *BookRepo) GetBookRevisions(ctx context.Context, bookID int64) ([]model.BookRevision, error) {
+	var rows []model.SQLBookRevision
+
+	q := `
+		SELECT book_id, revision, operation, actor, request_id, reverted_to, before, after, created_at
+			FROM library.book_revisions
+			WHERE book_id = $1
+			ORDER BY revision ASC;
+	`
+	err := repo.deps.DB.SelectContext(ctx, &rows, q, bookID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) == 0 {
+		return nil, xerrors.NewClientError(xerrors.ErrDataNotFound)
+	}
+
+	result := make([]model.BookRevision, 0, len(rows))
+	for _, row := range rows {
+		revision, err := toBookRevision(row)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, revision)
+	}
+
+	return result, nil
+}
+
+func (repo *BookRepo) GetBookRevision(ctx context.Context, bookID int64, revision int64) (model.BookRevision, error) {
+	var row model.SQLBookRevision
+
+	q := `
+		SELECT book_id, revision, operation, actor, request_id, reverted_to, before, after, created_at
+			FROM library.book_revisions
+			WHERE book_id = $1 AND revision = $2;
+	`
+	err := repo.deps.DB.QueryRowxContext(ctx, q, bookID, revision).StructScan(&row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.BookRevision{}, xerrors.NewClientError(xerrors.ErrDataNotFound)
+		}
+
+		return model.BookRevision{}, err
+	}
+
+	return toBookRevision(row)
+}
+
+func toBookRevision(temp model.SQLBookRevision) (model.BookRevision, error) {
+	result := model.BookRevision{
+		BookID:     temp.BookID.Int64,
+		Revision:   temp.Revision.Int64,
+		Operation:  temp.Operation.String,
+		Actor:      temp.Actor.String,
+		RequestID:  temp.RequestID.String,
+		RevertedTo: temp.RevertedTo.Int64,
+	}
+
+	if temp.CreatedAt.Valid {
+		result.CreatedAt = &temp.CreatedAt.Time
+	}
+
+	// snapshots are stored with the same json keys model.Book uses
+	if len(temp.Before) > 0 {
+		err := json.Unmarshal(temp.Before, &result.Before)
+		if err != nil {
+			return model.BookRevision{}, fmt.Errorf("invalid book revision snapshot: %v", err)
+		}
+	}
+	if len(temp.After) > 0 {
+		err := json.Unmarshal(temp.After, &result.After)
+		if err != nil {
+			return model.BookRevision{}, fmt.Errorf("invalid book revision snapshot: %v", err)
+		}
 	}
 
 	return result, nil
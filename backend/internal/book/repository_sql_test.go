@@ -3,25 +3,66 @@ package book
 import (
 	"byfood-app/internal/core"
 	"byfood-app/internal/model"
+	"byfood-app/internal/pkg/authorname"
+	"byfood-app/internal/pkg/edtf"
 	"byfood-app/internal/pkg/pagination"
+	"byfood-app/internal/pkg/xauth"
+	"byfood-app/internal/pkg/xerrors"
 	"context"
+	"database/sql"
+	"errors"
 	"log/slog"
+	"net/http"
 	"reflect"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-chi/chi/v5/middleware"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
+// expectBeginTx expects a write transaction tagged for the book_revisions trigger.
+func expectBeginTx(mockDB sqlmock.Sqlmock) {
+	mockDB.ExpectBegin()
+	mockDB.ExpectExec(`(?s)^.*set_config\('byfood.actor', \$1, true\), set_config\('byfood.request_id', \$2, true\).*$`).
+		WithArgs("", "").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+// expectResolveBookAuthors expects each credited name to be looked up by
+// its name key, resolving to the author as given.
+func expectResolveBookAuthors(mockDB sqlmock.Sqlmock, authors ...model.BookAuthor) {
+	for _, author := range authors {
+		mockDB.ExpectQuery(`(?s)^.*WITH found AS.*name_key = \$2.*INSERT INTO library.authors \(name, name_key\).*$`).
+			WithArgs(author.Name, authorname.Key(author.Name)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(author.ID, author.Name))
+	}
+}
+
+// expectWriteBookAuthors expects the credits of the book to be replaced by
+// the resolved authors.
+func expectWriteBookAuthors(mockDB sqlmock.Sqlmock, bookID int64, authors ...model.BookAuthor) {
+	mockDB.ExpectExec(`(?s)^DELETE FROM library.book_authors WHERE book_id = \$1;$`).
+		WithArgs(bookID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	for i, author := range authors {
+		mockDB.ExpectExec(`(?s)^INSERT INTO library.book_authors \(book_id, author_id, position, role\) VALUES \(\$1, \$2, \$3, \$4\);$`).
+			WithArgs(bookID, author.ID, i+1, author.Role).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+}
+
 func TestBookRepo_GetBooks(t *testing.T) {
 	type fields struct {
-		deps *core.Dependency
+		deps        *core.Dependency
+		fuzzySearch bool
 	}
 	type args struct {
 		ctx    context.Context
 		params model.BookSearchParams
-		page   pagination.Page
+		page   pagination.CursorPage
 	}
 
 	db, mockDB, err := sqlmock.New()
@@ -38,13 +79,14 @@ func TestBookRepo_GetBooks(t *testing.T) {
 	}
 
 	now := time.Now()
+	quarterEnd := time.Date(2025, 6, 30, 23, 59, 59, 0, time.FixedZone("WIB", 7*60*60))
 
 	tests := []struct {
 		name     string
 		fields   fields
 		args     args
 		want     []model.Book
-		want1    pagination.Metadata
+		want1    pagination.CursorMetadata
 		wantErr  bool
 		mockFunc func()
 	}{
@@ -56,9 +98,283 @@ func TestBookRepo_GetBooks(t *testing.T) {
 				params: model.BookSearchParams{
 					Search: "oda",
 				},
-				page: pagination.Page{
-					Page: 1,
-					Size: 10,
+				page: pagination.CursorPage{
+					Limit: 10,
+				},
+			},
+			want: []model.Book{
+				{
+					ID:          int64(1),
+					Title:       "One Piece",
+					Author:      "Eiichiro Oda",
+					PublishYear: 1997,
+					BaseAudit: model.BaseAudit{
+						CreatedAt: &now,
+						UpdatedAt: &now,
+					},
+				},
+			},
+			want1: pagination.CursorMetadata{
+				Limit: 10,
+			},
+			wantErr: false,
+			mockFunc: func() {
+				// q := `SELECT id, title, author, publish_year, created_at, updated_at FROM library.books WHERE (title ILIKE $1 OR author ILIKE $2) AND deleted_at IS NULL ORDER BY id ASC LIMIT $3`
+				expectedRows := sqlmock.NewRows([]string{"id", "title", "author", "publish_year", "created_at", "updated_at"})
+				expectedRows.AddRow(1, "One Piece", "Eiichiro Oda", 1997, now, now)
+				mockDB.ExpectQuery(`(?s)^.*$`).WithArgs("%oda%", "%oda%", 11).WillReturnRows(expectedRows)
+			},
+		},
+		{
+			name:   "success get next page with after cursor",
+			fields: mockFields,
+			args: args{
+				ctx: context.Background(),
+				page: pagination.CursorPage{
+					After: &pagination.Cursor{Sort: "id:asc", ID: 1},
+					Limit: 1,
+				},
+			},
+			want: []model.Book{
+				{
+					ID:          int64(2),
+					Title:       "Naruto",
+					Author:      "Masashi Kishimoto",
+					PublishYear: 1997,
+					BaseAudit: model.BaseAudit{
+						CreatedAt: &now,
+						UpdatedAt: &now,
+					},
+				},
+			},
+			want1: pagination.CursorMetadata{
+				Limit: 1,
+				Next:  &pagination.Cursor{Sort: "id:asc", ID: 2},
+				Prev:  &pagination.Cursor{Sort: "id:asc", ID: 2},
+			},
+			wantErr: false,
+			mockFunc: func() {
+				// q := `SELECT id, title, author, publish_year, created_at, updated_at FROM library.books WHERE deleted_at IS NULL AND (id > $1) ORDER BY id ASC LIMIT $2`
+				expectedRows := sqlmock.NewRows([]string{"id", "title", "author", "publish_year", "created_at", "updated_at"})
+				expectedRows.AddRow(2, "Naruto", "Masashi Kishimoto", 1997, now, now)
+				expectedRows.AddRow(3, "Bleach", "Tite Kubo", 2001, now, now)
+				mockDB.ExpectQuery(`(?s)^.*$`).WithArgs(int64(1), 2).WillReturnRows(expectedRows)
+			},
+		},
+		{
+			name:   "success get books with filters and sort after cursor",
+			fields: mockFields,
+			args: args{
+				ctx: context.Background(),
+				params: model.BookSearchParams{
+					Author:      "tolkien",
+					PublishedTo: &edtf.Date{Year: 1949},
+					Sort:        []model.SortParam{{Field: "publish_year", Desc: true}},
+				},
+				page: pagination.CursorPage{
+					After: &pagination.Cursor{Sort: "publish_year:desc,id:asc", Values: []string{"1954"}, ID: 10},
+					Limit: 10,
+				},
+			},
+			want: []model.Book{
+				{
+					ID:          int64(8),
+					Title:       "The Hobbit",
+					Author:      "J.R.R. Tolkien",
+					PublishYear: 1937,
+					BaseAudit: model.BaseAudit{
+						CreatedAt: &now,
+						UpdatedAt: &now,
+					},
+				},
+			},
+			want1: pagination.CursorMetadata{
+				Limit: 10,
+				Prev:  &pagination.Cursor{Sort: "publish_year:desc,id:asc", Values: []string{"1937"}, ID: 8},
+			},
+			wantErr: false,
+			mockFunc: func() {
+				// q := `SELECT id, title, author, publish_year, created_at, updated_at FROM library.books WHERE author ILIKE $1 AND (publish_year, COALESCE(publish_month, 1), COALESCE(publish_day, 1)) <= ($2, $3, $4) AND deleted_at IS NULL AND ((publish_year < $5) OR (publish_year = $6 AND id > $7)) ORDER BY publish_year DESC, id ASC LIMIT $8`
+				expectedRows := sqlmock.NewRows([]string{"id", "title", "author", "publish_year", "created_at", "updated_at"})
+				expectedRows.AddRow(8, "The Hobbit", "J.R.R. Tolkien", 1937, now, now)
+				mockDB.ExpectQuery(`(?s)^.*ORDER BY publish_year DESC, id ASC.*$`).WithArgs("%tolkien%", int64(1949), 12, 31, "1954", "1954", int64(10), 11).WillReturnRows(expectedRows)
+			},
+		},
+		{
+			name:   "success get books published from a month",
+			fields: mockFields,
+			args: args{
+				ctx: context.Background(),
+				params: model.BookSearchParams{
+					PublishedFrom: &edtf.Date{Year: 1949, Month: 6},
+				},
+				page: pagination.CursorPage{Limit: 10},
+			},
+			want: []model.Book{
+				{
+					ID:          int64(5),
+					Title:       "1984",
+					Author:      "George Orwell",
+					PublishYear: 1949,
+					Published:   &edtf.Date{Year: 1949, Month: 6, Day: 8},
+					BaseAudit: model.BaseAudit{
+						CreatedAt: &now,
+						UpdatedAt: &now,
+					},
+				},
+				{
+					ID:          int64(7),
+					Title:       "The Sheltering Sky",
+					Author:      "Paul Bowles",
+					PublishYear: 1949,
+					Published:   &edtf.Date{Year: 1949, Approximate: true},
+					BaseAudit: model.BaseAudit{
+						CreatedAt: &now,
+						UpdatedAt: &now,
+					},
+				},
+			},
+			want1: pagination.CursorMetadata{
+				Limit: 10,
+			},
+			wantErr: false,
+			mockFunc: func() {
+				expectedRows := sqlmock.NewRows([]string{"id", "title", "author", "publish_year", "published", "created_at", "updated_at"})
+				expectedRows.AddRow(5, "1984", "George Orwell", 1949, "1949-06-08", now, now)
+				expectedRows.AddRow(7, "The Sheltering Sky", "Paul Bowles", 1949, "1949~", now, now)
+				mockDB.ExpectQuery(`(?s)^.*\(publish_year, COALESCE\(publish_month, 12\), COALESCE\(publish_day, 31\)\) >= \(\$1, \$2, \$3\).*$`).WithArgs(int64(1949), 6, 1, 11).WillReturnRows(expectedRows)
+			},
+		},
+		{
+			name:   "success get books with a large print edition including editions",
+			fields: mockFields,
+			args: args{
+				ctx: context.Background(),
+				params: model.BookSearchParams{
+					EditionFormat:   model.EditionFormatLargePrint,
+					IncludeEditions: true,
+				},
+				page: pagination.CursorPage{Limit: 10},
+			},
+			want: []model.Book{
+				{
+					ID:          int64(3),
+					Title:       "Pride and Prejudice",
+					Author:      "Jane Austen",
+					PublishYear: 1813,
+					Editions: []model.Edition{
+						{ID: 1, BookID: 3, Publisher: "Penguin Classics", Format: "paperback", ISBN: "9780141439518", ISBN10: "0141439513"},
+						{ID: 3, BookID: 3, Publisher: "Thorndike Press", Format: "large_print", PageCount: 603},
+					},
+					BaseAudit: model.BaseAudit{
+						CreatedAt: &now,
+						UpdatedAt: &now,
+					},
+				},
+			},
+			want1: pagination.CursorMetadata{
+				Limit: 10,
+			},
+			wantErr: false,
+			mockFunc: func() {
+				expectedRows := sqlmock.NewRows([]string{"id", "title", "author", "publish_year", "editions", "created_at", "updated_at"})
+				expectedRows.AddRow(3, "Pride and Prejudice", "Jane Austen", 1813, `[{"id": 1, "book_id": 3, "publisher": "Penguin Classics", "format": "paperback", "isbn": "9780141439518"}, {"id": 3, "book_id": 3, "publisher": "Thorndike Press", "format": "large_print", "page_count": 603}]`, now, now)
+				mockDB.ExpectQuery(`(?s)^SELECT .*library.book_edition_list\(id\) AS editions FROM library.books WHERE EXISTS \(SELECT 1 FROM library.book_editions be WHERE be.book_id = books.id AND be.format = \$1\).*$`).WithArgs("large_print", 11).WillReturnRows(expectedRows)
+			},
+		},
+		{
+			name:   "success get books with full-text search ordered by rank",
+			fields: mockFields,
+			args: args{
+				ctx: context.Background(),
+				params: model.BookSearchParams{
+					Search:     "lord rings",
+					SearchMode: model.SearchModeFullText,
+				},
+				page: pagination.CursorPage{
+					Limit: 10,
+				},
+			},
+			want: []model.Book{
+				{
+					ID:          int64(10),
+					Title:       "The Lord of the Rings",
+					Author:      "J.R.R. Tolkien",
+					PublishYear: 1954,
+					Rank:        0.0607927,
+					Highlight: &model.BookHighlight{
+						Title:  "The <mark>Lord</mark> of the <mark>Rings</mark>",
+						Author: "J.R.R. Tolkien",
+					},
+					BaseAudit: model.BaseAudit{
+						CreatedAt: &now,
+						UpdatedAt: &now,
+					},
+				},
+			},
+			want1: pagination.CursorMetadata{
+				Limit: 10,
+			},
+			wantErr: false,
+			mockFunc: func() {
+				// q := `SELECT id, title, author, publish_year, created_at, updated_at, ts_rank(search_vector, websearch_to_tsquery('english', $1)) AS rank, ts_headline(...) AS title_highlight, ts_headline(...) AS author_highlight FROM library.books WHERE search_vector @@ websearch_to_tsquery('english', $4) AND deleted_at IS NULL ORDER BY ts_rank(search_vector, websearch_to_tsquery('english', $5)) DESC, id ASC LIMIT $6`
+				expectedRows := sqlmock.NewRows([]string{"id", "title", "author", "publish_year", "created_at", "updated_at", "rank", "title_highlight", "author_highlight"})
+				expectedRows.AddRow(10, "The Lord of the Rings", "J.R.R. Tolkien", 1954, now, now, 0.0607927, "The <mark>Lord</mark> of the <mark>Rings</mark>", "J.R.R. Tolkien")
+				mockDB.ExpectQuery(`(?s)^.*search_vector @@ websearch_to_tsquery.*ORDER BY ts_rank.* DESC, id ASC.*$`).WithArgs("lord rings", "lord rings", "lord rings", "lord rings", "lord rings", 11).WillReturnRows(expectedRows)
+			},
+		},
+		{
+			name: "success get books with fuzzy search",
+			fields: fields{
+				deps:        mockFields.deps,
+				fuzzySearch: true,
+			},
+			args: args{
+				ctx: context.Background(),
+				params: model.BookSearchParams{
+					Search: "tolkein",
+				},
+				page: pagination.CursorPage{
+					Limit: 10,
+				},
+			},
+			want: []model.Book{
+				{
+					ID:          int64(8),
+					Title:       "The Hobbit",
+					Author:      "J.R.R. Tolkien",
+					PublishYear: 1937,
+					BaseAudit: model.BaseAudit{
+						CreatedAt: &now,
+						UpdatedAt: &now,
+					},
+				},
+			},
+			want1: pagination.CursorMetadata{
+				Limit: 10,
+			},
+			wantErr: false,
+			mockFunc: func() {
+				// q := `SELECT id, title, author, publish_year, created_at, updated_at FROM library.books WHERE (library.f_unaccent(title) ILIKE library.f_unaccent($1) OR library.f_unaccent(author) ILIKE library.f_unaccent($2) OR library.f_unaccent($3) <% library.f_unaccent(title) OR library.f_unaccent($4) <% library.f_unaccent(author)) AND deleted_at IS NULL ORDER BY id ASC LIMIT $5`
+				expectedRows := sqlmock.NewRows([]string{"id", "title", "author", "publish_year", "created_at", "updated_at"})
+				expectedRows.AddRow(8, "The Hobbit", "J.R.R. Tolkien", 1937, now, now)
+				mockDB.ExpectBegin()
+				mockDB.ExpectExec(`(?s)^.*set_config.*$`).WithArgs(fuzzySearchThreshold).WillReturnResult(sqlmock.NewResult(0, 1))
+				mockDB.ExpectQuery(`(?s)^.*<% library.f_unaccent\(title\).*$`).WithArgs("%tolkein%", "%tolkein%", "tolkein", "tolkein", 11).WillReturnRows(expectedRows)
+				mockDB.ExpectRollback()
+			},
+		},
+		{
+			name:   "success get books as of a past moment",
+			fields: mockFields,
+			args: args{
+				ctx: context.Background(),
+				params: model.BookSearchParams{
+					Author: "oda",
+					AsOf:   &quarterEnd,
+				},
+				page: pagination.CursorPage{
+					Limit: 10,
 				},
 			},
 			want: []model.Book{
@@ -73,27 +389,174 @@ func TestBookRepo_GetBooks(t *testing.T) {
 					},
 				},
 			},
-			want1: pagination.Metadata{
-				CurrentPage:  1,
-				PageSize:     10,
-				FirstPage:    1,
-				LastPage:     1,
-				TotalRecords: 1,
+			want1: pagination.CursorMetadata{
+				Limit: 10,
 			},
 			wantErr: false,
 			mockFunc: func() {
-				// q := `SELECT id, title, author, publish_year, created_at, updated_at FROM library.books WHERE (title ILIKE $1 OR author ILIKE $2) AND deleted_at IS NULL ORDER BY id`
 				expectedRows := sqlmock.NewRows([]string{"id", "title", "author", "publish_year", "created_at", "updated_at"})
 				expectedRows.AddRow(1, "One Piece", "Eiichiro Oda", 1997, now, now)
-				mockDB.ExpectQuery(`(?s)^.*$`).WithArgs("%oda%", "%oda%", 10, 0).WillReturnRows(expectedRows)
-				mockDB.ExpectQuery(`(?s)^.*$`).WithArgs("%oda%", "%oda%").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mockDB.ExpectQuery(`(?s)^SELECT .* FROM library.books_as_of\(\$1\) AS books WHERE author ILIKE \$2 AND deleted_at IS NULL .*$`).
+					WithArgs(quarterEnd.UTC(), "%oda%", 11).
+					WillReturnRows(expectedRows)
+			},
+		},
+		{
+			name:   "success get books crediting an author",
+			fields: mockFields,
+			args: args{
+				ctx: context.Background(),
+				params: model.BookSearchParams{
+					AuthorID: 4,
+				},
+				page: pagination.CursorPage{
+					Limit: 10,
+				},
+			},
+			want: []model.Book{
+				{
+					ID:          int64(1),
+					Title:       "Good Omens",
+					Author:      "Terry Pratchett & Neil Gaiman",
+					PublishYear: 1990,
+					BaseAudit: model.BaseAudit{
+						CreatedAt: &now,
+						UpdatedAt: &now,
+					},
+				},
+			},
+			want1: pagination.CursorMetadata{
+				Limit: 10,
+			},
+			wantErr: false,
+			mockFunc: func() {
+				expectedRows := sqlmock.NewRows([]string{"id", "title", "author", "publish_year", "created_at", "updated_at"})
+				expectedRows.AddRow(1, "Good Omens", "Terry Pratchett & Neil Gaiman", 1990, now, now)
+				mockDB.ExpectQuery(`(?s)^SELECT .* FROM library.books WHERE EXISTS \(SELECT 1 FROM library.book_authors ba WHERE ba.book_id = books.id AND ba.author_id = \$1\) AND deleted_at IS NULL .*$`).
+					WithArgs(int64(4), 11).
+					WillReturnRows(expectedRows)
+			},
+		},
+		{
+			name:   "no books crediting an author as of a past date",
+			fields: mockFields,
+			args: args{
+				ctx: context.Background(),
+				params: model.BookSearchParams{
+					AuthorID: 4,
+					AsOf:     &quarterEnd,
+				},
+				page: pagination.CursorPage{
+					Limit: 10,
+				},
+			},
+			want:    nil,
+			want1:   pagination.CursorMetadata{Limit: 10},
+			wantErr: true,
+			mockFunc: func() {
+				expectedRows := sqlmock.NewRows([]string{"id", "title", "author", "publish_year", "created_at", "updated_at"})
+				mockDB.ExpectQuery(`(?s)^SELECT .* FROM library.books_as_of\(\$1\) AS books WHERE authors @> \$2::jsonb AND deleted_at IS NULL .*$`).
+					WithArgs(quarterEnd.UTC(), `[{"id": 4}]`, 11).
+					WillReturnRows(expectedRows)
+			},
+		},
+		{
+			name:   "success get books in a genre and below with a tag",
+			fields: mockFields,
+			args: args{
+				ctx: context.Background(),
+				params: model.BookSearchParams{
+					GenreID: 2,
+					Tag:     "quest",
+				},
+				page: pagination.CursorPage{
+					Limit: 10,
+				},
+			},
+			want: []model.Book{
+				{
+					ID:          int64(8),
+					Title:       "The Hobbit",
+					Author:      "J.R.R. Tolkien",
+					PublishYear: 1937,
+					Genres:      []model.BookGenre{{ID: 3, Name: "High Fantasy"}},
+					Tags:        []string{"quest"},
+					BaseAudit: model.BaseAudit{
+						CreatedAt: &now,
+						UpdatedAt: &now,
+					},
+				},
+			},
+			want1: pagination.CursorMetadata{
+				Limit: 10,
+			},
+			wantErr: false,
+			mockFunc: func() {
+				expectedRows := sqlmock.NewRows([]string{"id", "title", "author", "publish_year", "genres", "tags", "created_at", "updated_at"})
+				expectedRows.AddRow(8, "The Hobbit", "J.R.R. Tolkien", 1937, `[{"id": 3, "name": "High Fantasy"}]`, "{quest}", now, now)
+				mockDB.ExpectQuery(`(?s)^SELECT .*library.book_genre_list\(id\) AS genres, library.book_tag_list\(id\) AS tags FROM library.books WHERE EXISTS \(SELECT 1 FROM library.book_genres bg WHERE bg.book_id = books.id AND bg.genre_id IN \(SELECT library.genre_subtree\(\$1\)\)\) AND EXISTS \(SELECT 1 FROM library.book_tags bt JOIN library.tags t ON t.id = bt.tag_id WHERE bt.book_id = books.id AND t.name = \$2\) AND deleted_at IS NULL .*$`).
+					WithArgs(int64(2), "quest", 11).
+					WillReturnRows(expectedRows)
+			},
+		},
+		{
+			name:   "success get books by outside identifier",
+			fields: mockFields,
+			args: args{
+				ctx: context.Background(),
+				params: model.BookSearchParams{
+					Identifier: model.BookIdentifier{Scheme: "oclc", Value: "12345"},
+				},
+				page: pagination.CursorPage{
+					Limit: 10,
+				},
+			},
+			want: []model.Book{
+				{
+					ID:          int64(8),
+					Title:       "The Hobbit",
+					Author:      "J.R.R. Tolkien",
+					PublishYear: 1937,
+					Identifiers: []model.BookIdentifier{{Scheme: "oclc", Value: "12345"}},
+					BaseAudit: model.BaseAudit{
+						CreatedAt: &now,
+						UpdatedAt: &now,
+					},
+				},
+			},
+			want1: pagination.CursorMetadata{
+				Limit: 10,
+			},
+			wantErr: false,
+			mockFunc: func() {
+				expectedRows := sqlmock.NewRows([]string{"id", "title", "author", "publish_year", "identifiers", "created_at", "updated_at"})
+				expectedRows.AddRow(8, "The Hobbit", "J.R.R. Tolkien", 1937, `[{"scheme": "oclc", "value": "12345"}]`, now, now)
+				mockDB.ExpectQuery(`(?s)^SELECT .*library.book_identifier_list\(id\) AS identifiers, .* FROM library.books WHERE EXISTS \(SELECT 1 FROM library.book_identifiers bi WHERE bi.book_id = books.id AND bi.scheme = \$1 AND bi.value = \$2\) AND deleted_at IS NULL .*$`).
+					WithArgs("oclc", "12345", 11).
+					WillReturnRows(expectedRows)
+			},
+		},
+		{
+			name:   "failed get books with cursor from another ordering",
+			fields: mockFields,
+			args: args{
+				ctx: context.Background(),
+				page: pagination.CursorPage{
+					After: &pagination.Cursor{Sort: "title:asc,id:asc", Values: []string{"One Piece"}, ID: 1},
+					Limit: 10,
+				},
 			},
+			want:     nil,
+			want1:    pagination.CursorMetadata{Limit: 10},
+			wantErr:  true,
+			mockFunc: func() {},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			repo := &BookRepo{
-				deps: tt.fields.deps,
+				deps:        tt.fields.deps,
+				fuzzySearch: tt.fields.fuzzySearch,
 			}
 
 			tt.mockFunc()
@@ -112,3 +575,1606 @@ func TestBookRepo_GetBooks(t *testing.T) {
 		})
 	}
 }
+
+func TestBookRepo_GetBookSuggestions(t *testing.T) {
+	type fields struct {
+		deps *core.Dependency
+	}
+	type args struct {
+		ctx    context.Context
+		params model.BookSuggestParams
+	}
+
+	db, mockDB, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dbx := sqlx.NewDb(db, "sqlmock")
+
+	mockFields := fields{
+		deps: &core.Dependency{
+			Logger: slog.Default(),
+			DB:     dbx,
+		},
+	}
+
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		want     []model.BookSuggestion
+		wantErr  bool
+		mockFunc func()
+	}{
+		{
+			name:   "success get author suggestions by prefix",
+			fields: mockFields,
+			args: args{
+				ctx: context.Background(),
+				params: model.BookSuggestParams{
+					Query: "J.R.R_",
+					Field: "author",
+					Limit: 5,
+				},
+			},
+			want: []model.BookSuggestion{
+				{Value: "J.R.R. Tolkien", Count: 2},
+			},
+			wantErr: false,
+			mockFunc: func() {
+				// q := `SELECT author AS value, COUNT(1) AS count FROM library.books WHERE lower(author) LIKE $1 AND deleted_at IS NULL GROUP BY author ORDER BY count DESC, author ASC LIMIT $2`
+				expectedRows := sqlmock.NewRows([]string{"value", "count"})
+				expectedRows.AddRow("J.R.R. Tolkien", 2)
+				mockDB.ExpectQuery(`(?s)^.*lower\(author\) LIKE.*GROUP BY author.*$`).WithArgs(`j.r.r\_%`, 5).WillReturnRows(expectedRows)
+			},
+		},
+		{
+			name:   "failed get suggestions with unknown field",
+			fields: mockFields,
+			args: args{
+				ctx: context.Background(),
+				params: model.BookSuggestParams{
+					Query: "19",
+					Field: "publish_year",
+					Limit: 5,
+				},
+			},
+			want:     nil,
+			wantErr:  true,
+			mockFunc: func() {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &BookRepo{
+				deps: tt.fields.deps,
+			}
+
+			tt.mockFunc()
+
+			got, err := repo.GetBookSuggestions(tt.args.ctx, tt.args.params)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("BookRepo.GetBookSuggestions() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BookRepo.GetBookSuggestions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBookRepo_RestoreBook(t *testing.T) {
+	type fields struct {
+		deps *core.Dependency
+	}
+	type args struct {
+		ctx context.Context
+		id  int64
+	}
+
+	db, mockDB, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dbx := sqlx.NewDb(db, "sqlmock")
+
+	mockFields := fields{
+		deps: &core.Dependency{
+			Logger: slog.Default(),
+			DB:     dbx,
+		},
+	}
+
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		want     model.Book
+		wantErr  bool
+		mockFunc func()
+	}{
+		{
+			name:   "success restore deleted book",
+			fields: mockFields,
+			args: args{
+				ctx: context.Background(),
+				id:  int64(1),
+			},
+			want: model.Book{
+				ID:          int64(1),
+				Title:       "One Piece",
+				Author:      "Eiichiro Oda",
+				PublishYear: 1997,
+				Version:     3,
+				BaseAudit: model.BaseAudit{
+					CreatedAt: &now,
+					UpdatedAt: &now,
+				},
+			},
+			wantErr: false,
+			mockFunc: func() {
+				expectedRows := sqlmock.NewRows([]string{"id", "title", "author", "publish_year", "version", "created_at", "updated_at", "deleted_at"})
+				expectedRows.AddRow(1, "One Piece", "Eiichiro Oda", 1997, 3, now, now, nil)
+				expectBeginTx(mockDB)
+				mockDB.ExpectQuery(`(?s)^.*SET.*deleted_at = NULL.*$`).WithArgs(int64(1)).WillReturnRows(expectedRows)
+				mockDB.ExpectCommit()
+			},
+		},
+		{
+			name:   "failed restore book that is not deleted",
+			fields: mockFields,
+			args: args{
+				ctx: context.Background(),
+				id:  int64(2),
+			},
+			want:    model.Book{},
+			wantErr: true,
+			mockFunc: func() {
+				expectBeginTx(mockDB)
+				mockDB.ExpectQuery(`(?s)^.*SET.*deleted_at = NULL.*$`).WithArgs(int64(2)).WillReturnError(sql.ErrNoRows)
+				mockDB.ExpectRollback()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &BookRepo{
+				deps: tt.fields.deps,
+			}
+
+			tt.mockFunc()
+
+			got, err := repo.RestoreBook(tt.args.ctx, tt.args.id)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("BookRepo.RestoreBook() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BookRepo.RestoreBook() = %v, want %v", got, tt.want)
+			}
+			if err := mockDB.ExpectationsWereMet(); err != nil {
+				t.Errorf("BookRepo.RestoreBook() expectations = %v", err)
+			}
+		})
+	}
+}
+
+func TestBookRepo_UpdateBook(t *testing.T) {
+	type fields struct {
+		deps *core.Dependency
+	}
+	type args struct {
+		ctx  context.Context
+		data model.Book
+	}
+
+	db, mockDB, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dbx := sqlx.NewDb(db, "sqlmock")
+
+	mockFields := fields{
+		deps: &core.Dependency{
+			Logger: slog.Default(),
+			DB:     dbx,
+		},
+	}
+
+	now := time.Now()
+	data := model.Book{
+		ID:          int64(1),
+		Title:       "One Piece",
+		Author:      "Eiichiro Oda",
+		Authors:     []model.BookAuthor{{Name: "Eiichiro Oda", Role: model.AuthorRoleAuthor}},
+		PublishYear: 1997,
+		Version:     2,
+	}
+	oda := model.BookAuthor{ID: 7, Name: "Eiichiro Oda", Role: model.AuthorRoleAuthor}
+	withISBN := data
+	withISBN.Authors = []model.BookAuthor{oda}
+	withISBN.ISBN = "9781569319017"
+
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		want     model.Book
+		wantErr  bool
+		wantCode int
+		mockFunc func()
+	}{
+		{
+			name:   "success update book with current version",
+			fields: mockFields,
+			args: args{
+				ctx:  context.Background(),
+				data: data,
+			},
+			want: model.Book{
+				ID:          int64(1),
+				Title:       "One Piece",
+				Author:      "Eiichiro Oda",
+				Authors:     []model.BookAuthor{oda},
+				PublishYear: 1997,
+				Version:     3,
+				BaseAudit: model.BaseAudit{
+					CreatedAt: &now,
+					UpdatedAt: &now,
+				},
+			},
+			mockFunc: func() {
+				expectedRows := sqlmock.NewRows([]string{"id", "title", "author", "publish_year", "version", "created_at", "updated_at"})
+				expectedRows.AddRow(1, "One Piece", "Eiichiro Oda", 1997, 3, now, now)
+				expectBeginTx(mockDB)
+				expectResolveBookAuthors(mockDB, oda)
+				mockDB.ExpectQuery(`(?s)^.*UPDATE library.books.*isbn = \$8.*version = \$10.*$`).
+					WithArgs("One Piece", "Eiichiro Oda", int64(1997), nil, nil, false, false, nil, int64(1), int64(2)).
+					WillReturnRows(expectedRows)
+				expectWriteBookAuthors(mockDB, 1, oda)
+				mockDB.ExpectCommit()
+			},
+		},
+		{
+			name:   "failed update book with stale version",
+			fields: mockFields,
+			args: args{
+				ctx:  context.Background(),
+				data: data,
+			},
+			want:     model.Book{},
+			wantErr:  true,
+			wantCode: http.StatusPreconditionFailed,
+			mockFunc: func() {
+				expectBeginTx(mockDB)
+				expectResolveBookAuthors(mockDB, oda)
+				mockDB.ExpectQuery(`(?s)^.*UPDATE library.books.*isbn = \$8.*version = \$10.*$`).
+					WithArgs("One Piece", "Eiichiro Oda", int64(1997), nil, nil, false, false, nil, int64(1), int64(2)).
+					WillReturnError(sql.ErrNoRows)
+				mockDB.ExpectQuery(`(?s)^SELECT EXISTS.*$`).WithArgs(int64(1)).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+				mockDB.ExpectRollback()
+			},
+		},
+		{
+			name:   "failed update deleted book",
+			fields: mockFields,
+			args: args{
+				ctx:  context.Background(),
+				data: data,
+			},
+			want:     model.Book{},
+			wantErr:  true,
+			wantCode: http.StatusBadRequest,
+			mockFunc: func() {
+				expectBeginTx(mockDB)
+				expectResolveBookAuthors(mockDB, oda)
+				mockDB.ExpectQuery(`(?s)^.*UPDATE library.books.*isbn = \$8.*version = \$10.*$`).
+					WithArgs("One Piece", "Eiichiro Oda", int64(1997), nil, nil, false, false, nil, int64(1), int64(2)).
+					WillReturnError(sql.ErrNoRows)
+				mockDB.ExpectQuery(`(?s)^SELECT EXISTS.*$`).WithArgs(int64(1)).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+				mockDB.ExpectRollback()
+			},
+		},
+		{
+			name:   "failed update book with isbn of another book",
+			fields: mockFields,
+			args: args{
+				ctx:  context.Background(),
+				data: withISBN,
+			},
+			want:     withISBN,
+			wantErr:  true,
+			wantCode: http.StatusConflict,
+			mockFunc: func() {
+				expectBeginTx(mockDB)
+				expectResolveBookAuthors(mockDB, oda)
+				mockDB.ExpectQuery(`(?s)^.*UPDATE library.books.*isbn = \$8.*version = \$10.*$`).
+					WithArgs("One Piece", "Eiichiro Oda", int64(1997), nil, nil, false, false, "9781569319017", int64(1), int64(2)).
+					WillReturnError(&pq.Error{Code: "23505", Constraint: "idx_books_isbn"})
+				mockDB.ExpectRollback()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &BookRepo{
+				deps: tt.fields.deps,
+			}
+
+			tt.mockFunc()
+
+			got, err := repo.UpdateBook(tt.args.ctx, tt.args.data)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("BookRepo.UpdateBook() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && xerrors.ParseErrorTypeToCodeInt(err) != tt.wantCode {
+				t.Errorf("BookRepo.UpdateBook() error code = %v, want %v", xerrors.ParseErrorTypeToCodeInt(err), tt.wantCode)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BookRepo.UpdateBook() = %v, want %v", got, tt.want)
+			}
+			if err := mockDB.ExpectationsWereMet(); err != nil {
+				t.Errorf("BookRepo.UpdateBook() expectations = %v", err)
+			}
+		})
+	}
+}
+
+func TestBookRepo_PatchBook(t *testing.T) {
+	type fields struct {
+		deps *core.Dependency
+	}
+	type args struct {
+		ctx     context.Context
+		id      int64
+		version int64
+		apply   func(model.Book) (model.Book, error)
+	}
+
+	db, mockDB, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dbx := sqlx.NewDb(db, "sqlmock")
+
+	mockFields := fields{
+		deps: &core.Dependency{
+			Logger: slog.Default(),
+			DB:     dbx,
+		},
+	}
+
+	now := time.Now()
+	oda := model.BookAuthor{ID: 8, Name: "Eiichiro Oda", Role: model.AuthorRoleAuthor}
+	fixAuthor := func(book model.Book) (model.Book, error) {
+		book.Author = "Eiichiro Oda"
+		book.Authors = []model.BookAuthor{{Name: "Eiichiro Oda", Role: model.AuthorRoleAuthor}}
+		return book, nil
+	}
+	selectColumns := []string{"id", "title", "author", "authors", "publish_year", "version", "created_at", "updated_at"}
+	columns := []string{"id", "title", "author", "publish_year", "version", "created_at", "updated_at"}
+	typoAuthors := []byte(`[{"id": 7, "name": "Eichiro Oda", "role": "author"}]`)
+
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		want     model.Book
+		wantErr  bool
+		mockFunc func()
+	}{
+		{
+			name:   "success patch locked book",
+			fields: mockFields,
+			args: args{
+				ctx:     context.Background(),
+				id:      int64(1),
+				version: int64(2),
+				apply:   fixAuthor,
+			},
+			want: model.Book{
+				ID:          int64(1),
+				Title:       "One Piece",
+				Author:      "Eiichiro Oda",
+				Authors:     []model.BookAuthor{oda},
+				PublishYear: 1997,
+				Version:     3,
+				BaseAudit: model.BaseAudit{
+					CreatedAt: &now,
+					UpdatedAt: &now,
+				},
+			},
+			mockFunc: func() {
+				expectBeginTx(mockDB)
+				mockDB.ExpectQuery(`(?s)^.*SELECT.*library.book_author_list\(id\) AS authors.*FOR UPDATE.*$`).WithArgs(int64(1)).
+					WillReturnRows(sqlmock.NewRows(selectColumns).AddRow(1, "One Piece", "Eichiro Oda", typoAuthors, 1997, 2, now, now))
+				expectResolveBookAuthors(mockDB, oda)
+				mockDB.ExpectQuery(`(?s)^.*UPDATE library.books.*$`).
+					WithArgs("One Piece", "Eiichiro Oda", int64(1997), nil, nil, false, false, nil, int64(1)).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(1, "One Piece", "Eiichiro Oda", 1997, 3, now, now))
+				expectWriteBookAuthors(mockDB, 1, oda)
+				mockDB.ExpectCommit()
+			},
+		},
+		{
+			name:   "failed patch stale version",
+			fields: mockFields,
+			args: args{
+				ctx:     context.Background(),
+				id:      int64(1),
+				version: int64(1),
+				apply:   fixAuthor,
+			},
+			want:    model.Book{},
+			wantErr: true,
+			mockFunc: func() {
+				expectBeginTx(mockDB)
+				mockDB.ExpectQuery(`(?s)^.*SELECT.*FOR UPDATE.*$`).WithArgs(int64(1)).
+					WillReturnRows(sqlmock.NewRows(selectColumns).AddRow(1, "One Piece", "Eichiro Oda", typoAuthors, 1997, 2, now, now))
+				mockDB.ExpectRollback()
+			},
+		},
+		{
+			name:   "failed patch rejected by apply",
+			fields: mockFields,
+			args: args{
+				ctx:     context.Background(),
+				id:      int64(1),
+				version: int64(2),
+				apply: func(book model.Book) (model.Book, error) {
+					return model.Book{}, xerrors.NewClientError(errors.New("title field is empty"))
+				},
+			},
+			want:    model.Book{},
+			wantErr: true,
+			mockFunc: func() {
+				expectBeginTx(mockDB)
+				mockDB.ExpectQuery(`(?s)^.*SELECT.*FOR UPDATE.*$`).WithArgs(int64(1)).
+					WillReturnRows(sqlmock.NewRows(selectColumns).AddRow(1, "One Piece", "Eichiro Oda", typoAuthors, 1997, 2, now, now))
+				mockDB.ExpectRollback()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &BookRepo{
+				deps: tt.fields.deps,
+			}
+
+			tt.mockFunc()
+
+			got, err := repo.PatchBook(tt.args.ctx, tt.args.id, tt.args.version, tt.args.apply)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("BookRepo.PatchBook() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BookRepo.PatchBook() = %v, want %v", got, tt.want)
+			}
+			if err := mockDB.ExpectationsWereMet(); err != nil {
+				t.Errorf("BookRepo.PatchBook() expectations = %v", err)
+			}
+		})
+	}
+}
+
+func TestBookRepo_StoreBooks(t *testing.T) {
+	type fields struct {
+		deps *core.Dependency
+	}
+	type args struct {
+		ctx  context.Context
+		data []model.Book
+	}
+
+	db, mockDB, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dbx := sqlx.NewDb(db, "sqlmock")
+
+	mockFields := fields{
+		deps: &core.Dependency{
+			Logger: slog.Default(),
+			DB:     dbx,
+		},
+	}
+
+	now := time.Now()
+	columns := []string{"id", "title", "author", "publish_year", "version", "created_at", "updated_at"}
+	oda := model.BookAuthor{ID: 7, Name: "Eiichiro Oda", Role: model.AuthorRoleAuthor}
+	kubo := model.BookAuthor{ID: 9, Name: "Tite Kubo", Role: model.AuthorRoleAuthor}
+
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		want     []model.Book
+		wantErr  bool
+		mockFunc func()
+	}{
+		{
+			name:   "success store books in one multi row insert",
+			fields: mockFields,
+			args: args{
+				ctx: context.Background(),
+				data: []model.Book{
+					{Title: "One Piece", Author: "Eiichiro Oda", Authors: []model.BookAuthor{{Name: "Eiichiro Oda", Role: model.AuthorRoleAuthor}}, PublishYear: 1997},
+					{Title: "Bleach", Author: "Tite Kubo", Authors: []model.BookAuthor{{Name: "Tite Kubo", Role: model.AuthorRoleAuthor}}, PublishYear: 2001},
+				},
+			},
+			want: []model.Book{
+				{ID: 11, Title: "One Piece", Author: "Eiichiro Oda", Authors: []model.BookAuthor{oda}, PublishYear: 1997, Version: 1, BaseAudit: model.BaseAudit{CreatedAt: &now, UpdatedAt: &now}},
+				{ID: 12, Title: "Bleach", Author: "Tite Kubo", Authors: []model.BookAuthor{kubo}, PublishYear: 2001, Version: 1, BaseAudit: model.BaseAudit{CreatedAt: &now, UpdatedAt: &now}},
+			},
+			mockFunc: func() {
+				expectedRows := sqlmock.NewRows(columns).
+					AddRow(11, "One Piece", "Eiichiro Oda", 1997, 1, now, now).
+					AddRow(12, "Bleach", "Tite Kubo", 2001, 1, now, now)
+				expectBeginTx(mockDB)
+				expectResolveBookAuthors(mockDB, oda)
+				expectResolveBookAuthors(mockDB, kubo)
+				mockDB.ExpectQuery(`(?s)^INSERT INTO library.books \(title, author, publish_year, publish_month, publish_day, publish_approximate, publish_uncertain, isbn\) VALUES \(\$1, \$2, \$3, \$4, \$5, \$6, \$7, \$8\), \(\$9, \$10, \$11, \$12, \$13, \$14, \$15, \$16\) RETURNING.*$`).
+					WithArgs("One Piece", "Eiichiro Oda", int64(1997), nil, nil, false, false, nil, "Bleach", "Tite Kubo", int64(2001), nil, nil, false, false, nil).
+					WillReturnRows(expectedRows)
+				expectWriteBookAuthors(mockDB, 11, oda)
+				expectWriteBookAuthors(mockDB, 12, kubo)
+				mockDB.ExpectCommit()
+			},
+		},
+		{
+			name:   "failed store books rolls back",
+			fields: mockFields,
+			args: args{
+				ctx: context.Background(),
+				data: []model.Book{
+					{Title: "One Piece", Author: "Eiichiro Oda", PublishYear: 1997},
+				},
+			},
+			want:    nil,
+			wantErr: true,
+			mockFunc: func() {
+				expectBeginTx(mockDB)
+				mockDB.ExpectQuery(`(?s)^INSERT INTO library.books.*$`).WillReturnError(sql.ErrConnDone)
+				mockDB.ExpectRollback()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &BookRepo{
+				deps: tt.fields.deps,
+			}
+
+			tt.mockFunc()
+
+			got, err := repo.StoreBooks(tt.args.ctx, tt.args.data)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("BookRepo.StoreBooks() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BookRepo.StoreBooks() = %v, want %v", got, tt.want)
+			}
+			if err := mockDB.ExpectationsWereMet(); err != nil {
+				t.Errorf("BookRepo.StoreBooks() expectations = %v", err)
+			}
+		})
+	}
+}
+
+func TestBookRepo_StreamBooks(t *testing.T) {
+	db, mockDB, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo := &BookRepo{
+		deps: &core.Dependency{
+			Logger: slog.Default(),
+			DB:     sqlx.NewDb(db, "sqlmock"),
+		},
+	}
+
+	now := time.Now()
+	expectedRows := sqlmock.NewRows([]string{"id", "title", "author", "publish_year", "version", "created_at", "updated_at", "deleted_at"}).
+		AddRow(1, "One Piece", "Eiichiro Oda", 1997, 1, now, now, nil).
+		AddRow(2, "Bleach", "Tite Kubo", 2001, 1, now, now, nil).
+		AddRow(3, "Naruto", "Masashi Kishimoto", 1999, 1, now, now, nil)
+	mockDB.ExpectQuery(`(?s)^SELECT .* FROM library.books WHERE deleted_at IS NULL ORDER BY id ASC$`).WillReturnRows(expectedRows)
+
+	// the stream stops as soon as the callback fails
+	stop := errors.New("client gone")
+	var got []int64
+	err = repo.StreamBooks(context.Background(), model.BookSearchParams{}, func(book model.Book) error {
+		got = append(got, book.ID)
+		if len(got) == 2 {
+			return stop
+		}
+		return nil
+	})
+	if !errors.Is(err, stop) {
+		t.Errorf("BookRepo.StreamBooks() error = %v, want %v", err, stop)
+	}
+	if !reflect.DeepEqual(got, []int64{1, 2}) {
+		t.Errorf("BookRepo.StreamBooks() streamed = %v, want %v", got, []int64{1, 2})
+	}
+	if err := mockDB.ExpectationsWereMet(); err != nil {
+		t.Errorf("BookRepo.StreamBooks() expectations = %v", err)
+	}
+}
+
+func TestBookRepo_BatchBooks(t *testing.T) {
+	type fields struct {
+		deps *core.Dependency
+	}
+	type args struct {
+		ctx context.Context
+		ops []model.BookBatchOperation
+	}
+
+	db, mockDB, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dbx := sqlx.NewDb(db, "sqlmock")
+
+	mockFields := fields{
+		deps: &core.Dependency{
+			Logger: slog.Default(),
+			DB:     dbx,
+		},
+	}
+
+	now := time.Now()
+	oda := model.BookAuthor{ID: 7, Name: "Eiichiro Oda", Role: model.AuthorRoleAuthor}
+	ops := []model.BookBatchOperation{
+		{Op: model.BatchOpCreate, Title: "One Piece", Author: "Eiichiro Oda", Authors: []model.BookAuthor{{Name: "Eiichiro Oda", Role: model.AuthorRoleAuthor}}, PublishYear: 1997},
+		{Op: model.BatchOpDelete, ID: 3, Version: 1},
+		{Op: model.BatchOpUpdate, ID: 4, Version: 2, Title: "Emma", Author: "Jane Austen", PublishYear: 1815},
+	}
+
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		want     []model.BookBatchResult
+		wantCode int
+		mockFunc func()
+	}{
+		{
+			name:   "success apply every operation in one transaction",
+			fields: mockFields,
+			args: args{
+				ctx: context.Background(),
+				ops: ops[:2],
+			},
+			want: []model.BookBatchResult{
+				{Index: 0, Op: model.BatchOpCreate, ID: 11, Status: model.BatchStatusApplied, Data: &model.Book{
+					ID: 11, Title: "One Piece", Author: "Eiichiro Oda", Authors: []model.BookAuthor{oda}, PublishYear: 1997, Published: &edtf.Date{Year: 1997}, Version: 1,
+					BaseAudit: model.BaseAudit{CreatedAt: &now, UpdatedAt: &now},
+				}},
+				{Index: 1, Op: model.BatchOpDelete, ID: 3, Status: model.BatchStatusApplied},
+			},
+			mockFunc: func() {
+				expectBeginTx(mockDB)
+				expectResolveBookAuthors(mockDB, oda)
+				mockDB.ExpectQuery(`(?s)^.*INSERT INTO library.books.*$`).
+					WithArgs("One Piece", "Eiichiro Oda", int64(1997), nil, nil, false, false, nil).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "version", "created_at", "updated_at"}).AddRow(11, 1, now, now))
+				expectWriteBookAuthors(mockDB, 11, oda)
+				mockDB.ExpectExec(`(?s)^.*SET.*deleted_at = now\(\).*$`).WithArgs(int64(3), int64(1)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mockDB.ExpectCommit()
+			},
+		},
+		{
+			name:   "failed stale update rolls back the batch",
+			fields: mockFields,
+			args: args{
+				ctx: context.Background(),
+				ops: ops,
+			},
+			want: []model.BookBatchResult{
+				{Index: 0, Op: model.BatchOpCreate, ID: 11, Status: model.BatchStatusRolledBack},
+				{Index: 1, Op: model.BatchOpDelete, ID: 3, Status: model.BatchStatusRolledBack},
+				{Index: 2, Op: model.BatchOpUpdate, ID: 4, Status: model.BatchStatusFailed, Error: xerrors.ErrVersionMismatch.Error()},
+			},
+			wantCode: http.StatusPreconditionFailed,
+			mockFunc: func() {
+				expectBeginTx(mockDB)
+				expectResolveBookAuthors(mockDB, oda)
+				mockDB.ExpectQuery(`(?s)^.*INSERT INTO library.books.*$`).
+					WithArgs("One Piece", "Eiichiro Oda", int64(1997), nil, nil, false, false, nil).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "version", "created_at", "updated_at"}).AddRow(11, 1, now, now))
+				expectWriteBookAuthors(mockDB, 11, oda)
+				mockDB.ExpectExec(`(?s)^.*SET.*deleted_at = now\(\).*$`).WithArgs(int64(3), int64(1)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mockDB.ExpectQuery(`(?s)^.*UPDATE library.books.*isbn = \$8.*version = \$10.*$`).
+					WithArgs("Emma", "Jane Austen", int64(1815), nil, nil, false, false, nil, int64(4), int64(2)).
+					WillReturnError(sql.ErrNoRows)
+				mockDB.ExpectQuery(`(?s)^SELECT EXISTS.*$`).WithArgs(int64(4)).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+				mockDB.ExpectRollback()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &BookRepo{
+				deps: tt.fields.deps,
+			}
+
+			tt.mockFunc()
+
+			got, err := repo.BatchBooks(tt.args.ctx, tt.args.ops)
+			if (err != nil) != (tt.wantCode != 0) {
+				t.Errorf("BookRepo.BatchBooks() error = %v, wantCode %v", err, tt.wantCode)
+				return
+			}
+			if err != nil && xerrors.ParseErrorTypeToCodeInt(err) != tt.wantCode {
+				t.Errorf("BookRepo.BatchBooks() error code = %v, want %v", xerrors.ParseErrorTypeToCodeInt(err), tt.wantCode)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BookRepo.BatchBooks() = %v, want %v", got, tt.want)
+			}
+			if err := mockDB.ExpectationsWereMet(); err != nil {
+				t.Errorf("BookRepo.BatchBooks() expectations = %v", err)
+			}
+		})
+	}
+}
+
+func TestBookRepo_StoreBook(t *testing.T) {
+	db, mockDB, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo := &BookRepo{
+		deps: &core.Dependency{
+			Logger: slog.Default(),
+			DB:     sqlx.NewDb(db, "sqlmock"),
+		},
+	}
+
+	// the actor and request id end up in the revision written by the trigger
+	ctx := xauth.WithActor(context.Background(), "admin")
+	ctx = context.WithValue(ctx, middleware.RequestIDKey, "host/abc-000001")
+
+	now := time.Now()
+	orwell := model.BookAuthor{ID: 2, Name: "George Orwell", Role: model.AuthorRoleAuthor}
+
+	mockDB.ExpectBegin()
+	mockDB.ExpectExec(`(?s)^.*set_config.*$`).
+		WithArgs("admin", "host/abc-000001").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	expectResolveBookAuthors(mockDB, orwell)
+	mockDB.ExpectQuery(`(?s)^.*INSERT INTO library.books \(title, author, publish_year, publish_month, publish_day, publish_approximate, publish_uncertain, isbn\) VALUES \(\$1, \$2, \$3, \$4, \$5, \$6, \$7, \$8\) RETURNING.*$`).
+		WithArgs("1984", "George Orwell", int64(1949), int64(6), int64(8), false, false, "9780451524935").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "version", "created_at", "updated_at"}).AddRow(1, 1, now, now))
+	expectWriteBookAuthors(mockDB, 1, orwell)
+	mockDB.ExpectCommit()
+
+	got, err := repo.StoreBook(ctx, model.Book{
+		Title:       "1984",
+		Author:      "George Orwell",
+		Authors:     []model.BookAuthor{{Name: "George Orwell", Role: model.AuthorRoleAuthor}},
+		PublishYear: 1949,
+		Published:   &edtf.Date{Year: 1949, Month: 6, Day: 8},
+		ISBN:        "9780451524935",
+	})
+	if err != nil {
+		t.Fatalf("BookRepo.StoreBook() error = %v", err)
+	}
+
+	want := model.Book{ID: 1, Title: "1984", Author: "George Orwell", Authors: []model.BookAuthor{orwell}, PublishYear: 1949, Published: &edtf.Date{Year: 1949, Month: 6, Day: 8}, ISBN: "9780451524935", ISBN10: "0451524934", Version: 1, BaseAudit: model.BaseAudit{CreatedAt: &now, UpdatedAt: &now}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("BookRepo.StoreBook() = %+v, want %+v", got, want)
+	}
+	if err := mockDB.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet sql expectations: %v", err)
+	}
+}
+
+func TestBookRepo_StoreBook_CanonicalAuthors(t *testing.T) {
+	db, mockDB, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo := &BookRepo{
+		deps: &core.Dependency{
+			Logger: slog.Default(),
+			DB:     sqlx.NewDb(db, "sqlmock"),
+		},
+	}
+
+	now := time.Now()
+	resolveQ := `(?s)^.*WITH found AS.*name_key = \$2.*$`
+	orwell := model.BookAuthor{ID: 2, Name: "George Orwell", Role: model.AuthorRoleAuthor}
+
+	// the alias of George Orwell and his own name credit the same author once
+	expectBeginTx(mockDB)
+	mockDB.ExpectQuery(resolveQ).
+		WithArgs("Eric Arthur Blair", "eric arthur blair").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(2, "George Orwell"))
+	mockDB.ExpectQuery(resolveQ).
+		WithArgs("George Orwell", "george orwell").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(2, "George Orwell"))
+	mockDB.ExpectQuery(`(?s)^.*INSERT INTO library.books.*$`).
+		WithArgs("1984", "George Orwell", int64(1949), nil, nil, false, false, nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "version", "created_at", "updated_at"}).AddRow(1, 1, now, now))
+	expectWriteBookAuthors(mockDB, 1, orwell)
+	mockDB.ExpectCommit()
+
+	got, err := repo.StoreBook(context.Background(), model.Book{
+		Title:  "1984",
+		Author: "Eric Arthur Blair & George Orwell",
+		Authors: []model.BookAuthor{
+			{Name: "Eric Arthur Blair", Role: model.AuthorRoleAuthor},
+			{Name: "George Orwell", Role: model.AuthorRoleAuthor},
+		},
+		PublishYear: 1949,
+	})
+	if err != nil {
+		t.Fatalf("BookRepo.StoreBook() error = %v", err)
+	}
+
+	want := model.Book{ID: 1, Title: "1984", Author: "George Orwell", Authors: []model.BookAuthor{orwell}, PublishYear: 1949, Published: &edtf.Date{Year: 1949}, Version: 1, BaseAudit: model.BaseAudit{CreatedAt: &now, UpdatedAt: &now}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("BookRepo.StoreBook() = %+v, want %+v", got, want)
+	}
+	if err := mockDB.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet sql expectations: %v", err)
+	}
+}
+
+func TestBookRepo_GetBookRevisions(t *testing.T) {
+	type args struct {
+		ctx    context.Context
+		bookID int64
+	}
+
+	db, mockDB, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deps := &core.Dependency{
+		Logger: slog.Default(),
+		DB:     sqlx.NewDb(db, "sqlmock"),
+	}
+
+	createdAt := time.Date(2025, 8, 9, 15, 57, 49, 56000000, time.UTC)
+	updatedAt := createdAt.Add(time.Hour)
+	// snapshot times keep the offset they were serialized with
+	snapshotCreatedAt, _ := time.Parse(time.RFC3339, "2025-08-09T15:57:49.056+00:00")
+	snapshotUpdatedAt, _ := time.Parse(time.RFC3339, "2025-08-09T16:57:49.056+00:00")
+	columns := []string{"book_id", "revision", "operation", "actor", "request_id", "before", "after", "created_at"}
+
+	// snapshots as written by library.book_snapshot
+	v1 := `{"id": 1, "title": "1984", "author": "Orwel", "publish_year": 1949, "version": 1, "created_at": "2025-08-09T15:57:49.056+00:00", "updated_at": "2025-08-09T15:57:49.056+00:00", "deleted_at": null}`
+	v2 := `{"id": 1, "title": "1984", "author": "George Orwell", "publish_year": 1949, "version": 2, "created_at": "2025-08-09T15:57:49.056+00:00", "updated_at": "2025-08-09T16:57:49.056+00:00", "deleted_at": null}`
+
+	tests := []struct {
+		name     string
+		args     args
+		want     []model.BookRevision
+		wantCode int
+		mockFunc func()
+	}{
+		{
+			name: "success get revisions with snapshots",
+			args: args{
+				ctx:    context.Background(),
+				bookID: 1,
+			},
+			want: []model.BookRevision{
+				{
+					BookID: 1, Revision: 1, Operation: model.RevisionOpCreate,
+					After:     &model.Book{ID: 1, Title: "1984", Author: "Orwel", PublishYear: 1949, Version: 1, BaseAudit: model.BaseAudit{CreatedAt: &snapshotCreatedAt, UpdatedAt: &snapshotCreatedAt}},
+					CreatedAt: &createdAt,
+				},
+				{
+					BookID: 1, Revision: 2, Operation: model.RevisionOpUpdate, Actor: "admin", RequestID: "host/abc-000001",
+					Before:    &model.Book{ID: 1, Title: "1984", Author: "Orwel", PublishYear: 1949, Version: 1, BaseAudit: model.BaseAudit{CreatedAt: &snapshotCreatedAt, UpdatedAt: &snapshotCreatedAt}},
+					After:     &model.Book{ID: 1, Title: "1984", Author: "George Orwell", PublishYear: 1949, Version: 2, BaseAudit: model.BaseAudit{CreatedAt: &snapshotCreatedAt, UpdatedAt: &snapshotUpdatedAt}},
+					CreatedAt: &updatedAt,
+				},
+			},
+			wantCode: http.StatusOK,
+			mockFunc: func() {
+				mockDB.ExpectQuery(`(?s)^.*FROM library.book_revisions.*WHERE book_id = \$1.*ORDER BY revision ASC.*$`).
+					WithArgs(int64(1)).
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow(1, 1, "create", nil, nil, nil, []byte(v1), createdAt).
+						AddRow(1, 2, "update", "admin", "host/abc-000001", []byte(v1), []byte(v2), updatedAt))
+			},
+		},
+		{
+			name: "failed book without revisions",
+			args: args{
+				ctx:    context.Background(),
+				bookID: 99,
+			},
+			wantCode: http.StatusBadRequest,
+			mockFunc: func() {
+				mockDB.ExpectQuery(`(?s)^.*FROM library.book_revisions.*$`).
+					WithArgs(int64(99)).
+					WillReturnRows(sqlmock.NewRows(columns))
+			},
+		},
+		{
+			name: "failed query",
+			args: args{
+				ctx:    context.Background(),
+				bookID: 1,
+			},
+			wantCode: http.StatusInternalServerError,
+			mockFunc: func() {
+				mockDB.ExpectQuery(`(?s)^.*FROM library.book_revisions.*$`).
+					WithArgs(int64(1)).
+					WillReturnError(sql.ErrConnDone)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &BookRepo{
+				deps: deps,
+			}
+
+			tt.mockFunc()
+
+			got, err := repo.GetBookRevisions(tt.args.ctx, tt.args.bookID)
+			if err != nil && xerrors.ParseErrorTypeToCodeInt(err) != tt.wantCode {
+				t.Errorf("BookRepo.GetBookRevisions() error = %v, wantCode %v", err, tt.wantCode)
+				return
+			}
+			if (err != nil) != (tt.wantCode != http.StatusOK) {
+				t.Errorf("BookRepo.GetBookRevisions() error = %v, wantCode %v", err, tt.wantCode)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BookRepo.GetBookRevisions() = %+v, want %+v", got, tt.want)
+			}
+			if err := mockDB.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet sql expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestBookRepo_RevertBook(t *testing.T) {
+	type args struct {
+		ctx      context.Context
+		id       int64
+		version  int64
+		snapshot int64
+	}
+
+	db, mockDB, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deps := &core.Dependency{
+		Logger: slog.Default(),
+		DB:     sqlx.NewDb(db, "sqlmock"),
+	}
+
+	now := time.Now()
+	columns := []string{"id", "title", "author", "publish_year", "version", "created_at", "updated_at"}
+	snapshot := []byte(`{"id": 1, "title": "One Piece", "author": "Eiichiro Oda", "authors": [{"id": 7, "name": "Eiichiro Oda", "role": "author"}], "publish_year": 1997, "version": 1, "deleted_at": null}`)
+	oda := model.BookAuthor{ID: 7, Name: "Eiichiro Oda", Role: model.AuthorRoleAuthor}
+	takeFields := func(current model.Book, target model.Book) (model.Book, error) {
+		current.Title = target.Title
+		current.Author = target.Author
+		current.Authors = target.Authors
+		current.PublishYear = target.PublishYear
+		return current, nil
+	}
+
+	tests := []struct {
+		name     string
+		args     args
+		want     model.Book
+		wantCode int
+		mockFunc func()
+	}{
+		{
+			name: "success revert to snapshot",
+			args: args{
+				ctx:      context.Background(),
+				id:       1,
+				version:  3,
+				snapshot: 1,
+			},
+			want: model.Book{
+				ID:          1,
+				Title:       "One Piece",
+				Author:      "Eiichiro Oda",
+				Authors:     []model.BookAuthor{oda},
+				PublishYear: 1997,
+				Version:     4,
+				BaseAudit: model.BaseAudit{
+					CreatedAt: &now,
+					UpdatedAt: &now,
+				},
+			},
+			wantCode: http.StatusOK,
+			mockFunc: func() {
+				expectBeginTx(mockDB)
+				mockDB.ExpectQuery(`(?s)^SELECT after FROM library.book_revisions WHERE book_id = \$1 AND revision = \$2;$`).
+					WithArgs(int64(1), int64(1)).
+					WillReturnRows(sqlmock.NewRows([]string{"after"}).AddRow(snapshot))
+				mockDB.ExpectExec(`(?s)^.*set_config\('byfood.reverted_to', \$1, true\).*$`).
+					WithArgs("1").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mockDB.ExpectQuery(`(?s)^.*SELECT.*FOR UPDATE.*$`).WithArgs(int64(1)).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(1, "One Piece (bad edit)", "Oda", 1998, 3, now, now))
+				expectResolveBookAuthors(mockDB, oda)
+				mockDB.ExpectQuery(`(?s)^.*UPDATE library.books.*$`).
+					WithArgs("One Piece", "Eiichiro Oda", int64(1997), nil, nil, false, false, nil, int64(1)).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(1, "One Piece", "Eiichiro Oda", 1997, 4, now, now))
+				expectWriteBookAuthors(mockDB, 1, oda)
+				mockDB.ExpectCommit()
+			},
+		},
+		{
+			name: "failed unknown snapshot",
+			args: args{
+				ctx:      context.Background(),
+				id:       1,
+				version:  3,
+				snapshot: 9,
+			},
+			wantCode: http.StatusBadRequest,
+			mockFunc: func() {
+				expectBeginTx(mockDB)
+				mockDB.ExpectQuery(`(?s)^SELECT after FROM library.book_revisions.*$`).
+					WithArgs(int64(1), int64(9)).
+					WillReturnError(sql.ErrNoRows)
+				mockDB.ExpectRollback()
+			},
+		},
+		{
+			name: "failed revert stale version",
+			args: args{
+				ctx:      context.Background(),
+				id:       1,
+				version:  2,
+				snapshot: 1,
+			},
+			wantCode: http.StatusPreconditionFailed,
+			mockFunc: func() {
+				expectBeginTx(mockDB)
+				mockDB.ExpectQuery(`(?s)^SELECT after FROM library.book_revisions.*$`).
+					WithArgs(int64(1), int64(1)).
+					WillReturnRows(sqlmock.NewRows([]string{"after"}).AddRow(snapshot))
+				mockDB.ExpectExec(`(?s)^.*set_config\('byfood.reverted_to', \$1, true\).*$`).
+					WithArgs("1").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mockDB.ExpectQuery(`(?s)^.*SELECT.*FOR UPDATE.*$`).WithArgs(int64(1)).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(1, "One Piece (bad edit)", "Oda", 1998, 3, now, now))
+				mockDB.ExpectRollback()
+			},
+		},
+		{
+			name: "failed revert soft deleted book",
+			args: args{
+				ctx:      context.Background(),
+				id:       1,
+				version:  3,
+				snapshot: 1,
+			},
+			wantCode: http.StatusBadRequest,
+			mockFunc: func() {
+				expectBeginTx(mockDB)
+				mockDB.ExpectQuery(`(?s)^SELECT after FROM library.book_revisions.*$`).
+					WithArgs(int64(1), int64(1)).
+					WillReturnRows(sqlmock.NewRows([]string{"after"}).AddRow(snapshot))
+				mockDB.ExpectExec(`(?s)^.*set_config\('byfood.reverted_to', \$1, true\).*$`).
+					WithArgs("1").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mockDB.ExpectQuery(`(?s)^.*SELECT.*deleted_at ISNULL.*FOR UPDATE.*$`).WithArgs(int64(1)).
+					WillReturnError(sql.ErrNoRows)
+				mockDB.ExpectRollback()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &BookRepo{
+				deps: deps,
+			}
+
+			tt.mockFunc()
+
+			got, err := repo.RevertBook(tt.args.ctx, tt.args.id, tt.args.version, tt.args.snapshot, takeFields)
+			if err != nil && xerrors.ParseErrorTypeToCodeInt(err) != tt.wantCode {
+				t.Errorf("BookRepo.RevertBook() error = %v, wantCode %v", err, tt.wantCode)
+				return
+			}
+			if (err != nil) != (tt.wantCode != http.StatusOK) {
+				t.Errorf("BookRepo.RevertBook() error = %v, wantCode %v", err, tt.wantCode)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BookRepo.RevertBook() = %+v, want %+v", got, tt.want)
+			}
+			if err := mockDB.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet sql expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestBookRepo_GetBookAsOf(t *testing.T) {
+	db, mockDB, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo := &BookRepo{
+		deps: &core.Dependency{
+			Logger: slog.Default(),
+			DB:     sqlx.NewDb(db, "sqlmock"),
+		},
+	}
+
+	now := time.Now()
+	asOf := time.Date(2025, 6, 30, 23, 59, 59, 0, time.UTC)
+	columns := []string{"id", "title", "author", "authors", "publish_year", "version", "created_at", "updated_at"}
+
+	// the author list recorded with that version, not the current one
+	mockDB.ExpectQuery(`(?s)^SELECT id, title, author, authors, .*FROM library.books_as_of\(\$2\) WHERE id = \$1 AND deleted_at ISNULL.*$`).
+		WithArgs(int64(1), asOf).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(1, "One Piece", "Eichiro Oda", []byte(`[{"id": 7, "name": "Eichiro Oda", "role": "author"}]`), 1997, 2, now, now))
+	mockDB.ExpectQuery(`(?s)^.*FROM library.books_as_of\(\$2\).*$`).
+		WithArgs(int64(2), asOf).
+		WillReturnError(sql.ErrNoRows)
+
+	got, err := repo.GetBookAsOf(context.Background(), 1, asOf)
+	if err != nil {
+		t.Fatalf("BookRepo.GetBookAsOf() error = %v", err)
+	}
+	want := model.Book{
+		ID:          1,
+		Title:       "One Piece",
+		Author:      "Eichiro Oda",
+		Authors:     []model.BookAuthor{{ID: 7, Name: "Eichiro Oda", Role: model.AuthorRoleAuthor}},
+		PublishYear: 1997,
+		Version:     2,
+		BaseAudit:   model.BaseAudit{CreatedAt: &now, UpdatedAt: &now},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("BookRepo.GetBookAsOf() = %+v, want %+v", got, want)
+	}
+
+	// not created yet or already deleted at that moment
+	_, err = repo.GetBookAsOf(context.Background(), 2, asOf)
+	if xerrors.ParseErrorTypeToCodeInt(err) != http.StatusBadRequest {
+		t.Errorf("BookRepo.GetBookAsOf() error = %v, want data not found", err)
+	}
+
+	if err := mockDB.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet sql expectations: %v", err)
+	}
+}
+
+func TestBookRepo_GetBookByISBN(t *testing.T) {
+	db, mockDB, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo := &BookRepo{
+		deps: &core.Dependency{
+			Logger: slog.Default(),
+			DB:     sqlx.NewDb(db, "sqlmock"),
+		},
+	}
+
+	now := time.Now()
+	columns := []string{"id", "title", "author", "authors", "publish_year", "isbn", "genres", "tags", "editions", "version", "created_at", "updated_at"}
+
+	mockDB.ExpectQuery(`(?s)^SELECT id, title, author, .*FROM library.books WHERE \(isbn = \$1 OR id = \(SELECT book_id FROM library.book_editions WHERE isbn = \$1\)\) AND deleted_at ISNULL.*$`).
+		WithArgs("9780451524935").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(2, "1984", "George Orwell", []byte(`[{"id": 2, "name": "George Orwell", "role": "author"}]`), 1949, "9780451524935", `[{"id": 1, "name": "Dystopian"}]`, "{surveillance}", `[{"id": 5, "book_id": 2, "format": "ebook", "language": "en"}]`, 1, now, now))
+	mockDB.ExpectQuery(`(?s)^.*WHERE isbn = \$1.*$`).
+		WithArgs("9780261103573").
+		WillReturnError(sql.ErrNoRows)
+
+	got, err := repo.GetBookByISBN(context.Background(), "9780451524935")
+	if err != nil {
+		t.Fatalf("BookRepo.GetBookByISBN() error = %v", err)
+	}
+	want := model.Book{
+		ID:          2,
+		Title:       "1984",
+		Author:      "George Orwell",
+		Authors:     []model.BookAuthor{{ID: 2, Name: "George Orwell", Role: model.AuthorRoleAuthor}},
+		PublishYear: 1949,
+		ISBN:        "9780451524935",
+		ISBN10:      "0451524934",
+		Genres:      []model.BookGenre{{ID: 1, Name: "Dystopian"}},
+		Tags:        []string{"surveillance"},
+		Editions:    []model.Edition{{ID: 5, BookID: 2, Format: model.EditionFormatEbook, Language: "en"}},
+		Version:     1,
+		BaseAudit:   model.BaseAudit{CreatedAt: &now, UpdatedAt: &now},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("BookRepo.GetBookByISBN() = %+v, want %+v", got, want)
+	}
+
+	// no live book has it
+	_, err = repo.GetBookByISBN(context.Background(), "9780261103573")
+	if xerrors.ParseErrorTypeToCodeInt(err) != http.StatusBadRequest {
+		t.Errorf("BookRepo.GetBookByISBN() error = %v, want data not found", err)
+	}
+
+	if err := mockDB.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet sql expectations: %v", err)
+	}
+}
+
+func TestBookRepo_GetStoredISBNs(t *testing.T) {
+	db, mockDB, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo := &BookRepo{
+		deps: &core.Dependency{
+			Logger: slog.Default(),
+			DB:     sqlx.NewDb(db, "sqlmock"),
+		},
+	}
+
+	mockDB.ExpectQuery(`(?s)^SELECT isbn FROM library.books WHERE isbn = ANY\(\$1\) AND deleted_at ISNULL UNION SELECT isbn FROM library.book_editions WHERE isbn = ANY\(\$1\) ORDER BY isbn;$`).
+		WithArgs(pq.Array([]string{"9780261103573", "9780451524935"})).
+		WillReturnRows(sqlmock.NewRows([]string{"isbn"}).AddRow("9780451524935"))
+
+	got, err := repo.GetStoredISBNs(context.Background(), []string{"9780261103573", "9780451524935"})
+	if err != nil {
+		t.Fatalf("BookRepo.GetStoredISBNs() error = %v", err)
+	}
+	if want := []string{"9780451524935"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("BookRepo.GetStoredISBNs() = %v, want %v", got, want)
+	}
+
+	if err := mockDB.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet sql expectations: %v", err)
+	}
+}
+
+func TestBookRepo_PurgeDeletedBooks(t *testing.T) {
+	db, mockDB, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo := &BookRepo{
+		deps: &core.Dependency{
+			Logger: slog.Default(),
+			DB:     sqlx.NewDb(db, "sqlmock"),
+		},
+	}
+
+	cutoff := time.Now().Add(-90 * 24 * time.Hour)
+
+	// a merged book and a deleted book others were merged into both stay
+	mockDB.ExpectBegin()
+	mockDB.ExpectExec(`(?s)^DELETE FROM library.books WHERE id IN \( SELECT id FROM library.books WHERE deleted_at < \$1 AND merged_into ISNULL AND NOT EXISTS \(SELECT 1 FROM library.books m WHERE m.merged_into = books.id\) ORDER BY id LIMIT \$2 FOR UPDATE SKIP LOCKED \);$`).
+		WithArgs(cutoff, 100).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mockDB.ExpectCommit()
+
+	got, err := repo.PurgeDeletedBooks(context.Background(), cutoff, 100)
+	if err != nil {
+		t.Fatalf("BookRepo.PurgeDeletedBooks() error = %v", err)
+	}
+	if got != 3 {
+		t.Errorf("BookRepo.PurgeDeletedBooks() = %v, want 3", got)
+	}
+
+	if err := mockDB.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet sql expectations: %v", err)
+	}
+}
+
+func TestBookRepo_SetBookIdentifiers(t *testing.T) {
+	db, mockDB, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo := &BookRepo{
+		deps: &core.Dependency{
+			Logger: slog.Default(),
+			DB:     sqlx.NewDb(db, "sqlmock"),
+		},
+	}
+
+	identifiers := []model.BookIdentifier{
+		{Scheme: "oclc", Value: "12345"},
+		{Scheme: "openlibrary", Value: "OL27482W"},
+	}
+
+	mockDB.ExpectBegin()
+	mockDB.ExpectQuery(`(?s)^SELECT id FROM library.books WHERE id = \$1 AND deleted_at ISNULL FOR SHARE;$`).
+		WithArgs(int64(8)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(8))
+	mockDB.ExpectExec(`(?s)^DELETE FROM library.book_identifiers WHERE book_id = \$1;$`).
+		WithArgs(int64(8)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mockDB.ExpectExec(`(?s)^INSERT INTO library.book_identifiers \(book_id, scheme, value\) .*unnest\(\$2::TEXT\[\], \$3::TEXT\[\]\).*$`).
+		WithArgs(int64(8), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mockDB.ExpectQuery(`(?s)^SELECT library.book_identifier_list\(\$1\);$`).
+		WithArgs(int64(8)).
+		WillReturnRows(sqlmock.NewRows([]string{"book_identifier_list"}).AddRow(`[{"scheme": "oclc", "value": "12345"}, {"scheme": "openlibrary", "value": "OL27482W"}]`))
+	mockDB.ExpectCommit()
+
+	got, err := repo.SetBookIdentifiers(context.Background(), 8, identifiers)
+	if err != nil {
+		t.Fatalf("BookRepo.SetBookIdentifiers() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, identifiers) {
+		t.Errorf("BookRepo.SetBookIdentifiers() = %+v, want %+v", got, identifiers)
+	}
+
+	// deleted books keep their identifiers as they are
+	mockDB.ExpectBegin()
+	mockDB.ExpectQuery(`(?s)^SELECT id FROM library.books.*$`).
+		WithArgs(int64(3)).
+		WillReturnError(sql.ErrNoRows)
+	mockDB.ExpectRollback()
+
+	_, err = repo.SetBookIdentifiers(context.Background(), 3, identifiers)
+	if xerrors.ParseErrorTypeToCodeInt(err) != http.StatusBadRequest {
+		t.Errorf("BookRepo.SetBookIdentifiers() error = %v, want data not found", err)
+	}
+
+	if err := mockDB.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet sql expectations: %v", err)
+	}
+}
+
+func TestBookRepo_GetBookByID_Merged(t *testing.T) {
+	db, mockDB, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo := &BookRepo{
+		deps: &core.Dependency{
+			Logger: slog.Default(),
+			DB:     sqlx.NewDb(db, "sqlmock"),
+		},
+	}
+
+	getQ := `(?s)^SELECT id, title, author, .*FROM library.books WHERE id = \$1 AND deleted_at ISNULL;$`
+	mergedQ := `(?s)^SELECT merged_into FROM library.books WHERE id = \$1 AND merged_into NOTNULL;$`
+
+	mockDB.ExpectQuery(getQ).WithArgs(int64(12)).WillReturnError(sql.ErrNoRows)
+	mockDB.ExpectQuery(mergedQ).WithArgs(int64(12)).
+		WillReturnRows(sqlmock.NewRows([]string{"merged_into"}).AddRow(8))
+	mockDB.ExpectQuery(getQ).WithArgs(int64(13)).WillReturnError(sql.ErrNoRows)
+	mockDB.ExpectQuery(mergedQ).WithArgs(int64(13)).WillReturnError(sql.ErrNoRows)
+
+	_, err = repo.GetBookByID(context.Background(), 12)
+	var merged MergedBookError
+	if !errors.As(err, &merged) || merged.MergedInto != 8 {
+		t.Errorf("BookRepo.GetBookByID() error = %v, want merged into 8", err)
+	}
+
+	// deleted or never stored, not merged
+	_, err = repo.GetBookByID(context.Background(), 13)
+	if errors.As(err, &merged) || xerrors.ParseErrorTypeToCodeInt(err) != http.StatusBadRequest {
+		t.Errorf("BookRepo.GetBookByID() error = %v, want data not found", err)
+	}
+
+	if err := mockDB.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet sql expectations: %v", err)
+	}
+}
+
+func TestBookRepo_GetDuplicateCandidates(t *testing.T) {
+	db, mockDB, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo := &BookRepo{
+		deps: &core.Dependency{
+			Logger: slog.Default(),
+			DB:     sqlx.NewDb(db, "sqlmock"),
+		},
+		fuzzySearch: true,
+	}
+
+	mockDB.ExpectQuery(`(?s)^SELECT id, title, author, publish_year.*library.f_unaccent\(title\) % library.f_unaccent\(\$1\).*a.name_key = ANY\(\$2\) OR al.name_key = ANY\(\$2\).*LIMIT \$3;$`).
+		WithArgs("Pride & Predjudice", pq.Array([]string{"jane austen"}), maxDuplicateCandidates).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "author", "publish_year"}).
+			AddRow(3, "Pride and Prejudice", "Jane Austen", 1813))
+
+	got, err := repo.GetDuplicateCandidates(context.Background(), model.Book{
+		Title:       "Pride & Predjudice",
+		Author:      "Jane Austen",
+		Authors:     []model.BookAuthor{{Name: "Jane Austen", Role: model.AuthorRoleAuthor}},
+		PublishYear: 1813,
+	})
+	if err != nil {
+		t.Fatalf("BookRepo.GetDuplicateCandidates() error = %v", err)
+	}
+
+	want := []model.BookRef{{ID: 3, Title: "Pride and Prejudice", Author: "Jane Austen", PublishYear: 1813}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("BookRepo.GetDuplicateCandidates() = %+v, want %+v", got, want)
+	}
+	if err := mockDB.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet sql expectations: %v", err)
+	}
+}
+
+func TestBookRepo_MergeBooks(t *testing.T) {
+	db, mockDB, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo := &BookRepo{
+		deps: &core.Dependency{
+			Logger: slog.Default(),
+			DB:     sqlx.NewDb(db, "sqlmock"),
+		},
+	}
+
+	now := time.Now()
+	lockQ := `(?s)^SELECT id, isbn FROM library.books WHERE id = ANY\(\$1\) AND deleted_at ISNULL ORDER BY id FOR UPDATE;$`
+	expectMergeCopies := func() {
+		mockDB.ExpectExec(`(?s)^UPDATE library.book_editions SET book_id = \$1, .*WHERE book_id = ANY\(\$2\);$`).
+			WithArgs(int64(3), pq.Array([]int64{12})).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		for _, table := range []string{"book_identifiers", "book_genres", "book_tags"} {
+			mockDB.ExpectExec(`(?s)^INSERT INTO library.`+table+` .*WHERE book_id = ANY\(\$2\) ON CONFLICT DO NOTHING;$`).
+				WithArgs(int64(3), pq.Array([]int64{12})).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+		}
+	}
+
+	tests := []struct {
+		name       string
+		id         int64
+		sourceIDs  []int64
+		asEditions bool
+		want       model.BookMerge
+		wantCode   int
+		mockFunc   func()
+	}{
+		{
+			name:      "success merge takes over the isbn and classification",
+			id:        3,
+			sourceIDs: []int64{12},
+			want: model.BookMerge{
+				Book: model.Book{
+					ID:          3,
+					Title:       "Pride and Prejudice",
+					Author:      "Jane Austen",
+					Authors:     []model.BookAuthor{{ID: 3, Name: "Jane Austen", Role: model.AuthorRoleAuthor}},
+					PublishYear: 1813,
+					ISBN:        "9780141439518",
+					ISBN10:      "0141439513",
+					Tags:        []string{"classic"},
+					Version:     2,
+					BaseAudit:   model.BaseAudit{CreatedAt: &now, UpdatedAt: &now},
+				},
+				MergedBookIDs: []int64{12},
+			},
+			wantCode: http.StatusOK,
+			mockFunc: func() {
+				expectBeginTx(mockDB)
+				mockDB.ExpectQuery(lockQ).
+					WithArgs(pq.Array([]int64{3, 12})).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "isbn"}).AddRow(3, nil).AddRow(12, "9780141439518"))
+				mockDB.ExpectExec(`(?s)^UPDATE library.books SET deleted_at = now\(\), merged_into = \$1, .*WHERE id = ANY\(\$2\);$`).
+					WithArgs(int64(3), pq.Array([]int64{12})).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				expectMergeCopies()
+				mockDB.ExpectExec(`(?s)^UPDATE library.books SET isbn = \$2, .*WHERE id = \$1;$`).
+					WithArgs(int64(3), "9780141439518").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mockDB.ExpectQuery(`(?s)^SELECT id, title, author, .*FROM library.books WHERE id = \$1;$`).
+					WithArgs(int64(3)).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "title", "author", "authors", "publish_year", "isbn", "tags", "version", "created_at", "updated_at"}).
+						AddRow(3, "Pride and Prejudice", "Jane Austen", []byte(`[{"id": 3, "name": "Jane Austen", "role": "author"}]`), 1813, "9780141439518", "{classic}", 2, now, now))
+				mockDB.ExpectCommit()
+			},
+		},
+		{
+			name:       "success merge as editions keeps the isbn on the edition",
+			id:         3,
+			sourceIDs:  []int64{12},
+			asEditions: true,
+			want: model.BookMerge{
+				Book: model.Book{
+					ID:          3,
+					Title:       "Pride and Prejudice",
+					Author:      "Jane Austen",
+					PublishYear: 1813,
+					Published:   &edtf.Date{Year: 1813, Month: 1, Day: 28},
+					Editions: []model.Edition{
+						{ID: 7, BookID: 3, ISBN: "9780141439518", ISBN10: "0141439513", Published: &edtf.Date{Year: 2002}},
+					},
+					Version:   1,
+					BaseAudit: model.BaseAudit{CreatedAt: &now, UpdatedAt: &now},
+				},
+				MergedBookIDs: []int64{12},
+			},
+			wantCode: http.StatusOK,
+			mockFunc: func() {
+				expectBeginTx(mockDB)
+				mockDB.ExpectQuery(lockQ).
+					WithArgs(pq.Array([]int64{3, 12})).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "isbn"}).AddRow(3, nil).AddRow(12, "9780141439518"))
+				mockDB.ExpectExec(`(?s)^UPDATE library.books SET deleted_at = now\(\), merged_into = \$1, .*WHERE id = ANY\(\$2\);$`).
+					WithArgs(int64(3), pq.Array([]int64{12})).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				expectMergeCopies()
+				mockDB.ExpectExec(`(?s)^INSERT INTO library.book_editions \(book_id, isbn, published\).*WHERE id = ANY\(\$2\).*ON CONFLICT \(isbn\) DO NOTHING;\s*$`).
+					WithArgs(int64(3), pq.Array([]int64{12})).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mockDB.ExpectQuery(`(?s)^SELECT id, title, author, .*FROM library.books WHERE id = \$1;$`).
+					WithArgs(int64(3)).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "title", "author", "publish_year", "published", "editions", "version", "created_at", "updated_at"}).
+						AddRow(3, "Pride and Prejudice", "Jane Austen", 1813, "1813-01-28", []byte(`[{"id": 7, "book_id": 3, "isbn": "9780141439518", "published": "2002"}]`), 1, now, now))
+				mockDB.ExpectCommit()
+			},
+		},
+		{
+			name:      "failed merge of a deleted book",
+			id:        3,
+			sourceIDs: []int64{12},
+			wantCode:  http.StatusBadRequest,
+			mockFunc: func() {
+				expectBeginTx(mockDB)
+				mockDB.ExpectQuery(lockQ).
+					WithArgs(pq.Array([]int64{3, 12})).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "isbn"}).AddRow(3, nil))
+				mockDB.ExpectRollback()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc()
+
+			got, err := repo.MergeBooks(context.Background(), tt.id, tt.sourceIDs, tt.asEditions)
+			if err != nil {
+				if xerrors.ParseErrorTypeToCodeInt(err) != tt.wantCode {
+					t.Errorf("BookRepo.MergeBooks() error = %v, wantCode %v", err, tt.wantCode)
+				}
+				return
+			}
+			if tt.wantCode != http.StatusOK {
+				t.Errorf("BookRepo.MergeBooks() error = nil, wantCode %v", tt.wantCode)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BookRepo.MergeBooks() = %+v, want %+v", got, tt.want)
+			}
+			if err := mockDB.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet sql expectations: %v", err)
+			}
+		})
+	}
+}
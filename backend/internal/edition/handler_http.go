@@ -0,0 +1,321 @@
+package edition
+
+import (
+	"byfood-app/internal/core"
+	"byfood-app/internal/model"
+	"byfood-app/internal/pkg/xerrors"
+	"byfood-app/internal/pkg/xhttp"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type EditionHandler struct {
+	deps  *core.Dependency
+	logic LogicInterface
+}
+
+func NewHTTPHandler(deps *core.Dependency, logic LogicInterface) *EditionHandler {
+	return &EditionHandler{
+		deps:  deps,
+		logic: logic,
+	}
+}
+
+// GetEditions godoc
+// @Summary List the editions of a book by ID
+// @Description The book is the work, its editions are the forms it was published in, in the order they were added.
+// @Tags editions
+// @Produce json
+// @Param id path integer true "book ID"
+// @Param format query string false "filter by format (hardcover, paperback, ebook, audiobook, large_print)"
+// @Param language query string false "filter by BCP 47 language, e.g. en or pt-BR"
+// @Success 200 {object} xhttp.BaseResponse{data=[]model.Edition}
+// @Router /books/{id}/editions [get]
+func (h *EditionHandler) GetEditions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// get and validate id param
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   xerrors.ErrInvalidID.Error(),
+			Message: xerrors.ErrInvalidID.Error(),
+		}, http.StatusBadRequest)
+		return
+	}
+	idParam, err := strconv.Atoi(id)
+	if err != nil {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to parse id parameter",
+		}, http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	data, err := h.logic.GetEditions(ctx, int64(idParam), model.EditionSearchParams{
+		Format:   query.Get("format"),
+		Language: query.Get("language"),
+	})
+	if err != nil {
+		h.deps.Logger.ErrorContext(ctx, "failed to get editions", slog.Any("error", err))
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to get editions",
+		}, xerrors.ParseErrorTypeToCodeInt(err))
+		return
+	}
+
+	xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+		Data:    data,
+		Message: "editions fetched",
+	}, http.StatusOK)
+}
+
+// GetEditionByID godoc
+// @Summary Get an edition of a book by their IDs
+// @Tags editions
+// @Produce json
+// @Param id path integer true "book ID"
+// @Param edition_id path integer true "edition ID"
+// @Success 200 {object} xhttp.BaseResponse{data=model.Edition}
+// @Router /books/{id}/editions/{edition_id} [get]
+func (h *EditionHandler) GetEditionByID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// get and validate id and edition_id params
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   xerrors.ErrInvalidID.Error(),
+			Message: xerrors.ErrInvalidID.Error(),
+		}, http.StatusBadRequest)
+		return
+	}
+	idParam, err := strconv.Atoi(id)
+	if err != nil {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to parse id parameter",
+		}, http.StatusBadRequest)
+		return
+	}
+	editionIDParam, err := strconv.Atoi(chi.URLParam(r, "edition_id"))
+	if err != nil {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to parse edition_id parameter",
+		}, http.StatusBadRequest)
+		return
+	}
+
+	data, err := h.logic.GetEditionByID(ctx, int64(idParam), int64(editionIDParam))
+	if err != nil {
+		h.deps.Logger.ErrorContext(ctx, "failed to get edition data by id", slog.Any("error", err))
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to get edition data",
+		}, xerrors.ParseErrorTypeToCodeInt(err))
+		return
+	}
+
+	xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+		Data:    data,
+		Message: "edition data fetched",
+	}, http.StatusOK)
+}
+
+// StoreEdition godoc
+// @Summary Add an edition to a book by ID, return stored data
+// @Description Format is required. An ISBN belongs to one work, so one already on another edition or on another live book answers 409. Editions are not part of the book version, so this neither bumps the version nor adds a revision.
+// @Tags editions
+// @Produce json
+// @Param id path integer true "book ID"
+// @Param data body model.StoreEditionRequest true "edition data"
+// @Success 200 {object} xhttp.BaseResponse{data=model.Edition}
+// @Router /books/{id}/editions [post]
+func (h *EditionHandler) StoreEdition(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// get and validate id param
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   xerrors.ErrInvalidID.Error(),
+			Message: xerrors.ErrInvalidID.Error(),
+		}, http.StatusBadRequest)
+		return
+	}
+	idParam, err := strconv.Atoi(id)
+	if err != nil {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to parse id parameter",
+		}, http.StatusBadRequest)
+		return
+	}
+
+	// parse request body
+	var payload model.StoreEditionRequest
+	err = xhttp.BindJSONRequest(r, &payload)
+	if err != nil {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to parse request body",
+		}, http.StatusBadRequest)
+		return
+	}
+
+	data, err := h.logic.StoreEdition(ctx, model.Edition{
+		BookID:    int64(idParam),
+		Publisher: payload.Publisher,
+		Format:    payload.Format,
+		Language:  payload.Language,
+		PageCount: payload.PageCount,
+		ISBN:      payload.ISBN,
+		Published: payload.Published,
+	})
+	if err != nil {
+		h.deps.Logger.ErrorContext(ctx, "failed to store edition data", slog.Any("error", err))
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to store edition data",
+		}, xerrors.ParseErrorTypeToCodeInt(err))
+		return
+	}
+
+	xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+		Data:    data,
+		Message: "edition data stored",
+	}, http.StatusOK)
+}
+
+// UpdateEdition godoc
+// @Summary Update an edition of a book by their IDs, return updated data
+// @Description Every field is replaced, leaving one out clears it. Format is required.
+// @Tags editions
+// @Produce json
+// @Param id path integer true "book ID"
+// @Param edition_id path integer true "edition ID"
+// @Param data body model.UpdateEditionRequest true "edition data"
+// @Success 200 {object} xhttp.BaseResponse{data=model.Edition}
+// @Router /books/{id}/editions/{edition_id} [put]
+func (h *EditionHandler) UpdateEdition(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// get and validate id and edition_id params
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   xerrors.ErrInvalidID.Error(),
+			Message: xerrors.ErrInvalidID.Error(),
+		}, http.StatusBadRequest)
+		return
+	}
+	idParam, err := strconv.Atoi(id)
+	if err != nil {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to parse id parameter",
+		}, http.StatusBadRequest)
+		return
+	}
+	editionIDParam, err := strconv.Atoi(chi.URLParam(r, "edition_id"))
+	if err != nil {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to parse edition_id parameter",
+		}, http.StatusBadRequest)
+		return
+	}
+
+	// parse request body
+	var payload model.UpdateEditionRequest
+	err = xhttp.BindJSONRequest(r, &payload)
+	if err != nil {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to parse request body",
+		}, http.StatusBadRequest)
+		return
+	}
+
+	data, err := h.logic.UpdateEdition(ctx, model.Edition{
+		ID:        int64(editionIDParam),
+		BookID:    int64(idParam),
+		Publisher: payload.Publisher,
+		Format:    payload.Format,
+		Language:  payload.Language,
+		PageCount: payload.PageCount,
+		ISBN:      payload.ISBN,
+		Published: payload.Published,
+	})
+	if err != nil {
+		h.deps.Logger.ErrorContext(ctx, "failed to update edition data", slog.Any("error", err))
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to update edition data",
+		}, xerrors.ParseErrorTypeToCodeInt(err))
+		return
+	}
+
+	xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+		Data:    data,
+		Message: "edition data updated",
+	}, http.StatusOK)
+}
+
+// DeleteEdition godoc
+// @Summary Delete an edition of a book by their IDs
+// @Tags editions
+// @Produce json
+// @Param id path integer true "book ID"
+// @Param edition_id path integer true "edition ID"
+// @Success 200 {object} xhttp.BaseResponse{message=string}
+// @Router /books/{id}/editions/{edition_id} [delete]
+func (h *EditionHandler) DeleteEdition(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// get and validate id and edition_id params
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   xerrors.ErrInvalidID.Error(),
+			Message: xerrors.ErrInvalidID.Error(),
+		}, http.StatusBadRequest)
+		return
+	}
+	idParam, err := strconv.Atoi(id)
+	if err != nil {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to parse id parameter",
+		}, http.StatusBadRequest)
+		return
+	}
+	editionIDParam, err := strconv.Atoi(chi.URLParam(r, "edition_id"))
+	if err != nil {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to parse edition_id parameter",
+		}, http.StatusBadRequest)
+		return
+	}
+
+	err = h.logic.DeleteEdition(ctx, int64(idParam), int64(editionIDParam))
+	if err != nil {
+		h.deps.Logger.ErrorContext(ctx, "failed to delete edition data", slog.Any("error", err))
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to delete edition data",
+		}, xerrors.ParseErrorTypeToCodeInt(err))
+		return
+	}
+
+	xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+		Message: "edition data deleted",
+	}, http.StatusOK)
+}
@@ -0,0 +1,24 @@
+package edition
+
+import (
+	"byfood-app/internal/model"
+	"context"
+)
+
+//go:generate go run go.uber.org/mock/mockgen@latest -source=interface.go -destination=mock_interface.go -package=edition
+type RepositoryInterface interface {
+	GetEditions(ctx context.Context, bookID int64, params model.EditionSearchParams) ([]model.Edition, error)
+	GetEditionByID(ctx context.Context, bookID int64, id int64) (model.Edition, error)
+	StoreEdition(ctx context.Context, data model.Edition) (model.Edition, error)
+	UpdateEdition(ctx context.Context, data model.Edition) (model.Edition, error)
+	DeleteEdition(ctx context.Context, bookID int64, id int64) error
+}
+
+//go:generate go run go.uber.org/mock/mockgen@latest -source=interface.go -destination=mock_interface.go -package=edition
+type LogicInterface interface {
+	GetEditions(ctx context.Context, bookID int64, params model.EditionSearchParams) ([]model.Edition, error)
+	GetEditionByID(ctx context.Context, bookID int64, id int64) (model.Edition, error)
+	StoreEdition(ctx context.Context, data model.Edition) (model.Edition, error)
+	UpdateEdition(ctx context.Context, data model.Edition) (model.Edition, error)
+	DeleteEdition(ctx context.Context, bookID int64, id int64) error
+}
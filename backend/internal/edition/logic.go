@@ -0,0 +1,166 @@
+package edition
+
+import (
+	"byfood-app/internal/core"
+	"byfood-app/internal/model"
+	"byfood-app/internal/pkg/isbn"
+	"byfood-app/internal/pkg/langtag"
+	"byfood-app/internal/pkg/xerrors"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+)
+
+type EditionLogic struct {
+	deps *core.Dependency
+	repo RepositoryInterface
+}
+
+func NewEditionLogic(deps *core.Dependency, repo RepositoryInterface) *EditionLogic {
+	return &EditionLogic{
+		deps: deps,
+		repo: repo,
+	}
+}
+
+func (logic *EditionLogic) GetEditions(ctx context.Context, bookID int64, params model.EditionSearchParams) ([]model.Edition, error) {
+	if bookID <= 0 {
+		return []model.Edition{}, xerrors.NewClientError(xerrors.ErrInvalidID)
+	}
+
+	if params.Format != "" && !slices.Contains(model.EditionFormats, params.Format) {
+		return []model.Edition{}, xerrors.NewClientError(fmt.Errorf("unknown edition format: %s", params.Format))
+	}
+	if params.Language != "" {
+		language, err := langtag.Normalize(params.Language)
+		if err != nil {
+			return []model.Edition{}, xerrors.NewClientError(err)
+		}
+		params.Language = language
+	}
+
+	data, err := logic.repo.GetEditions(ctx, bookID, params)
+	if err != nil {
+		logic.deps.Logger.ErrorContext(ctx, "failed to get editions", slog.Any("error", err))
+		return []model.Edition{}, err
+	}
+
+	return data, nil
+}
+
+func (logic *EditionLogic) GetEditionByID(ctx context.Context, bookID int64, id int64) (model.Edition, error) {
+	if bookID <= 0 || id <= 0 {
+		return model.Edition{}, xerrors.NewClientError(xerrors.ErrInvalidID)
+	}
+
+	data, err := logic.repo.GetEditionByID(ctx, bookID, id)
+	if err != nil {
+		logic.deps.Logger.ErrorContext(ctx, "failed to get edition by id", slog.Any("error", err))
+		return model.Edition{}, err
+	}
+
+	return data, nil
+}
+
+func (logic *EditionLogic) StoreEdition(ctx context.Context, data model.Edition) (model.Edition, error) {
+	if data.BookID <= 0 {
+		return model.Edition{}, xerrors.NewClientError(xerrors.ErrInvalidID)
+	}
+
+	data = normalizeEdition(data)
+	if err := validateEdition(data); err != nil {
+		return model.Edition{}, err
+	}
+
+	result, err := logic.repo.StoreEdition(ctx, data)
+	if err != nil {
+		logic.deps.Logger.ErrorContext(ctx, "failed to store edition data", slog.Any("error", err))
+		return model.Edition{}, err
+	}
+
+	return result, nil
+}
+
+func (logic *EditionLogic) UpdateEdition(ctx context.Context, data model.Edition) (model.Edition, error) {
+	if data.BookID <= 0 || data.ID <= 0 {
+		return model.Edition{}, xerrors.NewClientError(xerrors.ErrInvalidID)
+	}
+
+	data = normalizeEdition(data)
+	if err := validateEdition(data); err != nil {
+		return model.Edition{}, err
+	}
+
+	result, err := logic.repo.UpdateEdition(ctx, data)
+	if err != nil {
+		logic.deps.Logger.ErrorContext(ctx, "failed to update edition data", slog.Any("error", err))
+		return model.Edition{}, err
+	}
+
+	return result, nil
+}
+
+func (logic *EditionLogic) DeleteEdition(ctx context.Context, bookID int64, id int64) error {
+	if bookID <= 0 || id <= 0 {
+		return xerrors.NewClientError(xerrors.ErrInvalidID)
+	}
+
+	err := logic.repo.DeleteEdition(ctx, bookID, id)
+	if err != nil {
+		logic.deps.Logger.ErrorContext(ctx, "failed to delete edition data", slog.Any("error", err))
+		return err
+	}
+
+	return nil
+}
+
+// normalizeEdition collapses the spaces of the publisher and turns the
+// language and ISBN into the form they are stored in. Invalid ones are left
+// for validateEdition to report.
+func normalizeEdition(data model.Edition) model.Edition {
+	data.Publisher = strings.Join(strings.Fields(data.Publisher), " ")
+	data.Format = strings.ToLower(strings.TrimSpace(data.Format))
+
+	if language, err := langtag.Normalize(data.Language); err == nil {
+		data.Language = language
+	}
+	if normalized, err := isbn.Normalize(data.ISBN); err == nil {
+		data.ISBN = normalized
+	}
+
+	return data
+}
+
+func validateEdition(data model.Edition) error {
+	switch {
+	case data.Format == "":
+		return xerrors.NewClientError(errors.New("format field is empty"))
+	case !slices.Contains(model.EditionFormats, data.Format):
+		return xerrors.NewClientError(fmt.Errorf("unknown edition format: %s", data.Format))
+	case data.PageCount < 0:
+		return xerrors.NewClientError(errors.New("page count can not be negative"))
+	}
+
+	if data.Language != "" {
+		if _, err := langtag.Normalize(data.Language); err != nil {
+			return xerrors.NewClientError(err)
+		}
+	}
+
+	if data.ISBN != "" {
+		if _, err := isbn.Normalize(data.ISBN); err != nil {
+			return xerrors.NewClientError(fmt.Errorf("%w: %s", err, data.ISBN))
+		}
+	}
+
+	if data.Published != nil {
+		if err := data.Published.Validate(); err != nil {
+			return xerrors.NewClientError(err)
+		}
+	}
+
+	return nil
+}
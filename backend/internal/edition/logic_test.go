@@ -0,0 +1,233 @@
+package edition
+
+import (
+	"byfood-app/internal/core"
+	"byfood-app/internal/model"
+	"byfood-app/internal/pkg/edtf"
+	"byfood-app/internal/pkg/xerrors"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+)
+
+type testSuite struct {
+	Ctrl            *gomock.Controller
+	MockEditionRepo *MockRepositoryInterface
+}
+
+func setupTestSuite(t *testing.T) *testSuite {
+	ctrl := gomock.NewController(t)
+	return &testSuite{
+		Ctrl:            ctrl,
+		MockEditionRepo: NewMockRepositoryInterface(ctrl),
+	}
+}
+
+func TestEditionLogic_GetEditions(t *testing.T) {
+	ts := setupTestSuite(t)
+	deps := &core.Dependency{Logger: slog.Default()}
+
+	tests := []struct {
+		name     string
+		bookID   int64
+		params   model.EditionSearchParams
+		wantCode int
+		mockFunc func()
+	}{
+		{
+			name:     "success normalized language filter",
+			bookID:   3,
+			params:   model.EditionSearchParams{Format: "paperback", Language: "EN_gb"},
+			wantCode: http.StatusOK,
+			mockFunc: func() {
+				ts.MockEditionRepo.EXPECT().GetEditions(gomock.Any(), int64(3), model.EditionSearchParams{Format: "paperback", Language: "en-GB"}).
+					Return([]model.Edition{{ID: 1, BookID: 3, Format: "paperback", Language: "en-GB"}}, nil)
+			},
+		},
+		{
+			name:     "failed unknown format",
+			bookID:   3,
+			params:   model.EditionSearchParams{Format: "scroll"},
+			wantCode: http.StatusBadRequest,
+			mockFunc: func() {},
+		},
+		{
+			name:     "failed invalid language",
+			bookID:   3,
+			params:   model.EditionSearchParams{Language: "english"},
+			wantCode: http.StatusBadRequest,
+			mockFunc: func() {},
+		},
+		{
+			name:     "failed invalid book id",
+			bookID:   0,
+			wantCode: http.StatusBadRequest,
+			mockFunc: func() {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logic := NewEditionLogic(deps, ts.MockEditionRepo)
+
+			tt.mockFunc()
+
+			_, err := logic.GetEditions(context.Background(), tt.bookID, tt.params)
+			if (err != nil) != (tt.wantCode != http.StatusOK) || (err != nil && xerrors.ParseErrorTypeToCodeInt(err) != tt.wantCode) {
+				t.Errorf("EditionLogic.GetEditions() error = %v, wantCode %v", err, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestEditionLogic_StoreEdition(t *testing.T) {
+	ts := setupTestSuite(t)
+	deps := &core.Dependency{Logger: slog.Default()}
+
+	published, err := edtf.Parse("2002-12-31")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		data     model.Edition
+		want     model.Edition
+		wantCode int
+		mockFunc func()
+	}{
+		{
+			name: "success store normalized edition",
+			data: model.Edition{
+				BookID:    3,
+				Publisher: " Penguin  Classics ",
+				Format:    "Paperback",
+				Language:  "en",
+				PageCount: 480,
+				ISBN:      "0-14-143951-3",
+				Published: &published,
+			},
+			want:     model.Edition{ID: 4, BookID: 3, Publisher: "Penguin Classics", Format: "paperback", ISBN: "9780141439518", ISBN10: "0141439513"},
+			wantCode: http.StatusOK,
+			mockFunc: func() {
+				ts.MockEditionRepo.EXPECT().StoreEdition(gomock.Any(), model.Edition{
+					BookID:    3,
+					Publisher: "Penguin Classics",
+					Format:    "paperback",
+					Language:  "en",
+					PageCount: 480,
+					ISBN:      "9780141439518",
+					Published: &published,
+				}).Return(model.Edition{ID: 4, BookID: 3, Publisher: "Penguin Classics", Format: "paperback", ISBN: "9780141439518", ISBN10: "0141439513"}, nil)
+			},
+		},
+		{
+			name:     "failed store edition without format",
+			data:     model.Edition{BookID: 3, Publisher: "Penguin Classics"},
+			wantCode: http.StatusBadRequest,
+			mockFunc: func() {},
+		},
+		{
+			name:     "failed store edition with unknown format",
+			data:     model.Edition{BookID: 3, Format: "scroll"},
+			wantCode: http.StatusBadRequest,
+			mockFunc: func() {},
+		},
+		{
+			name:     "failed store edition with negative page count",
+			data:     model.Edition{BookID: 3, Format: "hardcover", PageCount: -1},
+			wantCode: http.StatusBadRequest,
+			mockFunc: func() {},
+		},
+		{
+			name:     "failed store edition with invalid language",
+			data:     model.Edition{BookID: 3, Format: "ebook", Language: "zh-Hant"},
+			wantCode: http.StatusBadRequest,
+			mockFunc: func() {},
+		},
+		{
+			name:     "failed store edition with invalid isbn checksum",
+			data:     model.Edition{BookID: 3, Format: "ebook", ISBN: "9780141439519"},
+			wantCode: http.StatusBadRequest,
+			mockFunc: func() {},
+		},
+		{
+			name:     "failed store edition with isbn of another book",
+			data:     model.Edition{BookID: 3, Format: "ebook", ISBN: "9780451524935"},
+			wantCode: http.StatusConflict,
+			mockFunc: func() {
+				ts.MockEditionRepo.EXPECT().StoreEdition(gomock.Any(), model.Edition{BookID: 3, Format: "ebook", ISBN: "9780451524935"}).
+					Return(model.Edition{}, xerrors.ConflictError{Err: errors.New("another book or edition already has isbn 9780451524935")})
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logic := NewEditionLogic(deps, ts.MockEditionRepo)
+
+			tt.mockFunc()
+
+			got, err := logic.StoreEdition(context.Background(), tt.data)
+			if (err != nil) != (tt.wantCode != http.StatusOK) || (err != nil && xerrors.ParseErrorTypeToCodeInt(err) != tt.wantCode) {
+				t.Errorf("EditionLogic.StoreEdition() error = %v, wantCode %v", err, tt.wantCode)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("EditionLogic.StoreEdition() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEditionLogic_UpdateEdition(t *testing.T) {
+	ts := setupTestSuite(t)
+	deps := &core.Dependency{Logger: slog.Default()}
+
+	tests := []struct {
+		name     string
+		data     model.Edition
+		wantCode int
+		mockFunc func()
+	}{
+		{
+			name:     "success update edition",
+			data:     model.Edition{ID: 4, BookID: 3, Format: "large_print", PageCount: 603},
+			wantCode: http.StatusOK,
+			mockFunc: func() {
+				ts.MockEditionRepo.EXPECT().UpdateEdition(gomock.Any(), model.Edition{ID: 4, BookID: 3, Format: "large_print", PageCount: 603}).
+					Return(model.Edition{ID: 4, BookID: 3, Format: "large_print", PageCount: 603}, nil)
+			},
+		},
+		{
+			name:     "failed invalid edition id",
+			data:     model.Edition{BookID: 3, Format: "ebook"},
+			wantCode: http.StatusBadRequest,
+			mockFunc: func() {},
+		},
+		{
+			name:     "failed edition not found",
+			data:     model.Edition{ID: 99, BookID: 3, Format: "ebook"},
+			wantCode: http.StatusBadRequest,
+			mockFunc: func() {
+				ts.MockEditionRepo.EXPECT().UpdateEdition(gomock.Any(), model.Edition{ID: 99, BookID: 3, Format: "ebook"}).
+					Return(model.Edition{}, xerrors.NewClientError(xerrors.ErrDataNotFound))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logic := NewEditionLogic(deps, ts.MockEditionRepo)
+
+			tt.mockFunc()
+
+			_, err := logic.UpdateEdition(context.Background(), tt.data)
+			if (err != nil) != (tt.wantCode != http.StatusOK) || (err != nil && xerrors.ParseErrorTypeToCodeInt(err) != tt.wantCode) {
+				t.Errorf("EditionLogic.UpdateEdition() error = %v, wantCode %v", err, tt.wantCode)
+			}
+		})
+	}
+}
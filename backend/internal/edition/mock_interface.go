@@ -0,0 +1,214 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: interface.go
+//
+// Generated by this command:
+//
+//	mockgen -source=interface.go -destination=mock_interface.go -package=edition
+//
+
+// Package edition is a generated GoMock package.
+package edition
+
+import (
+	model "byfood-app/internal/model"
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRepositoryInterface is a mock of RepositoryInterface interface.
+type MockRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockRepositoryInterfaceMockRecorder is the mock recorder for MockRepositoryInterface.
+type MockRepositoryInterfaceMockRecorder struct {
+	mock *MockRepositoryInterface
+}
+
+// NewMockRepositoryInterface creates a new mock instance.
+func NewMockRepositoryInterface(ctrl *gomock.Controller) *MockRepositoryInterface {
+	mock := &MockRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepositoryInterface) EXPECT() *MockRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// DeleteEdition mocks base method.
+func (m *MockRepositoryInterface) DeleteEdition(ctx context.Context, bookID, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteEdition", ctx, bookID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteEdition indicates an expected call of DeleteEdition.
+func (mr *MockRepositoryInterfaceMockRecorder) DeleteEdition(ctx, bookID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEdition", reflect.TypeOf((*MockRepositoryInterface)(nil).DeleteEdition), ctx, bookID, id)
+}
+
+// GetEditionByID mocks base method.
+func (m *MockRepositoryInterface) GetEditionByID(ctx context.Context, bookID, id int64) (model.Edition, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEditionByID", ctx, bookID, id)
+	ret0, _ := ret[0].(model.Edition)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEditionByID indicates an expected call of GetEditionByID.
+func (mr *MockRepositoryInterfaceMockRecorder) GetEditionByID(ctx, bookID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEditionByID", reflect.TypeOf((*MockRepositoryInterface)(nil).GetEditionByID), ctx, bookID, id)
+}
+
+// GetEditions mocks base method.
+func (m *MockRepositoryInterface) GetEditions(ctx context.Context, bookID int64, params model.EditionSearchParams) ([]model.Edition, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEditions", ctx, bookID, params)
+	ret0, _ := ret[0].([]model.Edition)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEditions indicates an expected call of GetEditions.
+func (mr *MockRepositoryInterfaceMockRecorder) GetEditions(ctx, bookID, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEditions", reflect.TypeOf((*MockRepositoryInterface)(nil).GetEditions), ctx, bookID, params)
+}
+
+// StoreEdition mocks base method.
+func (m *MockRepositoryInterface) StoreEdition(ctx context.Context, data model.Edition) (model.Edition, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StoreEdition", ctx, data)
+	ret0, _ := ret[0].(model.Edition)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StoreEdition indicates an expected call of StoreEdition.
+func (mr *MockRepositoryInterfaceMockRecorder) StoreEdition(ctx, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreEdition", reflect.TypeOf((*MockRepositoryInterface)(nil).StoreEdition), ctx, data)
+}
+
+// UpdateEdition mocks base method.
+func (m *MockRepositoryInterface) UpdateEdition(ctx context.Context, data model.Edition) (model.Edition, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateEdition", ctx, data)
+	ret0, _ := ret[0].(model.Edition)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateEdition indicates an expected call of UpdateEdition.
+func (mr *MockRepositoryInterfaceMockRecorder) UpdateEdition(ctx, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEdition", reflect.TypeOf((*MockRepositoryInterface)(nil).UpdateEdition), ctx, data)
+}
+
+// MockLogicInterface is a mock of LogicInterface interface.
+type MockLogicInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockLogicInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockLogicInterfaceMockRecorder is the mock recorder for MockLogicInterface.
+type MockLogicInterfaceMockRecorder struct {
+	mock *MockLogicInterface
+}
+
+// NewMockLogicInterface creates a new mock instance.
+func NewMockLogicInterface(ctrl *gomock.Controller) *MockLogicInterface {
+	mock := &MockLogicInterface{ctrl: ctrl}
+	mock.recorder = &MockLogicInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLogicInterface) EXPECT() *MockLogicInterfaceMockRecorder {
+	return m.recorder
+}
+
+// DeleteEdition mocks base method.
+func (m *MockLogicInterface) DeleteEdition(ctx context.Context, bookID, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteEdition", ctx, bookID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteEdition indicates an expected call of DeleteEdition.
+func (mr *MockLogicInterfaceMockRecorder) DeleteEdition(ctx, bookID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEdition", reflect.TypeOf((*MockLogicInterface)(nil).DeleteEdition), ctx, bookID, id)
+}
+
+// GetEditionByID mocks base method.
+func (m *MockLogicInterface) GetEditionByID(ctx context.Context, bookID, id int64) (model.Edition, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEditionByID", ctx, bookID, id)
+	ret0, _ := ret[0].(model.Edition)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEditionByID indicates an expected call of GetEditionByID.
+func (mr *MockLogicInterfaceMockRecorder) GetEditionByID(ctx, bookID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEditionByID", reflect.TypeOf((*MockLogicInterface)(nil).GetEditionByID), ctx, bookID, id)
+}
+
+// GetEditions mocks base method.
+func (m *MockLogicInterface) GetEditions(ctx context.Context, bookID int64, params model.EditionSearchParams) ([]model.Edition, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEditions", ctx, bookID, params)
+	ret0, _ := ret[0].([]model.Edition)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEditions indicates an expected call of GetEditions.
+func (mr *MockLogicInterfaceMockRecorder) GetEditions(ctx, bookID, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEditions", reflect.TypeOf((*MockLogicInterface)(nil).GetEditions), ctx, bookID, params)
+}
+
+// StoreEdition mocks base method.
+func (m *MockLogicInterface) StoreEdition(ctx context.Context, data model.Edition) (model.Edition, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StoreEdition", ctx, data)
+	ret0, _ := ret[0].(model.Edition)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StoreEdition indicates an expected call of StoreEdition.
+func (mr *MockLogicInterfaceMockRecorder) StoreEdition(ctx, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreEdition", reflect.TypeOf((*MockLogicInterface)(nil).StoreEdition), ctx, data)
+}
+
+// UpdateEdition mocks base method.
+func (m *MockLogicInterface) UpdateEdition(ctx context.Context, data model.Edition) (model.Edition, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateEdition", ctx, data)
+	ret0, _ := ret[0].(model.Edition)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateEdition indicates an expected call of UpdateEdition.
+func (mr *MockLogicInterfaceMockRecorder) UpdateEdition(ctx, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEdition", reflect.TypeOf((*MockLogicInterface)(nil).UpdateEdition), ctx, data)
+}
@@ -0,0 +1,265 @@
+package edition
+
+import (
+	"byfood-app/internal/core"
+	"byfood-app/internal/model"
+	"byfood-app/internal/pkg/edtf"
+	"byfood-app/internal/pkg/isbn"
+	"byfood-app/internal/pkg/xerrors"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/huandu/go-sqlbuilder"
+	"github.com/jmoiron/sqlx"
+)
+
+const editionColumns = `id, book_id, publisher, format, language, page_count, isbn, published, created_at, updated_at`
+
+const (
+	// editionISBNIndex is the unique index keeping an ISBN on one edition,
+	// also raised when another book holds the ISBN.
+	editionISBNIndex = "idx_book_editions_isbn"
+)
+
+type EditionRepo struct {
+	deps *core.Dependency
+}
+
+func NewSQLRepo(deps *core.Dependency) *EditionRepo {
+	return &EditionRepo{
+		deps: deps,
+	}
+}
+
+// GetEditions returns the editions of a live book in the order they were
+// added, narrowed by params.
+func (repo *EditionRepo) GetEditions(ctx context.Context, bookID int64, params model.EditionSearchParams) ([]model.Edition, error) {
+	err := checkBook(ctx, repo.deps.DB, bookID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	q := sqlbuilder.NewSelectBuilder()
+	q.Select(editionColumns)
+	q.From("library.book_editions")
+	q.Where(q.Equal("book_id", bookID))
+
+	if params.Format != "" {
+		q.Where(q.Equal("format", params.Format))
+	}
+	if params.Language != "" {
+		q.Where(q.Equal("language", params.Language))
+	}
+
+	q.OrderBy("id ASC")
+
+	query, args := q.BuildWithFlavor(sqlbuilder.PostgreSQL)
+
+	var temp []model.SQLEdition
+	err = repo.deps.DB.SelectContext(ctx, &temp, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]model.Edition, 0, len(temp))
+	for _, edition := range temp {
+		result = append(result, toEdition(edition))
+	}
+
+	return result, nil
+}
+
+func (repo *EditionRepo) GetEditionByID(ctx context.Context, bookID int64, id int64) (model.Edition, error) {
+	var result model.SQLEdition
+
+	q := `
+		SELECT ` + editionColumns + `
+		FROM library.book_editions
+		WHERE id = $1 AND book_id = $2 AND book_id IN (SELECT id FROM library.books WHERE deleted_at ISNULL);
+	`
+	err := repo.deps.DB.QueryRowxContext(ctx, q, id, bookID).StructScan(&result)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Edition{}, xerrors.NewClientError(xerrors.ErrDataNotFound)
+		}
+
+		return model.Edition{}, err
+	}
+
+	return toEdition(result), nil
+}
+
+func (repo *EditionRepo) StoreEdition(ctx context.Context, data model.Edition) (model.Edition, error) {
+	tx, err := repo.deps.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return model.Edition{}, err
+	}
+	defer tx.Rollback()
+
+	err = checkBook(ctx, tx, data.BookID, true)
+	if err != nil {
+		return model.Edition{}, err
+	}
+
+	var result model.SQLEdition
+	q := `
+		INSERT INTO library.book_editions (book_id, publisher, format, language, page_count, isbn, published)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING ` + editionColumns + `;
+	`
+	err = tx.QueryRowxContext(ctx, q, data.BookID, nullString(data.Publisher), nullString(data.Format), nullString(data.Language), nullCount(data.PageCount), nullString(data.ISBN), nullPublished(data.Published)).
+		StructScan(&result)
+	if err != nil {
+		return model.Edition{}, isbnConflictError(err, data.ISBN)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		repo.deps.Logger.ErrorContext(ctx, "failed to commit sql transaction", slog.Any("error", err))
+		return model.Edition{}, err
+	}
+
+	return toEdition(result), nil
+}
+
+// UpdateEdition replaces every field of an edition of a live book.
+func (repo *EditionRepo) UpdateEdition(ctx context.Context, data model.Edition) (model.Edition, error) {
+	tx, err := repo.deps.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return model.Edition{}, err
+	}
+	defer tx.Rollback()
+
+	err = checkBook(ctx, tx, data.BookID, true)
+	if err != nil {
+		return model.Edition{}, err
+	}
+
+	var result model.SQLEdition
+	q := `
+		UPDATE library.book_editions
+		SET
+			publisher = $3,
+			format = $4,
+			language = $5,
+			page_count = $6,
+			isbn = $7,
+			published = $8,
+			updated_at = now()
+		WHERE id = $1 AND book_id = $2
+		RETURNING ` + editionColumns + `;
+	`
+	err = tx.QueryRowxContext(ctx, q, data.ID, data.BookID, nullString(data.Publisher), nullString(data.Format), nullString(data.Language), nullCount(data.PageCount), nullString(data.ISBN), nullPublished(data.Published)).
+		StructScan(&result)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Edition{}, xerrors.NewClientError(xerrors.ErrDataNotFound)
+		}
+
+		return model.Edition{}, isbnConflictError(err, data.ISBN)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		repo.deps.Logger.ErrorContext(ctx, "failed to commit sql transaction", slog.Any("error", err))
+		return model.Edition{}, err
+	}
+
+	return toEdition(result), nil
+}
+
+func (repo *EditionRepo) DeleteEdition(ctx context.Context, bookID int64, id int64) error {
+	q := `DELETE FROM library.book_editions WHERE id = $1 AND book_id = $2 AND book_id IN (SELECT id FROM library.books WHERE deleted_at ISNULL);`
+	res, err := repo.deps.DB.ExecContext(ctx, q, id, bookID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return xerrors.NewClientError(xerrors.ErrDataNotFound)
+	}
+
+	return nil
+}
+
+// checkBook fails with not found unless the book is live, lock keeps it so
+// until the transaction ends.
+func checkBook(ctx context.Context, db sqlx.QueryerContext, bookID int64, lock bool) error {
+	q := `SELECT id FROM library.books WHERE id = $1 AND deleted_at ISNULL`
+	if lock {
+		q += ` FOR SHARE`
+	}
+
+	err := db.QueryRowxContext(ctx, q+`;`, bookID).Scan(&bookID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return xerrors.NewClientError(xerrors.ErrDataNotFound)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// isbnConflictError reports an edition write putting an ISBN already on
+// another edition or another live book as a conflict.
+func isbnConflictError(err error, isbn string) error {
+	if !xerrors.IsUniqueViolation(err, editionISBNIndex) {
+		return err
+	}
+
+	return xerrors.ConflictError{Err: fmt.Errorf("another book or edition already has isbn %s", isbn)}
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+// nullCount stores an unknown page count, 0, as NULL.
+func nullCount(n int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: n, Valid: n != 0}
+}
+
+func nullPublished(date *edtf.Date) sql.NullString {
+	if date == nil {
+		return sql.NullString{}
+	}
+
+	return sql.NullString{String: date.String(), Valid: true}
+}
+
+func toEdition(temp model.SQLEdition) model.Edition {
+	edition := model.Edition{
+		ID:        temp.ID.Int64,
+		BookID:    temp.BookID.Int64,
+		Publisher: temp.Publisher.String,
+		Format:    temp.Format.String,
+		Language:  temp.Language.String,
+		PageCount: temp.PageCount.Int64,
+		ISBN:      temp.ISBN.String,
+		BaseAudit: model.BaseAudit{
+			CreatedAt: &temp.CreatedAt.Time,
+			UpdatedAt: &temp.UpdatedAt.Time,
+		},
+	}
+
+	if isbn10, err := isbn.To10(edition.ISBN); err == nil {
+		edition.ISBN10 = isbn10
+	}
+
+	if temp.Published.Valid {
+		if published, err := edtf.Parse(temp.Published.String); err == nil {
+			edition.Published = &published
+		}
+	}
+
+	return edition
+}
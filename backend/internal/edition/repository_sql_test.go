@@ -0,0 +1,271 @@
+package edition
+
+import (
+	"byfood-app/internal/core"
+	"byfood-app/internal/model"
+	"byfood-app/internal/pkg/edtf"
+	"byfood-app/internal/pkg/xerrors"
+	"context"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+var editionRows = []string{"id", "book_id", "publisher", "format", "language", "page_count", "isbn", "published", "created_at", "updated_at"}
+
+func newTestRepo(t *testing.T) (*EditionRepo, sqlmock.Sqlmock) {
+	db, mockDB, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return NewSQLRepo(&core.Dependency{
+		Logger: slog.Default(),
+		DB:     sqlx.NewDb(db, "sqlmock"),
+	}), mockDB
+}
+
+func TestEditionRepo_GetEditions(t *testing.T) {
+	now := time.Now()
+
+	published, err := edtf.Parse("1961-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		bookID   int64
+		params   model.EditionSearchParams
+		want     []model.Edition
+		wantCode int
+		mockFunc func(mockDB sqlmock.Sqlmock)
+	}{
+		{
+			name:   "success get editions by format and language",
+			bookID: 2,
+			params: model.EditionSearchParams{Format: "paperback", Language: "en"},
+			want: []model.Edition{{
+				ID:        4,
+				BookID:    2,
+				Publisher: "Signet Classics",
+				Format:    "paperback",
+				Language:  "en",
+				PageCount: 328,
+				ISBN:      "9780451524935",
+				ISBN10:    "0451524934",
+				Published: &published,
+				BaseAudit: model.BaseAudit{CreatedAt: &now, UpdatedAt: &now},
+			}},
+			wantCode: http.StatusOK,
+			mockFunc: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectQuery(`^SELECT id FROM library.books WHERE id = \$1 AND deleted_at ISNULL;$`).
+					WithArgs(2).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+				mockDB.ExpectQuery(`^SELECT .* FROM library.book_editions WHERE book_id = \$1 AND format = \$2 AND language = \$3 ORDER BY id ASC$`).
+					WithArgs(2, "paperback", "en").
+					WillReturnRows(sqlmock.NewRows(editionRows).
+						AddRow(4, 2, "Signet Classics", "paperback", "en", 328, "9780451524935", "1961-01", now, now))
+			},
+		},
+		{
+			name:     "failed book not found",
+			bookID:   99,
+			wantCode: http.StatusBadRequest,
+			mockFunc: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectQuery(`^SELECT id FROM library.books .*;$`).
+					WithArgs(99).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, mockDB := newTestRepo(t)
+			tt.mockFunc(mockDB)
+
+			got, err := repo.GetEditions(context.Background(), tt.bookID, tt.params)
+			if (err != nil) != (tt.wantCode != http.StatusOK) || (err != nil && xerrors.ParseErrorTypeToCodeInt(err) != tt.wantCode) {
+				t.Errorf("EditionRepo.GetEditions() error = %v, wantCode %v", err, tt.wantCode)
+				return
+			}
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("EditionRepo.GetEditions() = %+v, want %+v", got, tt.want)
+			}
+			if err := mockDB.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestEditionRepo_StoreEdition(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		data     model.Edition
+		want     model.Edition
+		wantCode int
+		mockFunc func(mockDB sqlmock.Sqlmock)
+	}{
+		{
+			name: "success store edition without optional fields",
+			data: model.Edition{BookID: 3, Format: "ebook"},
+			want: model.Edition{
+				ID:        5,
+				BookID:    3,
+				Format:    "ebook",
+				BaseAudit: model.BaseAudit{CreatedAt: &now, UpdatedAt: &now},
+			},
+			wantCode: http.StatusOK,
+			mockFunc: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectBegin()
+				mockDB.ExpectQuery(`^SELECT id FROM library.books WHERE id = \$1 AND deleted_at ISNULL FOR SHARE;$`).
+					WithArgs(3).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+				mockDB.ExpectQuery(`^INSERT INTO library.book_editions \(book_id, publisher, format, language, page_count, isbn, published\) VALUES \(\$1, \$2, \$3, \$4, \$5, \$6, \$7\) RETURNING .*;$`).
+					WithArgs(3, nil, "ebook", nil, nil, nil, nil).
+					WillReturnRows(sqlmock.NewRows(editionRows).AddRow(5, 3, nil, "ebook", nil, nil, nil, nil, now, now))
+				mockDB.ExpectCommit()
+			},
+		},
+		{
+			name:     "failed store edition with isbn of another book",
+			data:     model.Edition{BookID: 3, Format: "ebook", ISBN: "9780451524935"},
+			wantCode: http.StatusConflict,
+			mockFunc: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectBegin()
+				mockDB.ExpectQuery(`^SELECT id FROM library.books .* FOR SHARE;$`).
+					WithArgs(3).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+				mockDB.ExpectQuery(`^INSERT INTO library.book_editions .*;$`).
+					WithArgs(3, nil, "ebook", nil, nil, "9780451524935", nil).
+					WillReturnError(&pq.Error{Code: xerrors.PQUniqueViolation, Constraint: editionISBNIndex})
+				mockDB.ExpectRollback()
+			},
+		},
+		{
+			name:     "failed store edition of a deleted book",
+			data:     model.Edition{BookID: 7, Format: "ebook"},
+			wantCode: http.StatusBadRequest,
+			mockFunc: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectBegin()
+				mockDB.ExpectQuery(`^SELECT id FROM library.books .* FOR SHARE;$`).
+					WithArgs(7).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mockDB.ExpectRollback()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, mockDB := newTestRepo(t)
+			tt.mockFunc(mockDB)
+
+			got, err := repo.StoreEdition(context.Background(), tt.data)
+			if (err != nil) != (tt.wantCode != http.StatusOK) || (err != nil && xerrors.ParseErrorTypeToCodeInt(err) != tt.wantCode) {
+				t.Errorf("EditionRepo.StoreEdition() error = %v, wantCode %v", err, tt.wantCode)
+				return
+			}
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("EditionRepo.StoreEdition() = %+v, want %+v", got, tt.want)
+			}
+			if err := mockDB.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestEditionRepo_UpdateEdition(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     model.Edition
+		wantCode int
+		mockFunc func(mockDB sqlmock.Sqlmock)
+	}{
+		{
+			name:     "failed edition of another book",
+			data:     model.Edition{ID: 4, BookID: 3, Format: "ebook"},
+			wantCode: http.StatusBadRequest,
+			mockFunc: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectBegin()
+				mockDB.ExpectQuery(`^SELECT id FROM library.books .* FOR SHARE;$`).
+					WithArgs(3).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+				mockDB.ExpectQuery(`^UPDATE library.book_editions SET .* WHERE id = \$1 AND book_id = \$2 RETURNING .*;$`).
+					WithArgs(4, 3, nil, "ebook", nil, nil, nil, nil).
+					WillReturnRows(sqlmock.NewRows(editionRows))
+				mockDB.ExpectRollback()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, mockDB := newTestRepo(t)
+			tt.mockFunc(mockDB)
+
+			_, err := repo.UpdateEdition(context.Background(), tt.data)
+			if (err != nil) != (tt.wantCode != http.StatusOK) || (err != nil && xerrors.ParseErrorTypeToCodeInt(err) != tt.wantCode) {
+				t.Errorf("EditionRepo.UpdateEdition() error = %v, wantCode %v", err, tt.wantCode)
+			}
+			if err := mockDB.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestEditionRepo_DeleteEdition(t *testing.T) {
+	tests := []struct {
+		name     string
+		bookID   int64
+		id       int64
+		wantCode int
+		mockFunc func(mockDB sqlmock.Sqlmock)
+	}{
+		{
+			name:     "success delete edition",
+			bookID:   1,
+			id:       2,
+			wantCode: http.StatusOK,
+			mockFunc: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectExec(`^DELETE FROM library.book_editions WHERE id = \$1 AND book_id = \$2 .*;$`).
+					WithArgs(2, 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:     "failed edition not found",
+			bookID:   1,
+			id:       99,
+			wantCode: http.StatusBadRequest,
+			mockFunc: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectExec(`^DELETE FROM library.book_editions .*;$`).
+					WithArgs(99, 1).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, mockDB := newTestRepo(t)
+			tt.mockFunc(mockDB)
+
+			err := repo.DeleteEdition(context.Background(), tt.bookID, tt.id)
+			if (err != nil) != (tt.wantCode != http.StatusOK) || (err != nil && xerrors.ParseErrorTypeToCodeInt(err) != tt.wantCode) {
+				t.Errorf("EditionRepo.DeleteEdition() error = %v, wantCode %v", err, tt.wantCode)
+			}
+			if err := mockDB.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,298 @@
+package genre
+
+import (
+	"byfood-app/internal/core"
+	"byfood-app/internal/model"
+	"byfood-app/internal/pkg/xerrors"
+	"byfood-app/internal/pkg/xhttp"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type GenreHandler struct {
+	deps  *core.Dependency
+	logic LogicInterface
+}
+
+func NewHTTPHandler(deps *core.Dependency, logic LogicInterface) *GenreHandler {
+	return &GenreHandler{
+		deps:  deps,
+		logic: logic,
+	}
+}
+
+// GetGenres godoc
+// @Summary List the whole genre tree
+// @Description Genres come ordered by path, every genre right after its parent. Book counts include the genres below.
+// @Tags genres
+// @Produce json
+// @Success 200 {object} xhttp.BaseResponse{data=[]model.Genre}
+// @Router /genres [get]
+func (h *GenreHandler) GetGenres(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	data, err := h.logic.GetGenres(ctx)
+	if err != nil {
+		h.deps.Logger.ErrorContext(ctx, "failed to get genres", slog.Any("error", err))
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to get genres",
+		}, xerrors.ParseErrorTypeToCodeInt(err))
+		return
+	}
+
+	xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+		Data:    data,
+		Message: "genres fetched",
+	}, http.StatusOK)
+}
+
+// GetGenreByID godoc
+// @Summary Get a genre data by its ID
+// @Tags genres
+// @Produce json
+// @Param id path integer true "genre ID"
+// @Success 200 {object} xhttp.BaseResponse{data=model.Genre}
+// @Router /genres/{id} [get]
+func (h *GenreHandler) GetGenreByID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// get and validate id param
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   xerrors.ErrInvalidID.Error(),
+			Message: xerrors.ErrInvalidID.Error(),
+		}, http.StatusBadRequest)
+		return
+	}
+	idParam, err := strconv.Atoi(id)
+	if err != nil {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to parse id parameter",
+		}, http.StatusBadRequest)
+		return
+	}
+
+	data, err := h.logic.GetGenreByID(ctx, int64(idParam))
+	if err != nil {
+		h.deps.Logger.ErrorContext(ctx, "failed to get genre data by id", slog.Any("error", err))
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to get genre data",
+		}, xerrors.ParseErrorTypeToCodeInt(err))
+		return
+	}
+
+	xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+		Data:    data,
+		Message: "genre data fetched",
+	}, http.StatusOK)
+}
+
+// StoreGenre godoc
+// @Summary Store new genre data, return stored data
+// @Description A genre without parent_id is added at the top of the tree. Names are unique among the genres under the same parent.
+// @Tags genres
+// @Produce json
+// @Param data body model.StoreGenreRequest true "genre data"
+// @Success 200 {object} xhttp.BaseResponse{data=model.Genre}
+// @Router /genres [post]
+func (h *GenreHandler) StoreGenre(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// parse request body
+	var payload model.StoreGenreRequest
+	err := xhttp.BindJSONRequest(r, &payload)
+	if err != nil {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to parse request body",
+		}, http.StatusBadRequest)
+		return
+	}
+
+	data, err := h.logic.StoreGenre(ctx, model.Genre{
+		Name:     payload.Name,
+		ParentID: payload.ParentID,
+	})
+	if err != nil {
+		h.deps.Logger.ErrorContext(ctx, "failed to store genre data", slog.Any("error", err))
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to store genre data",
+		}, xerrors.ParseErrorTypeToCodeInt(err))
+		return
+	}
+
+	xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+		Data:    data,
+		Message: "genre data stored",
+	}, http.StatusOK)
+}
+
+// UpdateGenre godoc
+// @Summary Update genre data by ID, return updated data
+// @Description Changing parent_id moves the genre with everything below it. A genre can not be moved below itself, leaving parent_id out moves it to the top.
+// @Tags genres
+// @Produce json
+// @Param id path integer true "genre ID"
+// @Param data body model.UpdateGenreRequest true "genre data"
+// @Success 200 {object} xhttp.BaseResponse{data=model.Genre}
+// @Router /genres/{id} [put]
+func (h *GenreHandler) UpdateGenre(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// get and validate id param
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   xerrors.ErrInvalidID.Error(),
+			Message: xerrors.ErrInvalidID.Error(),
+		}, http.StatusBadRequest)
+		return
+	}
+	idParam, err := strconv.Atoi(id)
+	if err != nil {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to parse id parameter",
+		}, http.StatusBadRequest)
+		return
+	}
+
+	// parse request body
+	var payload model.UpdateGenreRequest
+	err = xhttp.BindJSONRequest(r, &payload)
+	if err != nil {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to parse request body",
+		}, http.StatusBadRequest)
+		return
+	}
+
+	data, err := h.logic.UpdateGenre(ctx, model.Genre{
+		ID:       int64(idParam),
+		Name:     payload.Name,
+		ParentID: payload.ParentID,
+	})
+	if err != nil {
+		h.deps.Logger.ErrorContext(ctx, "failed to update genre data", slog.Any("error", err))
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to update genre data",
+		}, xerrors.ParseErrorTypeToCodeInt(err))
+		return
+	}
+
+	xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+		Data:    data,
+		Message: "genre data updated",
+	}, http.StatusOK)
+}
+
+// DeleteGenre godoc
+// @Summary Delete genre data by ID
+// @Description The genre is taken off its books. Refused while other genres are below it.
+// @Tags genres
+// @Produce json
+// @Param id path integer true "genre ID"
+// @Success 200 {object} xhttp.BaseResponse{message=string}
+// @Router /genres/{id} [delete]
+func (h *GenreHandler) DeleteGenre(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// get and validate id param
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   xerrors.ErrInvalidID.Error(),
+			Message: xerrors.ErrInvalidID.Error(),
+		}, http.StatusBadRequest)
+		return
+	}
+	idParam, err := strconv.Atoi(id)
+	if err != nil {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to parse id parameter",
+		}, http.StatusBadRequest)
+		return
+	}
+
+	err = h.logic.DeleteGenre(ctx, int64(idParam))
+	if err != nil {
+		h.deps.Logger.ErrorContext(ctx, "failed to delete genre data", slog.Any("error", err))
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to delete genre data",
+		}, xerrors.ParseErrorTypeToCodeInt(err))
+		return
+	}
+
+	xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+		Message: "genre data deleted",
+	}, http.StatusOK)
+}
+
+// SetBookGenres godoc
+// @Summary Replace the genres of a book by ID, return them
+// @Description Genres are not part of the book version, so this neither bumps the version nor adds a revision. An empty genre_ids clears them.
+// @Tags genres
+// @Produce json
+// @Param id path integer true "book ID"
+// @Param data body model.SetBookGenresRequest true "genres of the book"
+// @Success 200 {object} xhttp.BaseResponse{data=[]model.BookGenre}
+// @Router /books/{id}/genres [put]
+func (h *GenreHandler) SetBookGenres(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// get and validate id param
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   xerrors.ErrInvalidID.Error(),
+			Message: xerrors.ErrInvalidID.Error(),
+		}, http.StatusBadRequest)
+		return
+	}
+	idParam, err := strconv.Atoi(id)
+	if err != nil {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to parse id parameter",
+		}, http.StatusBadRequest)
+		return
+	}
+
+	// parse request body
+	var payload model.SetBookGenresRequest
+	err = xhttp.BindJSONRequest(r, &payload)
+	if err != nil {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to parse request body",
+		}, http.StatusBadRequest)
+		return
+	}
+
+	data, err := h.logic.SetBookGenres(ctx, int64(idParam), payload.GenreIDs)
+	if err != nil {
+		h.deps.Logger.ErrorContext(ctx, "failed to set book genres", slog.Any("error", err))
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to set book genres",
+		}, xerrors.ParseErrorTypeToCodeInt(err))
+		return
+	}
+
+	xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+		Data:    data,
+		Message: "book genres updated",
+	}, http.StatusOK)
+}
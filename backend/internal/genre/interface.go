@@ -0,0 +1,26 @@
+package genre
+
+import (
+	"byfood-app/internal/model"
+	"context"
+)
+
+//go:generate go run go.uber.org/mock/mockgen@latest -source=interface.go -destination=mock_interface.go -package=genre
+type RepositoryInterface interface {
+	GetGenres(ctx context.Context) ([]model.Genre, error)
+	GetGenreByID(ctx context.Context, id int64) (model.Genre, error)
+	StoreGenre(ctx context.Context, data model.Genre) (model.Genre, error)
+	UpdateGenre(ctx context.Context, data model.Genre) (model.Genre, error)
+	DeleteGenre(ctx context.Context, id int64) error
+	SetBookGenres(ctx context.Context, bookID int64, genreIDs []int64) ([]model.BookGenre, error)
+}
+
+//go:generate go run go.uber.org/mock/mockgen@latest -source=interface.go -destination=mock_interface.go -package=genre
+type LogicInterface interface {
+	GetGenres(ctx context.Context) ([]model.Genre, error)
+	GetGenreByID(ctx context.Context, id int64) (model.Genre, error)
+	StoreGenre(ctx context.Context, data model.Genre) (model.Genre, error)
+	UpdateGenre(ctx context.Context, data model.Genre) (model.Genre, error)
+	DeleteGenre(ctx context.Context, id int64) error
+	SetBookGenres(ctx context.Context, bookID int64, genreIDs []int64) ([]model.BookGenre, error)
+}
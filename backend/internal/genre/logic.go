@@ -0,0 +1,145 @@
+package genre
+
+import (
+	"byfood-app/internal/core"
+	"byfood-app/internal/model"
+	"byfood-app/internal/pkg/xerrors"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+)
+
+const maxBookGenres = 20
+
+type GenreLogic struct {
+	deps *core.Dependency
+	repo RepositoryInterface
+}
+
+func NewGenreLogic(deps *core.Dependency, repo RepositoryInterface) *GenreLogic {
+	return &GenreLogic{
+		deps: deps,
+		repo: repo,
+	}
+}
+
+func (logic *GenreLogic) GetGenres(ctx context.Context) ([]model.Genre, error) {
+	data, err := logic.repo.GetGenres(ctx)
+	if err != nil {
+		logic.deps.Logger.ErrorContext(ctx, "failed to get genres", slog.Any("error", err))
+		return []model.Genre{}, err
+	}
+
+	return data, nil
+}
+
+func (logic *GenreLogic) GetGenreByID(ctx context.Context, id int64) (model.Genre, error) {
+	if id <= 0 {
+		return model.Genre{}, xerrors.NewClientError(xerrors.ErrInvalidID)
+	}
+
+	data, err := logic.repo.GetGenreByID(ctx, id)
+	if err != nil {
+		logic.deps.Logger.ErrorContext(ctx, "failed to get genre by id", slog.Any("error", err))
+		return model.Genre{}, err
+	}
+
+	return data, nil
+}
+
+func (logic *GenreLogic) StoreGenre(ctx context.Context, data model.Genre) (model.Genre, error) {
+	data.Name = normalizeGenreName(data.Name)
+	err := validateGenre(data)
+	if err != nil {
+		return model.Genre{}, err
+	}
+
+	result, err := logic.repo.StoreGenre(ctx, data)
+	if err != nil {
+		logic.deps.Logger.ErrorContext(ctx, "failed to store genre data", slog.Any("error", err))
+		return model.Genre{}, err
+	}
+
+	return result, nil
+}
+
+func (logic *GenreLogic) UpdateGenre(ctx context.Context, data model.Genre) (model.Genre, error) {
+	if data.ID <= 0 {
+		return model.Genre{}, xerrors.NewClientError(xerrors.ErrInvalidID)
+	}
+
+	data.Name = normalizeGenreName(data.Name)
+	err := validateGenre(data)
+	if err != nil {
+		return model.Genre{}, err
+	}
+
+	result, err := logic.repo.UpdateGenre(ctx, data)
+	if err != nil {
+		logic.deps.Logger.ErrorContext(ctx, "failed to update genre data", slog.Any("error", err))
+		return model.Genre{}, err
+	}
+
+	return result, nil
+}
+
+func (logic *GenreLogic) DeleteGenre(ctx context.Context, id int64) error {
+	if id <= 0 {
+		return xerrors.NewClientError(xerrors.ErrInvalidID)
+	}
+
+	err := logic.repo.DeleteGenre(ctx, id)
+	if err != nil {
+		logic.deps.Logger.ErrorContext(ctx, "failed to delete genre data", slog.Any("error", err))
+		return err
+	}
+
+	return nil
+}
+
+func (logic *GenreLogic) SetBookGenres(ctx context.Context, bookID int64, genreIDs []int64) ([]model.BookGenre, error) {
+	if bookID <= 0 {
+		return nil, xerrors.NewClientError(xerrors.ErrInvalidID)
+	}
+
+	genreIDs = slices.Clone(genreIDs)
+	slices.Sort(genreIDs)
+	genreIDs = slices.Compact(genreIDs)
+
+	switch {
+	case len(genreIDs) > maxBookGenres:
+		return nil, xerrors.NewClientError(fmt.Errorf("a book can have at most %d genres", maxBookGenres))
+	case len(genreIDs) > 0 && genreIDs[0] <= 0:
+		return nil, xerrors.NewClientError(errors.New("genre_ids has an invalid id"))
+	}
+
+	result, err := logic.repo.SetBookGenres(ctx, bookID, genreIDs)
+	if err != nil {
+		logic.deps.Logger.ErrorContext(ctx, "failed to set book genres", slog.Any("error", err))
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func normalizeGenreName(name string) string {
+	return strings.Join(strings.Fields(name), " ")
+}
+
+func validateGenre(data model.Genre) error {
+	switch {
+	case data.Name == "":
+		return xerrors.NewClientError(errors.New("name field is empty"))
+	case strings.Contains(data.Name, model.GenrePathSeparator):
+		return xerrors.NewClientError(fmt.Errorf("name can not contain %q", model.GenrePathSeparator))
+	case data.ParentID < 0:
+		return xerrors.NewClientError(errors.New("parent_id is invalid"))
+	case data.ID != 0 && data.ParentID == data.ID:
+		return xerrors.NewClientError(errors.New("a genre can not be its own parent"))
+	}
+
+	return nil
+}
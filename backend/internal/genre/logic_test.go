@@ -0,0 +1,225 @@
+package genre
+
+import (
+	"byfood-app/internal/core"
+	"byfood-app/internal/model"
+	"byfood-app/internal/pkg/xerrors"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+)
+
+type testSuite struct {
+	Ctrl          *gomock.Controller
+	MockGenreRepo *MockRepositoryInterface
+}
+
+func setupTestSuite(t *testing.T) *testSuite {
+	ctrl := gomock.NewController(t)
+	return &testSuite{
+		Ctrl:          ctrl,
+		MockGenreRepo: NewMockRepositoryInterface(ctrl),
+	}
+}
+
+func TestGenreLogic_StoreGenre(t *testing.T) {
+	ts := setupTestSuite(t)
+	deps := &core.Dependency{Logger: slog.Default()}
+
+	tests := []struct {
+		name     string
+		data     model.Genre
+		want     model.Genre
+		wantCode int
+		mockFunc func()
+	}{
+		{
+			name:     "success store genre with collapsed spaces",
+			data:     model.Genre{Name: " High   Fantasy ", ParentID: 3},
+			want:     model.Genre{ID: 7, ParentID: 3, Name: "High Fantasy", Path: "Fiction > Fantasy > High Fantasy"},
+			wantCode: http.StatusOK,
+			mockFunc: func() {
+				ts.MockGenreRepo.EXPECT().StoreGenre(gomock.Any(), model.Genre{Name: "High Fantasy", ParentID: 3}).
+					Return(model.Genre{ID: 7, ParentID: 3, Name: "High Fantasy", Path: "Fiction > Fantasy > High Fantasy"}, nil)
+			},
+		},
+		{
+			name:     "failed store genre without name",
+			data:     model.Genre{Name: " "},
+			wantCode: http.StatusBadRequest,
+			mockFunc: func() {},
+		},
+		{
+			name:     "failed store genre with name looking like a path",
+			data:     model.Genre{Name: "Fantasy > High Fantasy"},
+			wantCode: http.StatusBadRequest,
+			mockFunc: func() {},
+		},
+		{
+			name:     "failed store genre with invalid parent",
+			data:     model.Genre{Name: "Fantasy", ParentID: -1},
+			wantCode: http.StatusBadRequest,
+			mockFunc: func() {},
+		},
+		{
+			name:     "failed store genre taken under the parent",
+			data:     model.Genre{Name: "Fantasy", ParentID: 1},
+			wantCode: http.StatusBadRequest,
+			mockFunc: func() {
+				ts.MockGenreRepo.EXPECT().StoreGenre(gomock.Any(), gomock.Any()).
+					Return(model.Genre{}, xerrors.NewClientError(errors.New(`genre "Fantasy" already exists there`)))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logic := NewGenreLogic(deps, ts.MockGenreRepo)
+
+			tt.mockFunc()
+
+			got, err := logic.StoreGenre(context.Background(), tt.data)
+			if (err != nil) != (tt.wantCode != http.StatusOK) || (err != nil && xerrors.ParseErrorTypeToCodeInt(err) != tt.wantCode) {
+				t.Errorf("GenreLogic.StoreGenre() error = %v, wantCode %v", err, tt.wantCode)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GenreLogic.StoreGenre() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenreLogic_UpdateGenre(t *testing.T) {
+	ts := setupTestSuite(t)
+	deps := &core.Dependency{Logger: slog.Default()}
+
+	tests := []struct {
+		name     string
+		data     model.Genre
+		wantCode int
+		mockFunc func()
+	}{
+		{
+			name:     "success move genre to the top",
+			data:     model.Genre{ID: 3, Name: "Fantasy"},
+			wantCode: http.StatusOK,
+			mockFunc: func() {
+				ts.MockGenreRepo.EXPECT().UpdateGenre(gomock.Any(), model.Genre{ID: 3, Name: "Fantasy"}).
+					Return(model.Genre{ID: 3, Name: "Fantasy", Path: "Fantasy"}, nil)
+			},
+		},
+		{
+			name:     "failed update genre with invalid id",
+			data:     model.Genre{Name: "Fantasy"},
+			wantCode: http.StatusBadRequest,
+			mockFunc: func() {},
+		},
+		{
+			name:     "failed genre as its own parent",
+			data:     model.Genre{ID: 3, Name: "Fantasy", ParentID: 3},
+			wantCode: http.StatusBadRequest,
+			mockFunc: func() {},
+		},
+		{
+			name:     "failed move genre below itself",
+			data:     model.Genre{ID: 3, Name: "Fantasy", ParentID: 7},
+			wantCode: http.StatusBadRequest,
+			mockFunc: func() {
+				ts.MockGenreRepo.EXPECT().UpdateGenre(gomock.Any(), gomock.Any()).
+					Return(model.Genre{}, xerrors.NewClientError(errors.New("a genre can not be moved below itself")))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logic := NewGenreLogic(deps, ts.MockGenreRepo)
+
+			tt.mockFunc()
+
+			_, err := logic.UpdateGenre(context.Background(), tt.data)
+			if (err != nil) != (tt.wantCode != http.StatusOK) || (err != nil && xerrors.ParseErrorTypeToCodeInt(err) != tt.wantCode) {
+				t.Errorf("GenreLogic.UpdateGenre() error = %v, wantCode %v", err, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestGenreLogic_SetBookGenres(t *testing.T) {
+	ts := setupTestSuite(t)
+	deps := &core.Dependency{Logger: slog.Default()}
+
+	tests := []struct {
+		name     string
+		bookID   int64
+		genreIDs []int64
+		want     []model.BookGenre
+		wantCode int
+		mockFunc func()
+	}{
+		{
+			name:     "success set deduplicated genres",
+			bookID:   1,
+			genreIDs: []int64{7, 3, 7},
+			want:     []model.BookGenre{{ID: 3, Name: "Fantasy"}, {ID: 7, Name: "High Fantasy"}},
+			wantCode: http.StatusOK,
+			mockFunc: func() {
+				ts.MockGenreRepo.EXPECT().SetBookGenres(gomock.Any(), int64(1), []int64{3, 7}).
+					Return([]model.BookGenre{{ID: 3, Name: "Fantasy"}, {ID: 7, Name: "High Fantasy"}}, nil)
+			},
+		},
+		{
+			name:     "success clear genres",
+			bookID:   1,
+			genreIDs: nil,
+			wantCode: http.StatusOK,
+			mockFunc: func() {
+				ts.MockGenreRepo.EXPECT().SetBookGenres(gomock.Any(), int64(1), []int64(nil)).Return(nil, nil)
+			},
+		},
+		{
+			name:     "failed invalid genre id",
+			bookID:   1,
+			genreIDs: []int64{3, 0},
+			wantCode: http.StatusBadRequest,
+			mockFunc: func() {},
+		},
+		{
+			name:     "failed invalid book id",
+			bookID:   0,
+			genreIDs: []int64{3},
+			wantCode: http.StatusBadRequest,
+			mockFunc: func() {},
+		},
+		{
+			name:     "failed book not found",
+			bookID:   99,
+			genreIDs: []int64{3},
+			wantCode: http.StatusBadRequest,
+			mockFunc: func() {
+				ts.MockGenreRepo.EXPECT().SetBookGenres(gomock.Any(), int64(99), []int64{3}).
+					Return(nil, xerrors.NewClientError(xerrors.ErrDataNotFound))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logic := NewGenreLogic(deps, ts.MockGenreRepo)
+
+			tt.mockFunc()
+
+			got, err := logic.SetBookGenres(context.Background(), tt.bookID, tt.genreIDs)
+			if (err != nil) != (tt.wantCode != http.StatusOK) || (err != nil && xerrors.ParseErrorTypeToCodeInt(err) != tt.wantCode) {
+				t.Errorf("GenreLogic.SetBookGenres() error = %v, wantCode %v", err, tt.wantCode)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GenreLogic.SetBookGenres() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
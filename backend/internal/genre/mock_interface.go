@@ -0,0 +1,244 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: interface.go
+//
+// Generated by this command:
+//
+//	mockgen -source=interface.go -destination=mock_interface.go -package=genre
+//
+
+// Package genre is a generated GoMock package.
+package genre
+
+import (
+	model "byfood-app/internal/model"
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRepositoryInterface is a mock of RepositoryInterface interface.
+type MockRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockRepositoryInterfaceMockRecorder is the mock recorder for MockRepositoryInterface.
+type MockRepositoryInterfaceMockRecorder struct {
+	mock *MockRepositoryInterface
+}
+
+// NewMockRepositoryInterface creates a new mock instance.
+func NewMockRepositoryInterface(ctrl *gomock.Controller) *MockRepositoryInterface {
+	mock := &MockRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepositoryInterface) EXPECT() *MockRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// DeleteGenre mocks base method.
+func (m *MockRepositoryInterface) DeleteGenre(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteGenre", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteGenre indicates an expected call of DeleteGenre.
+func (mr *MockRepositoryInterfaceMockRecorder) DeleteGenre(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteGenre", reflect.TypeOf((*MockRepositoryInterface)(nil).DeleteGenre), ctx, id)
+}
+
+// GetGenreByID mocks base method.
+func (m *MockRepositoryInterface) GetGenreByID(ctx context.Context, id int64) (model.Genre, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGenreByID", ctx, id)
+	ret0, _ := ret[0].(model.Genre)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGenreByID indicates an expected call of GetGenreByID.
+func (mr *MockRepositoryInterfaceMockRecorder) GetGenreByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGenreByID", reflect.TypeOf((*MockRepositoryInterface)(nil).GetGenreByID), ctx, id)
+}
+
+// GetGenres mocks base method.
+func (m *MockRepositoryInterface) GetGenres(ctx context.Context) ([]model.Genre, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGenres", ctx)
+	ret0, _ := ret[0].([]model.Genre)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGenres indicates an expected call of GetGenres.
+func (mr *MockRepositoryInterfaceMockRecorder) GetGenres(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGenres", reflect.TypeOf((*MockRepositoryInterface)(nil).GetGenres), ctx)
+}
+
+// SetBookGenres mocks base method.
+func (m *MockRepositoryInterface) SetBookGenres(ctx context.Context, bookID int64, genreIDs []int64) ([]model.BookGenre, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetBookGenres", ctx, bookID, genreIDs)
+	ret0, _ := ret[0].([]model.BookGenre)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetBookGenres indicates an expected call of SetBookGenres.
+func (mr *MockRepositoryInterfaceMockRecorder) SetBookGenres(ctx, bookID, genreIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBookGenres", reflect.TypeOf((*MockRepositoryInterface)(nil).SetBookGenres), ctx, bookID, genreIDs)
+}
+
+// StoreGenre mocks base method.
+func (m *MockRepositoryInterface) StoreGenre(ctx context.Context, data model.Genre) (model.Genre, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StoreGenre", ctx, data)
+	ret0, _ := ret[0].(model.Genre)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StoreGenre indicates an expected call of StoreGenre.
+func (mr *MockRepositoryInterfaceMockRecorder) StoreGenre(ctx, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreGenre", reflect.TypeOf((*MockRepositoryInterface)(nil).StoreGenre), ctx, data)
+}
+
+// UpdateGenre mocks base method.
+func (m *MockRepositoryInterface) UpdateGenre(ctx context.Context, data model.Genre) (model.Genre, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateGenre", ctx, data)
+	ret0, _ := ret[0].(model.Genre)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateGenre indicates an expected call of UpdateGenre.
+func (mr *MockRepositoryInterfaceMockRecorder) UpdateGenre(ctx, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateGenre", reflect.TypeOf((*MockRepositoryInterface)(nil).UpdateGenre), ctx, data)
+}
+
+// MockLogicInterface is a mock of LogicInterface interface.
+type MockLogicInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockLogicInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockLogicInterfaceMockRecorder is the mock recorder for MockLogicInterface.
+type MockLogicInterfaceMockRecorder struct {
+	mock *MockLogicInterface
+}
+
+// NewMockLogicInterface creates a new mock instance.
+func NewMockLogicInterface(ctrl *gomock.Controller) *MockLogicInterface {
+	mock := &MockLogicInterface{ctrl: ctrl}
+	mock.recorder = &MockLogicInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLogicInterface) EXPECT() *MockLogicInterfaceMockRecorder {
+	return m.recorder
+}
+
+// DeleteGenre mocks base method.
+func (m *MockLogicInterface) DeleteGenre(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteGenre", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteGenre indicates an expected call of DeleteGenre.
+func (mr *MockLogicInterfaceMockRecorder) DeleteGenre(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteGenre", reflect.TypeOf((*MockLogicInterface)(nil).DeleteGenre), ctx, id)
+}
+
+// GetGenreByID mocks base method.
+func (m *MockLogicInterface) GetGenreByID(ctx context.Context, id int64) (model.Genre, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGenreByID", ctx, id)
+	ret0, _ := ret[0].(model.Genre)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGenreByID indicates an expected call of GetGenreByID.
+func (mr *MockLogicInterfaceMockRecorder) GetGenreByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGenreByID", reflect.TypeOf((*MockLogicInterface)(nil).GetGenreByID), ctx, id)
+}
+
+// GetGenres mocks base method.
+func (m *MockLogicInterface) GetGenres(ctx context.Context) ([]model.Genre, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGenres", ctx)
+	ret0, _ := ret[0].([]model.Genre)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGenres indicates an expected call of GetGenres.
+func (mr *MockLogicInterfaceMockRecorder) GetGenres(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGenres", reflect.TypeOf((*MockLogicInterface)(nil).GetGenres), ctx)
+}
+
+// SetBookGenres mocks base method.
+func (m *MockLogicInterface) SetBookGenres(ctx context.Context, bookID int64, genreIDs []int64) ([]model.BookGenre, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetBookGenres", ctx, bookID, genreIDs)
+	ret0, _ := ret[0].([]model.BookGenre)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetBookGenres indicates an expected call of SetBookGenres.
+func (mr *MockLogicInterfaceMockRecorder) SetBookGenres(ctx, bookID, genreIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBookGenres", reflect.TypeOf((*MockLogicInterface)(nil).SetBookGenres), ctx, bookID, genreIDs)
+}
+
+// StoreGenre mocks base method.
+func (m *MockLogicInterface) StoreGenre(ctx context.Context, data model.Genre) (model.Genre, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StoreGenre", ctx, data)
+	ret0, _ := ret[0].(model.Genre)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StoreGenre indicates an expected call of StoreGenre.
+func (mr *MockLogicInterfaceMockRecorder) StoreGenre(ctx, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreGenre", reflect.TypeOf((*MockLogicInterface)(nil).StoreGenre), ctx, data)
+}
+
+// UpdateGenre mocks base method.
+func (m *MockLogicInterface) UpdateGenre(ctx context.Context, data model.Genre) (model.Genre, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateGenre", ctx, data)
+	ret0, _ := ret[0].(model.Genre)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateGenre indicates an expected call of UpdateGenre.
+func (mr *MockLogicInterfaceMockRecorder) UpdateGenre(ctx, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateGenre", reflect.TypeOf((*MockLogicInterface)(nil).UpdateGenre), ctx, data)
+}
@@ -0,0 +1,282 @@
+package genre
+
+import (
+	"byfood-app/internal/core"
+	"byfood-app/internal/model"
+	"byfood-app/internal/pkg/xerrors"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// genreTreeQuery walks the genre tree from the top level genres down,
+// building the path of every genre and counting the books that are not
+// deleted in it or any genre below it.
+const genreTreeQuery = `
+	WITH RECURSIVE tree AS (
+		SELECT id, parent_id, name, ARRAY[name] AS path, ARRAY[id] AS ancestors, created_at, updated_at
+			FROM library.genres
+			WHERE parent_id ISNULL
+		UNION ALL
+		SELECT g.id, g.parent_id, g.name, t.path || g.name, t.ancestors || g.id, g.created_at, g.updated_at
+			FROM library.genres g
+			JOIN tree t ON g.parent_id = t.id
+	)
+	SELECT
+		t.id, t.parent_id, t.name, array_to_string(t.path, ' > ') AS path,
+		(
+			SELECT COUNT(DISTINCT bg.book_id)
+			FROM library.book_genres bg
+			JOIN library.books b ON b.id = bg.book_id AND b.deleted_at ISNULL
+			JOIN tree d ON d.id = bg.genre_id
+			WHERE t.id = ANY(d.ancestors)
+		) AS books,
+		t.created_at, t.updated_at
+	FROM tree t
+`
+
+type GenreRepo struct {
+	deps *core.Dependency
+}
+
+func NewSQLRepo(deps *core.Dependency) *GenreRepo {
+	return &GenreRepo{
+		deps: deps,
+	}
+}
+
+// GetGenres returns the whole genre tree, every genre right after its parent.
+func (repo *GenreRepo) GetGenres(ctx context.Context) ([]model.Genre, error) {
+	var temp []model.SQLGenre
+
+	err := repo.deps.DB.SelectContext(ctx, &temp, genreTreeQuery+`ORDER BY t.path;`)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]model.Genre, 0, len(temp))
+	for _, genre := range temp {
+		result = append(result, toGenre(genre))
+	}
+
+	return result, nil
+}
+
+func (repo *GenreRepo) GetGenreByID(ctx context.Context, id int64) (model.Genre, error) {
+	return getGenreByID(ctx, repo.deps.DB, id)
+}
+
+func getGenreByID(ctx context.Context, db sqlx.QueryerContext, id int64) (model.Genre, error) {
+	var result model.SQLGenre
+
+	err := db.QueryRowxContext(ctx, genreTreeQuery+`WHERE t.id = $1;`, id).StructScan(&result)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Genre{}, xerrors.NewClientError(xerrors.ErrDataNotFound)
+		}
+
+		return model.Genre{}, err
+	}
+
+	return toGenre(result), nil
+}
+
+func (repo *GenreRepo) StoreGenre(ctx context.Context, data model.Genre) (model.Genre, error) {
+	tx, err := repo.deps.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return model.Genre{}, err
+	}
+	defer tx.Rollback()
+
+	var id int64
+	q := `INSERT INTO library.genres (parent_id, name) VALUES ($1, $2) RETURNING id;`
+	err = tx.QueryRowxContext(ctx, q, nullID(data.ParentID), data.Name).Scan(&id)
+	if err != nil {
+		return model.Genre{}, genreWriteError(err, data)
+	}
+
+	result, err := getGenreByID(ctx, tx, id)
+	if err != nil {
+		return model.Genre{}, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		repo.deps.Logger.ErrorContext(ctx, "failed to commit sql transaction", slog.Any("error", err))
+		return model.Genre{}, err
+	}
+
+	return result, nil
+}
+
+// UpdateGenre renames the genre and moves it, with everything below it,
+// under data.ParentID. Moves are serialized so two of them can't build a
+// cycle between them.
+func (repo *GenreRepo) UpdateGenre(ctx context.Context, data model.Genre) (model.Genre, error) {
+	tx, err := repo.deps.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return model.Genre{}, err
+	}
+	defer tx.Rollback()
+
+	lockQ := `LOCK TABLE library.genres IN SHARE ROW EXCLUSIVE MODE;`
+	cycleQ := `SELECT $2 IN (SELECT library.genre_subtree($1));`
+	updateQ := `UPDATE library.genres SET parent_id = $2, name = $3, updated_at = now() WHERE id = $1;`
+
+	_, err = tx.ExecContext(ctx, lockQ)
+	if err != nil {
+		return model.Genre{}, err
+	}
+
+	if data.ParentID != 0 {
+		var cycle bool
+		err = tx.QueryRowxContext(ctx, cycleQ, data.ID, data.ParentID).Scan(&cycle)
+		if err != nil {
+			return model.Genre{}, err
+		}
+		if cycle {
+			return model.Genre{}, xerrors.NewClientError(errors.New("a genre can not be moved below itself"))
+		}
+	}
+
+	res, err := tx.ExecContext(ctx, updateQ, data.ID, nullID(data.ParentID), data.Name)
+	if err != nil {
+		return model.Genre{}, genreWriteError(err, data)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return model.Genre{}, err
+	}
+	if affected == 0 {
+		return model.Genre{}, xerrors.NewClientError(xerrors.ErrDataNotFound)
+	}
+
+	result, err := getGenreByID(ctx, tx, data.ID)
+	if err != nil {
+		return model.Genre{}, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		repo.deps.Logger.ErrorContext(ctx, "failed to commit sql transaction", slog.Any("error", err))
+		return model.Genre{}, err
+	}
+
+	return result, nil
+}
+
+// DeleteGenre deletes the genre and takes it off its books, refused while
+// other genres are below it.
+func (repo *GenreRepo) DeleteGenre(ctx context.Context, id int64) error {
+	q := `DELETE FROM library.genres WHERE id = $1;`
+	res, err := repo.deps.DB.ExecContext(ctx, q, id)
+	if err != nil {
+		if xerrors.IsForeignKeyViolation(err) {
+			return xerrors.NewClientError(errors.New("genre has genres below it, delete or move them first"))
+		}
+
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return xerrors.NewClientError(xerrors.ErrDataNotFound)
+	}
+
+	return nil
+}
+
+// SetBookGenres replaces the genres of a book that is not deleted and
+// returns them.
+func (repo *GenreRepo) SetBookGenres(ctx context.Context, bookID int64, genreIDs []int64) ([]model.BookGenre, error) {
+	tx, err := repo.deps.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	bookQ := `SELECT id FROM library.books WHERE id = $1 AND deleted_at ISNULL FOR SHARE;`
+	deleteQ := `DELETE FROM library.book_genres WHERE book_id = $1;`
+	insertQ := `INSERT INTO library.book_genres (book_id, genre_id) SELECT $1, unnest($2::BIGINT[]);`
+	listQ := `SELECT library.book_genre_list($1);`
+
+	err = tx.QueryRowxContext(ctx, bookQ, bookID).Scan(&bookID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, xerrors.NewClientError(xerrors.ErrDataNotFound)
+		}
+
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, deleteQ, bookID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(genreIDs) > 0 {
+		_, err = tx.ExecContext(ctx, insertQ, bookID, pq.Array(genreIDs))
+		if err != nil {
+			if xerrors.IsForeignKeyViolation(err) {
+				return nil, xerrors.NewClientError(errors.New("genre_ids has a genre that does not exist"))
+			}
+
+			return nil, err
+		}
+	}
+
+	var result model.SQLBookGenres
+	err = tx.QueryRowxContext(ctx, listQ, bookID).Scan(&result)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		repo.deps.Logger.ErrorContext(ctx, "failed to commit sql transaction", slog.Any("error", err))
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// genreWriteError turns the constraint violations of a genre write into
+// client errors.
+func genreWriteError(err error, data model.Genre) error {
+	switch {
+	case xerrors.IsUniqueViolation(err, ""):
+		return xerrors.NewClientError(fmt.Errorf("genre %q already exists there", data.Name))
+	case xerrors.IsForeignKeyViolation(err):
+		return xerrors.NewClientError(fmt.Errorf("parent genre %d does not exist", data.ParentID))
+	default:
+		return err
+	}
+}
+
+// nullID stores a top level genre, parent 0, with a NULL parent.
+func nullID(id int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: id, Valid: id != 0}
+}
+
+func toGenre(temp model.SQLGenre) model.Genre {
+	return model.Genre{
+		ID:       temp.ID.Int64,
+		ParentID: temp.ParentID.Int64,
+		Name:     temp.Name.String,
+		Path:     temp.Path.String,
+		Books:    temp.Books.Int64,
+		BaseAudit: model.BaseAudit{
+			CreatedAt: &temp.CreatedAt.Time,
+			UpdatedAt: &temp.UpdatedAt.Time,
+		},
+	}
+}
@@ -0,0 +1,291 @@
+package genre
+
+import (
+	"byfood-app/internal/core"
+	"byfood-app/internal/model"
+	"byfood-app/internal/pkg/xerrors"
+	"context"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+var genreColumns = []string{"id", "parent_id", "name", "path", "books", "created_at", "updated_at"}
+
+func newTestRepo(t *testing.T) (*GenreRepo, sqlmock.Sqlmock) {
+	db, mockDB, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return NewSQLRepo(&core.Dependency{
+		Logger: slog.Default(),
+		DB:     sqlx.NewDb(db, "sqlmock"),
+	}), mockDB
+}
+
+func TestGenreRepo_GetGenres(t *testing.T) {
+	repo, mockDB := newTestRepo(t)
+	now := time.Now()
+
+	mockDB.ExpectQuery(`(?s)^\s*WITH RECURSIVE tree AS .*WHERE parent_id ISNULL.*JOIN tree t ON g.parent_id = t.id.*WHERE t.id = ANY\(d.ancestors\).*ORDER BY t.path;$`).
+		WillReturnRows(sqlmock.NewRows(genreColumns).
+			AddRow(1, nil, "Fiction", "Fiction", 3, now, now).
+			AddRow(3, 1, "Fantasy", "Fiction > Fantasy", 2, now, now))
+
+	got, err := repo.GetGenres(context.Background())
+	if err != nil {
+		t.Fatalf("GenreRepo.GetGenres() error = %v", err)
+	}
+
+	want := []model.Genre{
+		{ID: 1, Name: "Fiction", Path: "Fiction", Books: 3, BaseAudit: model.BaseAudit{CreatedAt: &now, UpdatedAt: &now}},
+		{ID: 3, ParentID: 1, Name: "Fantasy", Path: "Fiction > Fantasy", Books: 2, BaseAudit: model.BaseAudit{CreatedAt: &now, UpdatedAt: &now}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GenreRepo.GetGenres() = %+v, want %+v", got, want)
+	}
+	if err := mockDB.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestGenreRepo_UpdateGenre(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		data     model.Genre
+		want     model.Genre
+		wantCode int
+		mockFunc func(mockDB sqlmock.Sqlmock)
+	}{
+		{
+			name:     "success move genre under another",
+			data:     model.Genre{ID: 3, ParentID: 2, Name: "Fantasy"},
+			want:     model.Genre{ID: 3, ParentID: 2, Name: "Fantasy", Path: "Nonfiction > Fantasy", BaseAudit: model.BaseAudit{CreatedAt: &now, UpdatedAt: &now}},
+			wantCode: http.StatusOK,
+			mockFunc: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectBegin()
+				mockDB.ExpectExec(`^LOCK TABLE library.genres IN SHARE ROW EXCLUSIVE MODE;$`).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mockDB.ExpectQuery(`^SELECT \$2 IN \(SELECT library.genre_subtree\(\$1\)\);$`).
+					WithArgs(3, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"?column?"}).AddRow(false))
+				mockDB.ExpectExec(`^UPDATE library.genres SET parent_id = \$2, name = \$3, updated_at = now\(\) WHERE id = \$1;$`).
+					WithArgs(3, 2, "Fantasy").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mockDB.ExpectQuery(`(?s)^\s*WITH RECURSIVE tree AS .*WHERE t.id = \$1;$`).
+					WithArgs(3).
+					WillReturnRows(sqlmock.NewRows(genreColumns).AddRow(3, 2, "Fantasy", "Nonfiction > Fantasy", 0, now, now))
+				mockDB.ExpectCommit()
+			},
+		},
+		{
+			name:     "failed move genre below itself",
+			data:     model.Genre{ID: 3, ParentID: 7, Name: "Fantasy"},
+			wantCode: http.StatusBadRequest,
+			mockFunc: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectBegin()
+				mockDB.ExpectExec(`^LOCK TABLE library.genres IN SHARE ROW EXCLUSIVE MODE;$`).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mockDB.ExpectQuery(`^SELECT \$2 IN \(SELECT library.genre_subtree\(\$1\)\);$`).
+					WithArgs(3, 7).
+					WillReturnRows(sqlmock.NewRows([]string{"?column?"}).AddRow(true))
+				mockDB.ExpectRollback()
+			},
+		},
+		{
+			name:     "failed rename to a name taken under the parent",
+			data:     model.Genre{ID: 3, Name: "Fiction"},
+			wantCode: http.StatusBadRequest,
+			mockFunc: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectBegin()
+				mockDB.ExpectExec(`^LOCK TABLE library.genres IN SHARE ROW EXCLUSIVE MODE;$`).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mockDB.ExpectExec(`^UPDATE library.genres SET parent_id = \$2, name = \$3, updated_at = now\(\) WHERE id = \$1;$`).
+					WithArgs(3, nil, "Fiction").
+					WillReturnError(&pq.Error{Code: xerrors.PQUniqueViolation})
+				mockDB.ExpectRollback()
+			},
+		},
+		{
+			name:     "failed genre not found",
+			data:     model.Genre{ID: 99, Name: "Fantasy"},
+			wantCode: http.StatusBadRequest,
+			mockFunc: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectBegin()
+				mockDB.ExpectExec(`^LOCK TABLE library.genres IN SHARE ROW EXCLUSIVE MODE;$`).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mockDB.ExpectExec(`^UPDATE library.genres SET .* WHERE id = \$1;$`).
+					WithArgs(99, nil, "Fantasy").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mockDB.ExpectRollback()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, mockDB := newTestRepo(t)
+			tt.mockFunc(mockDB)
+
+			got, err := repo.UpdateGenre(context.Background(), tt.data)
+			if (err != nil) != (tt.wantCode != http.StatusOK) || (err != nil && xerrors.ParseErrorTypeToCodeInt(err) != tt.wantCode) {
+				t.Errorf("GenreRepo.UpdateGenre() error = %v, wantCode %v", err, tt.wantCode)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GenreRepo.UpdateGenre() = %+v, want %+v", got, tt.want)
+			}
+			if err := mockDB.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestGenreRepo_DeleteGenre(t *testing.T) {
+	tests := []struct {
+		name     string
+		id       int64
+		wantCode int
+		mockFunc func(mockDB sqlmock.Sqlmock)
+	}{
+		{
+			name:     "success delete genre",
+			id:       7,
+			wantCode: http.StatusOK,
+			mockFunc: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectExec(`^DELETE FROM library.genres WHERE id = \$1;$`).
+					WithArgs(7).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:     "failed delete genre with genres below it",
+			id:       3,
+			wantCode: http.StatusBadRequest,
+			mockFunc: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectExec(`^DELETE FROM library.genres WHERE id = \$1;$`).
+					WithArgs(3).
+					WillReturnError(&pq.Error{Code: xerrors.PQForeignKeyViolation})
+			},
+		},
+		{
+			name:     "failed genre not found",
+			id:       99,
+			wantCode: http.StatusBadRequest,
+			mockFunc: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectExec(`^DELETE FROM library.genres WHERE id = \$1;$`).
+					WithArgs(99).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, mockDB := newTestRepo(t)
+			tt.mockFunc(mockDB)
+
+			err := repo.DeleteGenre(context.Background(), tt.id)
+			if (err != nil) != (tt.wantCode != http.StatusOK) || (err != nil && xerrors.ParseErrorTypeToCodeInt(err) != tt.wantCode) {
+				t.Errorf("GenreRepo.DeleteGenre() error = %v, wantCode %v", err, tt.wantCode)
+			}
+			if err := mockDB.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestGenreRepo_SetBookGenres(t *testing.T) {
+	tests := []struct {
+		name     string
+		bookID   int64
+		genreIDs []int64
+		want     []model.BookGenre
+		wantCode int
+		mockFunc func(mockDB sqlmock.Sqlmock)
+	}{
+		{
+			name:     "success replace book genres",
+			bookID:   1,
+			genreIDs: []int64{3, 7},
+			want:     []model.BookGenre{{ID: 3, Name: "Fantasy"}, {ID: 7, Name: "High Fantasy"}},
+			wantCode: http.StatusOK,
+			mockFunc: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectBegin()
+				mockDB.ExpectQuery(`^SELECT id FROM library.books WHERE id = \$1 AND deleted_at ISNULL FOR SHARE;$`).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mockDB.ExpectExec(`^DELETE FROM library.book_genres WHERE book_id = \$1;$`).
+					WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mockDB.ExpectExec(`^INSERT INTO library.book_genres \(book_id, genre_id\) SELECT \$1, unnest\(\$2::BIGINT\[\]\);$`).
+					WithArgs(1, pq.Array([]int64{3, 7})).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mockDB.ExpectQuery(`^SELECT library.book_genre_list\(\$1\);$`).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"book_genre_list"}).
+						AddRow(`[{"id": 3, "name": "Fantasy"}, {"id": 7, "name": "High Fantasy"}]`))
+				mockDB.ExpectCommit()
+			},
+		},
+		{
+			name:     "failed unknown genre",
+			bookID:   1,
+			genreIDs: []int64{99},
+			wantCode: http.StatusBadRequest,
+			mockFunc: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectBegin()
+				mockDB.ExpectQuery(`^SELECT id FROM library.books .* FOR SHARE;$`).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mockDB.ExpectExec(`^DELETE FROM library.book_genres WHERE book_id = \$1;$`).
+					WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mockDB.ExpectExec(`^INSERT INTO library.book_genres .*$`).
+					WithArgs(1, pq.Array([]int64{99})).
+					WillReturnError(&pq.Error{Code: xerrors.PQForeignKeyViolation})
+				mockDB.ExpectRollback()
+			},
+		},
+		{
+			name:     "failed book deleted or not found",
+			bookID:   99,
+			genreIDs: []int64{3},
+			wantCode: http.StatusBadRequest,
+			mockFunc: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectBegin()
+				mockDB.ExpectQuery(`^SELECT id FROM library.books .* FOR SHARE;$`).
+					WithArgs(99).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mockDB.ExpectRollback()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, mockDB := newTestRepo(t)
+			tt.mockFunc(mockDB)
+
+			got, err := repo.SetBookGenres(context.Background(), tt.bookID, tt.genreIDs)
+			if (err != nil) != (tt.wantCode != http.StatusOK) || (err != nil && xerrors.ParseErrorTypeToCodeInt(err) != tt.wantCode) {
+				t.Errorf("GenreRepo.SetBookGenres() error = %v, wantCode %v", err, tt.wantCode)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GenreRepo.SetBookGenres() = %+v, want %+v", got, tt.want)
+			}
+			if err := mockDB.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
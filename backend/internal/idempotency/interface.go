@@ -0,0 +1,18 @@
+package idempotency
+
+import (
+	"byfood-app/internal/model"
+	"context"
+	"net/http"
+	"time"
+)
+
+type RepositoryInterface interface {
+	// Reserve claims the key of the actor for a new request. When the key is
+	// already held and not expired, the existing record is returned with
+	// claimed false.
+	Reserve(ctx context.Context, actor string, key string, fingerprint string, ttl time.Duration) (record model.IdempotencyRecord, claimed bool, err error)
+	Complete(ctx context.Context, actor string, key string, statusCode int, header http.Header, body []byte) error
+	Release(ctx context.Context, actor string, key string) error
+	DeleteExpired(ctx context.Context) (int64, error)
+}
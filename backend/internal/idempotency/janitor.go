@@ -0,0 +1,45 @@
+package idempotency
+
+import (
+	"byfood-app/internal/core"
+	"context"
+	"log/slog"
+	"time"
+)
+
+const cleanupInterval = time.Hour
+
+// Janitor deletes idempotency keys once they expire. Expired keys are
+// already ignored by Reserve, this only keeps the table from growing.
+type Janitor struct {
+	deps *core.Dependency
+	repo RepositoryInterface
+}
+
+func NewJanitor(deps *core.Dependency, repo RepositoryInterface) *Janitor {
+	return &Janitor{
+		deps: deps,
+		repo: repo,
+	}
+}
+
+// Run cleans up once per interval until ctx is cancelled.
+func (j *Janitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(cleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		deleted, err := j.repo.DeleteExpired(ctx)
+		if err != nil {
+			j.deps.Logger.ErrorContext(ctx, "failed to delete expired idempotency keys", slog.Any("error", err))
+		} else if deleted > 0 {
+			j.deps.Logger.InfoContext(ctx, "expired idempotency keys deleted", slog.Int64("deleted", deleted))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package idempotency
+
+import (
+	"byfood-app/internal/core"
+	"byfood-app/internal/pkg/xauth"
+	"byfood-app/internal/pkg/xhttp"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+)
+
+const (
+	HeaderKey      = "Idempotency-Key"
+	HeaderReplayed = "Idempotent-Replayed"
+
+	maxKeyLength = 255
+	// maxBodySize matches the largest upload accepted by the book import
+	maxBodySize = 32 << 20
+)
+
+var (
+	ErrKeyTooLong   = fmt.Errorf("Idempotency-Key must be at most %d characters", maxKeyLength)
+	ErrKeyReused    = errors.New("Idempotency-Key was already used with a different request")
+	ErrKeyInProcess = errors.New("a request with this Idempotency-Key is still in progress")
+)
+
+// replayedHeaders are the response headers stored alongside the body and
+// sent back again when a request is replayed.
+var replayedHeaders = []string{"Content-Type", "Content-Disposition", "ETag", "Link", "Location"}
+
+// Middleware makes POST, PUT, PATCH and DELETE requests carrying an
+// Idempotency-Key header safe to retry. The first request runs and its
+// response is stored, repeats within the TTL get that response replayed
+// without running the handler again. Keys are scoped by the actor set by
+// xauth.Actor, and reusing a key for a different request is rejected with
+// 422. Server errors are not stored so they can be retried.
+func Middleware(deps *core.Dependency, repo RepositoryInterface) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(HeaderKey)
+			if key == "" || !isMutating(r.Method) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx := r.Context()
+
+			if len(key) > maxKeyLength {
+				xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+					Error:   ErrKeyTooLong.Error(),
+					Message: "invalid idempotency key",
+				}, http.StatusBadRequest)
+				return
+			}
+
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+			r.Body.Close()
+			if err != nil {
+				code := http.StatusBadRequest
+				var maxBytesErr *http.MaxBytesError
+				if errors.As(err, &maxBytesErr) {
+					code = http.StatusRequestEntityTooLarge
+				}
+				xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+					Error:   err.Error(),
+					Message: "failed to read request body",
+				}, code)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			actor := xauth.ActorFromContext(ctx)
+			fingerprint := requestFingerprint(r, actor, body)
+
+			record, claimed, err := repo.Reserve(ctx, actor, key, fingerprint, deps.IdempotencyKeyTTL)
+			if err != nil {
+				deps.Logger.ErrorContext(ctx, "failed to reserve idempotency key", slog.Any("error", err))
+				xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+					Error:   err.Error(),
+					Message: "failed to check idempotency key",
+				}, http.StatusInternalServerError)
+				return
+			}
+
+			if !claimed {
+				switch {
+				case record.Fingerprint != fingerprint:
+					xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+						Error:   ErrKeyReused.Error(),
+						Message: "idempotency key reused",
+					}, http.StatusUnprocessableEntity)
+				case record.StatusCode == 0:
+					xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+						Error:   ErrKeyInProcess.Error(),
+						Message: "request in progress",
+					}, http.StatusConflict)
+				default:
+					for _, name := range replayedHeaders {
+						for _, value := range record.Header.Values(name) {
+							w.Header().Add(name, value)
+						}
+					}
+					w.Header().Set(HeaderReplayed, "true")
+					w.WriteHeader(record.StatusCode)
+					w.Write(record.Body)
+				}
+				return
+			}
+
+			// the outcome is saved even when the client already went away,
+			// a retry after a dropped connection is exactly what keys are for
+			saveCtx := context.WithoutCancel(ctx)
+
+			recorder := &responseRecorder{ResponseWriter: w}
+			defer func() {
+				// a panicking handler never finished, let the key be retried
+				if p := recover(); p != nil {
+					release(saveCtx, deps, repo, actor, key)
+					panic(p)
+				}
+			}()
+
+			next.ServeHTTP(recorder, r)
+
+			if recorder.statusCode() >= http.StatusInternalServerError {
+				release(saveCtx, deps, repo, actor, key)
+				return
+			}
+
+			header := http.Header{}
+			for _, name := range replayedHeaders {
+				for _, value := range recorder.Header().Values(name) {
+					header.Add(name, value)
+				}
+			}
+
+			err = repo.Complete(saveCtx, actor, key, recorder.statusCode(), header, recorder.body.Bytes())
+			if err != nil {
+				deps.Logger.ErrorContext(ctx, "failed to store idempotent response", slog.Any("error", err))
+				// without a stored response the key would report in progress until it expires
+				release(saveCtx, deps, repo, actor, key)
+			}
+		})
+	}
+}
+
+func isMutating(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// requestFingerprint identifies a request by actor, method, target, the
+// headers changing what the body means or whether it applies, and the body.
+// A retry fixing a stale If-Match is a different request, not a replay.
+func requestFingerprint(r *http.Request, actor string, body []byte) string {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s\n%s %s\n", actor, r.Method, r.URL.RequestURI())
+	fmt.Fprintf(hash, "Content-Type: %s\nIf-Match: %s\n\n", r.Header.Get("Content-Type"), r.Header.Get("If-Match"))
+	hash.Write(body)
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func release(ctx context.Context, deps *core.Dependency, repo RepositoryInterface, actor string, key string) {
+	err := repo.Release(ctx, actor, key)
+	if err != nil {
+		deps.Logger.ErrorContext(ctx, "failed to release idempotency key", slog.Any("error", err))
+	}
+}
+
+// responseRecorder passes the response through while keeping a copy of it.
+type responseRecorder struct {
+	http.ResponseWriter
+	code int
+	body bytes.Buffer
+}
+
+func (rec *responseRecorder) WriteHeader(code int) {
+	if rec.code == 0 {
+		rec.code = code
+	}
+	rec.ResponseWriter.WriteHeader(code)
+}
+
+func (rec *responseRecorder) Write(data []byte) (int, error) {
+	if rec.code == 0 {
+		rec.code = http.StatusOK
+	}
+	rec.body.Write(data)
+	return rec.ResponseWriter.Write(data)
+}
+
+func (rec *responseRecorder) statusCode() int {
+	if rec.code == 0 {
+		return http.StatusOK
+	}
+	return rec.code
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (rec *responseRecorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
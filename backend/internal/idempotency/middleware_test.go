@@ -0,0 +1,272 @@
+package idempotency
+
+import (
+	"byfood-app/internal/config"
+	"byfood-app/internal/core"
+	"byfood-app/internal/model"
+	"byfood-app/internal/pkg/xauth"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestMiddleware(t *testing.T) {
+	type args struct {
+		method  string
+		target  string
+		key     string
+		actor   string
+		ifMatch string
+		body    string
+	}
+
+	ctrl := gomock.NewController(t)
+	repo := NewMockRepositoryInterface(ctrl)
+	deps := &core.Dependency{
+		Logger: slog.Default(),
+		Config: &config.Config{
+			IdempotencyKeyTTL: 24 * time.Hour,
+		},
+	}
+
+	body := `{"title":"1984","author":"George Orwell","publish_year":1949}`
+	fingerprint := requestFingerprint(httptest.NewRequest(http.MethodPost, "/books", nil), xauth.ActorAdmin, []byte(body))
+
+	tests := []struct {
+		name           string
+		args           args
+		handlerCode    int
+		wantCode       int
+		wantBody       string
+		wantReplayed   bool
+		wantHandlerHit bool
+		mockFunc       func()
+	}{
+		{
+			name:           "success request without key passes through",
+			args:           args{method: http.MethodPost, target: "/books", body: body},
+			handlerCode:    http.StatusCreated,
+			wantCode:       http.StatusCreated,
+			wantBody:       `{"data":"stored"}`,
+			wantHandlerHit: true,
+			mockFunc:       func() {},
+		},
+		{
+			name:           "success read request ignores key",
+			args:           args{method: http.MethodGet, target: "/books", key: "abc"},
+			handlerCode:    http.StatusOK,
+			wantCode:       http.StatusOK,
+			wantBody:       `{"data":"stored"}`,
+			wantHandlerHit: true,
+			mockFunc:       func() {},
+		},
+		{
+			name:           "success first request stores response",
+			args:           args{method: http.MethodPost, target: "/books", key: "abc", actor: xauth.ActorAdmin, body: body},
+			handlerCode:    http.StatusCreated,
+			wantCode:       http.StatusCreated,
+			wantBody:       `{"data":"stored"}`,
+			wantHandlerHit: true,
+			mockFunc: func() {
+				repo.EXPECT().Reserve(gomock.Any(), xauth.ActorAdmin, "abc", fingerprint, 24*time.Hour).Return(model.IdempotencyRecord{}, true, nil)
+				repo.EXPECT().Complete(gomock.Any(), xauth.ActorAdmin, "abc", http.StatusCreated, http.Header{
+					"Content-Type": {"application/json"},
+					"Etag":         {`"1"`},
+				}, []byte(`{"data":"stored"}`)).Return(nil)
+			},
+		},
+		{
+			name:         "success repeated request replays stored response",
+			args:         args{method: http.MethodPost, target: "/books", key: "abc", actor: xauth.ActorAdmin, body: body},
+			wantCode:     http.StatusCreated,
+			wantBody:     `{"data":"replayed"}`,
+			wantReplayed: true,
+			mockFunc: func() {
+				repo.EXPECT().Reserve(gomock.Any(), xauth.ActorAdmin, "abc", fingerprint, 24*time.Hour).Return(model.IdempotencyRecord{
+					Key:         "abc",
+					Fingerprint: fingerprint,
+					StatusCode:  http.StatusCreated,
+					Header:      http.Header{"Content-Type": {"application/json"}},
+					Body:        []byte(`{"data":"replayed"}`),
+				}, false, nil)
+			},
+		},
+		{
+			name:     "failed key reused with different payload",
+			args:     args{method: http.MethodPost, target: "/books", key: "abc", actor: xauth.ActorAdmin, body: `{"title":"Animal Farm"}`},
+			wantCode: http.StatusUnprocessableEntity,
+			mockFunc: func() {
+				repo.EXPECT().Reserve(gomock.Any(), xauth.ActorAdmin, "abc", gomock.Not(fingerprint), 24*time.Hour).Return(model.IdempotencyRecord{
+					Key:         "abc",
+					Fingerprint: fingerprint,
+					StatusCode:  http.StatusCreated,
+				}, false, nil)
+			},
+		},
+		{
+			name:     "failed key reused by another actor",
+			args:     args{method: http.MethodPost, target: "/books", key: "abc", actor: "anonymous@10.0.0.7", body: body},
+			wantCode: http.StatusUnprocessableEntity,
+			mockFunc: func() {
+				// the key is scoped by actor, so this only finds a record of the same caller
+				repo.EXPECT().Reserve(gomock.Any(), "anonymous@10.0.0.7", "abc", gomock.Not(fingerprint), 24*time.Hour).Return(model.IdempotencyRecord{
+					Key:         "abc",
+					Fingerprint: fingerprint,
+					StatusCode:  http.StatusCreated,
+				}, false, nil)
+			},
+		},
+		{
+			name:     "failed key reused with a fixed If-Match",
+			args:     args{method: http.MethodPost, target: "/books", key: "abc", actor: xauth.ActorAdmin, ifMatch: `"2"`, body: body},
+			wantCode: http.StatusUnprocessableEntity,
+			mockFunc: func() {
+				repo.EXPECT().Reserve(gomock.Any(), xauth.ActorAdmin, "abc", gomock.Not(fingerprint), 24*time.Hour).Return(model.IdempotencyRecord{
+					Key:         "abc",
+					Fingerprint: fingerprint,
+					StatusCode:  http.StatusPreconditionFailed,
+				}, false, nil)
+			},
+		},
+		{
+			name:     "failed first request still in progress",
+			args:     args{method: http.MethodPost, target: "/books", key: "abc", actor: xauth.ActorAdmin, body: body},
+			wantCode: http.StatusConflict,
+			mockFunc: func() {
+				repo.EXPECT().Reserve(gomock.Any(), xauth.ActorAdmin, "abc", fingerprint, 24*time.Hour).Return(model.IdempotencyRecord{
+					Key:         "abc",
+					Fingerprint: fingerprint,
+				}, false, nil)
+			},
+		},
+		{
+			name:           "failed server error releases key",
+			args:           args{method: http.MethodPost, target: "/books", key: "abc", actor: xauth.ActorAdmin, body: body},
+			handlerCode:    http.StatusInternalServerError,
+			wantCode:       http.StatusInternalServerError,
+			wantBody:       `{"data":"stored"}`,
+			wantHandlerHit: true,
+			mockFunc: func() {
+				repo.EXPECT().Reserve(gomock.Any(), xauth.ActorAdmin, "abc", fingerprint, 24*time.Hour).Return(model.IdempotencyRecord{}, true, nil)
+				repo.EXPECT().Release(gomock.Any(), xauth.ActorAdmin, "abc").Return(nil)
+			},
+		},
+		{
+			name:     "failed key too long",
+			args:     args{method: http.MethodPost, target: "/books", key: strings.Repeat("k", maxKeyLength+1), body: body},
+			wantCode: http.StatusBadRequest,
+			mockFunc: func() {},
+		},
+		{
+			name:     "failed reserving key",
+			args:     args{method: http.MethodPost, target: "/books", key: "abc", actor: xauth.ActorAdmin, body: body},
+			wantCode: http.StatusInternalServerError,
+			mockFunc: func() {
+				repo.EXPECT().Reserve(gomock.Any(), xauth.ActorAdmin, "abc", fingerprint, 24*time.Hour).Return(model.IdempotencyRecord{}, false, errors.New("connection reset"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc()
+
+			handlerHit := false
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				handlerHit = true
+
+				// the handler still sees the full body after fingerprinting
+				got, _ := io.ReadAll(r.Body)
+				if string(got) != tt.args.body {
+					t.Errorf("handler body = %s, want %s", got, tt.args.body)
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set("ETag", `"1"`)
+				w.Header().Set("X-Request-Only", "true")
+				w.WriteHeader(tt.handlerCode)
+				io.WriteString(w, `{"data":"stored"}`)
+			})
+
+			r := httptest.NewRequest(tt.args.method, tt.args.target, strings.NewReader(tt.args.body))
+			if tt.args.key != "" {
+				r.Header.Set(HeaderKey, tt.args.key)
+			}
+			if tt.args.ifMatch != "" {
+				r.Header.Set("If-Match", tt.args.ifMatch)
+			}
+			if tt.args.actor != "" {
+				r = r.WithContext(xauth.WithActor(r.Context(), tt.args.actor))
+			}
+			w := httptest.NewRecorder()
+
+			Middleware(deps, repo)(next).ServeHTTP(w, r)
+
+			if w.Code != tt.wantCode {
+				t.Errorf("Middleware() code = %d, want %d", w.Code, tt.wantCode)
+			}
+			if handlerHit != tt.wantHandlerHit {
+				t.Errorf("Middleware() handler hit = %v, want %v", handlerHit, tt.wantHandlerHit)
+			}
+			if tt.wantBody != "" && w.Body.String() != tt.wantBody {
+				t.Errorf("Middleware() body = %s, want %s", w.Body.String(), tt.wantBody)
+			}
+			if replayed := w.Header().Get(HeaderReplayed) == "true"; replayed != tt.wantReplayed {
+				t.Errorf("Middleware() replayed = %v, want %v", replayed, tt.wantReplayed)
+			}
+		})
+	}
+}
+
+func TestRequestFingerprint(t *testing.T) {
+	newRequest := func(contentType string, ifMatch string) *http.Request {
+		r := httptest.NewRequest(http.MethodPatch, "/books/3", nil)
+		r.Header.Set("Content-Type", contentType)
+		r.Header.Set("If-Match", ifMatch)
+		return r
+	}
+	body := []byte(`[{"op":"replace","path":"/title","value":"Emma"}]`)
+	base := requestFingerprint(newRequest("application/json-patch+json", `"1"`), xauth.ActorAdmin, body)
+
+	tests := []struct {
+		name  string
+		r     *http.Request
+		actor string
+		same  bool
+	}{
+		{
+			name:  "same request",
+			r:     newRequest("application/json-patch+json", `"1"`),
+			actor: xauth.ActorAdmin,
+			same:  true,
+		},
+		{
+			name:  "other patch content type",
+			r:     newRequest("application/merge-patch+json", `"1"`),
+			actor: xauth.ActorAdmin,
+		},
+		{
+			name:  "other If-Match",
+			r:     newRequest("application/json-patch+json", `"2"`),
+			actor: xauth.ActorAdmin,
+		},
+		{
+			name:  "other actor",
+			r:     newRequest("application/json-patch+json", `"1"`),
+			actor: xauth.ActorAnonymous,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := requestFingerprint(tt.r, tt.actor, body) == base; got != tt.same {
+				t.Errorf("requestFingerprint() same = %v, want %v", got, tt.same)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package idempotency
+
+import (
+	"byfood-app/internal/core"
+	"byfood-app/internal/model"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// reserveAttempts bounds how often Reserve retries when the key it lost the
+// race for is released before it could be read back.
+const reserveAttempts = 2
+
+type IdempotencyRepo struct {
+	deps *core.Dependency
+}
+
+func NewSQLRepo(deps *core.Dependency) *IdempotencyRepo {
+	return &IdempotencyRepo{
+		deps: deps,
+	}
+}
+
+// Reserve inserts the key, taking over an expired one in place.
+func (repo *IdempotencyRepo) Reserve(ctx context.Context, actor string, key string, fingerprint string, ttl time.Duration) (model.IdempotencyRecord, bool, error) {
+	insertQuery := `
+		INSERT INTO library.idempotency_keys (actor, key, fingerprint, expires_at)
+			VALUES ($1, $2, $3, now() + make_interval(secs => $4))
+		ON CONFLICT (actor, key) DO UPDATE
+			SET
+				fingerprint = EXCLUDED.fingerprint,
+				status_code = NULL,
+				response_headers = NULL,
+				response_body = NULL,
+				created_at = now(),
+				expires_at = EXCLUDED.expires_at
+			WHERE
+				library.idempotency_keys.expires_at <= now()
+		RETURNING key;
+	`
+	selectQuery := `
+		SELECT key, fingerprint, status_code, response_headers, response_body, expires_at
+			FROM library.idempotency_keys
+			WHERE actor = $1 AND key = $2;
+	`
+
+	for range reserveAttempts {
+		var reserved string
+		err := repo.deps.DB.QueryRowxContext(ctx, insertQuery, actor, key, fingerprint, ttl.Seconds()).Scan(&reserved)
+		if err == nil {
+			return model.IdempotencyRecord{}, true, nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return model.IdempotencyRecord{}, false, err
+		}
+
+		// the key is held by another request, hand back what it stored
+		var returned model.SQLIdempotencyRecord
+		err = repo.deps.DB.QueryRowxContext(ctx, selectQuery, actor, key).StructScan(&returned)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return model.IdempotencyRecord{}, false, err
+		}
+
+		record, err := parseSQLIdempotencyRecord(returned)
+		return record, false, err
+	}
+
+	return model.IdempotencyRecord{}, false, fmt.Errorf("failed to reserve idempotency key after %d attempts", reserveAttempts)
+}
+
+// Complete stores the response of the request holding the key.
+func (repo *IdempotencyRepo) Complete(ctx context.Context, actor string, key string, statusCode int, header http.Header, body []byte) error {
+	headers, err := json.Marshal(header)
+	if err != nil {
+		return err
+	}
+
+	q := `
+		UPDATE library.idempotency_keys
+			SET
+				status_code = $1,
+				response_headers = $2,
+				response_body = $3
+			WHERE
+				actor = $4 AND key = $5;
+	`
+	_, err = repo.deps.DB.ExecContext(ctx, q, statusCode, headers, body, actor, key)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Release drops an unfinished key so the request can be retried.
+func (repo *IdempotencyRepo) Release(ctx context.Context, actor string, key string) error {
+	q := `DELETE FROM library.idempotency_keys WHERE actor = $1 AND key = $2 AND status_code ISNULL;`
+	_, err := repo.deps.DB.ExecContext(ctx, q, actor, key)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteExpired deletes every key past its expiry.
+func (repo *IdempotencyRepo) DeleteExpired(ctx context.Context) (int64, error) {
+	q := `DELETE FROM library.idempotency_keys WHERE expires_at <= now();`
+	res, err := repo.deps.DB.ExecContext(ctx, q)
+	if err != nil {
+		return 0, err
+	}
+
+	rowsCount, err := res.RowsAffected()
+	if err != nil {
+		repo.deps.Logger.WarnContext(ctx, "failed to check affected row", slog.Any("error", err))
+		return 0, err
+	}
+
+	return rowsCount, nil
+}
+
+func parseSQLIdempotencyRecord(data model.SQLIdempotencyRecord) (model.IdempotencyRecord, error) {
+	record := model.IdempotencyRecord{
+		Key:         data.Key.String,
+		Fingerprint: data.Fingerprint.String,
+		StatusCode:  int(data.StatusCode.Int64),
+		Body:        data.ResponseBody,
+		ExpiresAt:   data.ExpiresAt.Time,
+	}
+
+	if len(data.ResponseHeaders) > 0 {
+		err := json.Unmarshal(data.ResponseHeaders, &record.Header)
+		if err != nil {
+			return model.IdempotencyRecord{}, fmt.Errorf("invalid stored response headers: %v", err)
+		}
+	}
+
+	return record, nil
+}
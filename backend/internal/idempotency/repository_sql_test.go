@@ -0,0 +1,137 @@
+package idempotency
+
+import (
+	"byfood-app/internal/core"
+	"byfood-app/internal/model"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+)
+
+func TestIdempotencyRepo_Reserve(t *testing.T) {
+	type args struct {
+		ctx         context.Context
+		actor       string
+		key         string
+		fingerprint string
+		ttl         time.Duration
+	}
+
+	db, mockDB, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dbx := sqlx.NewDb(db, "sqlmock")
+
+	deps := &core.Dependency{
+		Logger: slog.Default(),
+		DB:     dbx,
+	}
+
+	expiresAt := time.Now().Add(time.Hour)
+	defaultArgs := args{
+		ctx:         context.Background(),
+		actor:       "admin",
+		key:         "abc",
+		fingerprint: "f1",
+		ttl:         time.Hour,
+	}
+
+	tests := []struct {
+		name        string
+		args        args
+		want        model.IdempotencyRecord
+		wantClaimed bool
+		wantErr     bool
+		mockFunc    func()
+	}{
+		{
+			name:        "success claim new key",
+			args:        defaultArgs,
+			wantClaimed: true,
+			mockFunc: func() {
+				mockDB.ExpectQuery(`(?s)^.*INSERT INTO library.idempotency_keys.*ON CONFLICT \(actor, key\) DO UPDATE.*expires_at <= now\(\).*$`).
+					WithArgs("admin", "abc", "f1", float64(3600)).
+					WillReturnRows(sqlmock.NewRows([]string{"key"}).AddRow("abc"))
+			},
+		},
+		{
+			name: "success return stored response of held key",
+			args: defaultArgs,
+			want: model.IdempotencyRecord{
+				Key:         "abc",
+				Fingerprint: "f1",
+				StatusCode:  http.StatusCreated,
+				Header:      http.Header{"Content-Type": {"application/json"}},
+				Body:        []byte(`{"data":{}}`),
+				ExpiresAt:   expiresAt,
+			},
+			mockFunc: func() {
+				mockDB.ExpectQuery(`(?s)^.*INSERT INTO library.idempotency_keys.*$`).
+					WithArgs("admin", "abc", "f1", float64(3600)).
+					WillReturnRows(sqlmock.NewRows([]string{"key"}))
+				mockDB.ExpectQuery(`(?s)^.*SELECT key, fingerprint, status_code, response_headers, response_body, expires_at.*FROM library.idempotency_keys.*$`).
+					WithArgs("admin", "abc").
+					WillReturnRows(sqlmock.NewRows([]string{"key", "fingerprint", "status_code", "response_headers", "response_body", "expires_at"}).
+						AddRow("abc", "f1", http.StatusCreated, []byte(`{"Content-Type":["application/json"]}`), []byte(`{"data":{}}`), expiresAt))
+			},
+		},
+		{
+			name:        "success claim key released while reading it back",
+			args:        defaultArgs,
+			wantClaimed: true,
+			mockFunc: func() {
+				mockDB.ExpectQuery(`(?s)^.*INSERT INTO library.idempotency_keys.*$`).
+					WithArgs("admin", "abc", "f1", float64(3600)).
+					WillReturnRows(sqlmock.NewRows([]string{"key"}))
+				mockDB.ExpectQuery(`(?s)^.*SELECT key, fingerprint.*$`).
+					WithArgs("admin", "abc").
+					WillReturnRows(sqlmock.NewRows([]string{"key", "fingerprint", "status_code", "response_headers", "response_body", "expires_at"}))
+				mockDB.ExpectQuery(`(?s)^.*INSERT INTO library.idempotency_keys.*$`).
+					WithArgs("admin", "abc", "f1", float64(3600)).
+					WillReturnRows(sqlmock.NewRows([]string{"key"}).AddRow("abc"))
+			},
+		},
+		{
+			name:    "failed insert",
+			args:    defaultArgs,
+			wantErr: true,
+			mockFunc: func() {
+				mockDB.ExpectQuery(`(?s)^.*INSERT INTO library.idempotency_keys.*$`).
+					WithArgs("admin", "abc", "f1", float64(3600)).
+					WillReturnError(errors.New("connection reset"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &IdempotencyRepo{
+				deps: deps,
+			}
+
+			tt.mockFunc()
+
+			got, claimed, err := repo.Reserve(tt.args.ctx, tt.args.actor, tt.args.key, tt.args.fingerprint, tt.args.ttl)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("IdempotencyRepo.Reserve() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if claimed != tt.wantClaimed {
+				t.Errorf("IdempotencyRepo.Reserve() claimed = %v, want %v", claimed, tt.wantClaimed)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("IdempotencyRepo.Reserve() = %+v, want %+v", got, tt.want)
+			}
+			if err := mockDB.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet sql expectations: %v", err)
+			}
+		})
+	}
+}
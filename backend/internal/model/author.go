@@ -0,0 +1,118 @@
+package model
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+const (
+	AuthorRoleAuthor      = "author"
+	AuthorRoleEditor      = "editor"
+	AuthorRoleTranslator  = "translator"
+	AuthorRoleIllustrator = "illustrator"
+)
+
+// BookAuthor is a single credit of a book, listed in credit order. Authors
+// are matched by name when a book is written, ID is only filled on reads.
+type BookAuthor struct {
+	ID   int64  `json:"id,omitempty" example:"4"`
+	Name string `json:"name" example:"Neil Gaiman"`
+	Role string `json:"role" example:"author"`
+}
+
+// SQLBookAuthors scans the json author list built by library.book_author_list.
+type SQLBookAuthors []BookAuthor
+
+func (a *SQLBookAuthors) Scan(src any) error {
+	switch src := src.(type) {
+	case nil:
+		*a = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(src, (*[]BookAuthor)(a))
+	case string:
+		return json.Unmarshal([]byte(src), (*[]BookAuthor)(a))
+	default:
+		return fmt.Errorf("unsupported book authors type: %T", src)
+	}
+}
+
+// Author is a person credited on books. Years are nil when unknown, 0 for
+// 1 BCE and negative before that.
+type Author struct {
+	ID          int64    `json:"id" example:"4"`
+	Name        string   `json:"name" example:"George Orwell"`
+	Biography   string   `json:"biography" example:"English novelist, essayist and critic."`
+	BirthYear   *int64   `json:"birth_year,omitempty" example:"1903"`
+	DeathYear   *int64   `json:"death_year,omitempty" example:"1950"`
+	Nationality string   `json:"nationality" example:"British"`
+	Aliases     []string `json:"aliases" example:"Eric Arthur Blair"`
+
+	BaseAudit
+}
+
+type SQLAuthor struct {
+	ID          sql.NullInt64  `db:"id"`
+	Name        sql.NullString `db:"name"`
+	Biography   sql.NullString `db:"biography"`
+	BirthYear   sql.NullInt64  `db:"birth_year"`
+	DeathYear   sql.NullInt64  `db:"death_year"`
+	Nationality sql.NullString `db:"nationality"`
+	Aliases     pq.StringArray `db:"aliases"`
+
+	SQLBaseAudit
+}
+
+type AuthorSearchParams struct {
+	// Search matches names and aliases
+	Search string
+}
+
+type StoreAuthorRequest struct {
+	Name        string   `json:"name"`
+	Biography   string   `json:"biography"`
+	BirthYear   *int64   `json:"birth_year"`
+	DeathYear   *int64   `json:"death_year"`
+	Nationality string   `json:"nationality"`
+	Aliases     []string `json:"aliases"`
+}
+
+// UpdateAuthorRequest replaces every field, aliases included.
+type UpdateAuthorRequest struct {
+	Name        string   `json:"name"`
+	Biography   string   `json:"biography"`
+	BirthYear   *int64   `json:"birth_year"`
+	DeathYear   *int64   `json:"death_year"`
+	Nationality string   `json:"nationality"`
+	Aliases     []string `json:"aliases"`
+}
+
+// AuthorRef is an author as listed among probable duplicates, with the
+// number of books crediting them.
+type AuthorRef struct {
+	ID    int64  `json:"id" example:"4"`
+	Name  string `json:"name" example:"J. Tolkien"`
+	Books int64  `json:"books" example:"1"`
+}
+
+// AuthorDuplicate is a pair of authors whose names probably name the same
+// person, the same surname with given names that match or are initials.
+type AuthorDuplicate struct {
+	Authors []AuthorRef `json:"authors"`
+}
+
+// MergeAuthorsRequest lists the authors merged into the author of the path.
+type MergeAuthorsRequest struct {
+	AuthorIDs []int64 `json:"author_ids" example:"7,9"`
+}
+
+// AuthorMerge is the outcome of a merge, the merged authors are gone and
+// their names are aliases of Author.
+type AuthorMerge struct {
+	Author          Author  `json:"author"`
+	MergedAuthorIDs []int64 `json:"merged_author_ids" example:"7,9"`
+	BookIDs         []int64 `json:"book_ids" example:"8,10"`
+}
@@ -1,36 +1,255 @@
 package model
 
-import "database/sql"
+import (
+	"byfood-app/internal/pkg/edtf"
+	"byfood-app/internal/pkg/pagination"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const (
+	SearchModeSimple   = "simple"
+	SearchModeFullText = "fulltext"
+)
+
+const (
+	ImportFormatCSV    = "csv"
+	ImportFormatNDJSON = "ndjson"
+
+	ImportModeAllOrNothing = "all_or_nothing"
+	ImportModeBestEffort   = "best_effort"
+
+	ImportRowAccepted = "accepted"
+	ImportRowRejected = "rejected"
+)
+
+const (
+	BatchOpCreate  = "create"
+	BatchOpUpdate  = "update"
+	BatchOpDelete  = "delete"
+	BatchOpRestore = "restore"
+
+	BatchStatusApplied    = "applied"
+	BatchStatusFailed     = "failed"
+	BatchStatusRolledBack = "rolled_back"
+	BatchStatusSkipped    = "skipped"
+)
 
 type Book struct {
-	ID          int64  `json:"id"`
-	Title       string `json:"title"`
-	Author      string `json:"author"`
-	PublishYear int64  `json:"publish_year"`
+	ID    int64  `json:"id"`
+	Title string `json:"title"`
+	// Author is the display string of Authors, the names credited as author
+	// joined by " & ", kept for clients of the single author field
+	Author      string       `json:"author" example:"Terry Pratchett & Neil Gaiman"`
+	Authors     []BookAuthor `json:"authors"`
+	PublishYear int64        `json:"publish_year"`
+	// Published is the EDTF publication date, PublishYear is its year, 0
+	// being 1 BCE
+	Published *edtf.Date `json:"published,omitempty" swaggertype:"string" example:"1949-06-08"`
+	// ISBN is stored as ISBN-13, ISBN10 is derived from it when there is one
+	ISBN   string `json:"isbn,omitempty" example:"9780261103573"`
+	ISBN10 string `json:"isbn_10,omitempty" example:"0261103571"`
+
+	// only filled on reads, identifiers, genres and tags are set through
+	// their own endpoints and are not part of the book version
+	Identifiers []BookIdentifier `json:"identifiers,omitempty"`
+	Genres      []BookGenre      `json:"genres,omitempty"`
+	Tags        []string         `json:"tags,omitempty"`
+
+	// only filled on single book reads and on listings asking for them
+	Editions []Edition `json:"editions,omitempty"`
+
+	// bumped on every write, the version part of the book ETag
+	Version int64 `json:"version"`
+
+	// only filled on full-text search
+	Rank      float64        `json:"rank,omitempty"`
+	Highlight *BookHighlight `json:"highlight,omitempty"`
+
 	BaseAudit
 }
 
+type BookHighlight struct {
+	Title  string `json:"title" example:"The <mark>Lord</mark> of the <mark>Rings</mark>"`
+	Author string `json:"author"`
+}
+
 type SQLBook struct {
-	ID          sql.NullInt64  `db:"id"`
-	Title       sql.NullString `db:"title"`
-	Author      sql.NullString `db:"author"`
-	PublishYear sql.NullInt64  `db:"publish_year"`
+	ID          sql.NullInt64      `db:"id"`
+	Title       sql.NullString     `db:"title"`
+	Author      sql.NullString     `db:"author"`
+	Authors     SQLBookAuthors     `db:"authors"`
+	PublishYear sql.NullInt64      `db:"publish_year"`
+	Published   sql.NullString     `db:"published"`
+	ISBN        sql.NullString     `db:"isbn"`
+	Identifiers SQLBookIdentifiers `db:"identifiers"`
+	Genres      SQLBookGenres      `db:"genres"`
+	Tags        pq.StringArray     `db:"tags"`
+	Editions    SQLEditions        `db:"editions"`
+	Version     sql.NullInt64      `db:"version"`
+
+	Rank            sql.NullFloat64 `db:"rank"`
+	TitleHighlight  sql.NullString  `db:"title_highlight"`
+	AuthorHighlight sql.NullString  `db:"author_highlight"`
+
 	SQLBaseAudit
 }
 
+type BookListMetadata struct {
+	pagination.CursorMetadata
+	DidYouMean string `json:"did_you_mean,omitempty" example:"J.R.R. Tolkien"`
+}
+
 type BookSearchParams struct {
 	Search           string
+	SearchMode       string
+	Author           string
+	PublishedFrom    *edtf.Date
+	PublishedTo      *edtf.Date
+	CreatedAfter     *time.Time
+	IDs              []int64
+	Sort             []SortParam
+	IncludeDeleted   bool
+	OnlyDeleted      bool
 	RemovePagination bool
+
+	// AsOf reads the catalog as it was at that moment instead of now
+	AsOf *time.Time
+
+	// AuthorID lists the books crediting the author, in any role
+	AuthorID int64
+
+	// GenreID lists the books in the genre or any genre below it
+	GenreID int64
+	Tag     string
+
+	// Identifier lists the books with the identifier, when its Scheme is set
+	Identifier BookIdentifier
+
+	// EditionFormat and EditionLanguage list the books with an edition of
+	// that format or language, IncludeEditions lists the editions along
+	EditionFormat   string
+	EditionLanguage string
+	IncludeEditions bool
 }
 
+type SortParam struct {
+	Field string
+	Desc  bool
+}
+
+// StoreBookRequest takes either an author string, split on " & " into
+// authors, or an authors list, which wins when both are given. ISBN is an
+// ISBN-10 or ISBN-13, hyphens allowed, and is stored as ISBN-13. Published
+// is an EDTF date and wins over PublishYear the same way.
 type StoreBookRequest struct {
-	Title       string `json:"title"`
-	Author      string `json:"author"`
-	PublishYear int64  `json:"publish_year"`
+	Title       string       `json:"title"`
+	Author      string       `json:"author"`
+	Authors     []BookAuthor `json:"authors,omitempty"`
+	PublishYear int64        `json:"publish_year"`
+	Published   *edtf.Date   `json:"published,omitempty" swaggertype:"string" example:"1949-06-08"`
+	ISBN        string       `json:"isbn,omitempty" example:"0-261-10357-1"`
 }
 
+// UpdateBookRequest reads its author, publication and ISBN fields the same
+// way as StoreBookRequest, leaving ISBN out clears it.
 type UpdateBookRequest struct {
-	Title       string `json:"title"`
-	Author      string `json:"author"`
-	PublishYear int64  `json:"publish_year"`
+	Title       string       `json:"title"`
+	Author      string       `json:"author"`
+	Authors     []BookAuthor `json:"authors,omitempty"`
+	PublishYear int64        `json:"publish_year"`
+	Published   *edtf.Date   `json:"published,omitempty" swaggertype:"string" example:"1949-06-08"`
+	ISBN        string       `json:"isbn,omitempty" example:"0-261-10357-1"`
+}
+
+// BookPatch is a raw patch document for a book, either a JSON Merge Patch
+// or a JSON Patch as told by its content type.
+type BookPatch struct {
+	ContentType string
+	Data        []byte
+}
+
+type BookSuggestParams struct {
+	Query string
+	Field string
+	Limit int
+}
+
+type BookSuggestion struct {
+	Value string `json:"value" db:"value" example:"J.R.R. Tolkien"`
+	Count int64  `json:"count" db:"count" example:"2"`
+}
+
+type BookImportParams struct {
+	Format string
+	Mode   string
+	DryRun bool
+	// Force imports rows that probably are already stored books
+	Force bool
+}
+
+type BookImportReport struct {
+	DryRun bool   `json:"dry_run"`
+	Mode   string `json:"mode" example:"all_or_nothing"`
+
+	Total    int `json:"total" example:"3"`
+	Accepted int `json:"accepted" example:"2"`
+	Rejected int `json:"rejected" example:"1"`
+	// number of books actually stored, zero on dry run or aborted import
+	Imported int `json:"imported" example:"0"`
+
+	Rows []BookImportRow `json:"rows"`
+}
+
+type BookImportRow struct {
+	Line   int    `json:"line" example:"2"`
+	Status string `json:"status" example:"rejected"`
+	ID     int64  `json:"id,omitempty"`
+	Error  string `json:"error,omitempty" example:"publish year field is empty or less than equal 0"`
+	// stored books the row probably is, best first
+	Matches []BookMatch `json:"matches,omitempty"`
+}
+
+type BookBatchRequest struct {
+	Operations []BookBatchOperation `json:"operations"`
+}
+
+// BookBatchOperation is a single step of a batch. ID is required by every op
+// but create, Version by update and delete, the book fields by create and update.
+type BookBatchOperation struct {
+	Op          string       `json:"op" example:"update"`
+	ID          int64        `json:"id,omitempty" example:"11"`
+	Version     int64        `json:"version,omitempty" example:"2"`
+	Title       string       `json:"title,omitempty"`
+	Author      string       `json:"author,omitempty"`
+	Authors     []BookAuthor `json:"authors,omitempty"`
+	PublishYear int64        `json:"publish_year,omitempty"`
+	Published   *edtf.Date   `json:"published,omitempty" swaggertype:"string"`
+	ISBN        string       `json:"isbn,omitempty"`
+}
+
+func (op BookBatchOperation) Book() Book {
+	return Book{
+		ID:          op.ID,
+		Title:       op.Title,
+		Author:      op.Author,
+		Authors:     op.Authors,
+		PublishYear: op.PublishYear,
+		Published:   op.Published,
+		ISBN:        op.ISBN,
+		Version:     op.Version,
+	}
+}
+
+type BookBatchResult struct {
+	Index  int    `json:"index"`
+	Op     string `json:"op" example:"update"`
+	ID     int64  `json:"id,omitempty" example:"11"`
+	Status string `json:"status" example:"applied"`
+	Error  string `json:"error,omitempty"`
+	Data   *Book  `json:"data,omitempty"`
+	// stored books a create probably is, best first
+	Matches []BookMatch `json:"matches,omitempty"`
 }
@@ -0,0 +1,37 @@
+package model
+
+// BookRef is a book as listed in duplicate reports.
+type BookRef struct {
+	ID          int64  `json:"id" db:"id" example:"8"`
+	Title       string `json:"title" db:"title" example:"The Hobbit"`
+	Author      string `json:"author" db:"author" example:"J.R.R. Tolkien"`
+	PublishYear int64  `json:"publish_year" db:"publish_year" example:"1937"`
+}
+
+// BookMatch is a stored book a new one probably duplicates. Score goes from
+// 0 to 1, weighing title, author and publish year similarity.
+type BookMatch struct {
+	Book  BookRef `json:"book"`
+	Score float64 `json:"score" example:"0.93"`
+}
+
+// BookDuplicate is a pair of books that are probably the same book.
+type BookDuplicate struct {
+	Books []BookRef `json:"books"`
+	Score float64   `json:"score" example:"0.93"`
+}
+
+// MergeBooksRequest lists the books merged into the book of the path.
+// AsEditions keeps each merged book as an edition of it, carrying its ISBN
+// and publication date.
+type MergeBooksRequest struct {
+	BookIDs    []int64 `json:"book_ids" example:"12"`
+	AsEditions bool    `json:"as_editions"`
+}
+
+// BookMerge is the outcome of a merge, the merged books are soft deleted and
+// point to Book.
+type BookMerge struct {
+	Book          Book    `json:"book"`
+	MergedBookIDs []int64 `json:"merged_book_ids" example:"12"`
+}
@@ -0,0 +1,36 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// BookIdentifier is the record of a book in an outside dataset, Scheme is
+// oclc, lccn, openlibrary or goodreads.
+type BookIdentifier struct {
+	Scheme string `json:"scheme" example:"oclc"`
+	Value  string `json:"value" example:"12345"`
+}
+
+// SQLBookIdentifiers scans the json identifier list built by
+// library.book_identifier_list.
+type SQLBookIdentifiers []BookIdentifier
+
+func (i *SQLBookIdentifiers) Scan(src any) error {
+	switch src := src.(type) {
+	case nil:
+		*i = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(src, (*[]BookIdentifier)(i))
+	case string:
+		return json.Unmarshal([]byte(src), (*[]BookIdentifier)(i))
+	default:
+		return fmt.Errorf("unsupported book identifiers type: %T", src)
+	}
+}
+
+// SetBookIdentifiersRequest replaces every identifier of a book.
+type SetBookIdentifiersRequest struct {
+	Identifiers []BookIdentifier `json:"identifiers"`
+}
@@ -0,0 +1,62 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+const (
+	RevisionOpCreate  = "create"
+	RevisionOpUpdate  = "update"
+	RevisionOpDelete  = "delete"
+	RevisionOpRestore = "restore"
+	RevisionOpRevert  = "revert"
+	RevisionOpMerge   = "merge"
+)
+
+// BookRevision is a single recorded change of a book. Revision is the book
+// version the change produced, Before is nil for the revision creating it.
+type BookRevision struct {
+	BookID    int64  `json:"book_id" example:"11"`
+	Revision  int64  `json:"revision" example:"2"`
+	Operation string `json:"operation" example:"update"`
+	Actor     string `json:"actor,omitempty" example:"admin"`
+	RequestID string `json:"request_id,omitempty"`
+	// RevertedTo is the snapshot a revert restored the book fields from
+	RevertedTo int64 `json:"reverted_to,omitempty" example:"1"`
+
+	Changes []BookFieldChange `json:"changes"`
+	Before  *Book             `json:"before"`
+	After   *Book             `json:"after"`
+
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+}
+
+// BookFieldChange is a single field that differs between two revisions.
+type BookFieldChange struct {
+	Field  string `json:"field" example:"title"`
+	Before any    `json:"before"`
+	After  any    `json:"after"`
+}
+
+type SQLBookRevision struct {
+	BookID     sql.NullInt64  `db:"book_id"`
+	Revision   sql.NullInt64  `db:"revision"`
+	Operation  sql.NullString `db:"operation"`
+	Actor      sql.NullString `db:"actor"`
+	RequestID  sql.NullString `db:"request_id"`
+	RevertedTo sql.NullInt64  `db:"reverted_to"`
+	Before     []byte         `db:"before"`
+	After      []byte         `db:"after"`
+	CreatedAt  sql.NullTime   `db:"created_at"`
+}
+
+// BookSnapshot is the state of a book right after one of its revisions.
+// Snapshot is the book version it captures, the target of a revert.
+type BookSnapshot struct {
+	Snapshot  int64      `json:"snapshot" example:"1"`
+	Operation string     `json:"operation" example:"update"`
+	Actor     string     `json:"actor,omitempty" example:"admin"`
+	Book      *Book      `json:"book"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+}
@@ -0,0 +1,102 @@
+package model
+
+import (
+	"byfood-app/internal/pkg/edtf"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+)
+
+// Edition formats.
+const (
+	EditionFormatHardcover  = "hardcover"
+	EditionFormatPaperback  = "paperback"
+	EditionFormatEbook      = "ebook"
+	EditionFormatAudiobook  = "audiobook"
+	EditionFormatLargePrint = "large_print"
+)
+
+// EditionFormats lists the formats an edition can have.
+var EditionFormats = []string{
+	EditionFormatHardcover,
+	EditionFormatPaperback,
+	EditionFormatEbook,
+	EditionFormatAudiobook,
+	EditionFormatLargePrint,
+}
+
+// Edition is a published form of a book, the book being the work. Language
+// is a BCP 47 tag, PageCount is 0 when unknown or when the format has no
+// pages. ISBN is stored as ISBN-13 like the book one.
+type Edition struct {
+	ID        int64      `json:"id" example:"4"`
+	BookID    int64      `json:"book_id" example:"3"`
+	Publisher string     `json:"publisher,omitempty" example:"Penguin Classics"`
+	Format    string     `json:"format,omitempty" example:"paperback"`
+	Language  string     `json:"language,omitempty" example:"en"`
+	PageCount int64      `json:"page_count,omitempty" example:"480"`
+	ISBN      string     `json:"isbn,omitempty" example:"9780141439518"`
+	ISBN10    string     `json:"isbn_10,omitempty" example:"0141439513"`
+	Published *edtf.Date `json:"published,omitempty" swaggertype:"string" example:"2002-12-31"`
+
+	BaseAudit
+}
+
+type SQLEdition struct {
+	ID        sql.NullInt64  `db:"id"`
+	BookID    sql.NullInt64  `db:"book_id"`
+	Publisher sql.NullString `db:"publisher"`
+	Format    sql.NullString `db:"format"`
+	Language  sql.NullString `db:"language"`
+	PageCount sql.NullInt64  `db:"page_count"`
+	ISBN      sql.NullString `db:"isbn"`
+	Published sql.NullString `db:"published"`
+
+	SQLBaseAudit
+}
+
+// SQLEditions scans the json edition list built by library.book_edition_list.
+type SQLEditions []Edition
+
+func (e *SQLEditions) Scan(src any) error {
+	switch src := src.(type) {
+	case nil:
+		*e = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(src, (*[]Edition)(e))
+	case string:
+		return json.Unmarshal([]byte(src), (*[]Edition)(e))
+	default:
+		return fmt.Errorf("unsupported book editions type: %T", src)
+	}
+}
+
+// EditionSearchParams narrows the editions of a book, empty fields match
+// every edition.
+type EditionSearchParams struct {
+	Format   string
+	Language string
+}
+
+// StoreEditionRequest takes an ISBN-10 or ISBN-13, hyphens allowed, and an
+// EDTF publication date.
+type StoreEditionRequest struct {
+	Publisher string     `json:"publisher"`
+	Format    string     `json:"format" example:"paperback"`
+	Language  string     `json:"language" example:"en"`
+	PageCount int64      `json:"page_count" example:"480"`
+	ISBN      string     `json:"isbn,omitempty" example:"0-14-143951-3"`
+	Published *edtf.Date `json:"published,omitempty" swaggertype:"string" example:"2002-12-31"`
+}
+
+// UpdateEditionRequest replaces every field of the edition, leaving a field
+// out clears it.
+type UpdateEditionRequest struct {
+	Publisher string     `json:"publisher"`
+	Format    string     `json:"format" example:"paperback"`
+	Language  string     `json:"language" example:"en"`
+	PageCount int64      `json:"page_count" example:"480"`
+	ISBN      string     `json:"isbn,omitempty" example:"0-14-143951-3"`
+	Published *edtf.Date `json:"published,omitempty" swaggertype:"string" example:"2002-12-31"`
+}
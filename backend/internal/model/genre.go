@@ -0,0 +1,74 @@
+package model
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+)
+
+// GenrePathSeparator joins the genre names from the top of the tree down to
+// a genre, so no genre name may contain it.
+const GenrePathSeparator = " > "
+
+// Genre is a node of the genre tree. Path names it from the top of the tree,
+// and Books counts the books that are not deleted in the genre or below it.
+type Genre struct {
+	ID       int64  `json:"id" example:"3"`
+	ParentID int64  `json:"parent_id,omitempty" example:"2"`
+	Name     string `json:"name" example:"High Fantasy"`
+	Path     string `json:"path" example:"Fiction > Fantasy > High Fantasy"`
+	Books    int64  `json:"books" example:"2"`
+
+	BaseAudit
+}
+
+type SQLGenre struct {
+	ID       sql.NullInt64  `db:"id"`
+	ParentID sql.NullInt64  `db:"parent_id"`
+	Name     sql.NullString `db:"name"`
+	Path     sql.NullString `db:"path"`
+	Books    sql.NullInt64  `db:"books"`
+
+	SQLBaseAudit
+}
+
+// BookGenre is a genre a book is in, as listed on the book.
+type BookGenre struct {
+	ID   int64  `json:"id" example:"3"`
+	Name string `json:"name" example:"High Fantasy"`
+}
+
+// SQLBookGenres scans the json genre list built by library.book_genre_list.
+type SQLBookGenres []BookGenre
+
+func (g *SQLBookGenres) Scan(src any) error {
+	switch src := src.(type) {
+	case nil:
+		*g = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(src, (*[]BookGenre)(g))
+	case string:
+		return json.Unmarshal([]byte(src), (*[]BookGenre)(g))
+	default:
+		return fmt.Errorf("unsupported book genres type: %T", src)
+	}
+}
+
+// StoreGenreRequest adds a top level genre when ParentID is left out.
+type StoreGenreRequest struct {
+	Name     string `json:"name"`
+	ParentID int64  `json:"parent_id"`
+}
+
+// UpdateGenreRequest renames the genre, and moves it along with everything
+// below it when ParentID changes.
+type UpdateGenreRequest struct {
+	Name     string `json:"name"`
+	ParentID int64  `json:"parent_id"`
+}
+
+// SetBookGenresRequest replaces every genre of a book.
+type SetBookGenresRequest struct {
+	GenreIDs []int64 `json:"genre_ids" example:"3,7"`
+}
@@ -0,0 +1,27 @@
+package model
+
+import (
+	"database/sql"
+	"net/http"
+	"time"
+)
+
+// IdempotencyRecord is the stored outcome of a request sent with an Idempotency-Key.
+type IdempotencyRecord struct {
+	Key         string
+	Fingerprint string
+	// StatusCode is 0 while the first request is still in progress
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	ExpiresAt  time.Time
+}
+
+type SQLIdempotencyRecord struct {
+	Key             sql.NullString `db:"key"`
+	Fingerprint     sql.NullString `db:"fingerprint"`
+	StatusCode      sql.NullInt64  `db:"status_code"`
+	ResponseHeaders []byte         `db:"response_headers"`
+	ResponseBody    []byte         `db:"response_body"`
+	ExpiresAt       sql.NullTime   `db:"expires_at"`
+}
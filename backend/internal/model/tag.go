@@ -0,0 +1,40 @@
+package model
+
+import "database/sql"
+
+// Tag is a free-form label of books, Books counts the books that are not
+// deleted carrying it.
+type Tag struct {
+	ID    int64  `json:"id" example:"4"`
+	Name  string `json:"name" example:"coming of age"`
+	Books int64  `json:"books" example:"2"`
+
+	BaseAudit
+}
+
+type SQLTag struct {
+	ID    sql.NullInt64  `db:"id"`
+	Name  sql.NullString `db:"name"`
+	Books sql.NullInt64  `db:"books"`
+
+	SQLBaseAudit
+}
+
+type TagSearchParams struct {
+	// Search matches the start of tag names
+	Search string
+	Limit  int
+}
+
+type StoreTagRequest struct {
+	Name string `json:"name"`
+}
+
+type UpdateTagRequest struct {
+	Name string `json:"name"`
+}
+
+// SetBookTagsRequest replaces every tag of a book, unknown tags are added.
+type SetBookTagsRequest struct {
+	Tags []string `json:"tags" example:"quest,war"`
+}
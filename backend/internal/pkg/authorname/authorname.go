@@ -0,0 +1,176 @@
+package authorname
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// nameSuffixes are read as the end of a name rather than the given names of
+// an inverted one, as in "King, Jr.".
+var nameSuffixes = []string{"jr", "sr", "ii", "iii", "iv"}
+
+// Normalize returns the display form of a person name. It is Unicode NFC with
+// single spaces, an inverted "Tolkien, J. R. R." is turned around and initials
+// are written together, so the result is "J.R.R. Tolkien".
+func Normalize(name string) string {
+	name = strings.Join(strings.Fields(norm.NFC.String(name)), " ")
+	name = uninvert(name)
+
+	return strings.Join(joinInitials(strings.Fields(name)), " ")
+}
+
+// Key is what names are matched by, the normalized name with diacritics
+// folded, lower cased and periods read as spaces. "J.R.R. Tolkien",
+// "Tolkien, J. R. R." and "JRR Tolkien" all give "j r r tolkien".
+func Key(name string) string {
+	fold := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	folded, _, err := transform.String(fold, Normalize(name))
+	if err != nil {
+		folded = Normalize(name)
+	}
+
+	folded = strings.ReplaceAll(strings.ToLower(folded), ".", " ")
+	return strings.Join(strings.Fields(folded), " ")
+}
+
+// ProbableDuplicate reports whether two name keys likely name the same person,
+// the same surname with given names where each one either matches the other
+// or is its initial, e.g. "j tolkien" and "john ronald reuel tolkien".
+// A name without given names is never taken for a duplicate.
+func ProbableDuplicate(a string, b string) bool {
+	aTokens, bTokens := strings.Fields(a), strings.Fields(b)
+	if len(aTokens) < 2 || len(bTokens) < 2 || aTokens[len(aTokens)-1] != bTokens[len(bTokens)-1] {
+		return false
+	}
+
+	aGiven, bGiven := aTokens[:len(aTokens)-1], bTokens[:len(bTokens)-1]
+	for i := range min(len(aGiven), len(bGiven)) {
+		if !givenNameMatches(aGiven[i], bGiven[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func givenNameMatches(a string, b string) bool {
+	switch {
+	case a == b:
+		return true
+	case utf8.RuneCountInString(a) == 1:
+		return strings.HasPrefix(b, a)
+	case utf8.RuneCountInString(b) == 1:
+		return strings.HasPrefix(a, b)
+	default:
+		return false
+	}
+}
+
+// uninvert turns "Last, First" into "First Last". Names with more than one
+// comma, or where the comma precedes a suffix, are left as they are.
+func uninvert(name string) string {
+	last, given, ok := strings.Cut(name, ",")
+	if !ok || strings.Contains(given, ",") {
+		return name
+	}
+
+	last, given = strings.TrimSpace(last), strings.TrimSpace(given)
+	if last == "" || given == "" || isSuffix(given) {
+		return name
+	}
+
+	return given + " " + last
+}
+
+func isSuffix(s string) bool {
+	s = strings.ToLower(strings.TrimSuffix(s, "."))
+	for _, suffix := range nameSuffixes {
+		if s == suffix {
+			return true
+		}
+	}
+
+	return false
+}
+
+// joinInitials writes runs of initials before the surname as one token,
+// "J. R. R.", "J R R" and "JRR" all become "J.R.R.". A token of two or three
+// capitals only counts as initials when the name is not all capitals.
+func joinInitials(tokens []string) []string {
+	shouting := strings.ToUpper(strings.Join(tokens, " ")) == strings.Join(tokens, " ")
+
+	result := make([]string, 0, len(tokens))
+	var run []rune
+	for i, token := range tokens {
+		var letters []rune
+		if i < len(tokens)-1 {
+			letters = initials(token, shouting)
+		}
+
+		if letters == nil {
+			if len(run) > 0 {
+				result = append(result, writeInitials(run))
+				run = nil
+			}
+			result = append(result, token)
+			continue
+		}
+
+		run = append(run, letters...)
+	}
+	if len(run) > 0 {
+		result = append(result, writeInitials(run))
+	}
+
+	return result
+}
+
+// initials returns the letters of a token made only of initials, nil for
+// any other token.
+func initials(token string, shouting bool) []rune {
+	var letters []rune
+	dotted := strings.Contains(token, ".")
+	for _, part := range strings.Split(strings.TrimSuffix(token, "."), ".") {
+		part = strings.TrimSpace(part)
+		count := utf8.RuneCountInString(part)
+		if count == 0 || strings.ToUpper(part) != part || !isLetters(part) {
+			return nil
+		}
+
+		switch {
+		case count == 1:
+		case !dotted && !shouting && count <= 3:
+		default:
+			return nil
+		}
+
+		letters = append(letters, []rune(part)...)
+	}
+
+	return letters
+}
+
+func isLetters(s string) bool {
+	for _, r := range s {
+		if !unicode.IsLetter(r) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func writeInitials(letters []rune) string {
+	var b strings.Builder
+	for _, letter := range letters {
+		b.WriteRune(letter)
+		b.WriteByte('.')
+	}
+
+	return b.String()
+}
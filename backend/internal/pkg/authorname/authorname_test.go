@@ -0,0 +1,76 @@
+package authorname
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "already normalized", in: "J.R.R. Tolkien", want: "J.R.R. Tolkien"},
+		{name: "spaced initials", in: "J. R. R. Tolkien", want: "J.R.R. Tolkien"},
+		{name: "initials without periods", in: "J R R Tolkien", want: "J.R.R. Tolkien"},
+		{name: "run together initials", in: "JRR Tolkien", want: "J.R.R. Tolkien"},
+		{name: "inverted name", in: "Tolkien, J. R. R.", want: "J.R.R. Tolkien"},
+		{name: "inverted full name", in: "Austen,   Jane", want: "Jane Austen"},
+		{name: "initial before a given name", in: "F.  Scott Fitzgerald", want: "F. Scott Fitzgerald"},
+		{name: "suffix is not inverted", in: "Martin Luther King, Jr.", want: "Martin Luther King, Jr."},
+		{name: "several commas are left alone", in: "Tolkien, John, Ronald", want: "Tolkien, John, Ronald"},
+		{name: "all capitals are not initials", in: "BOB SMITH", want: "BOB SMITH"},
+		{name: "surname is never initials", in: "Malcolm X", want: "Malcolm X"},
+		{name: "decomposed accents are composed", in: "Gabriel García Márquez", want: "Gabriel García Márquez"},
+		{name: "single name", in: " Homer ", want: "Homer"},
+		{name: "empty", in: "  ", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Normalize(tt.in); got != tt.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKey(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "initials", in: "J.R.R. Tolkien", want: "j r r tolkien"},
+		{name: "inverted initials", in: "Tolkien, J. R. R.", want: "j r r tolkien"},
+		{name: "run together initials", in: "JRR Tolkien", want: "j r r tolkien"},
+		{name: "diacritics are folded", in: "Gabriel García Márquez", want: "gabriel garcia marquez"},
+		{name: "trailing period", in: "Martin Luther King, Jr.", want: "martin luther king, jr"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Key(tt.in); got != tt.want {
+				t.Errorf("Key(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProbableDuplicate(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		want bool
+	}{
+		{name: "initials of the given names", a: "j r r tolkien", b: "john ronald reuel tolkien", want: true},
+		{name: "fewer given names", a: "j tolkien", b: "j r r tolkien", want: true},
+		{name: "different given names", a: "christopher tolkien", b: "j r r tolkien", want: false},
+		{name: "different surnames", a: "jane austen", b: "jane eyre", want: false},
+		{name: "single name", a: "homer", b: "winslow homer", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ProbableDuplicate(tt.a, tt.b); got != tt.want {
+				t.Errorf("ProbableDuplicate(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package booktitle
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// leadingArticles are dropped from the start of a title, so "The Hobbit" and
+// "Hobbit" are the same title.
+var leadingArticles = []string{"the", "a", "an"}
+
+// Key is what titles are compared by, the title with diacritics folded,
+// lower cased, "&" read as "and", punctuation read as spaces and without a
+// leading article. "The Lord of the Rings", "Lord of the Rings" and
+// "lord-of-the-rings" all give "lord of the rings".
+func Key(title string) string {
+	fold := transform.Chain(norm.NFKD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	folded, _, err := transform.String(fold, title)
+	if err != nil {
+		folded = title
+	}
+
+	folded = strings.ToLower(strings.ReplaceAll(folded, "&", " and "))
+	words := strings.FieldsFunc(folded, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) > 1 && slices.Contains(leadingArticles, words[0]) {
+		words = words[1:]
+	}
+
+	return strings.Join(words, " ")
+}
+
+// Similarity compares two keys by their trigrams the way pg_trgm does, from
+// 0 for nothing in common to 1 for the same words.
+func Similarity(a string, b string) float64 {
+	if a == b {
+		return 1
+	}
+
+	aTrigrams, bTrigrams := trigrams(a), trigrams(b)
+	if len(aTrigrams) == 0 || len(bTrigrams) == 0 {
+		return 0
+	}
+
+	shared := 0
+	for trigram := range aTrigrams {
+		if bTrigrams[trigram] {
+			shared++
+		}
+	}
+
+	return float64(shared) / float64(len(aTrigrams)+len(bTrigrams)-shared)
+}
+
+// trigrams returns the set of three letter runs of every word, padded with
+// two spaces in front and one behind like pg_trgm does.
+func trigrams(s string) map[string]bool {
+	result := map[string]bool{}
+	for _, word := range strings.Fields(s) {
+		letters := []rune("  " + word + " ")
+		for i := 0; i+3 <= len(letters); i++ {
+			result[string(letters[i:i+3])] = true
+		}
+	}
+
+	return result
+}
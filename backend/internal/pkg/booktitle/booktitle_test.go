@@ -0,0 +1,49 @@
+package booktitle
+
+import (
+	"math"
+	"testing"
+)
+
+func TestKey(t *testing.T) {
+	tests := []struct {
+		name  string
+		title string
+		want  string
+	}{
+		{name: "leading article", title: "The Lord of the Rings", want: "lord of the rings"},
+		{name: "punctuation", title: "lord-of-the-rings!", want: "lord of the rings"},
+		{name: "ampersand", title: "Pride & Prejudice", want: "pride and prejudice"},
+		{name: "diacritics", title: "Les Misérables", want: "les miserables"},
+		{name: "article alone is kept", title: "The", want: "the"},
+		{name: "digits", title: "Nineteen Eighty-Four (1984)", want: "nineteen eighty four 1984"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Key(tt.title); got != tt.want {
+				t.Errorf("Key(%q) = %q, want %q", tt.title, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		want float64
+	}{
+		{name: "same", a: "the hobbit", b: "the hobbit", want: 1},
+		{name: "typo", a: "pride and prejudice", b: "pride and predjudice", want: 0.76},
+		{name: "nothing in common", a: "dune", b: "emma", want: 0},
+		{name: "empty", a: "", b: "emma", want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Similarity(tt.a, tt.b); math.Abs(got-tt.want) > 0.01 {
+				t.Errorf("Similarity(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
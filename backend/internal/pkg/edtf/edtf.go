@@ -0,0 +1,194 @@
+package edtf
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Precisions a date can be known to.
+const (
+	PrecisionYear  = "year"
+	PrecisionMonth = "month"
+	PrecisionDay   = "day"
+)
+
+var (
+	ErrSyntax = errors.New("date must be written as EDTF YYYY, YYYY-MM or YYYY-MM-DD, with an optional leading - and a trailing ?, ~ or %")
+	ErrYear   = errors.New("date year must be from -9999 to 9999")
+	ErrMonth  = errors.New("date month must be from 01 to 12")
+	ErrDay    = errors.New("date day does not exist in its month")
+)
+
+var datePattern = regexp.MustCompile(`^(-?)([0-9]{4})(?:-([0-9]{2})(?:-([0-9]{2}))?)?([?~%]?)$`)
+
+// Date is a date of the Extended Date/Time Format (ISO 8601-2) at level 1,
+// known to the year, the month or the day. Year follows ISO 8601 numbering,
+// 0 is 1 BCE and -1 is 2 BCE. Month and Day are 0 when unknown. Approximate
+// is the "~" qualifier, "c. 1590", Uncertain the "?" one, and "%" is both.
+type Date struct {
+	Year        int64
+	Month       int
+	Day         int
+	Approximate bool
+	Uncertain   bool
+}
+
+// Parse reads a date written in EDTF, like "1949", "1949-06-08", "1590~" or
+// "-0699?", and checks the month and day exist.
+func Parse(s string) (Date, error) {
+	match := datePattern.FindStringSubmatch(strings.TrimSpace(s))
+	if match == nil || match[1] == "-" && match[2] == "0000" {
+		return Date{}, ErrSyntax
+	}
+
+	var date Date
+	date.Year, _ = strconv.ParseInt(match[2], 10, 64)
+	if match[1] == "-" {
+		date.Year = -date.Year
+	}
+	if match[3] != "" {
+		date.Month, _ = strconv.Atoi(match[3])
+		if date.Month == 0 {
+			return Date{}, ErrMonth
+		}
+	}
+	if match[4] != "" {
+		date.Day, _ = strconv.Atoi(match[4])
+		if date.Day == 0 {
+			return Date{}, ErrDay
+		}
+	}
+	switch match[5] {
+	case "~":
+		date.Approximate = true
+	case "?":
+		date.Uncertain = true
+	case "%":
+		date.Approximate, date.Uncertain = true, true
+	}
+
+	return date, date.Validate()
+}
+
+// Validate checks the month and day of a date built by hand exist.
+func (d Date) Validate() error {
+	switch {
+	case d.Year < -9999 || d.Year > 9999:
+		return ErrYear
+	case d.Month < 0 || d.Month > 12:
+		return ErrMonth
+	case d.Day != 0 && (d.Month == 0 || d.Day < 0 || d.Day > daysIn(d.Year, d.Month)):
+		return ErrDay
+	}
+
+	return nil
+}
+
+// Precision is what the date is known to, PrecisionYear, PrecisionMonth or
+// PrecisionDay.
+func (d Date) Precision() string {
+	switch {
+	case d.Day != 0:
+		return PrecisionDay
+	case d.Month != 0:
+		return PrecisionMonth
+	default:
+		return PrecisionYear
+	}
+}
+
+// Start is the first day the date can be, "1949" starts on 1949-01-01.
+func (d Date) Start() Date {
+	return Date{Year: d.Year, Month: max(d.Month, 1), Day: max(d.Day, 1)}
+}
+
+// End is the last day the date can be, "1949-02" ends on 1949-02-28.
+func (d Date) End() Date {
+	end := Date{Year: d.Year, Month: d.Month, Day: d.Day}
+	if end.Month == 0 {
+		end.Month = 12
+	}
+	if end.Day == 0 {
+		end.Day = daysIn(end.Year, end.Month)
+	}
+
+	return end
+}
+
+// Compare orders dates by year, month and day, unknown parts coming first
+// and qualifiers being ignored.
+func Compare(a Date, b Date) int {
+	if c := cmp.Compare(a.Year, b.Year); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(a.Month, b.Month); c != 0 {
+		return c
+	}
+
+	return cmp.Compare(a.Day, b.Day)
+}
+
+// String writes the date in EDTF, the way Parse reads it.
+func (d Date) String() string {
+	var b strings.Builder
+	if d.Year < 0 {
+		b.WriteByte('-')
+	}
+	fmt.Fprintf(&b, "%04d", abs(d.Year))
+	if d.Month != 0 {
+		fmt.Fprintf(&b, "-%02d", d.Month)
+	}
+	if d.Day != 0 {
+		fmt.Fprintf(&b, "-%02d", d.Day)
+	}
+	switch {
+	case d.Approximate && d.Uncertain:
+		b.WriteByte('%')
+	case d.Approximate:
+		b.WriteByte('~')
+	case d.Uncertain:
+		b.WriteByte('?')
+	}
+
+	return b.String()
+}
+
+func (d Date) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+func (d *Date) UnmarshalText(text []byte) error {
+	date, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*d = date
+	return nil
+}
+
+// daysIn counts the days of a month of the proleptic Gregorian calendar.
+func daysIn(year int64, month int) int {
+	switch month {
+	case 2:
+		if year%4 == 0 && (year%100 != 0 || year%400 == 0) {
+			return 29
+		}
+		return 28
+	case 4, 6, 9, 11:
+		return 30
+	default:
+		return 31
+	}
+}
+
+func abs(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
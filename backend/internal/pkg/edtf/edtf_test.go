@@ -0,0 +1,91 @@
+package edtf
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    Date
+		wantErr error
+	}{
+		{name: "year", s: "1949", want: Date{Year: 1949}},
+		{name: "month", s: "1949-06", want: Date{Year: 1949, Month: 6}},
+		{name: "day", s: "1949-06-08", want: Date{Year: 1949, Month: 6, Day: 8}},
+		{name: "approximate", s: "1590~", want: Date{Year: 1590, Approximate: true}},
+		{name: "uncertain", s: "1590-03?", want: Date{Year: 1590, Month: 3, Uncertain: true}},
+		{name: "approximate and uncertain", s: "1590%", want: Date{Year: 1590, Approximate: true, Uncertain: true}},
+		{name: "bce", s: "-0699~", want: Date{Year: -699, Approximate: true}},
+		{name: "year zero is 1 bce", s: "0000", want: Date{Year: 0}},
+		{name: "leap day", s: "2000-02-29", want: Date{Year: 2000, Month: 2, Day: 29}},
+		{name: "negative zero", s: "-0000", wantErr: ErrSyntax},
+		{name: "short year", s: "949", wantErr: ErrSyntax},
+		{name: "day without month", s: "1949--08", wantErr: ErrSyntax},
+		{name: "qualifier in the middle", s: "1949~-06", wantErr: ErrSyntax},
+		{name: "month 13", s: "1949-13", wantErr: ErrMonth},
+		{name: "month 00", s: "1949-00", wantErr: ErrMonth},
+		{name: "no leap day in 1900", s: "1900-02-29", wantErr: ErrDay},
+		{name: "april 31", s: "1949-04-31", wantErr: ErrDay},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.s)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Parse(%q) error = %v, wantErr %v", tt.s, err, tt.wantErr)
+			}
+			if err == nil && got != tt.want {
+				t.Errorf("Parse(%q) = %+v, want %+v", tt.s, got, tt.want)
+			}
+			if err == nil && got.String() != tt.s {
+				t.Errorf("Parse(%q).String() = %q", tt.s, got.String())
+			}
+		})
+	}
+}
+
+func TestDate_Bounds(t *testing.T) {
+	tests := []struct {
+		name      string
+		date      Date
+		wantStart Date
+		wantEnd   Date
+	}{
+		{name: "year", date: Date{Year: 1949}, wantStart: Date{Year: 1949, Month: 1, Day: 1}, wantEnd: Date{Year: 1949, Month: 12, Day: 31}},
+		{name: "february of a leap year", date: Date{Year: 1948, Month: 2}, wantStart: Date{Year: 1948, Month: 2, Day: 1}, wantEnd: Date{Year: 1948, Month: 2, Day: 29}},
+		{name: "day", date: Date{Year: -699, Month: 3, Day: 15, Approximate: true}, wantStart: Date{Year: -699, Month: 3, Day: 15}, wantEnd: Date{Year: -699, Month: 3, Day: 15}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.date.Start(); got != tt.wantStart {
+				t.Errorf("Date.Start() = %+v, want %+v", got, tt.wantStart)
+			}
+			if got := tt.date.End(); got != tt.wantEnd {
+				t.Errorf("Date.End() = %+v, want %+v", got, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name string
+		a    Date
+		b    Date
+		want int
+	}{
+		{name: "bce first", a: Date{Year: -699}, b: Date{Year: 1}, want: -1},
+		{name: "unknown month first", a: Date{Year: 1949}, b: Date{Year: 1949, Month: 1}, want: -1},
+		{name: "qualifiers ignored", a: Date{Year: 1949, Approximate: true}, b: Date{Year: 1949}, want: 0},
+		{name: "later day", a: Date{Year: 1949, Month: 6, Day: 8}, b: Date{Year: 1949, Month: 6, Day: 1}, want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Compare(tt.a, tt.b); got != tt.want {
+				t.Errorf("Compare() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
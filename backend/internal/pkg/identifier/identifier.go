@@ -0,0 +1,104 @@
+package identifier
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Schemes of the outside datasets a book can be identified in.
+const (
+	OCLC        = "oclc"
+	LCCN        = "lccn"
+	OpenLibrary = "openlibrary"
+	Goodreads   = "goodreads"
+)
+
+var (
+	ErrUnknownScheme = errors.New("unknown identifier scheme, expected oclc, lccn, openlibrary or goodreads")
+	ErrFormat        = errors.New("identifier format is invalid")
+	ErrSyntax        = errors.New("identifier must be written as scheme:value")
+)
+
+var (
+	numberPattern      = regexp.MustCompile(`^[1-9][0-9]{0,11}$`)
+	lccnPattern        = regexp.MustCompile(`^([a-z]{0,3}[0-9]{8}|[a-z]{0,2}[0-9]{10})$`)
+	openLibraryPattern = regexp.MustCompile(`^OL[1-9][0-9]*[MW]$`)
+)
+
+// Normalize checks the value against the format of its scheme and returns
+// both in the form they are stored in. Schemes are case insensitive, and
+// values are read the way each dataset prints them, so "ocm00012345" is OCLC
+// number "12345" and LCCN "n78-890351" is "n78890351".
+func Normalize(scheme, value string) (string, string, error) {
+	scheme = strings.ToLower(strings.TrimSpace(scheme))
+	value = strings.TrimSpace(value)
+
+	var normalized string
+	var ok bool
+	switch scheme {
+	case OCLC:
+		normalized = normalizeOCLC(value)
+		ok = numberPattern.MatchString(normalized)
+	case LCCN:
+		normalized, ok = normalizeLCCN(value)
+		ok = ok && lccnPattern.MatchString(normalized)
+	case OpenLibrary:
+		normalized = strings.ToUpper(value)
+		ok = openLibraryPattern.MatchString(normalized)
+	case Goodreads:
+		normalized = value
+		ok = numberPattern.MatchString(normalized)
+	default:
+		return "", "", fmt.Errorf("%w: %q", ErrUnknownScheme, scheme)
+	}
+	if !ok {
+		return "", "", fmt.Errorf("%w for %s: %q", ErrFormat, scheme, value)
+	}
+
+	return scheme, normalized, nil
+}
+
+// Parse reads an identifier written as "scheme:value", like "oclc:12345",
+// and normalizes it.
+func Parse(s string) (string, string, error) {
+	scheme, value, found := strings.Cut(s, ":")
+	if !found || strings.TrimSpace(value) == "" {
+		return "", "", fmt.Errorf("%w: %q", ErrSyntax, s)
+	}
+
+	return Normalize(scheme, value)
+}
+
+// normalizeOCLC drops the "(OCoLC)" and "ocm", "ocn" or "on" prefixes of
+// OCLC control numbers along with leading zeros.
+func normalizeOCLC(value string) string {
+	lower := strings.ToLower(value)
+	for _, prefix := range []string{"(ocolc)", "ocm", "ocn", "on"} {
+		if strings.HasPrefix(lower, prefix) {
+			value = value[len(prefix):]
+			break
+		}
+	}
+
+	return strings.TrimLeft(value, "0")
+}
+
+// normalizeLCCN follows the Library of Congress normalization: blanks are
+// removed, so is everything from a slash on, and a hyphen is removed with the
+// serial number after it left padded to six digits.
+func normalizeLCCN(value string) (string, bool) {
+	value = strings.ToLower(strings.Join(strings.Fields(value), ""))
+	value, _, _ = strings.Cut(value, "/")
+
+	prefix, serial, found := strings.Cut(value, "-")
+	if !found {
+		return value, true
+	}
+	if serial == "" || len(serial) > 6 || strings.Trim(serial, "0123456789") != "" {
+		return "", false
+	}
+
+	return prefix + strings.Repeat("0", 6-len(serial)) + serial, true
+}
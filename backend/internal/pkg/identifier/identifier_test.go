@@ -0,0 +1,75 @@
+package identifier
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name       string
+		scheme     string
+		value      string
+		wantScheme string
+		want       string
+		wantErr    error
+	}{
+		{name: "oclc number", scheme: "oclc", value: "12345", wantScheme: OCLC, want: "12345"},
+		{name: "oclc with ocm prefix", scheme: "OCLC", value: "ocm00012345", wantScheme: OCLC, want: "12345"},
+		{name: "oclc with ocolc prefix", scheme: "oclc", value: "(OCoLC)1103573", wantScheme: OCLC, want: "1103573"},
+		{name: "oclc with letters", scheme: "oclc", value: "12a45", wantErr: ErrFormat},
+		{name: "oclc of zeros", scheme: "oclc", value: "000", wantErr: ErrFormat},
+		{name: "lccn", scheme: "lccn", value: "n78890351", wantScheme: LCCN, want: "n78890351"},
+		{name: "lccn with hyphen is padded", scheme: "lccn", value: "n78-89035", wantScheme: LCCN, want: "n78089035"},
+		{name: "lccn with blanks and revision", scheme: "lccn", value: " 85-2 /AC/r932", wantScheme: LCCN, want: "85000002"},
+		{name: "lccn with four digit year", scheme: "lccn", value: "2001-000002", wantScheme: LCCN, want: "2001000002"},
+		{name: "lccn with long serial", scheme: "lccn", value: "85-1234567", wantErr: ErrFormat},
+		{name: "lccn too short", scheme: "lccn", value: "8512", wantErr: ErrFormat},
+		{name: "open library edition", scheme: "openlibrary", value: "ol7353617m", wantScheme: OpenLibrary, want: "OL7353617M"},
+		{name: "open library work", scheme: "openlibrary", value: "OL27482W", wantScheme: OpenLibrary, want: "OL27482W"},
+		{name: "open library author", scheme: "openlibrary", value: "OL26320A", wantErr: ErrFormat},
+		{name: "goodreads", scheme: "goodreads", value: "5907", wantScheme: Goodreads, want: "5907"},
+		{name: "goodreads with slug", scheme: "goodreads", value: "5907.The_Hobbit", wantErr: ErrFormat},
+		{name: "unknown scheme", scheme: "amazon", value: "B000FC1PJI", wantErr: ErrUnknownScheme},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotScheme, got, err := Normalize(tt.scheme, tt.value)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Normalize(%q, %q) error = %v, want %v", tt.scheme, tt.value, err, tt.wantErr)
+				return
+			}
+			if gotScheme != tt.wantScheme || got != tt.want {
+				t.Errorf("Normalize(%q, %q) = %q, %q, want %q, %q", tt.scheme, tt.value, gotScheme, got, tt.wantScheme, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name       string
+		in         string
+		wantScheme string
+		want       string
+		wantErr    error
+	}{
+		{name: "scheme and value", in: "oclc:12345", wantScheme: OCLC, want: "12345"},
+		{name: "lccn keeps its hyphen split", in: "lccn:n78-890351", wantScheme: LCCN, want: "n78890351"},
+		{name: "no scheme", in: "12345", wantErr: ErrSyntax},
+		{name: "no value", in: "goodreads:", wantErr: ErrSyntax},
+		{name: "unknown scheme", in: "isbn:9780261103573", wantErr: ErrUnknownScheme},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotScheme, got, err := Parse(tt.in)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Parse(%q) error = %v, want %v", tt.in, err, tt.wantErr)
+				return
+			}
+			if gotScheme != tt.wantScheme || got != tt.want {
+				t.Errorf("Parse(%q) = %q, %q, want %q, %q", tt.in, gotScheme, got, tt.wantScheme, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package isbn
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrLength      = errors.New("isbn must have 10 or 13 digits")
+	ErrCharacters  = errors.New("isbn can only contain digits, hyphens and spaces, and an X check digit on ISBN-10")
+	ErrCheckDigit  = errors.New("isbn check digit is invalid")
+	ErrPrefix13    = errors.New("isbn-13 must start with 978 or 979")
+	ErrNotBookland = errors.New("isbn-13 starting with 979 has no ISBN-10")
+)
+
+// Normalize returns the ISBN-13 of an ISBN-10 or ISBN-13 written with or
+// without hyphens and spaces, "0-261-10357-1" gives "9780261103573". Both
+// forms are checked against their check digit.
+func Normalize(s string) (string, error) {
+	digits := strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(strings.TrimSpace(s)))
+
+	switch len(digits) {
+	case 10:
+		err := validate10(digits)
+		if err != nil {
+			return "", err
+		}
+
+		return "978" + digits[:9] + string(checkDigit13("978"+digits[:9])), nil
+	case 13:
+		err := validate13(digits)
+		if err != nil {
+			return "", err
+		}
+
+		return digits, nil
+	default:
+		return "", ErrLength
+	}
+}
+
+// To10 returns the ISBN-10 of a normalized ISBN-13, only ISBNs starting
+// with 978 have one.
+func To10(isbn13 string) (string, error) {
+	err := validate13(isbn13)
+	if err != nil {
+		return "", err
+	}
+	if !strings.HasPrefix(isbn13, "978") {
+		return "", ErrNotBookland
+	}
+
+	return isbn13[3:12] + string(checkDigit10(isbn13[3:12])), nil
+}
+
+func validate10(digits string) error {
+	for i, r := range digits {
+		if (r < '0' || r > '9') && !(i == 9 && r == 'X') {
+			return ErrCharacters
+		}
+	}
+	if checkDigit10(digits[:9]) != digits[9] {
+		return fmt.Errorf("%w: %s", ErrCheckDigit, digits)
+	}
+
+	return nil
+}
+
+func validate13(digits string) error {
+	for _, r := range digits {
+		if r < '0' || r > '9' {
+			return ErrCharacters
+		}
+	}
+	if !strings.HasPrefix(digits, "978") && !strings.HasPrefix(digits, "979") {
+		return ErrPrefix13
+	}
+	if checkDigit13(digits[:12]) != digits[12] {
+		return fmt.Errorf("%w: %s", ErrCheckDigit, digits)
+	}
+
+	return nil
+}
+
+// checkDigit10 weighs the nine digits 10 down to 2, the check digit makes the
+// sum a multiple of 11 and is X for 10.
+func checkDigit10(digits string) byte {
+	sum := 0
+	for i := range 9 {
+		sum += int(digits[i]-'0') * (10 - i)
+	}
+
+	check := (11 - sum%11) % 11
+	if check == 10 {
+		return 'X'
+	}
+
+	return byte('0' + check)
+}
+
+// checkDigit13 weighs the twelve digits alternately 1 and 3, the check digit
+// makes the sum a multiple of 10.
+func checkDigit13(digits string) byte {
+	sum := 0
+	for i := range 12 {
+		weight := 1
+		if i%2 == 1 {
+			weight = 3
+		}
+		sum += int(digits[i]-'0') * weight
+	}
+
+	return byte('0' + (10-sum%10)%10)
+}
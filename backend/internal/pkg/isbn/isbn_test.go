@@ -0,0 +1,67 @@
+package isbn
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    string
+		wantErr error
+	}{
+		{name: "isbn-13", in: "9780261103573", want: "9780261103573"},
+		{name: "hyphenated isbn-13", in: "978-0-261-10357-3", want: "9780261103573"},
+		{name: "isbn-10 is converted", in: "0-261-10357-1", want: "9780261103573"},
+		{name: "isbn-10 with X check digit", in: "0-8044-2957-x", want: "9780804429573"},
+		{name: "spaced isbn-10", in: " 0 451 52493 4 ", want: "9780451524935"},
+		{name: "979 isbn-13", in: "979-10-90636-07-1", want: "9791090636071"},
+		{name: "wrong isbn-10 check digit", in: "0261103572", wantErr: ErrCheckDigit},
+		{name: "wrong isbn-13 check digit", in: "9780261103574", wantErr: ErrCheckDigit},
+		{name: "isbn-13 with unknown prefix", in: "9770261103573", wantErr: ErrPrefix13},
+		{name: "X inside isbn-10", in: "02611X3571", wantErr: ErrCharacters},
+		{name: "letters", in: "978026110357A", wantErr: ErrCharacters},
+		{name: "too short", in: "978026110", wantErr: ErrLength},
+		{name: "empty", in: "", wantErr: ErrLength},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Normalize(tt.in)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Normalize(%q) error = %v, want %v", tt.in, err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTo10(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    string
+		wantErr error
+	}{
+		{name: "978 isbn", in: "9780261103573", want: "0261103571"},
+		{name: "X check digit", in: "9780804429573", want: "080442957X"},
+		{name: "979 isbn has none", in: "9791090636071", wantErr: ErrNotBookland},
+		{name: "invalid isbn", in: "9780261103574", wantErr: ErrCheckDigit},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := To10(tt.in)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("To10(%q) error = %v, want %v", tt.in, err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("To10(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
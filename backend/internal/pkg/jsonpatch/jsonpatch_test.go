@@ -0,0 +1,128 @@
+package jsonpatch
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func jsonEqual(t *testing.T, got []byte, want string) bool {
+	t.Helper()
+
+	var gotValue, wantValue any
+	if err := json.Unmarshal(got, &gotValue); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(want), &wantValue); err != nil {
+		t.Fatal(err)
+	}
+
+	return reflect.DeepEqual(gotValue, wantValue)
+}
+
+func TestMergePatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     string
+		patch   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "success replace single field",
+			doc:   `{"title":"1984","author":"Orwel","publish_year":1949}`,
+			patch: `{"author":"George Orwell"}`,
+			want:  `{"title":"1984","author":"George Orwell","publish_year":1949}`,
+		},
+		{
+			name:  "success remove field with null",
+			doc:   `{"title":"1984","author":"George Orwell"}`,
+			patch: `{"author":null}`,
+			want:  `{"title":"1984"}`,
+		},
+		{
+			name:  "success merge nested object",
+			doc:   `{"a":{"b":"c","d":"e"}}`,
+			patch: `{"a":{"d":null,"f":"g"}}`,
+			want:  `{"a":{"b":"c","f":"g"}}`,
+		},
+		{
+			name:    "failed malformed patch",
+			doc:     `{"title":"1984"}`,
+			patch:   `{"title":`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MergePatch([]byte(tt.doc), []byte(tt.patch))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("MergePatch() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !jsonEqual(t, got, tt.want) {
+				t.Errorf("MergePatch() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	doc := `{"title":"1984","author":"Orwel","publish_year":1949,"tags":["dystopia"]}`
+
+	tests := []struct {
+		name    string
+		patch   string
+		want    string
+		wantErr error
+	}{
+		{
+			name:  "success replace after test",
+			patch: `[{"op":"test","path":"/author","value":"Orwel"},{"op":"replace","path":"/author","value":"George Orwell"}]`,
+			want:  `{"title":"1984","author":"George Orwell","publish_year":1949,"tags":["dystopia"]}`,
+		},
+		{
+			name:  "success add and remove array items",
+			patch: `[{"op":"add","path":"/tags/-","value":"classic"},{"op":"add","path":"/tags/0","value":"novel"},{"op":"remove","path":"/tags/1"}]`,
+			want:  `{"title":"1984","author":"Orwel","publish_year":1949,"tags":["novel","classic"]}`,
+		},
+		{
+			name:  "success move and copy",
+			patch: `[{"op":"copy","from":"/title","path":"/original_title"},{"op":"move","from":"/author","path":"/writer"}]`,
+			want:  `{"title":"1984","original_title":"1984","writer":"Orwel","publish_year":1949,"tags":["dystopia"]}`,
+		},
+		{
+			name:    "failed test operation",
+			patch:   `[{"op":"test","path":"/publish_year","value":1950},{"op":"replace","path":"/publish_year","value":1950}]`,
+			wantErr: ErrTestFailed,
+		},
+		{
+			name:    "failed replace missing path",
+			patch:   `[{"op":"replace","path":"/isbn","value":"123"}]`,
+			wantErr: ErrPathNotFound,
+		},
+		{
+			name:    "failed unknown operation",
+			patch:   `[{"op":"rename","path":"/title"}]`,
+			wantErr: ErrInvalidPatch,
+		},
+		{
+			name:    "failed patch is not an array",
+			patch:   `{"title":"Animal Farm"}`,
+			wantErr: ErrInvalidPatch,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Apply([]byte(doc), []byte(tt.patch))
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Apply() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr == nil && !jsonEqual(t, got, tt.want) {
+				t.Errorf("Apply() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package langtag
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var ErrFormat = errors.New("language must be a BCP 47 tag like en, pt-BR or haw")
+
+var tagPattern = regexp.MustCompile(`^[a-z]{2,3}(-[A-Z]{2})?$`)
+
+// Normalize returns the form a language tag is stored in, an ISO 639 language
+// code optionally followed by an ISO 3166 region. Case and the separator are
+// read loosely, so "EN_gb" is "en-GB". Scripts and variants are not taken.
+func Normalize(tag string) (string, error) {
+	language, region, found := strings.Cut(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"), "-")
+
+	normalized := strings.ToLower(language)
+	if found {
+		normalized += "-" + strings.ToUpper(region)
+	}
+	if !tagPattern.MatchString(normalized) {
+		return "", fmt.Errorf("%w: %q", ErrFormat, tag)
+	}
+
+	return normalized, nil
+}
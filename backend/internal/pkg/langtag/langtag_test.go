@@ -0,0 +1,36 @@
+package langtag
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name    string
+		tag     string
+		want    string
+		wantErr error
+	}{
+		{name: "language", tag: "en", want: "en"},
+		{name: "three letter language", tag: "haw", want: "haw"},
+		{name: "language and region", tag: "pt-BR", want: "pt-BR"},
+		{name: "loose case and underscore", tag: " EN_gb ", want: "en-GB"},
+		{name: "script subtag", tag: "zh-Hant", wantErr: ErrFormat},
+		{name: "numeric region", tag: "es-419", wantErr: ErrFormat},
+		{name: "language name", tag: "english", wantErr: ErrFormat},
+		{name: "empty", tag: "", wantErr: ErrFormat},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Normalize(tt.tag)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Normalize(%q) error = %v, want %v", tt.tag, err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.tag, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package pagination
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	DefaultCursorLimit = 20
+	MaxCursorLimit     = 100
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor points at a single row in a keyset ordered listing.
+// Values holds the sort key values of that row (in sort order)
+// and ID is the tie breaker, so the cursor stays stable even
+// when rows are inserted or deleted between requests.
+type Cursor struct {
+	Sort   string   `json:"s"`
+	Values []string `json:"v"`
+	ID     int64    `json:"id"`
+}
+
+type CursorPage struct {
+	After  *Cursor
+	Before *Cursor
+	Limit  int
+}
+
+type CursorMetadata struct {
+	Limit      int     `json:"limit" example:"20"`
+	NextCursor string  `json:"next_cursor,omitempty"`
+	PrevCursor string  `json:"prev_cursor,omitempty"`
+	Next       *Cursor `json:"-"`
+	Prev       *Cursor `json:"-"`
+}
+
+// EncodeCursor serializes and signs the cursor so clients can only
+// hand back cursors issued by us.
+func EncodeCursor(secret []byte, c Cursor) (string, error) {
+	payload, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(sign(secret, payload)), nil
+}
+
+func DecodeCursor(secret []byte, token string) (Cursor, error) {
+	var c Cursor
+
+	encodedPayload, encodedSig, ok := strings.Cut(token, ".")
+	if !ok {
+		return c, ErrInvalidCursor
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return c, ErrInvalidCursor
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(encodedSig)
+	if err != nil {
+		return c, ErrInvalidCursor
+	}
+
+	if !hmac.Equal(sig, sign(secret, payload)) {
+		return c, ErrInvalidCursor
+	}
+
+	err = json.Unmarshal(payload, &c)
+	if err != nil {
+		return c, ErrInvalidCursor
+	}
+
+	return c, nil
+}
+
+func sign(secret []byte, payload []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+func ParseCursorRequest(r *http.Request, secret []byte) (CursorPage, error) {
+	var page CursorPage
+
+	limit := r.URL.Query().Get("limit")
+	if limit == "" {
+		limit = strconv.Itoa(DefaultCursorLimit)
+	}
+
+	limitInt, err := strconv.Atoi(limit)
+	if err != nil {
+		return page, fmt.Errorf("failed to parse limit params: %v", err)
+	}
+	if limitInt <= 0 || limitInt > MaxCursorLimit {
+		return page, fmt.Errorf("limit params must be between 1 and %d", MaxCursorLimit)
+	}
+	page.Limit = limitInt
+
+	after := r.URL.Query().Get("after")
+	before := r.URL.Query().Get("before")
+	if after != "" && before != "" {
+		return page, fmt.Errorf("after and before params can not be used together")
+	}
+
+	if after != "" {
+		c, err := DecodeCursor(secret, after)
+		if err != nil {
+			return page, fmt.Errorf("failed to parse after params: %v", err)
+		}
+		page.After = &c
+	}
+
+	if before != "" {
+		c, err := DecodeCursor(secret, before)
+		if err != nil {
+			return page, fmt.Errorf("failed to parse before params: %v", err)
+		}
+		page.Before = &c
+	}
+
+	return page, nil
+}
+
+// Encode fills the opaque next/prev cursor strings from the raw cursors.
+func (m *CursorMetadata) Encode(secret []byte) error {
+	if m.Next != nil {
+		token, err := EncodeCursor(secret, *m.Next)
+		if err != nil {
+			return err
+		}
+		m.NextCursor = token
+	}
+
+	if m.Prev != nil {
+		token, err := EncodeCursor(secret, *m.Prev)
+		if err != nil {
+			return err
+		}
+		m.PrevCursor = token
+	}
+
+	return nil
+}
+
+// Links builds an RFC 8288 Link header value pointing at the next and
+// previous pages, keeping every other query param of the current request.
+func (m CursorMetadata) Links(u *url.URL) string {
+	var links []string
+
+	build := func(param string, token string, rel string) {
+		query := u.Query()
+		query.Del("after")
+		query.Del("before")
+		query.Set(param, token)
+		query.Set("limit", strconv.Itoa(m.Limit))
+
+		link := url.URL{Path: u.Path, RawQuery: query.Encode()}
+		links = append(links, fmt.Sprintf(`<%s>; rel="%s"`, link.String(), rel))
+	}
+
+	if m.NextCursor != "" {
+		build("after", m.NextCursor, "next")
+	}
+	if m.PrevCursor != "" {
+		build("before", m.PrevCursor, "prev")
+	}
+
+	return strings.Join(links, ", ")
+}
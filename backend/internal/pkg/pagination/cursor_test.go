@@ -0,0 +1,77 @@
+package pagination
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestDecodeCursor(t *testing.T) {
+	secret := []byte("secret")
+	cursor := Cursor{Sort: "title:asc,id:asc", Values: []string{"The Hobbit"}, ID: 8}
+
+	token, err := EncodeCursor(secret, cursor)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		secret  []byte
+		token   string
+		want    Cursor
+		wantErr bool
+	}{
+		{
+			name:   "success decode issued cursor",
+			secret: secret,
+			token:  token,
+			want:   cursor,
+		},
+		{
+			name:    "failed decode cursor signed with another secret",
+			secret:  []byte("another secret"),
+			token:   token,
+			wantErr: true,
+		},
+		{
+			name:    "failed decode tampered cursor",
+			secret:  secret,
+			token:   "eyJzIjoiaWQ6YXNjIiwidiI6bnVsbCwiaWQiOjF9." + token[len(token)-43:],
+			wantErr: true,
+		},
+		{
+			name:    "failed decode malformed cursor",
+			secret:  secret,
+			token:   "not-a-cursor",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeCursor(tt.secret, tt.token)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DecodeCursor() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DecodeCursor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCursorMetadata_Links(t *testing.T) {
+	u, _ := url.Parse("/books?search=tolkien&after=old&limit=5")
+
+	meta := CursorMetadata{
+		Limit:      5,
+		NextCursor: "next",
+		PrevCursor: "prev",
+	}
+
+	want := `</books?after=next&limit=5&search=tolkien>; rel="next", </books?before=prev&limit=5&search=tolkien>; rel="prev"`
+	if got := meta.Links(u); got != want {
+		t.Errorf("CursorMetadata.Links() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,94 @@
+package xauth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIsAdmin(t *testing.T) {
+	tests := []struct {
+		name   string
+		token  string
+		header string
+		want   bool
+	}{
+		{
+			name:   "admin with matching bearer token",
+			token:  "secret",
+			header: "Bearer secret",
+			want:   true,
+		},
+		{
+			name:   "not admin with wrong bearer token",
+			token:  "secret",
+			header: "Bearer guess",
+			want:   false,
+		},
+		{
+			name:   "not admin without bearer scheme",
+			token:  "secret",
+			header: "secret",
+			want:   false,
+		},
+		{
+			name:   "not admin when no token is configured",
+			token:  "",
+			header: "Bearer ",
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/books", nil)
+			r.Header.Set("Authorization", tt.header)
+
+			if got := IsAdmin(r, tt.token); got != tt.want {
+				t.Errorf("IsAdmin() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestActor(t *testing.T) {
+	tests := []struct {
+		name       string
+		header     string
+		remoteAddr string
+		want       string
+	}{
+		{
+			name:       "admin with matching bearer token",
+			header:     "Bearer secret",
+			remoteAddr: "203.0.113.7:5123",
+			want:       "admin",
+		},
+		{
+			name:       "anonymous with client ip",
+			remoteAddr: "203.0.113.7:5123",
+			want:       "anonymous@203.0.113.7",
+		},
+		{
+			name:       "anonymous with ip set by real ip middleware",
+			remoteAddr: "198.51.100.2",
+			want:       "anonymous@198.51.100.2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/books", nil)
+			r.Header.Set("Authorization", tt.header)
+			r.RemoteAddr = tt.remoteAddr
+
+			var got string
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = ActorFromContext(r.Context())
+			})
+			Actor("secret")(next).ServeHTTP(httptest.NewRecorder(), r)
+
+			if got != tt.want {
+				t.Errorf("Actor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
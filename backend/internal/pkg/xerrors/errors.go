@@ -5,4 +5,7 @@ import "fmt"
 var (
 	ErrDataNotFound = fmt.Errorf("data not found")
 	ErrInvalidID    = fmt.Errorf("invalid id")
+
+	ErrVersionMismatch = fmt.Errorf("data has been modified, version does not match")
+	ErrMissingIfMatch  = fmt.Errorf("If-Match header is required")
 )
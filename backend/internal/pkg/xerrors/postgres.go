@@ -0,0 +1,27 @@
+package xerrors
+
+import (
+	"errors"
+
+	"github.com/lib/pq"
+)
+
+const (
+	// PQUniqueViolation is the postgres error code of a unique constraint violation.
+	PQUniqueViolation = "23505"
+	// PQForeignKeyViolation is the postgres error code of a foreign key violation.
+	PQForeignKeyViolation = "23503"
+)
+
+// IsUniqueViolation reports whether err is a postgres unique violation, of
+// the given constraint or unique index when it is not empty.
+func IsUniqueViolation(err error, constraint string) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == PQUniqueViolation && (constraint == "" || pqErr.Constraint == constraint)
+}
+
+// IsForeignKeyViolation reports whether err is a postgres foreign key violation.
+func IsForeignKeyViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == PQForeignKeyViolation
+}
@@ -43,6 +43,32 @@ func (e AuthError) Error() string {
 	return e.Err.Error()
 }
 
+// ConflictError is a write clashing with data already stored, such as a
+// unique value taken by another row.
+type ConflictError struct {
+	Err error
+}
+
+func (e ConflictError) Error() string {
+	return e.Err.Error()
+}
+
+type PreconditionFailedError struct {
+	Err error
+}
+
+func (e PreconditionFailedError) Error() string {
+	return e.Err.Error()
+}
+
+type PreconditionRequiredError struct {
+	Err error
+}
+
+func (e PreconditionRequiredError) Error() string {
+	return e.Err.Error()
+}
+
 func ParseErrorTypeToCodeInt(err error) int {
 	switch {
 	case errors.As(err, &LogicError{}): //200
@@ -51,6 +77,12 @@ func ParseErrorTypeToCodeInt(err error) int {
 		return http.StatusBadRequest
 	case errors.As(err, &AuthError{}): //401
 		return http.StatusUnauthorized
+	case errors.As(err, &ConflictError{}): //409
+		return http.StatusConflict
+	case errors.As(err, &PreconditionFailedError{}): //412
+		return http.StatusPreconditionFailed
+	case errors.As(err, &PreconditionRequiredError{}): //428
+		return http.StatusPreconditionRequired
 	case errors.As(err, &ServerError{}): //500
 		return http.StatusInternalServerError
 	default: //500
@@ -0,0 +1,82 @@
+package xhttp
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidETag = errors.New("invalid etag")
+
+// ETag formats a row version and a hash of the representation served as a
+// strong entity tag, "<version>-<hash>". The version alone misses whatever
+// is written without bumping it, e.g. the genres of a book, the hash makes
+// If-None-Match see those changes too.
+func ETag(version int64, representation any) string {
+	hash := sha256.New()
+	// a representation that can't be encoded has no other tag to fall back
+	// on, what was hashed of it still changes with the version
+	_ = json.NewEncoder(hash).Encode(representation)
+
+	return fmt.Sprintf(`"%d-%x"`, version, hash.Sum(nil)[:8])
+}
+
+// ParseETagVersion reads the row version back from a strong entity tag,
+// as sent in an If-Match header, ignoring the representation hash since
+// writes only ever check the version. Weak tags never match a write
+// precondition.
+func ParseETagVersion(tag string) (int64, error) {
+	tag = strings.TrimSpace(tag)
+	if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		return 0, ErrInvalidETag
+	}
+
+	value, _, _ := strings.Cut(tag[1:len(tag)-1], "-")
+	version, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, ErrInvalidETag
+	}
+
+	return version, nil
+}
+
+// ParseIfMatch reads the row versions an If-Match header accepts, a comma
+// separated list of tags. wildcard is set for "*", which matches whatever version
+// is current. Tags we never issued are skipped as they can't match, and
+// ErrInvalidETag is returned when none is left.
+func ParseIfMatch(header string) (versions []int64, wildcard bool, err error) {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" {
+			return nil, true, nil
+		}
+
+		version, err := ParseETagVersion(tag)
+		if err != nil {
+			continue
+		}
+		versions = append(versions, version)
+	}
+
+	if len(versions) == 0 {
+		return nil, false, ErrInvalidETag
+	}
+
+	return versions, false, nil
+}
+
+// MatchETag reports whether an If-None-Match header value matches the etag,
+// using the weak comparison RFC 9110 asks for on GET.
+func MatchETag(header string, etag string) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,159 @@
+package xhttp
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseETagVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		tag     string
+		want    int64
+		wantErr bool
+	}{
+		{
+			name: "success parse strong etag",
+			tag:  `"3"`,
+			want: 3,
+		},
+		{
+			name: "success parse strong etag with representation hash",
+			tag:  `"3-1f2e3d4c5b6a7988"`,
+			want: 3,
+		},
+		{
+			name:    "failed parse weak etag",
+			tag:     `W/"3"`,
+			wantErr: true,
+		},
+		{
+			name:    "failed parse unquoted etag",
+			tag:     "3",
+			wantErr: true,
+		},
+		{
+			name:    "failed parse negative version",
+			tag:     `"-3"`,
+			wantErr: true,
+		},
+		{
+			name:    "failed parse non numeric etag",
+			tag:     `"abc"`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseETagVersion(tt.tag)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseETagVersion() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseETagVersion() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseIfMatch(t *testing.T) {
+	tests := []struct {
+		name         string
+		header       string
+		want         []int64
+		wantWildcard bool
+		wantErr      bool
+	}{
+		{
+			name:   "success parse single etag",
+			header: `"3"`,
+			want:   []int64{3},
+		},
+		{
+			name:   "success parse list skipping tags never issued",
+			header: `"2", W/"3", "abc", "4"`,
+			want:   []int64{2, 4},
+		},
+		{
+			name:         "success parse wildcard",
+			header:       "*",
+			wantWildcard: true,
+		},
+		{
+			name:    "failed parse list without a strong etag",
+			header:  `W/"3", "abc"`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotWildcard, err := ParseIfMatch(tt.header)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseIfMatch() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) || gotWildcard != tt.wantWildcard {
+				t.Errorf("ParseIfMatch() = %v, %v, want %v, %v", got, gotWildcard, tt.want, tt.wantWildcard)
+			}
+		})
+	}
+}
+
+func TestETag(t *testing.T) {
+	type representation struct {
+		Version int64    `json:"version"`
+		Genres  []string `json:"genres"`
+	}
+
+	etag := ETag(3, representation{Version: 3})
+	version, err := ParseETagVersion(etag)
+	if err != nil || version != 3 {
+		t.Errorf("ParseETagVersion(ETag()) = %v, %v, want 3", version, err)
+	}
+	if ETag(3, representation{Version: 3}) != etag {
+		t.Errorf("ETag() differs for the same representation")
+	}
+	if ETag(3, representation{Version: 3, Genres: []string{"fantasy"}}) == etag {
+		t.Errorf("ETag() = %v for a representation changed without a version bump", etag)
+	}
+}
+
+func TestMatchETag(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   bool
+	}{
+		{
+			name:   "match same etag",
+			header: `"3"`,
+			want:   true,
+		},
+		{
+			name:   "match weak etag in list",
+			header: `"1", W/"3"`,
+			want:   true,
+		},
+		{
+			name:   "match wildcard",
+			header: "*",
+			want:   true,
+		},
+		{
+			name:   "no match stale etag",
+			header: `"2"`,
+		},
+		{
+			name:   "no match empty header",
+			header: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MatchETag(tt.header, `"3"`); got != tt.want {
+				t.Errorf("MatchETag() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package xhttp
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// RealIP replaces the request RemoteAddr with the client address forwarded by
+// a trusted proxy. X-Forwarded-For and X-Real-IP are only read when the
+// connection comes from one of the trusted prefixes, anyone can send them, and
+// X-Forwarded-For is read from the right, skipping the trusted hops, so
+// addresses a client prepended are never taken. Nothing is trusted when
+// trusted is empty.
+func RealIP(trusted []netip.Prefix) func(http.Handler) http.Handler {
+	isTrusted := func(addr netip.Addr) bool {
+		for _, prefix := range trusted {
+			if prefix.Contains(addr.Unmap()) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			peer, err := parseAddr(r.RemoteAddr)
+			if err != nil || !isTrusted(peer) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if forwarded := r.Header.Values("X-Forwarded-For"); len(forwarded) > 0 {
+				hops := strings.Split(strings.Join(forwarded, ","), ",")
+				for i := len(hops) - 1; i >= 0; i-- {
+					addr, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+					if err != nil {
+						// a hop we can't read ends the trusted chain
+						break
+					}
+					if !isTrusted(addr) {
+						r.RemoteAddr = addr.Unmap().String()
+						break
+					}
+				}
+			} else if addr, err := netip.ParseAddr(strings.TrimSpace(r.Header.Get("X-Real-IP"))); err == nil {
+				r.RemoteAddr = addr.Unmap().String()
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// parseAddr reads the address of a RemoteAddr, with or without a port.
+func parseAddr(remoteAddr string) (netip.Addr, error) {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return netip.Addr{}, err
+	}
+
+	return addr.Unmap(), nil
+}
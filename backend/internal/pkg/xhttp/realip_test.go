@@ -0,0 +1,78 @@
+package xhttp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+)
+
+func TestRealIP(t *testing.T) {
+	trusted := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}
+
+	tests := []struct {
+		name           string
+		trusted        []netip.Prefix
+		remoteAddr     string
+		forwardedFor   string
+		realIP         string
+		wantRemoteAddr string
+	}{
+		{
+			name:           "untrusted peer keeps its address",
+			trusted:        trusted,
+			remoteAddr:     "203.0.113.7:5123",
+			forwardedFor:   "198.51.100.2",
+			wantRemoteAddr: "203.0.113.7:5123",
+		},
+		{
+			name:           "nothing trusted by default",
+			remoteAddr:     "10.0.0.2:5123",
+			forwardedFor:   "198.51.100.2",
+			wantRemoteAddr: "10.0.0.2:5123",
+		},
+		{
+			name:           "trusted proxy forwards the client",
+			trusted:        trusted,
+			remoteAddr:     "10.0.0.2:5123",
+			forwardedFor:   "198.51.100.2",
+			wantRemoteAddr: "198.51.100.2",
+		},
+		{
+			name:           "client prepended address is skipped",
+			trusted:        trusted,
+			remoteAddr:     "10.0.0.2:5123",
+			forwardedFor:   "192.0.2.1, 198.51.100.2, 10.0.0.3",
+			wantRemoteAddr: "198.51.100.2",
+		},
+		{
+			name:           "trusted proxy sets x real ip",
+			trusted:        trusted,
+			remoteAddr:     "10.0.0.2:5123",
+			realIP:         "198.51.100.2",
+			wantRemoteAddr: "198.51.100.2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/books", nil)
+			r.RemoteAddr = tt.remoteAddr
+			if tt.forwardedFor != "" {
+				r.Header.Set("X-Forwarded-For", tt.forwardedFor)
+			}
+			if tt.realIP != "" {
+				r.Header.Set("X-Real-IP", tt.realIP)
+			}
+
+			var got string
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.RemoteAddr
+			})
+			RealIP(tt.trusted)(next).ServeHTTP(httptest.NewRecorder(), r)
+
+			if got != tt.wantRemoteAddr {
+				t.Errorf("RealIP() RemoteAddr = %v, want %v", got, tt.wantRemoteAddr)
+			}
+		})
+	}
+}
@@ -1,7 +1,6 @@
 package xhttp
 
 import (
-	"byfood-app/internal/pkg/pagination"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -17,10 +16,10 @@ type BaseResponse struct {
 }
 
 type BaseListResponse struct {
-	Error    string              `json:"error,omitempty"`
-	Message  string              `json:"message,omitempty"`
-	Data     any                 `json:"data,omitempty"`
-	Metadata pagination.Metadata `json:"metadata,omitempty"`
+	Error    string `json:"error,omitempty"`
+	Message  string `json:"message,omitempty"`
+	Data     any    `json:"data,omitempty"`
+	Metadata any    `json:"metadata,omitempty"`
 }
 
 func BindJSONRequest(request *http.Request, destination any) error {
@@ -1,9 +1,16 @@
 package server
 
 import (
+	"byfood-app/internal/author"
 	"byfood-app/internal/book"
 	"byfood-app/internal/config"
 	"byfood-app/internal/core"
+	"byfood-app/internal/edition"
+	"byfood-app/internal/genre"
+	"byfood-app/internal/idempotency"
+	"byfood-app/internal/pkg/xauth"
+	"byfood-app/internal/pkg/xhttp"
+	"byfood-app/internal/tag"
 	"byfood-app/internal/urlcleaner"
 	"context"
 	"errors"
@@ -12,6 +19,8 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -34,22 +43,45 @@ func StartServer(ctx context.Context) {
 	// setup server
 	var srv http.Server
 
+	// wiring the repositories shared by the routes and the background jobs
+	bookRepo := book.NewSQLRepo(deps)
+	idempotencyRepo := idempotency.NewSQLRepo(deps)
+
 	// register routes
-	routes := InitRoutes(ctx, deps)
+	routes := InitRoutes(ctx, deps, bookRepo, idempotencyRepo)
 	srv.Handler = routes
 
+	// start background jobs, they stop once the server shuts down
+	jobsCtx, stopJobs := context.WithCancel(ctx)
+	var jobs sync.WaitGroup
+	if cfg.BookPurgeAfter > 0 {
+		purger := book.NewPurger(deps, bookRepo)
+		jobs.Add(1)
+		go func() {
+			defer jobs.Done()
+			purger.Run(jobsCtx)
+		}()
+	}
+	janitor := idempotency.NewJanitor(deps, idempotencyRepo)
+	jobs.Add(1)
+	go func() {
+		defer jobs.Done()
+		janitor.Run(jobsCtx)
+	}()
+
 	// setup graceful shutdown
 	idleConnectionClosed := make(chan struct{})
 	go func() {
 		sigint := make(chan os.Signal, 1)
-		signal.Notify(sigint, os.Interrupt)
+		signal.Notify(sigint, os.Interrupt, syscall.SIGTERM)
 		<-sigint
 
 		deps.Logger.InfoContext(ctx, "server is shutting down")
+		stopJobs()
 
 		ctx := context.Background()
 		ctxCncl, cancel := context.WithTimeout(ctx, time.Duration(10)*time.Second)
-		cancel()
+		defer cancel()
 		// We received an interrupt signal, shut down.
 		if err := srv.Shutdown(ctxCncl); err != nil {
 			// Error from closing listeners, or context timeout:
@@ -66,50 +98,114 @@ func StartServer(ctx context.Context) {
 		deps.Logger.ErrorContext(ctx, "failed to listen and serve", slog.Any("error", err))
 		os.Exit(1)
 	}
+
+	<-idleConnectionClosed
+	jobs.Wait()
 }
 
-func InitRoutes(ctx context.Context, deps *core.Dependency) http.Handler {
+func InitRoutes(ctx context.Context, deps *core.Dependency, bookRepo book.RepositoryInterface, idempotencyRepo idempotency.RepositoryInterface) http.Handler {
 	// wiring shared packages
 
 	// wiring repository layer
-	bookRepo := book.NewSQLRepo(deps)
+	authorRepo := author.NewSQLRepo(deps)
+	genreRepo := genre.NewSQLRepo(deps)
+	tagRepo := tag.NewSQLRepo(deps)
+	editionRepo := edition.NewSQLRepo(deps)
 
 	// wiring logic layer
 	bookLogic := book.NewBookLogic(deps, bookRepo)
+	authorLogic := author.NewAuthorLogic(deps, authorRepo)
+	genreLogic := genre.NewGenreLogic(deps, genreRepo)
+	tagLogic := tag.NewTagLogic(deps, tagRepo)
+	editionLogic := edition.NewEditionLogic(deps, editionRepo)
 	urlCleanerLogic := urlcleaner.NewURLCleanerLogic(deps)
 
 	// wiring handler layer
 	bookHandler := book.NewHTTPHandler(deps, bookLogic)
+	authorHandler := author.NewHTTPHandler(deps, authorLogic)
+	genreHandler := genre.NewHTTPHandler(deps, genreLogic)
+	tagHandler := tag.NewHTTPHandler(deps, tagLogic)
+	editionHandler := edition.NewHTTPHandler(deps, editionLogic)
 	urlCleanerHandler := urlcleaner.NewURLCleanerHandler(deps, urlCleanerLogic)
 
 	r := chi.NewRouter()
 
 	// base middleware stack
 	r.Use(middleware.RequestID)
-	r.Use(middleware.RealIP)
+	r.Use(xhttp.RealIP(deps.TrustedProxies))
 	r.Use(middleware.Recoverer)
 	r.Use(httplog.RequestLogger(deps.Logger, &httplog.Options{}))
+	r.Use(xauth.Actor(deps.AdminToken))
 
 	// basic CORS
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{"*"},
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
-		ExposedHeaders:   []string{"Link"},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "If-Match", "If-None-Match", idempotency.HeaderKey},
+		ExposedHeaders:   []string{"Link", "ETag", idempotency.HeaderReplayed},
 		AllowCredentials: false,
 		MaxAge:           300, // Maximum value not ignored by any of major browsers
 	}))
 
+	// replay retried writes sent with an Idempotency-Key
+	r.Use(idempotency.Middleware(deps, idempotencyRepo))
+
 	// setup routes
 	// docs routes
 	r.HandleFunc("/swagger/*", httpSwagger.WrapHandler)
 
 	// book routes
 	r.Get("/books", bookHandler.GetBooks)
+	r.Get("/books/suggest", bookHandler.SuggestBooks)
+	r.With(xauth.RequireAdmin(deps.AdminToken)).Get("/books/trash", bookHandler.GetTrashBooks)
+	r.Get("/books/export", bookHandler.ExportBooks)
+	r.Get("/books/isbn/{isbn}", bookHandler.GetBookByISBN)
+	r.With(xauth.RequireAdmin(deps.AdminToken)).Get("/books/duplicates", bookHandler.GetBookDuplicates)
 	r.Get("/books/{id}", bookHandler.GetBookByID)
+	r.Get("/books/{id}/history", bookHandler.GetBookHistory)
+	r.Get("/books/{id}/history/{rev}", bookHandler.GetBookRevision)
+	r.Get("/books/{id}/snapshots", bookHandler.GetBookSnapshots)
 	r.Post("/books", bookHandler.StoreBook)
+	r.Post("/books/import", bookHandler.ImportBooks)
+	r.Post("/books/batch", bookHandler.BatchBooks)
 	r.Put("/books/{id}", bookHandler.UpdateBook)
+	r.Patch("/books/{id}", bookHandler.PatchBook)
 	r.Delete("/books/{id}", bookHandler.DeleteBook)
+	r.With(xauth.RequireAdmin(deps.AdminToken)).Post("/books/{id}/restore", bookHandler.RestoreBook)
+	r.Post("/books/{id}/revert", bookHandler.RevertBook)
+	r.Put("/books/{id}/identifiers", bookHandler.SetBookIdentifiers)
+	r.With(xauth.RequireAdmin(deps.AdminToken)).Post("/books/{id}/merge", bookHandler.MergeBooks)
+	r.Put("/books/{id}/genres", genreHandler.SetBookGenres)
+	r.Put("/books/{id}/tags", tagHandler.SetBookTags)
+	r.Get("/books/{id}/editions", editionHandler.GetEditions)
+	r.Get("/books/{id}/editions/{edition_id}", editionHandler.GetEditionByID)
+	r.Post("/books/{id}/editions", editionHandler.StoreEdition)
+	r.Put("/books/{id}/editions/{edition_id}", editionHandler.UpdateEdition)
+	r.Delete("/books/{id}/editions/{edition_id}", editionHandler.DeleteEdition)
+
+	// author routes
+	r.Get("/authors", authorHandler.GetAuthors)
+	r.With(xauth.RequireAdmin(deps.AdminToken)).Get("/authors/duplicates", authorHandler.GetAuthorDuplicates)
+	r.Get("/authors/{id}", authorHandler.GetAuthorByID)
+	r.Get("/authors/{id}/books", bookHandler.GetAuthorBooks)
+	r.Post("/authors", authorHandler.StoreAuthor)
+	r.Put("/authors/{id}", authorHandler.UpdateAuthor)
+	r.Delete("/authors/{id}", authorHandler.DeleteAuthor)
+	r.With(xauth.RequireAdmin(deps.AdminToken)).Post("/authors/{id}/merge", authorHandler.MergeAuthors)
+
+	// genre routes
+	r.Get("/genres", genreHandler.GetGenres)
+	r.Get("/genres/{id}", genreHandler.GetGenreByID)
+	r.Post("/genres", genreHandler.StoreGenre)
+	r.Put("/genres/{id}", genreHandler.UpdateGenre)
+	r.Delete("/genres/{id}", genreHandler.DeleteGenre)
+
+	// tag routes
+	r.Get("/tags", tagHandler.GetTags)
+	r.Get("/tags/{id}", tagHandler.GetTagByID)
+	r.Post("/tags", tagHandler.StoreTag)
+	r.Put("/tags/{id}", tagHandler.UpdateTag)
+	r.Delete("/tags/{id}", tagHandler.DeleteTag)
 
 	// url cleanup routes
 	r.Post("/url/cleanup", urlCleanerHandler.CleanURL)
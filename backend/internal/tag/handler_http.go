@@ -0,0 +1,313 @@
+package tag
+
+import (
+	"byfood-app/internal/core"
+	"byfood-app/internal/model"
+	"byfood-app/internal/pkg/xerrors"
+	"byfood-app/internal/pkg/xhttp"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type TagHandler struct {
+	deps  *core.Dependency
+	logic LogicInterface
+}
+
+func NewHTTPHandler(deps *core.Dependency, logic LogicInterface) *TagHandler {
+	return &TagHandler{
+		deps:  deps,
+		logic: logic,
+	}
+}
+
+// GetTags godoc
+// @Summary List tags, most used first
+// @Tags tags
+// @Produce json
+// @Param search query string false "only tags starting with it"
+// @Param limit query integer false "max tags, default 20, max 100"
+// @Success 200 {object} xhttp.BaseResponse{data=[]model.Tag}
+// @Router /tags [get]
+func (h *TagHandler) GetTags(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var limit int
+	if val := r.URL.Query().Get("limit"); val != "" {
+		limitInt, err := strconv.Atoi(val)
+		if err != nil {
+			xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+				Error:   err.Error(),
+				Message: "failed to parse limit parameter",
+			}, http.StatusBadRequest)
+			return
+		}
+		limit = limitInt
+	}
+
+	data, err := h.logic.GetTags(ctx, model.TagSearchParams{
+		Search: r.URL.Query().Get("search"),
+		Limit:  limit,
+	})
+	if err != nil {
+		h.deps.Logger.ErrorContext(ctx, "failed to get tags", slog.Any("error", err))
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to get tags",
+		}, xerrors.ParseErrorTypeToCodeInt(err))
+		return
+	}
+
+	xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+		Data:    data,
+		Message: "tags fetched",
+	}, http.StatusOK)
+}
+
+// GetTagByID godoc
+// @Summary Get a tag data by its ID
+// @Tags tags
+// @Produce json
+// @Param id path integer true "tag ID"
+// @Success 200 {object} xhttp.BaseResponse{data=model.Tag}
+// @Router /tags/{id} [get]
+func (h *TagHandler) GetTagByID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// get and validate id param
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   xerrors.ErrInvalidID.Error(),
+			Message: xerrors.ErrInvalidID.Error(),
+		}, http.StatusBadRequest)
+		return
+	}
+	idParam, err := strconv.Atoi(id)
+	if err != nil {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to parse id parameter",
+		}, http.StatusBadRequest)
+		return
+	}
+
+	data, err := h.logic.GetTagByID(ctx, int64(idParam))
+	if err != nil {
+		h.deps.Logger.ErrorContext(ctx, "failed to get tag data by id", slog.Any("error", err))
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to get tag data",
+		}, xerrors.ParseErrorTypeToCodeInt(err))
+		return
+	}
+
+	xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+		Data:    data,
+		Message: "tag data fetched",
+	}, http.StatusOK)
+}
+
+// StoreTag godoc
+// @Summary Store new tag data, return stored data
+// @Description Names are lower cased with single spaces.
+// @Tags tags
+// @Produce json
+// @Param data body model.StoreTagRequest true "tag data"
+// @Success 200 {object} xhttp.BaseResponse{data=model.Tag}
+// @Router /tags [post]
+func (h *TagHandler) StoreTag(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// parse request body
+	var payload model.StoreTagRequest
+	err := xhttp.BindJSONRequest(r, &payload)
+	if err != nil {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to parse request body",
+		}, http.StatusBadRequest)
+		return
+	}
+
+	data, err := h.logic.StoreTag(ctx, model.Tag{
+		Name: payload.Name,
+	})
+	if err != nil {
+		h.deps.Logger.ErrorContext(ctx, "failed to store tag data", slog.Any("error", err))
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to store tag data",
+		}, xerrors.ParseErrorTypeToCodeInt(err))
+		return
+	}
+
+	xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+		Data:    data,
+		Message: "tag data stored",
+	}, http.StatusOK)
+}
+
+// UpdateTag godoc
+// @Summary Update tag data by ID, return updated data
+// @Description The new name shows on every book carrying the tag.
+// @Tags tags
+// @Produce json
+// @Param id path integer true "tag ID"
+// @Param data body model.UpdateTagRequest true "tag data"
+// @Success 200 {object} xhttp.BaseResponse{data=model.Tag}
+// @Router /tags/{id} [put]
+func (h *TagHandler) UpdateTag(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// get and validate id param
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   xerrors.ErrInvalidID.Error(),
+			Message: xerrors.ErrInvalidID.Error(),
+		}, http.StatusBadRequest)
+		return
+	}
+	idParam, err := strconv.Atoi(id)
+	if err != nil {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to parse id parameter",
+		}, http.StatusBadRequest)
+		return
+	}
+
+	// parse request body
+	var payload model.UpdateTagRequest
+	err = xhttp.BindJSONRequest(r, &payload)
+	if err != nil {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to parse request body",
+		}, http.StatusBadRequest)
+		return
+	}
+
+	data, err := h.logic.UpdateTag(ctx, model.Tag{
+		ID:   int64(idParam),
+		Name: payload.Name,
+	})
+	if err != nil {
+		h.deps.Logger.ErrorContext(ctx, "failed to update tag data", slog.Any("error", err))
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to update tag data",
+		}, xerrors.ParseErrorTypeToCodeInt(err))
+		return
+	}
+
+	xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+		Data:    data,
+		Message: "tag data updated",
+	}, http.StatusOK)
+}
+
+// DeleteTag godoc
+// @Summary Delete tag data by ID
+// @Description The tag is taken off its books.
+// @Tags tags
+// @Produce json
+// @Param id path integer true "tag ID"
+// @Success 200 {object} xhttp.BaseResponse{message=string}
+// @Router /tags/{id} [delete]
+func (h *TagHandler) DeleteTag(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// get and validate id param
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   xerrors.ErrInvalidID.Error(),
+			Message: xerrors.ErrInvalidID.Error(),
+		}, http.StatusBadRequest)
+		return
+	}
+	idParam, err := strconv.Atoi(id)
+	if err != nil {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to parse id parameter",
+		}, http.StatusBadRequest)
+		return
+	}
+
+	err = h.logic.DeleteTag(ctx, int64(idParam))
+	if err != nil {
+		h.deps.Logger.ErrorContext(ctx, "failed to delete tag data", slog.Any("error", err))
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to delete tag data",
+		}, xerrors.ParseErrorTypeToCodeInt(err))
+		return
+	}
+
+	xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+		Message: "tag data deleted",
+	}, http.StatusOK)
+}
+
+// SetBookTags godoc
+// @Summary Replace the tags of a book by ID, return them
+// @Description Unknown tags are added. Tags are not part of the book version, so this neither bumps the version nor adds a revision. An empty list clears them.
+// @Tags tags
+// @Produce json
+// @Param id path integer true "book ID"
+// @Param data body model.SetBookTagsRequest true "tags of the book"
+// @Success 200 {object} xhttp.BaseResponse{data=[]string}
+// @Router /books/{id}/tags [put]
+func (h *TagHandler) SetBookTags(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// get and validate id param
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   xerrors.ErrInvalidID.Error(),
+			Message: xerrors.ErrInvalidID.Error(),
+		}, http.StatusBadRequest)
+		return
+	}
+	idParam, err := strconv.Atoi(id)
+	if err != nil {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to parse id parameter",
+		}, http.StatusBadRequest)
+		return
+	}
+
+	// parse request body
+	var payload model.SetBookTagsRequest
+	err = xhttp.BindJSONRequest(r, &payload)
+	if err != nil {
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to parse request body",
+		}, http.StatusBadRequest)
+		return
+	}
+
+	data, err := h.logic.SetBookTags(ctx, int64(idParam), payload.Tags)
+	if err != nil {
+		h.deps.Logger.ErrorContext(ctx, "failed to set book tags", slog.Any("error", err))
+		xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+			Error:   err.Error(),
+			Message: "failed to set book tags",
+		}, xerrors.ParseErrorTypeToCodeInt(err))
+		return
+	}
+
+	xhttp.SendJSONResponse(w, xhttp.BaseResponse{
+		Data:    data,
+		Message: "book tags updated",
+	}, http.StatusOK)
+}
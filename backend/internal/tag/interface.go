@@ -0,0 +1,26 @@
+package tag
+
+import (
+	"byfood-app/internal/model"
+	"context"
+)
+
+//go:generate go run go.uber.org/mock/mockgen@latest -source=interface.go -destination=mock_interface.go -package=tag
+type RepositoryInterface interface {
+	GetTags(ctx context.Context, params model.TagSearchParams) ([]model.Tag, error)
+	GetTagByID(ctx context.Context, id int64) (model.Tag, error)
+	StoreTag(ctx context.Context, data model.Tag) (model.Tag, error)
+	UpdateTag(ctx context.Context, data model.Tag) (model.Tag, error)
+	DeleteTag(ctx context.Context, id int64) error
+	SetBookTags(ctx context.Context, bookID int64, names []string) ([]string, error)
+}
+
+//go:generate go run go.uber.org/mock/mockgen@latest -source=interface.go -destination=mock_interface.go -package=tag
+type LogicInterface interface {
+	GetTags(ctx context.Context, params model.TagSearchParams) ([]model.Tag, error)
+	GetTagByID(ctx context.Context, id int64) (model.Tag, error)
+	StoreTag(ctx context.Context, data model.Tag) (model.Tag, error)
+	UpdateTag(ctx context.Context, data model.Tag) (model.Tag, error)
+	DeleteTag(ctx context.Context, id int64) error
+	SetBookTags(ctx context.Context, bookID int64, names []string) ([]string, error)
+}
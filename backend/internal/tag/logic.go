@@ -0,0 +1,147 @@
+package tag
+
+import (
+	"byfood-app/internal/core"
+	"byfood-app/internal/model"
+	"byfood-app/internal/pkg/xerrors"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+)
+
+const (
+	defaultTagLimit = 20
+	maxTagLimit     = 100
+)
+
+const maxBookTags = 30
+
+type TagLogic struct {
+	deps *core.Dependency
+	repo RepositoryInterface
+}
+
+func NewTagLogic(deps *core.Dependency, repo RepositoryInterface) *TagLogic {
+	return &TagLogic{
+		deps: deps,
+		repo: repo,
+	}
+}
+
+func (logic *TagLogic) GetTags(ctx context.Context, params model.TagSearchParams) ([]model.Tag, error) {
+	params.Search = normalizeTag(params.Search)
+	if params.Limit == 0 {
+		params.Limit = defaultTagLimit
+	}
+	if params.Limit < 0 || params.Limit > maxTagLimit {
+		return []model.Tag{}, xerrors.NewClientError(fmt.Errorf("limit must be between 1 and %d", maxTagLimit))
+	}
+
+	data, err := logic.repo.GetTags(ctx, params)
+	if err != nil {
+		logic.deps.Logger.ErrorContext(ctx, "failed to get tags", slog.Any("error", err))
+		return []model.Tag{}, err
+	}
+
+	return data, nil
+}
+
+func (logic *TagLogic) GetTagByID(ctx context.Context, id int64) (model.Tag, error) {
+	if id <= 0 {
+		return model.Tag{}, xerrors.NewClientError(xerrors.ErrInvalidID)
+	}
+
+	data, err := logic.repo.GetTagByID(ctx, id)
+	if err != nil {
+		logic.deps.Logger.ErrorContext(ctx, "failed to get tag by id", slog.Any("error", err))
+		return model.Tag{}, err
+	}
+
+	return data, nil
+}
+
+func (logic *TagLogic) StoreTag(ctx context.Context, data model.Tag) (model.Tag, error) {
+	data.Name = normalizeTag(data.Name)
+	if data.Name == "" {
+		return model.Tag{}, xerrors.NewClientError(errors.New("name field is empty"))
+	}
+
+	result, err := logic.repo.StoreTag(ctx, data)
+	if err != nil {
+		logic.deps.Logger.ErrorContext(ctx, "failed to store tag data", slog.Any("error", err))
+		return model.Tag{}, err
+	}
+
+	return result, nil
+}
+
+func (logic *TagLogic) UpdateTag(ctx context.Context, data model.Tag) (model.Tag, error) {
+	if data.ID <= 0 {
+		return model.Tag{}, xerrors.NewClientError(xerrors.ErrInvalidID)
+	}
+
+	data.Name = normalizeTag(data.Name)
+	if data.Name == "" {
+		return model.Tag{}, xerrors.NewClientError(errors.New("name field is empty"))
+	}
+
+	result, err := logic.repo.UpdateTag(ctx, data)
+	if err != nil {
+		logic.deps.Logger.ErrorContext(ctx, "failed to update tag data", slog.Any("error", err))
+		return model.Tag{}, err
+	}
+
+	return result, nil
+}
+
+func (logic *TagLogic) DeleteTag(ctx context.Context, id int64) error {
+	if id <= 0 {
+		return xerrors.NewClientError(xerrors.ErrInvalidID)
+	}
+
+	err := logic.repo.DeleteTag(ctx, id)
+	if err != nil {
+		logic.deps.Logger.ErrorContext(ctx, "failed to delete tag data", slog.Any("error", err))
+		return err
+	}
+
+	return nil
+}
+
+func (logic *TagLogic) SetBookTags(ctx context.Context, bookID int64, names []string) ([]string, error) {
+	if bookID <= 0 {
+		return nil, xerrors.NewClientError(xerrors.ErrInvalidID)
+	}
+
+	tags := make([]string, 0, len(names))
+	for _, name := range names {
+		name = normalizeTag(name)
+		if name == "" {
+			return nil, xerrors.NewClientError(errors.New("tags has an empty tag"))
+		}
+		tags = append(tags, name)
+	}
+	slices.Sort(tags)
+	tags = slices.Compact(tags)
+
+	if len(tags) > maxBookTags {
+		return nil, xerrors.NewClientError(fmt.Errorf("a book can have at most %d tags", maxBookTags))
+	}
+
+	result, err := logic.repo.SetBookTags(ctx, bookID, tags)
+	if err != nil {
+		logic.deps.Logger.ErrorContext(ctx, "failed to set book tags", slog.Any("error", err))
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// normalizeTag lower cases the tag and collapses its spaces, so "Coming  of
+// Age" and "coming of age" are the same tag.
+func normalizeTag(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
@@ -0,0 +1,195 @@
+package tag
+
+import (
+	"byfood-app/internal/core"
+	"byfood-app/internal/model"
+	"byfood-app/internal/pkg/xerrors"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+)
+
+type testSuite struct {
+	Ctrl        *gomock.Controller
+	MockTagRepo *MockRepositoryInterface
+}
+
+func setupTestSuite(t *testing.T) *testSuite {
+	ctrl := gomock.NewController(t)
+	return &testSuite{
+		Ctrl:        ctrl,
+		MockTagRepo: NewMockRepositoryInterface(ctrl),
+	}
+}
+
+func TestTagLogic_GetTags(t *testing.T) {
+	ts := setupTestSuite(t)
+	deps := &core.Dependency{Logger: slog.Default()}
+
+	tests := []struct {
+		name     string
+		params   model.TagSearchParams
+		wantCode int
+		mockFunc func()
+	}{
+		{
+			name:     "success default limit and normalized search",
+			params:   model.TagSearchParams{Search: " Coming  Of"},
+			wantCode: http.StatusOK,
+			mockFunc: func() {
+				ts.MockTagRepo.EXPECT().GetTags(gomock.Any(), model.TagSearchParams{Search: "coming of", Limit: defaultTagLimit}).
+					Return([]model.Tag{{ID: 1, Name: "coming of age", Books: 2}}, nil)
+			},
+		},
+		{
+			name:     "failed limit above max",
+			params:   model.TagSearchParams{Limit: maxTagLimit + 1},
+			wantCode: http.StatusBadRequest,
+			mockFunc: func() {},
+		},
+		{
+			name:     "failed negative limit",
+			params:   model.TagSearchParams{Limit: -1},
+			wantCode: http.StatusBadRequest,
+			mockFunc: func() {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logic := NewTagLogic(deps, ts.MockTagRepo)
+
+			tt.mockFunc()
+
+			_, err := logic.GetTags(context.Background(), tt.params)
+			if (err != nil) != (tt.wantCode != http.StatusOK) || (err != nil && xerrors.ParseErrorTypeToCodeInt(err) != tt.wantCode) {
+				t.Errorf("TagLogic.GetTags() error = %v, wantCode %v", err, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestTagLogic_StoreTag(t *testing.T) {
+	ts := setupTestSuite(t)
+	deps := &core.Dependency{Logger: slog.Default()}
+
+	tests := []struct {
+		name     string
+		data     model.Tag
+		want     model.Tag
+		wantCode int
+		mockFunc func()
+	}{
+		{
+			name:     "success store normalized tag",
+			data:     model.Tag{Name: " Coming  of Age "},
+			want:     model.Tag{ID: 8, Name: "coming of age"},
+			wantCode: http.StatusOK,
+			mockFunc: func() {
+				ts.MockTagRepo.EXPECT().StoreTag(gomock.Any(), model.Tag{Name: "coming of age"}).
+					Return(model.Tag{ID: 8, Name: "coming of age"}, nil)
+			},
+		},
+		{
+			name:     "failed store tag without name",
+			data:     model.Tag{Name: "  "},
+			wantCode: http.StatusBadRequest,
+			mockFunc: func() {},
+		},
+		{
+			name:     "failed store existing tag",
+			data:     model.Tag{Name: "War"},
+			wantCode: http.StatusBadRequest,
+			mockFunc: func() {
+				ts.MockTagRepo.EXPECT().StoreTag(gomock.Any(), model.Tag{Name: "war"}).
+					Return(model.Tag{}, xerrors.NewClientError(errors.New(`tag "war" already exists`)))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logic := NewTagLogic(deps, ts.MockTagRepo)
+
+			tt.mockFunc()
+
+			got, err := logic.StoreTag(context.Background(), tt.data)
+			if (err != nil) != (tt.wantCode != http.StatusOK) || (err != nil && xerrors.ParseErrorTypeToCodeInt(err) != tt.wantCode) {
+				t.Errorf("TagLogic.StoreTag() error = %v, wantCode %v", err, tt.wantCode)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("TagLogic.StoreTag() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTagLogic_SetBookTags(t *testing.T) {
+	ts := setupTestSuite(t)
+	deps := &core.Dependency{Logger: slog.Default()}
+
+	tests := []struct {
+		name     string
+		bookID   int64
+		tags     []string
+		want     []string
+		wantCode int
+		mockFunc func()
+	}{
+		{
+			name:     "success set normalized and deduplicated tags",
+			bookID:   1,
+			tags:     []string{"Quest", " war", "quest "},
+			want:     []string{"quest", "war"},
+			wantCode: http.StatusOK,
+			mockFunc: func() {
+				ts.MockTagRepo.EXPECT().SetBookTags(gomock.Any(), int64(1), []string{"quest", "war"}).
+					Return([]string{"quest", "war"}, nil)
+			},
+		},
+		{
+			name:     "success clear tags",
+			bookID:   1,
+			tags:     nil,
+			want:     []string{},
+			wantCode: http.StatusOK,
+			mockFunc: func() {
+				ts.MockTagRepo.EXPECT().SetBookTags(gomock.Any(), int64(1), []string{}).Return([]string{}, nil)
+			},
+		},
+		{
+			name:     "failed empty tag",
+			bookID:   1,
+			tags:     []string{"war", " "},
+			wantCode: http.StatusBadRequest,
+			mockFunc: func() {},
+		},
+		{
+			name:     "failed invalid book id",
+			bookID:   -1,
+			tags:     []string{"war"},
+			wantCode: http.StatusBadRequest,
+			mockFunc: func() {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logic := NewTagLogic(deps, ts.MockTagRepo)
+
+			tt.mockFunc()
+
+			got, err := logic.SetBookTags(context.Background(), tt.bookID, tt.tags)
+			if (err != nil) != (tt.wantCode != http.StatusOK) || (err != nil && xerrors.ParseErrorTypeToCodeInt(err) != tt.wantCode) {
+				t.Errorf("TagLogic.SetBookTags() error = %v, wantCode %v", err, tt.wantCode)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("TagLogic.SetBookTags() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,244 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: interface.go
+//
+// Generated by this command:
+//
+//	mockgen -source=interface.go -destination=mock_interface.go -package=tag
+//
+
+// Package tag is a generated GoMock package.
+package tag
+
+import (
+	model "byfood-app/internal/model"
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRepositoryInterface is a mock of RepositoryInterface interface.
+type MockRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockRepositoryInterfaceMockRecorder is the mock recorder for MockRepositoryInterface.
+type MockRepositoryInterfaceMockRecorder struct {
+	mock *MockRepositoryInterface
+}
+
+// NewMockRepositoryInterface creates a new mock instance.
+func NewMockRepositoryInterface(ctrl *gomock.Controller) *MockRepositoryInterface {
+	mock := &MockRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepositoryInterface) EXPECT() *MockRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// DeleteTag mocks base method.
+func (m *MockRepositoryInterface) DeleteTag(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTag", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTag indicates an expected call of DeleteTag.
+func (mr *MockRepositoryInterfaceMockRecorder) DeleteTag(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTag", reflect.TypeOf((*MockRepositoryInterface)(nil).DeleteTag), ctx, id)
+}
+
+// GetTagByID mocks base method.
+func (m *MockRepositoryInterface) GetTagByID(ctx context.Context, id int64) (model.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTagByID", ctx, id)
+	ret0, _ := ret[0].(model.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTagByID indicates an expected call of GetTagByID.
+func (mr *MockRepositoryInterfaceMockRecorder) GetTagByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTagByID", reflect.TypeOf((*MockRepositoryInterface)(nil).GetTagByID), ctx, id)
+}
+
+// GetTags mocks base method.
+func (m *MockRepositoryInterface) GetTags(ctx context.Context, params model.TagSearchParams) ([]model.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTags", ctx, params)
+	ret0, _ := ret[0].([]model.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTags indicates an expected call of GetTags.
+func (mr *MockRepositoryInterfaceMockRecorder) GetTags(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTags", reflect.TypeOf((*MockRepositoryInterface)(nil).GetTags), ctx, params)
+}
+
+// SetBookTags mocks base method.
+func (m *MockRepositoryInterface) SetBookTags(ctx context.Context, bookID int64, names []string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetBookTags", ctx, bookID, names)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetBookTags indicates an expected call of SetBookTags.
+func (mr *MockRepositoryInterfaceMockRecorder) SetBookTags(ctx, bookID, names any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBookTags", reflect.TypeOf((*MockRepositoryInterface)(nil).SetBookTags), ctx, bookID, names)
+}
+
+// StoreTag mocks base method.
+func (m *MockRepositoryInterface) StoreTag(ctx context.Context, data model.Tag) (model.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StoreTag", ctx, data)
+	ret0, _ := ret[0].(model.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StoreTag indicates an expected call of StoreTag.
+func (mr *MockRepositoryInterfaceMockRecorder) StoreTag(ctx, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreTag", reflect.TypeOf((*MockRepositoryInterface)(nil).StoreTag), ctx, data)
+}
+
+// UpdateTag mocks base method.
+func (m *MockRepositoryInterface) UpdateTag(ctx context.Context, data model.Tag) (model.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTag", ctx, data)
+	ret0, _ := ret[0].(model.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTag indicates an expected call of UpdateTag.
+func (mr *MockRepositoryInterfaceMockRecorder) UpdateTag(ctx, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTag", reflect.TypeOf((*MockRepositoryInterface)(nil).UpdateTag), ctx, data)
+}
+
+// MockLogicInterface is a mock of LogicInterface interface.
+type MockLogicInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockLogicInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockLogicInterfaceMockRecorder is the mock recorder for MockLogicInterface.
+type MockLogicInterfaceMockRecorder struct {
+	mock *MockLogicInterface
+}
+
+// NewMockLogicInterface creates a new mock instance.
+func NewMockLogicInterface(ctrl *gomock.Controller) *MockLogicInterface {
+	mock := &MockLogicInterface{ctrl: ctrl}
+	mock.recorder = &MockLogicInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLogicInterface) EXPECT() *MockLogicInterfaceMockRecorder {
+	return m.recorder
+}
+
+// DeleteTag mocks base method.
+func (m *MockLogicInterface) DeleteTag(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTag", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTag indicates an expected call of DeleteTag.
+func (mr *MockLogicInterfaceMockRecorder) DeleteTag(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTag", reflect.TypeOf((*MockLogicInterface)(nil).DeleteTag), ctx, id)
+}
+
+// GetTagByID mocks base method.
+func (m *MockLogicInterface) GetTagByID(ctx context.Context, id int64) (model.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTagByID", ctx, id)
+	ret0, _ := ret[0].(model.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTagByID indicates an expected call of GetTagByID.
+func (mr *MockLogicInterfaceMockRecorder) GetTagByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTagByID", reflect.TypeOf((*MockLogicInterface)(nil).GetTagByID), ctx, id)
+}
+
+// GetTags mocks base method.
+func (m *MockLogicInterface) GetTags(ctx context.Context, params model.TagSearchParams) ([]model.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTags", ctx, params)
+	ret0, _ := ret[0].([]model.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTags indicates an expected call of GetTags.
+func (mr *MockLogicInterfaceMockRecorder) GetTags(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTags", reflect.TypeOf((*MockLogicInterface)(nil).GetTags), ctx, params)
+}
+
+// SetBookTags mocks base method.
+func (m *MockLogicInterface) SetBookTags(ctx context.Context, bookID int64, names []string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetBookTags", ctx, bookID, names)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetBookTags indicates an expected call of SetBookTags.
+func (mr *MockLogicInterfaceMockRecorder) SetBookTags(ctx, bookID, names any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBookTags", reflect.TypeOf((*MockLogicInterface)(nil).SetBookTags), ctx, bookID, names)
+}
+
+// StoreTag mocks base method.
+func (m *MockLogicInterface) StoreTag(ctx context.Context, data model.Tag) (model.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StoreTag", ctx, data)
+	ret0, _ := ret[0].(model.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StoreTag indicates an expected call of StoreTag.
+func (mr *MockLogicInterfaceMockRecorder) StoreTag(ctx, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreTag", reflect.TypeOf((*MockLogicInterface)(nil).StoreTag), ctx, data)
+}
+
+// UpdateTag mocks base method.
+func (m *MockLogicInterface) UpdateTag(ctx context.Context, data model.Tag) (model.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTag", ctx, data)
+	ret0, _ := ret[0].(model.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTag indicates an expected call of UpdateTag.
+func (mr *MockLogicInterfaceMockRecorder) UpdateTag(ctx, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTag", reflect.TypeOf((*MockLogicInterface)(nil).UpdateTag), ctx, data)
+}
@@ -0,0 +1,209 @@
+package tag
+
+import (
+	"byfood-app/internal/core"
+	"byfood-app/internal/model"
+	"byfood-app/internal/pkg/xerrors"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/huandu/go-sqlbuilder"
+	"github.com/lib/pq"
+)
+
+// tagBooksColumn counts the books that are not deleted carrying the tag.
+const tagBooksColumn = `(SELECT COUNT(1) FROM library.book_tags bt JOIN library.books b ON b.id = bt.book_id AND b.deleted_at ISNULL WHERE bt.tag_id = tags.id) AS books`
+
+type TagRepo struct {
+	deps *core.Dependency
+}
+
+func NewSQLRepo(deps *core.Dependency) *TagRepo {
+	return &TagRepo{
+		deps: deps,
+	}
+}
+
+// GetTags returns the most used tags first, optionally only those starting
+// with params.Search.
+func (repo *TagRepo) GetTags(ctx context.Context, params model.TagSearchParams) ([]model.Tag, error) {
+	q := sqlbuilder.NewSelectBuilder()
+	q.Select("id", "name", tagBooksColumn, "created_at", "updated_at")
+	q.From("library.tags")
+
+	if params.Search != "" {
+		q.Where("name LIKE " + q.Var(escapeLikePattern(params.Search)+"%"))
+	}
+
+	q.OrderBy("books DESC", "name ASC")
+	q.Limit(params.Limit)
+
+	query, args := q.BuildWithFlavor(sqlbuilder.PostgreSQL)
+
+	var temp []model.SQLTag
+	err := repo.deps.DB.SelectContext(ctx, &temp, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]model.Tag, 0, len(temp))
+	for _, tag := range temp {
+		result = append(result, toTag(tag))
+	}
+
+	return result, nil
+}
+
+func (repo *TagRepo) GetTagByID(ctx context.Context, id int64) (model.Tag, error) {
+	var result model.SQLTag
+
+	q := `SELECT id, name, ` + tagBooksColumn + `, created_at, updated_at FROM library.tags WHERE id = $1;`
+	err := repo.deps.DB.QueryRowxContext(ctx, q, id).StructScan(&result)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Tag{}, xerrors.NewClientError(xerrors.ErrDataNotFound)
+		}
+
+		return model.Tag{}, err
+	}
+
+	return toTag(result), nil
+}
+
+func (repo *TagRepo) StoreTag(ctx context.Context, data model.Tag) (model.Tag, error) {
+	var result model.SQLTag
+
+	q := `INSERT INTO library.tags (name) VALUES ($1) ON CONFLICT (name) DO NOTHING RETURNING id, name, 0 AS books, created_at, updated_at;`
+	err := repo.deps.DB.QueryRowxContext(ctx, q, data.Name).StructScan(&result)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Tag{}, xerrors.NewClientError(fmt.Errorf("tag %q already exists", data.Name))
+		}
+
+		return model.Tag{}, err
+	}
+
+	return toTag(result), nil
+}
+
+// UpdateTag renames the tag on every book carrying it.
+func (repo *TagRepo) UpdateTag(ctx context.Context, data model.Tag) (model.Tag, error) {
+	var result model.SQLTag
+
+	q := `UPDATE library.tags SET name = $2, updated_at = now() WHERE id = $1 RETURNING id, name, ` + tagBooksColumn + `, created_at, updated_at;`
+	err := repo.deps.DB.QueryRowxContext(ctx, q, data.ID, data.Name).StructScan(&result)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Tag{}, xerrors.NewClientError(xerrors.ErrDataNotFound)
+		}
+		if xerrors.IsUniqueViolation(err, "") {
+			return model.Tag{}, xerrors.NewClientError(fmt.Errorf("tag %q already exists", data.Name))
+		}
+
+		return model.Tag{}, err
+	}
+
+	return toTag(result), nil
+}
+
+// DeleteTag deletes the tag and takes it off its books.
+func (repo *TagRepo) DeleteTag(ctx context.Context, id int64) error {
+	q := `DELETE FROM library.tags WHERE id = $1;`
+	res, err := repo.deps.DB.ExecContext(ctx, q, id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return xerrors.NewClientError(xerrors.ErrDataNotFound)
+	}
+
+	return nil
+}
+
+// SetBookTags replaces the tags of a book that is not deleted, adding the
+// tags that don't exist yet, and returns them.
+func (repo *TagRepo) SetBookTags(ctx context.Context, bookID int64, names []string) ([]string, error) {
+	tx, err := repo.deps.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	bookQ := `SELECT id FROM library.books WHERE id = $1 AND deleted_at ISNULL FOR SHARE;`
+	deleteQ := `DELETE FROM library.book_tags WHERE book_id = $1;`
+	insertQ := `
+		WITH input AS (
+			SELECT unnest($2::TEXT[]) AS name
+		), created AS (
+			INSERT INTO library.tags (name) SELECT name FROM input
+			ON CONFLICT (name) DO NOTHING
+			RETURNING id
+		)
+		INSERT INTO library.book_tags (book_id, tag_id)
+		SELECT $1, id FROM created
+		UNION
+		SELECT $1, t.id FROM library.tags t JOIN input i ON i.name = t.name;
+	`
+	listQ := `SELECT library.book_tag_list($1);`
+
+	err = tx.QueryRowxContext(ctx, bookQ, bookID).Scan(&bookID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, xerrors.NewClientError(xerrors.ErrDataNotFound)
+		}
+
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, deleteQ, bookID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(names) > 0 {
+		_, err = tx.ExecContext(ctx, insertQ, bookID, pq.Array(names))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var result pq.StringArray
+	err = tx.QueryRowxContext(ctx, listQ, bookID).Scan(&result)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		repo.deps.Logger.ErrorContext(ctx, "failed to commit sql transaction", slog.Any("error", err))
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// escapeLikePattern escapes LIKE wildcards so user input is matched literally.
+func escapeLikePattern(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+func toTag(temp model.SQLTag) model.Tag {
+	return model.Tag{
+		ID:    temp.ID.Int64,
+		Name:  temp.Name.String,
+		Books: temp.Books.Int64,
+		BaseAudit: model.BaseAudit{
+			CreatedAt: &temp.CreatedAt.Time,
+			UpdatedAt: &temp.UpdatedAt.Time,
+		},
+	}
+}
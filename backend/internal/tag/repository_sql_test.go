@@ -0,0 +1,248 @@
+package tag
+
+import (
+	"byfood-app/internal/core"
+	"byfood-app/internal/model"
+	"byfood-app/internal/pkg/xerrors"
+	"context"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+var tagColumns = []string{"id", "name", "books", "created_at", "updated_at"}
+
+func newTestRepo(t *testing.T) (*TagRepo, sqlmock.Sqlmock) {
+	db, mockDB, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return NewSQLRepo(&core.Dependency{
+		Logger: slog.Default(),
+		DB:     sqlx.NewDb(db, "sqlmock"),
+	}), mockDB
+}
+
+func TestTagRepo_GetTags(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		params   model.TagSearchParams
+		want     []model.Tag
+		mockFunc func(mockDB sqlmock.Sqlmock)
+	}{
+		{
+			name:   "success get most used tags",
+			params: model.TagSearchParams{Limit: 2},
+			want: []model.Tag{
+				{ID: 2, Name: "war", Books: 3, BaseAudit: model.BaseAudit{CreatedAt: &now, UpdatedAt: &now}},
+				{ID: 1, Name: "coming of age", Books: 2, BaseAudit: model.BaseAudit{CreatedAt: &now, UpdatedAt: &now}},
+			},
+			mockFunc: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectQuery(`(?s)^SELECT id, name, \(SELECT COUNT\(1\) FROM library.book_tags bt JOIN library.books b ON b.id = bt.book_id AND b.deleted_at ISNULL WHERE bt.tag_id = tags.id\) AS books, created_at, updated_at FROM library.tags ORDER BY books DESC, name ASC LIMIT \$1$`).
+					WithArgs(2).
+					WillReturnRows(sqlmock.NewRows(tagColumns).
+						AddRow(2, "war", 3, now, now).
+						AddRow(1, "coming of age", 2, now, now))
+			},
+		},
+		{
+			name:   "success search tags by escaped prefix",
+			params: model.TagSearchParams{Search: "100%", Limit: 20},
+			want:   []model.Tag{},
+			mockFunc: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectQuery(`(?s)^SELECT .* FROM library.tags WHERE name LIKE \$1 ORDER BY books DESC, name ASC LIMIT \$2$`).
+					WithArgs(`100\%%`, 20).
+					WillReturnRows(sqlmock.NewRows(tagColumns))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, mockDB := newTestRepo(t)
+			tt.mockFunc(mockDB)
+
+			got, err := repo.GetTags(context.Background(), tt.params)
+			if err != nil {
+				t.Errorf("TagRepo.GetTags() error = %v", err)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("TagRepo.GetTags() = %+v, want %+v", got, tt.want)
+			}
+			if err := mockDB.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestTagRepo_StoreTag(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		data     model.Tag
+		want     model.Tag
+		wantCode int
+		mockFunc func(mockDB sqlmock.Sqlmock)
+	}{
+		{
+			name:     "success store tag",
+			data:     model.Tag{Name: "heist"},
+			want:     model.Tag{ID: 8, Name: "heist", BaseAudit: model.BaseAudit{CreatedAt: &now, UpdatedAt: &now}},
+			wantCode: http.StatusOK,
+			mockFunc: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectQuery(`^INSERT INTO library.tags \(name\) VALUES \(\$1\) ON CONFLICT \(name\) DO NOTHING RETURNING .*;$`).
+					WithArgs("heist").
+					WillReturnRows(sqlmock.NewRows(tagColumns).AddRow(8, "heist", 0, now, now))
+			},
+		},
+		{
+			name:     "failed store existing tag",
+			data:     model.Tag{Name: "war"},
+			wantCode: http.StatusBadRequest,
+			mockFunc: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectQuery(`^INSERT INTO library.tags .*;$`).
+					WithArgs("war").
+					WillReturnRows(sqlmock.NewRows(tagColumns))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, mockDB := newTestRepo(t)
+			tt.mockFunc(mockDB)
+
+			got, err := repo.StoreTag(context.Background(), tt.data)
+			if (err != nil) != (tt.wantCode != http.StatusOK) || (err != nil && xerrors.ParseErrorTypeToCodeInt(err) != tt.wantCode) {
+				t.Errorf("TagRepo.StoreTag() error = %v, wantCode %v", err, tt.wantCode)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("TagRepo.StoreTag() = %+v, want %+v", got, tt.want)
+			}
+			if err := mockDB.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestTagRepo_UpdateTag(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     model.Tag
+		wantCode int
+		mockFunc func(mockDB sqlmock.Sqlmock)
+	}{
+		{
+			name:     "failed rename to an existing tag",
+			data:     model.Tag{ID: 1, Name: "war"},
+			wantCode: http.StatusBadRequest,
+			mockFunc: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectQuery(`^UPDATE library.tags SET name = \$2, updated_at = now\(\) WHERE id = \$1 RETURNING .*;$`).
+					WithArgs(1, "war").
+					WillReturnError(&pq.Error{Code: xerrors.PQUniqueViolation})
+			},
+		},
+		{
+			name:     "failed tag not found",
+			data:     model.Tag{ID: 99, Name: "heist"},
+			wantCode: http.StatusBadRequest,
+			mockFunc: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectQuery(`^UPDATE library.tags .*;$`).
+					WithArgs(99, "heist").
+					WillReturnRows(sqlmock.NewRows(tagColumns))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, mockDB := newTestRepo(t)
+			tt.mockFunc(mockDB)
+
+			_, err := repo.UpdateTag(context.Background(), tt.data)
+			if (err != nil) != (tt.wantCode != http.StatusOK) || (err != nil && xerrors.ParseErrorTypeToCodeInt(err) != tt.wantCode) {
+				t.Errorf("TagRepo.UpdateTag() error = %v, wantCode %v", err, tt.wantCode)
+			}
+			if err := mockDB.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestTagRepo_SetBookTags(t *testing.T) {
+	tests := []struct {
+		name     string
+		bookID   int64
+		tags     []string
+		want     []string
+		wantCode int
+		mockFunc func(mockDB sqlmock.Sqlmock)
+	}{
+		{
+			name:     "success replace book tags adding unknown ones",
+			bookID:   1,
+			tags:     []string{"heist", "war"},
+			want:     []string{"heist", "war"},
+			wantCode: http.StatusOK,
+			mockFunc: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectBegin()
+				mockDB.ExpectQuery(`^SELECT id FROM library.books WHERE id = \$1 AND deleted_at ISNULL FOR SHARE;$`).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mockDB.ExpectExec(`^DELETE FROM library.book_tags WHERE book_id = \$1;$`).
+					WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mockDB.ExpectExec(`(?s)^\s*WITH input AS .*INSERT INTO library.tags \(name\) SELECT name FROM input.*ON CONFLICT \(name\) DO NOTHING.*INSERT INTO library.book_tags \(book_id, tag_id\).*UNION.*JOIN input i ON i.name = t.name;\s*$`).
+					WithArgs(1, pq.Array([]string{"heist", "war"})).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mockDB.ExpectQuery(`^SELECT library.book_tag_list\(\$1\);$`).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"book_tag_list"}).AddRow(`{heist,war}`))
+				mockDB.ExpectCommit()
+			},
+		},
+		{
+			name:     "failed book deleted or not found",
+			bookID:   99,
+			tags:     []string{"war"},
+			wantCode: http.StatusBadRequest,
+			mockFunc: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectBegin()
+				mockDB.ExpectQuery(`^SELECT id FROM library.books .* FOR SHARE;$`).
+					WithArgs(99).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mockDB.ExpectRollback()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, mockDB := newTestRepo(t)
+			tt.mockFunc(mockDB)
+
+			got, err := repo.SetBookTags(context.Background(), tt.bookID, tt.tags)
+			if (err != nil) != (tt.wantCode != http.StatusOK) || (err != nil && xerrors.ParseErrorTypeToCodeInt(err) != tt.wantCode) {
+				t.Errorf("TagRepo.SetBookTags() error = %v, wantCode %v", err, tt.wantCode)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("TagRepo.SetBookTags() = %+v, want %+v", got, tt.want)
+			}
+			if err := mockDB.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
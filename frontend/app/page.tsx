@@ -9,7 +9,7 @@ import EditBookForm from "@/components/EditBookForm";
 
 export default function DashboardPage() {
   const router = useRouter();
-  const { books, fetchBooks, deleteBook } = useBooks();
+  const { books, nextCursor, prevCursor, fetchBooks, deleteBook } = useBooks();
 
   // Modal states
   const [isAddModalOpen, setIsAddModalOpen] = useState(false);
@@ -25,7 +25,7 @@ export default function DashboardPage() {
 
   const handleDeleteConfirm = async () => {
     if (selectedBook) {
-      await deleteBook(selectedBook.id);
+      await deleteBook(selectedBook.id, selectedBook.version);
       fetchBooks();
     }
     setIsDeleteModalOpen(false);
@@ -112,7 +112,15 @@ export default function DashboardPage() {
         </tbody>
       </table>
 
-      
+      {/* Pagination */}
+      <div style={{ display: "flex", justifyContent: "flex-end", gap: "10px", marginTop: "10px" }}>
+        <button onClick={() => fetchBooks({ before: prevCursor })} disabled={!prevCursor}>
+          ◀ Previous
+        </button>
+        <button onClick={() => fetchBooks({ after: nextCursor })} disabled={!nextCursor}>
+          Next ▶
+        </button>
+      </div>
 
       {/* Add Modal */}
       <Modal
@@ -1,20 +1,25 @@
 "use client";
 
 import { useState } from "react";
+import { BookMatch, DuplicateBookError, useBooks } from "../context/BookContext";
 
 interface AddBookFormProps {
   onSuccess: () => void; // Called when the book is successfully added
 }
 
 export default function AddBookForm({ onSuccess }: AddBookFormProps) {
+  const { addBook } = useBooks();
   const [title, setTitle] = useState("");
   const [author, setAuthor] = useState("");
   const [year, setYear] = useState("");
   const [error, setError] = useState("");
+  // books the new one probably duplicates, it is only added anyway on request
+  const [matches, setMatches] = useState<BookMatch[]>([]);
 
-  const handleSubmit = async (e: React.FormEvent) => {
+  const handleSubmit = async (e: React.FormEvent, force = false) => {
     e.preventDefault();
     setError("");
+    setMatches([]);
 
     // Basic validation
     if (!title.trim() || !author.trim() || !year) {
@@ -22,21 +27,14 @@ export default function AddBookForm({ onSuccess }: AddBookFormProps) {
       return;
     }
 
-    if (Number(year) < 0 || Number(year) > new Date().getFullYear()) {
+    // years are numbered like ISO 8601, 0 being 1 BCE and -699 being 700 BCE
+    if (Number(year) < -9999 || Number(year) > new Date().getFullYear()) {
       setError("Please enter a valid publication year.");
       return;
     }
 
     try {
-      const res = await fetch("http://localhost:8080/books", {
-        method: "POST",
-        headers: { "Content-Type": "application/json" },
-        body: JSON.stringify({ title, author, publish_year: Number(year) }),
-      });
-
-      if (!res.ok) {
-        throw new Error(`Error: ${res.statusText}`);
-      }
+      await addBook({ title, author, publish_year: Number(year) }, force);
 
       // Reset form
       setTitle("");
@@ -45,14 +43,34 @@ export default function AddBookForm({ onSuccess }: AddBookFormProps) {
 
       onSuccess(); // refresh list or close modal
     } catch (err: any) {
-      setError("Failed to add book. Please try again.");
       console.error(err);
+      if (err instanceof DuplicateBookError && err.matches.length > 0) {
+        setError("This book looks like one already in the library:");
+        setMatches(err.matches);
+        return;
+      }
+      setError("Failed to add book. Please try again.");
     }
   };
 
   return (
     <form onSubmit={handleSubmit} style={formStyle}>
       {error && <p style={errorStyle}>{error}</p>}
+      {matches.length > 0 && (
+        <div style={fieldStyle}>
+          <ul style={matchListStyle}>
+            {matches.map((match) => (
+              <li key={match.book.id}>
+                {match.book.title} by {match.book.author} ({match.book.publish_year}),{" "}
+                {Math.round(match.score * 100)}% alike
+              </li>
+            ))}
+          </ul>
+          <button type="button" onClick={(e) => handleSubmit(e, true)} style={forceButtonStyle}>
+            Add anyway
+          </button>
+        </div>
+      )}
 
       <div style={fieldStyle}>
         <label style={labelStyle}>Title:</label>
@@ -80,6 +98,7 @@ export default function AddBookForm({ onSuccess }: AddBookFormProps) {
         <label style={labelStyle}>Year:</label>
         <input
           type="number"
+          placeholder="0 is 1 BCE, -699 is 700 BCE"
           value={year}
           onChange={(e) => setYear(e.target.value)}
           style={inputStyle}
@@ -134,3 +153,14 @@ const buttonStyle: React.CSSProperties = {
   cursor: "pointer",
   fontWeight: "bold",
 };
+
+const matchListStyle: React.CSSProperties = {
+  margin: "0 0 8px",
+  paddingLeft: "20px",
+  color: "#ddd",
+};
+
+const forceButtonStyle: React.CSSProperties = {
+  ...buttonStyle,
+  backgroundColor: "#b36b00",
+};
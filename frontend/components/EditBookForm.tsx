@@ -1,10 +1,10 @@
 "use client";
 
 import { useState } from "react";
-import { useBooks } from "../context/BookContext";
+import { BookConflictError, useBooks } from "../context/BookContext";
 
 interface EditBookFormProps {
-  book: { id: number; title: string; author: string; publish_year: number };
+  book: { id: number; title: string; author: string; publish_year: number; isbn?: string; version: number };
   onSuccess: () => void;
 }
 
@@ -13,7 +13,8 @@ export default function EditBookForm({ book, onSuccess }: EditBookFormProps) {
   const [formData, setFormData] = useState({
     title: book.title,
     author: book.author,
-    year: book.publish_year,
+    // kept as typed, a lone "-" on the way to a BCE year is no number yet
+    year: String(book.publish_year),
   });
   const [error, setError] = useState("");
 
@@ -21,25 +22,33 @@ export default function EditBookForm({ book, onSuccess }: EditBookFormProps) {
     e.preventDefault();
     setError("");
 
-    if (!formData.title.trim() || !formData.author.trim() || !formData.year) {
+    if (!formData.title.trim() || !formData.author.trim() || !formData.year.trim()) {
       setError("All fields are required.");
       return;
     }
 
-    if (Number(formData.year) < 0 || Number(formData.year) > new Date().getFullYear()) {
+    // years are numbered like ISO 8601, 0 being 1 BCE and -699 being 700 BCE
+    const year = Number(formData.year);
+    if (!Number.isInteger(year) || year < -9999 || year > new Date().getFullYear()) {
       setError("Please enter a valid publication year.");
       return;
     }
 
     try {
-      await updateBook(book.id, {
+      await updateBook(book.id, book.version, {
         title: formData.title,
         author: formData.author,
-        publish_year: formData.year,
+        publish_year: year,
+        // a PUT replaces the book, the ISBN isn't edited here but must be kept
+        isbn: book.isbn,
       });
       onSuccess();
     } catch (err) {
       console.error(err);
+      if (err instanceof BookConflictError) {
+        setError("This book was changed by someone else. Close the form and reload it before editing again.");
+        return;
+      }
       setError("Failed to update book. Please try again.");
     }
   };
@@ -72,10 +81,9 @@ export default function EditBookForm({ book, onSuccess }: EditBookFormProps) {
         <label style={labelStyle}>Year:</label>
         <input
           type="text"
+          placeholder="0 is 1 BCE, -699 is 700 BCE"
           value={formData.year}
-          onChange={(e) =>
-            setFormData({ ...formData, year: parseInt(e.target.value, 10) })
-          }
+          onChange={(e) => setFormData({ ...formData, year: e.target.value })}
           style={inputStyle}
         />
       </div>
@@ -1,35 +1,64 @@
 "use client";
 
-import React, { createContext, useContext, useState, ReactNode, useCallback } from "react";
+import React, { createContext, useContext, useState, ReactNode, useCallback, useRef } from "react";
 
 export interface Book {
   id: number;
   title: string;
   author: string;
   publish_year: number;
+  isbn?: string;
+  version: number;
 }
 
-interface BookContextType {
-  books: Book[];
-  fetchBooks: () => Promise<void>;
-  addBook: (book: Omit<Book, "id">) => Promise<void>;
-  updateBook: (id: number, updatedBook: Omit<Book, "id">) => Promise<void>;
-  deleteBook: (id: number) => Promise<void>;
+// Thrown when the book was changed by someone else since it was loaded.
+export class BookConflictError extends Error {}
+
+// A stored book a new one probably duplicates, scored from 0 to 1.
+export interface BookMatch {
+  book: { id: number; title: string; author: string; publish_year: number };
+  score: number;
 }
 
-interface PaginationType {
+// Thrown when a new book probably is already stored, adding it with force
+// stores it anyway.
+export class DuplicateBookError extends Error {
+  constructor(public matches: BookMatch[]) {
+    super("Book probably is already stored");
+  }
+}
 
+// Page moves through the listing with the cursors the API hands back,
+// fetching without one reloads the page last shown.
+export interface Page {
+  after?: string;
+  before?: string;
+}
+
+interface BookContextType {
+  books: Book[];
+  nextCursor: string;
+  prevCursor: string;
+  fetchBooks: (page?: Page) => Promise<void>;
+  addBook: (book: Omit<Book, "id" | "version">, force?: boolean) => Promise<void>;
+  updateBook: (id: number, version: number, updatedBook: Omit<Book, "id" | "version">) => Promise<void>;
+  deleteBook: (id: number, version: number) => Promise<void>;
 }
 
 const BookContext = createContext<BookContextType | undefined>(undefined);
 
 export const BookProvider = ({ children }: { children: ReactNode }) => {
   const [books, setBooks] = useState<Book[]>([]);
-  
+  const [nextCursor, setNextCursor] = useState("");
+  const [prevCursor, setPrevCursor] = useState("");
+  const currentPage = useRef<Page>({});
 
-  const fetchBooks = useCallback(async () => {
+  const fetchBooks = useCallback(async (page: Page = currentPage.current) => {
+    currentPage.current = page;
     try {
         const baseURL = new URL("http://localhost:8080/books")
+        if (page.after) baseURL.searchParams.set("after", page.after);
+        if (page.before) baseURL.searchParams.set("before", page.before);
 
         const res = await fetch(baseURL);
         
@@ -38,49 +67,65 @@ export const BookProvider = ({ children }: { children: ReactNode }) => {
         const body = await res.json();
         
         setBooks(Array.isArray(body.data) ? body.data : []);
+        setNextCursor(body.metadata?.next_cursor ?? "");
+        setPrevCursor(body.metadata?.prev_cursor ?? "");
     } catch (error) {
         console.error("Error fetching books:", error);
         setBooks([]);
+        setNextCursor("");
+        setPrevCursor("");
     }
   }, []);
 
-  const addBook = async (book: Omit<Book, "id">) => {
+  const addBook = async (book: Omit<Book, "id" | "version">, force = false) => {
     try {
-      const res = await fetch("http://localhost:8080/books", {
+      const url = new URL("http://localhost:8080/books");
+      if (force) url.searchParams.set("force", "true");
+
+      const res = await fetch(url, {
         method: "POST",
         headers: { "Content-Type": "application/json" },
         body: JSON.stringify(book),
       });
+      if (res.status === 409) {
+        const body = await res.json();
+        throw new DuplicateBookError(Array.isArray(body.data) ? body.data : []);
+      }
       if (!res.ok) throw new Error("Failed to add book");
-      const newBook = await res.json();
-      setBooks((prev) => [...prev, newBook]);
+      const body = await res.json();
+      setBooks((prev) => [...prev, body.data]);
     } catch (error) {
       console.error("Error adding book:", error);
+      throw error;
     }
   };
 
-  const updateBook = async (id: number, updatedBook: Omit<Book, "id">) => {
+  const updateBook = async (id: number, version: number, updatedBook: Omit<Book, "id" | "version">) => {
     try {
       const res = await fetch(`http://localhost:8080/books/${id}`, {
         method: "PUT",
-        headers: { "Content-Type": "application/json" },
+        headers: { "Content-Type": "application/json", "If-Match": `"${version}"` },
         body: JSON.stringify(updatedBook),
       });
+      if (res.status === 412) throw new BookConflictError("Book was modified by someone else");
       if (!res.ok) throw new Error("Failed to update book");
-      const updated = await res.json();
+      const body = await res.json();
       setBooks((prev) =>
-        prev.map((book) => (book.id === id ? updated : book))
+        prev.map((book) => (book.id === id ? body.data : book))
       );
     } catch (error) {
       console.error("Error updating book:", error);
+      throw error;
     }
   };
 
-  const deleteBook = async (id: number) => {
+  const deleteBook = async (id: number, version: number) => {
     try {
       const res = await fetch(`http://localhost:8080/books/${id}`, {
         method: "DELETE",
+        headers: { "If-Match": `"${version}"` },
       });
+      if (res.status === 412) throw new BookConflictError("Book was modified by someone else");
       if (!res.ok) throw new Error("Failed to delete book");
       setBooks((prev) => prev.filter((book) => book.id !== id));
     } catch (error) {
@@ -90,7 +135,7 @@ export const BookProvider = ({ children }: { children: ReactNode }) => {
 
   return (
     <BookContext.Provider
-      value={{ books, fetchBooks, addBook, updateBook, deleteBook }}
+      value={{ books, nextCursor, prevCursor, fetchBooks, addBook, updateBook, deleteBook }}
     >
       {children}
     </BookContext.Provider>
@@ -6,10 +6,27 @@ CREATE TABLE IF NOT EXISTS library.books (
     id BIGSERIAL PRIMARY KEY,
     title TEXT NOT NULL,
     author TEXT NOT NULL,
-    publish_year INTEGER NOT NULL,
+    -- publish_year counts like ISO 8601, 0 is 1 BCE and -1 is 2 BCE, the
+    -- month and day are NULL when the date is only known to the year or month
+    publish_year INTEGER NOT NULL CHECK (publish_year BETWEEN -9999 AND 9999),
+    publish_month SMALLINT CHECK (publish_month BETWEEN 1 AND 12),
+    publish_day SMALLINT CHECK (publish_day BETWEEN 1 AND 31),
+    publish_approximate BOOLEAN NOT NULL DEFAULT false,
+    publish_uncertain BOOLEAN NOT NULL DEFAULT false,
+    -- isbn is stored as ISBN-13 without hyphens, ISBN-10 input is converted
+    isbn TEXT CHECK (isbn ~ '^97[89][0-9]{10}$'),
+    version BIGINT NOT NULL DEFAULT 1,
     created_at TIMESTAMP DEFAULT now(),
     updated_at TIMESTAMP DEFAULT now(),
-    deleted_at TIMESTAMP
+    deleted_at TIMESTAMP,
+    -- merged_into is the book a merged, soft deleted book now lives on. The
+    -- purge keeps such books, clearing it on delete would record no revision
+    merged_into BIGINT REFERENCES library.books (id),
+    search_vector TSVECTOR GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce(author, '')), 'B')
+    ) STORED,
+    CHECK (publish_day ISNULL OR publish_month IS NOT NULL)
 );
 
 -- Create index for title column
@@ -20,7 +37,518 @@ ON library.books (title);
 CREATE INDEX idx_books_author
 ON library.books (author);
 
--- insert books data as seeder
+-- Create index for soft deleted books, used by trash listing and purge
+CREATE INDEX idx_books_deleted_at
+ON library.books (deleted_at)
+WHERE deleted_at IS NOT NULL;
+
+-- Create case insensitive prefix indexes for title and author autocomplete
+CREATE INDEX idx_books_title_prefix
+ON library.books (lower(title) text_pattern_ops);
+
+CREATE INDEX idx_books_author_prefix
+ON library.books (lower(author) text_pattern_ops);
+
+-- Create unique index for isbn, soft deleted books don't hold on to theirs
+CREATE UNIQUE INDEX idx_books_isbn
+ON library.books (isbn)
+WHERE deleted_at IS NULL;
+
+-- Create index for publication date range filters
+CREATE INDEX idx_books_published
+ON library.books (publish_year, publish_month, publish_day);
+
+-- edtf_date writes a publication date in EDTF the way the API does, like
+-- "1949-06-08", "1590~" or "-0699?"
+CREATE OR REPLACE FUNCTION library.edtf_date(year INTEGER, month SMALLINT, day SMALLINT, approximate BOOLEAN, uncertain BOOLEAN) RETURNS TEXT
+LANGUAGE sql IMMUTABLE
+AS $$
+    SELECT CASE WHEN year < 0 THEN '-' ELSE '' END || lpad(abs(year)::TEXT, 4, '0') ||
+        coalesce('-' || lpad(month::TEXT, 2, '0'), '') ||
+        coalesce('-' || lpad(day::TEXT, 2, '0'), '') ||
+        CASE
+            WHEN approximate AND uncertain THEN '%'
+            WHEN approximate THEN '~'
+            WHEN uncertain THEN '?'
+            ELSE ''
+        END
+$$;
+
+-- Create full-text search index for title and author
+CREATE INDEX idx_books_search_vector
+ON library.books USING GIN (search_vector);
+
+-- Enable typo tolerant and accent insensitive search when pg_trgm and unaccent
+-- are available, the app falls back to plain ILIKE search otherwise
+DO $$
+BEGIN
+    CREATE EXTENSION IF NOT EXISTS pg_trgm;
+    CREATE EXTENSION IF NOT EXISTS unaccent;
+
+    -- unaccent() is only STABLE, wrap it as IMMUTABLE so it can be indexed
+    CREATE OR REPLACE FUNCTION library.f_unaccent(TEXT) RETURNS TEXT
+    LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT
+    AS $fn$ SELECT public.unaccent('public.unaccent'::regdictionary, $1) $fn$;
+
+    CREATE INDEX IF NOT EXISTS idx_books_title_trgm
+    ON library.books USING GIN (library.f_unaccent(title) gin_trgm_ops);
+
+    CREATE INDEX IF NOT EXISTS idx_books_author_trgm
+    ON library.books USING GIN (library.f_unaccent(author) gin_trgm_ops);
+EXCEPTION WHEN OTHERS THEN
+    RAISE NOTICE 'fuzzy search is disabled: %', SQLERRM;
+END
+$$;
+
+-- Create authors table, authors are matched by name_key when a book is
+-- written, the normalized name folded to lower case ASCII ("j r r tolkien").
+-- Years are NULL when unknown and negative for BCE
+CREATE TABLE library.authors (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    name_key TEXT NOT NULL UNIQUE,
+    biography TEXT NOT NULL DEFAULT '',
+    birth_year INTEGER,
+    death_year INTEGER,
+    nationality TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT now(),
+    updated_at TIMESTAMP DEFAULT now(),
+    deleted_at TIMESTAMP,
+    CHECK (death_year >= birth_year)
+);
+
+-- Create index for probable duplicate authors, compared by surname
+CREATE INDEX idx_authors_surname_key
+ON library.authors ((substring(name_key from '[^ ]+$')));
+
+-- Create author aliases table, other names an author is known by. A name
+-- matching an alias key is credited to the aliased author
+CREATE TABLE library.author_aliases (
+    author_id BIGINT NOT NULL REFERENCES library.authors (id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    name_key TEXT NOT NULL UNIQUE,
+    PRIMARY KEY (author_id, name)
+);
+
+-- Create book authors table, the credits of a book in order. books.author
+-- stays as the display string of the names credited as author
+CREATE TABLE library.book_authors (
+    book_id BIGINT NOT NULL REFERENCES library.books (id) ON DELETE CASCADE,
+    author_id BIGINT NOT NULL REFERENCES library.authors (id),
+    -- position is the credit order, starting at 1
+    position INTEGER NOT NULL,
+    role TEXT NOT NULL DEFAULT 'author' CHECK (role IN ('author', 'editor', 'translator', 'illustrator')),
+    PRIMARY KEY (book_id, position),
+    UNIQUE (book_id, author_id, role)
+);
+
+-- Create index for the books of an author
+CREATE INDEX idx_book_authors_author_id
+ON library.book_authors (author_id);
+
+-- book_author_list returns the credits of a book the way the API does
+CREATE OR REPLACE FUNCTION library.book_author_list(book_id BIGINT) RETURNS JSONB
+LANGUAGE sql STABLE
+AS $$
+    SELECT coalesce(
+        jsonb_agg(jsonb_build_object('id', a.id, 'name', a.name, 'role', ba.role) ORDER BY ba.position),
+        '[]'
+    )
+    FROM library.book_authors ba
+    JOIN library.authors a ON a.id = ba.author_id
+    WHERE ba.book_id = $1
+$$;
+
+-- Create genres table, a tree of genres such as Fiction > Fantasy > High Fantasy
+CREATE TABLE library.genres (
+    id BIGSERIAL PRIMARY KEY,
+    parent_id BIGINT REFERENCES library.genres (id),
+    name TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT now(),
+    updated_at TIMESTAMP DEFAULT now(),
+    CHECK (parent_id <> id)
+);
+
+-- Create index for unique genre names among siblings, top level genres included
+CREATE UNIQUE INDEX idx_genres_parent_id_name
+ON library.genres ((coalesce(parent_id, 0)), lower(name));
+
+-- Create book genres table, a book can be in any number of genres
+CREATE TABLE library.book_genres (
+    book_id BIGINT NOT NULL REFERENCES library.books (id) ON DELETE CASCADE,
+    genre_id BIGINT NOT NULL REFERENCES library.genres (id) ON DELETE CASCADE,
+    PRIMARY KEY (book_id, genre_id)
+);
+
+-- Create index for the books of a genre
+CREATE INDEX idx_book_genres_genre_id
+ON library.book_genres (genre_id);
+
+-- genre_subtree returns the genre and every genre below it
+CREATE OR REPLACE FUNCTION library.genre_subtree(genre_id BIGINT) RETURNS SETOF BIGINT
+LANGUAGE sql STABLE
+AS $$
+    WITH RECURSIVE subtree AS (
+        SELECT id FROM library.genres WHERE id = $1
+        UNION
+        SELECT g.id FROM library.genres g JOIN subtree s ON g.parent_id = s.id
+    )
+    SELECT id FROM subtree
+$$;
+
+-- book_genre_list returns the genres of a book the way the API does
+CREATE OR REPLACE FUNCTION library.book_genre_list(book_id BIGINT) RETURNS JSONB
+LANGUAGE sql STABLE
+AS $$
+    SELECT coalesce(jsonb_agg(jsonb_build_object('id', g.id, 'name', g.name) ORDER BY g.name, g.id), '[]')
+    FROM library.book_genres bg
+    JOIN library.genres g ON g.id = bg.genre_id
+    WHERE bg.book_id = $1
+$$;
+
+-- Create tags table, free-form labels kept lower case with single spaces
+CREATE TABLE library.tags (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMP DEFAULT now(),
+    updated_at TIMESTAMP DEFAULT now()
+);
+
+-- Create book tags table
+CREATE TABLE library.book_tags (
+    book_id BIGINT NOT NULL REFERENCES library.books (id) ON DELETE CASCADE,
+    tag_id BIGINT NOT NULL REFERENCES library.tags (id) ON DELETE CASCADE,
+    PRIMARY KEY (book_id, tag_id)
+);
+
+-- Create index for the books of a tag
+CREATE INDEX idx_book_tags_tag_id
+ON library.book_tags (tag_id);
+
+-- book_tag_list returns the tag names of a book in name order
+CREATE OR REPLACE FUNCTION library.book_tag_list(book_id BIGINT) RETURNS TEXT[]
+LANGUAGE sql STABLE
+AS $$
+    SELECT coalesce(array_agg(t.name ORDER BY t.name), '{}')
+    FROM library.book_tags bt
+    JOIN library.tags t ON t.id = bt.tag_id
+    WHERE bt.book_id = $1
+$$;
+
+-- Create book identifiers table, the records of a book in outside datasets.
+-- Values are stored normalized, and two books may share one, e.g. when the
+-- catalog has the same book twice
+CREATE TABLE library.book_identifiers (
+    book_id BIGINT NOT NULL REFERENCES library.books (id) ON DELETE CASCADE,
+    scheme TEXT NOT NULL CHECK (scheme IN ('oclc', 'lccn', 'openlibrary', 'goodreads')),
+    value TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT now(),
+    PRIMARY KEY (book_id, scheme, value)
+);
+
+-- Create index for identifier lookups
+CREATE INDEX idx_book_identifiers_scheme_value
+ON library.book_identifiers (scheme, value);
+
+-- book_identifier_list returns the identifiers of a book the way the API does
+CREATE OR REPLACE FUNCTION library.book_identifier_list(book_id BIGINT) RETURNS JSONB
+LANGUAGE sql STABLE
+AS $$
+    SELECT coalesce(jsonb_agg(jsonb_build_object('scheme', bi.scheme, 'value', bi.value) ORDER BY bi.scheme, bi.value), '[]')
+    FROM library.book_identifiers bi
+    WHERE bi.book_id = $1
+$$;
+
+-- Create book editions table, a book row is the work and its editions are
+-- the published forms of it. Like identifiers they are not versioned
+CREATE TABLE library.book_editions (
+    id BIGSERIAL PRIMARY KEY,
+    book_id BIGINT NOT NULL REFERENCES library.books (id) ON DELETE CASCADE,
+    publisher TEXT,
+    -- format stays NULL for editions folded in from merged books
+    format TEXT CHECK (format IN ('hardcover', 'paperback', 'ebook', 'audiobook', 'large_print')),
+    -- language is a BCP 47 tag like en or pt-BR
+    language TEXT CHECK (language ~ '^[a-z]{2,3}(-[A-Z]{2})?$'),
+    page_count INTEGER CHECK (page_count > 0),
+    isbn TEXT CHECK (isbn ~ '^97[89][0-9]{10}$'),
+    -- published is an EDTF date as written by the app
+    published TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    updated_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+-- Create index for the editions of a book
+CREATE INDEX idx_book_editions_book_id
+ON library.book_editions (book_id);
+
+-- Create unique index for edition isbn
+CREATE UNIQUE INDEX idx_book_editions_isbn
+ON library.book_editions (isbn);
+
+-- An ISBN names one work, so an edition can't take the ISBN of another live
+-- book and a live book can't take the ISBN of another book's edition. Both
+-- checks fail like the unique index of the table written to
+CREATE OR REPLACE FUNCTION library.check_edition_isbn() RETURNS TRIGGER
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM library.books b
+        WHERE b.isbn = NEW.isbn AND b.id <> NEW.book_id AND b.deleted_at ISNULL
+    ) THEN
+        RAISE EXCEPTION 'isbn % belongs to another book', NEW.isbn
+            USING ERRCODE = 'unique_violation', CONSTRAINT = 'idx_book_editions_isbn';
+    END IF;
+
+    RETURN NEW;
+END
+$$;
+
+CREATE TRIGGER trg_book_editions_check_isbn
+BEFORE INSERT OR UPDATE ON library.book_editions
+FOR EACH ROW WHEN (NEW.isbn IS NOT NULL)
+EXECUTE FUNCTION library.check_edition_isbn();
+
+CREATE OR REPLACE FUNCTION library.check_book_isbn() RETURNS TRIGGER
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM library.book_editions e
+        WHERE e.isbn = NEW.isbn AND e.book_id <> NEW.id
+    ) THEN
+        RAISE EXCEPTION 'isbn % belongs to an edition of another book', NEW.isbn
+            USING ERRCODE = 'unique_violation', CONSTRAINT = 'idx_books_isbn';
+    END IF;
+
+    RETURN NEW;
+END
+$$;
+
+CREATE TRIGGER trg_books_check_isbn
+BEFORE INSERT OR UPDATE ON library.books
+FOR EACH ROW WHEN (NEW.isbn IS NOT NULL AND NEW.deleted_at IS NULL)
+EXECUTE FUNCTION library.check_book_isbn();
+
+-- book_edition_list returns the editions of a book the way the API does
+CREATE OR REPLACE FUNCTION library.book_edition_list(book_id BIGINT) RETURNS JSONB
+LANGUAGE sql STABLE
+AS $$
+    SELECT coalesce(jsonb_agg(jsonb_strip_nulls(jsonb_build_object(
+        'id', e.id,
+        'book_id', e.book_id,
+        'publisher', e.publisher,
+        'format', e.format,
+        'language', e.language,
+        'page_count', e.page_count,
+        'isbn', e.isbn,
+        'published', e.published
+    )) ORDER BY e.id), '[]')
+    FROM library.book_editions e
+    WHERE e.book_id = $1
+$$;
+
+-- Create book revisions table, one row per change of a book written by
+-- the trigger below, so the audit trail can't be skipped by any write path.
+-- Like book_versions it has no foreign key, the trail outlives a purge
+CREATE TABLE library.book_revisions (
+    id BIGSERIAL PRIMARY KEY,
+    book_id BIGINT NOT NULL,
+    -- revision is the book version the change produced
+    revision BIGINT NOT NULL,
+    operation TEXT NOT NULL,
+    actor TEXT,
+    request_id TEXT,
+    -- reverted_to is the revision a revert restored the book fields from
+    reverted_to BIGINT,
+    before JSONB,
+    after JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    UNIQUE (book_id, revision)
+);
+
+-- book_snapshot serializes a book row the way the API does, timestamps are stored as UTC
+CREATE OR REPLACE FUNCTION library.book_snapshot(b library.books) RETURNS JSONB
+LANGUAGE sql STABLE
+AS $$
+    SELECT (to_jsonb(b) - 'search_vector' - 'publish_month' - 'publish_day' - 'publish_approximate' - 'publish_uncertain') || jsonb_build_object(
+        'authors', library.book_author_list(b.id),
+        'published', library.edtf_date(b.publish_year, b.publish_month, b.publish_day, b.publish_approximate, b.publish_uncertain),
+        'created_at', b.created_at AT TIME ZONE 'UTC',
+        'updated_at', b.updated_at AT TIME ZONE 'UTC',
+        'deleted_at', b.deleted_at AT TIME ZONE 'UTC'
+    )
+$$;
+
+-- record_book_revision reads the actor, request id and reverted revision from
+-- the transaction local settings byfood.actor, byfood.request_id and
+-- byfood.reverted_to set by the app. It runs deferred at commit, once the
+-- book authors written after the book row are in place
+CREATE OR REPLACE FUNCTION library.record_book_revision() RETURNS TRIGGER
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    op TEXT;
+    before JSONB;
+    reverted_to BIGINT;
+BEGIN
+    reverted_to := NULLIF(current_setting('byfood.reverted_to', true), '')::BIGINT;
+
+    IF TG_OP = 'INSERT' THEN
+        op := 'create';
+    ELSE
+        -- OLD authors are gone by commit, the previous revision still has them
+        SELECT r.after INTO before
+            FROM library.book_revisions r
+            WHERE r.book_id = NEW.id
+            ORDER BY r.revision DESC
+            LIMIT 1;
+        IF before ISNULL THEN
+            before := library.book_snapshot(OLD);
+        END IF;
+
+        IF OLD.merged_into ISNULL AND NEW.merged_into NOTNULL THEN
+            op := 'merge';
+        ELSIF OLD.deleted_at ISNULL AND NEW.deleted_at NOTNULL THEN
+            op := 'delete';
+        ELSIF OLD.deleted_at NOTNULL AND NEW.deleted_at ISNULL THEN
+            op := 'restore';
+        ELSIF reverted_to NOTNULL THEN
+            op := 'revert';
+        ELSE
+            op := 'update';
+        END IF;
+    END IF;
+
+    INSERT INTO library.book_revisions (book_id, revision, operation, actor, request_id, reverted_to, before, after)
+    VALUES (
+        NEW.id,
+        NEW.version,
+        op,
+        NULLIF(current_setting('byfood.actor', true), ''),
+        NULLIF(current_setting('byfood.request_id', true), ''),
+        CASE WHEN op = 'revert' THEN reverted_to END,
+        before,
+        library.book_snapshot(NEW)
+    );
+
+    RETURN NULL;
+END
+$$;
+
+CREATE CONSTRAINT TRIGGER trg_books_record_revision
+AFTER INSERT OR UPDATE ON library.books
+DEFERRABLE INITIALLY DEFERRED
+FOR EACH ROW EXECUTE FUNCTION library.record_book_revision();
+
+-- Create book versions table, the temporal history of every book row. A
+-- version is valid from the change that wrote it until the next one, and is
+-- kept after the book is purged so past catalogs can still be rebuilt
+CREATE TABLE library.book_versions (
+    book_id BIGINT NOT NULL,
+    version BIGINT NOT NULL,
+    title TEXT NOT NULL,
+    author TEXT NOT NULL,
+    authors JSONB NOT NULL DEFAULT '[]',
+    publish_year INTEGER NOT NULL,
+    publish_month SMALLINT,
+    publish_day SMALLINT,
+    publish_approximate BOOLEAN NOT NULL DEFAULT false,
+    publish_uncertain BOOLEAN NOT NULL DEFAULT false,
+    isbn TEXT,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP,
+    deleted_at TIMESTAMP,
+    valid_from TIMESTAMP NOT NULL,
+    -- valid_to stays NULL for the current version
+    valid_to TIMESTAMP,
+    PRIMARY KEY (book_id, version)
+);
+
+-- Create index for point in time lookups
+CREATE INDEX idx_book_versions_valid
+ON library.book_versions (valid_from, valid_to);
+
+-- record_book_version runs deferred at commit like record_book_revision
+CREATE OR REPLACE FUNCTION library.record_book_version() RETURNS TRIGGER
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    UPDATE library.book_versions
+        SET valid_to = now()
+        WHERE book_id = NEW.id AND valid_to ISNULL;
+
+    INSERT INTO library.book_versions (book_id, version, title, author, authors, publish_year, publish_month, publish_day, publish_approximate, publish_uncertain, isbn, created_at, updated_at, deleted_at, valid_from)
+    VALUES (NEW.id, NEW.version, NEW.title, NEW.author, library.book_author_list(NEW.id), NEW.publish_year, NEW.publish_month, NEW.publish_day, NEW.publish_approximate, NEW.publish_uncertain, NEW.isbn, NEW.created_at, NEW.updated_at, NEW.deleted_at, now());
+
+    RETURN NULL;
+END
+$$;
+
+CREATE CONSTRAINT TRIGGER trg_books_record_version
+AFTER INSERT OR UPDATE ON library.books
+DEFERRABLE INITIALLY DEFERRED
+FOR EACH ROW EXECUTE FUNCTION library.record_book_version();
+
+-- books_as_of returns library.books as it was at the given moment, with the
+-- same columns so listing queries can read from either
+CREATE OR REPLACE FUNCTION library.books_as_of(as_of TIMESTAMPTZ)
+RETURNS TABLE (
+    id BIGINT,
+    title TEXT,
+    author TEXT,
+    authors JSONB,
+    publish_year INTEGER,
+    publish_month SMALLINT,
+    publish_day SMALLINT,
+    publish_approximate BOOLEAN,
+    publish_uncertain BOOLEAN,
+    isbn TEXT,
+    version BIGINT,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP,
+    deleted_at TIMESTAMP,
+    search_vector TSVECTOR
+)
+LANGUAGE sql STABLE
+AS $$
+    SELECT
+        v.book_id, v.title, v.author, v.authors, v.publish_year, v.publish_month, v.publish_day,
+        v.publish_approximate, v.publish_uncertain, v.isbn, v.version,
+        v.created_at, v.updated_at, v.deleted_at,
+        setweight(to_tsvector('english', coalesce(v.title, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce(v.author, '')), 'B')
+    FROM library.book_versions v
+    WHERE
+        v.valid_from <= (as_of AT TIME ZONE 'UTC')
+    AND
+        (v.valid_to ISNULL OR v.valid_to > (as_of AT TIME ZONE 'UTC'))
+$$;
+
+-- Create idempotency keys table, stores the response of a mutating request
+-- so a retry with the same Idempotency-Key header replays it. Keys are
+-- scoped by actor, a caller never gets the response stored for another one
+CREATE TABLE library.idempotency_keys (
+    actor TEXT NOT NULL,
+    key TEXT NOT NULL,
+    fingerprint TEXT NOT NULL,
+    -- status_code stays NULL while the first request is still in progress
+    status_code INTEGER,
+    response_headers JSONB,
+    response_body BYTEA,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (actor, key)
+);
+
+-- Create index for expired idempotency keys cleanup
+CREATE INDEX idx_idempotency_keys_expires_at
+ON library.idempotency_keys (expires_at);
+
+-- insert books data as seeder, in one transaction with the author
+-- migration so the deferred revision triggers record the seeded authors
+BEGIN;
+
 INSERT INTO library.books (title, author, publish_year) VALUES 
 ('To Kill a Mockingbird', 'Harper Lee', 1960),
 ('1984', 'George Orwell', 1949),
@@ -31,4 +559,121 @@ INSERT INTO library.books (title, author, publish_year) VALUES
 ('The Catcher in the Rye', 'J.D. Salinger', 1951),
 ('The Hobbit', 'J.R.R. Tolkien', 1937),
 ('Fahrenheit 451', 'Ray Bradbury', 1953),
-('The Lord of the Rings', 'J.R.R. Tolkien', 1954);
\ No newline at end of file
+('The Lord of the Rings', 'J.R.R. Tolkien', 1954),
+('Good Omens', 'Terry Pratchett & Neil Gaiman', 1990);
+
+-- migrate single author strings into book authors, "A & B" credits two authors.
+-- The seeded names are already normalized ASCII, their key only needs
+-- lower casing and periods read as spaces
+INSERT INTO library.authors (name, name_key)
+SELECT DISTINCT trim(s.name), trim(lower(regexp_replace(s.name, '[.[:space:]]+', ' ', 'g')))
+FROM library.books b
+CROSS JOIN LATERAL regexp_split_to_table(b.author, ' & ') AS s(name)
+WHERE NOT EXISTS (SELECT 1 FROM library.book_authors ba WHERE ba.book_id = b.id)
+ON CONFLICT (name_key) DO NOTHING;
+
+INSERT INTO library.book_authors (book_id, author_id, position, role)
+SELECT b.id, a.id, s.position, 'author'
+FROM library.books b
+CROSS JOIN LATERAL regexp_split_to_table(b.author, ' & ') WITH ORDINALITY AS s(name, position)
+JOIN library.authors a ON a.name = trim(s.name)
+WHERE NOT EXISTS (SELECT 1 FROM library.book_authors ba WHERE ba.book_id = b.id);
+
+UPDATE library.authors a
+SET birth_year = s.birth_year, death_year = s.death_year, nationality = s.nationality
+FROM (VALUES
+    ('Harper Lee', 1926, 2016, 'American'),
+    ('George Orwell', 1903, 1950, 'British'),
+    ('Jane Austen', 1775, 1817, 'British'),
+    ('F. Scott Fitzgerald', 1896, 1940, 'American'),
+    ('Herman Melville', 1819, 1891, 'American'),
+    ('Leo Tolstoy', 1828, 1910, 'Russian'),
+    ('J.D. Salinger', 1919, 2010, 'American'),
+    ('J.R.R. Tolkien', 1892, 1973, 'British'),
+    ('Ray Bradbury', 1920, 2012, 'American'),
+    ('Terry Pratchett', 1948, 2015, 'British'),
+    ('Neil Gaiman', 1960, NULL, 'British')
+) AS s(name, birth_year, death_year, nationality)
+WHERE a.name = s.name;
+
+INSERT INTO library.author_aliases (author_id, name, name_key)
+SELECT a.id, s.alias, lower(s.alias)
+FROM (VALUES
+    ('George Orwell', 'Eric Arthur Blair'),
+    ('Leo Tolstoy', 'Lev Nikolayevich Tolstoy'),
+    ('J.R.R. Tolkien', 'John Ronald Reuel Tolkien')
+) AS s(name, alias)
+JOIN library.authors a ON a.name = s.name;
+
+COMMIT;
+-- insert genres and tags data as seeder, classifying the seeded books
+INSERT INTO library.genres (name) VALUES ('Fiction'), ('Nonfiction');
+
+INSERT INTO library.genres (parent_id, name)
+SELECT p.id, s.name
+FROM (VALUES
+    ('Fiction', 'Fantasy'),
+    ('Fiction', 'Science Fiction'),
+    ('Fiction', 'Classics'),
+    ('Fiction', 'Literary Fiction')
+) AS s(parent, name)
+JOIN library.genres p ON p.name = s.parent AND p.parent_id IS NULL;
+
+INSERT INTO library.genres (parent_id, name)
+SELECT p.id, s.name
+FROM (VALUES
+    ('Fantasy', 'High Fantasy'),
+    ('Fantasy', 'Comic Fantasy'),
+    ('Science Fiction', 'Dystopian')
+) AS s(parent, name)
+JOIN library.genres p ON p.name = s.parent AND p.parent_id IS NOT NULL;
+
+INSERT INTO library.book_genres (book_id, genre_id)
+SELECT b.id, g.id
+FROM (VALUES
+    ('To Kill a Mockingbird', 'Literary Fiction'),
+    ('1984', 'Dystopian'),
+    ('Pride and Prejudice', 'Classics'),
+    ('The Great Gatsby', 'Literary Fiction'),
+    ('Moby-Dick', 'Classics'),
+    ('War and Peace', 'Classics'),
+    ('The Catcher in the Rye', 'Literary Fiction'),
+    ('The Hobbit', 'High Fantasy'),
+    ('Fahrenheit 451', 'Dystopian'),
+    ('The Lord of the Rings', 'High Fantasy'),
+    ('Good Omens', 'Comic Fantasy')
+) AS s(title, genre)
+JOIN library.books b ON b.title = s.title
+JOIN library.genres g ON g.name = s.genre;
+
+INSERT INTO library.tags (name) VALUES
+('coming of age'), ('war'), ('romance'), ('quest'), ('censorship'), ('surveillance'), ('apocalypse');
+
+INSERT INTO library.book_tags (book_id, tag_id)
+SELECT b.id, t.id
+FROM (VALUES
+    ('To Kill a Mockingbird', 'coming of age'),
+    ('1984', 'surveillance'),
+    ('Pride and Prejudice', 'romance'),
+    ('War and Peace', 'war'),
+    ('War and Peace', 'romance'),
+    ('The Catcher in the Rye', 'coming of age'),
+    ('The Hobbit', 'quest'),
+    ('Fahrenheit 451', 'censorship'),
+    ('The Lord of the Rings', 'quest'),
+    ('The Lord of the Rings', 'war'),
+    ('Good Omens', 'apocalypse')
+) AS s(title, tag)
+JOIN library.books b ON b.title = s.title
+JOIN library.tags t ON t.name = s.tag;
+
+-- insert editions data as seeder
+INSERT INTO library.book_editions (book_id, publisher, format, language, page_count, isbn, published)
+SELECT b.id, s.publisher, s.format, s.language, s.page_count, s.isbn, s.published
+FROM (VALUES
+    ('Pride and Prejudice', 'Penguin Classics', 'paperback', 'en', 480, '9780141439518', '2002-12-31'),
+    ('Pride and Prejudice', 'Penguin Classics', 'ebook', 'en', NULL, NULL, '2003-05-01'),
+    ('Pride and Prejudice', 'Thorndike Press', 'large_print', 'en', 603, NULL, '2005'),
+    ('1984', 'Signet Classics', 'paperback', 'en', 328, '9780451524935', '1961-01')
+) AS s(title, publisher, format, language, page_count, isbn, published)
+JOIN library.books b ON b.title = s.title;